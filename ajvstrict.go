@@ -0,0 +1,579 @@
+package jsonschema
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// AJVStrict sets up reflection of schemas that are compatible with AJV strict mode.
+//
+// Unknown keywords (e.g. "x-go-type" or other extensions) are removed, union types are replaced with `anyOf`
+// of single-typed schemas with keywords of their types, schemas that constrain referenced definitions
+// (e.g. with `items` prefixed tags) get types of definitions. Remaining violations of strict mode are returned
+// as AJVStrictProblems error of Reflect, see CheckAJVStrict.
+func AJVStrict(rc *ReflectContext) {
+	rc.AJVStrict = true
+}
+
+// AJVStrictProblem describes a violation of AJV strict mode.
+type AJVStrictProblem struct {
+	// Pointer is a JSON Pointer of subschema, e.g. "/properties/id" or "/definitions/Foo".
+	Pointer string `json:"pointer"`
+
+	// Keyword is a keyword that violates strict mode, e.g. "x-go-type" or "minLength".
+	Keyword string `json:"keyword"`
+
+	Message string `json:"message"`
+}
+
+// Error implements error.
+func (p AJVStrictProblem) Error() string {
+	ptr := p.Pointer
+	if ptr == "" {
+		ptr = "/"
+	}
+
+	return ptr + ": " + p.Message
+}
+
+// AJVStrictProblems is a list of AJV strict mode violations.
+type AJVStrictProblems []AJVStrictProblem
+
+// Error implements error.
+func (e AJVStrictProblems) Error() string {
+	msgs := make([]string, 0, len(e))
+
+	for _, p := range e {
+		msgs = append(msgs, p.Error())
+	}
+
+	return "AJV strict mode violations: " + strings.Join(msgs, ", ")
+}
+
+// ajvKnownExtraKeywords are keywords that AJV supports, but Schema keeps in ExtraProperties.
+var ajvKnownExtraKeywords = map[string]bool{
+	"$defs":                 true,
+	"$anchor":               true,
+	"$dynamicRef":           true,
+	"$dynamicAnchor":        true,
+	"prefixItems":           true,
+	"minContains":           true,
+	"maxContains":           true,
+	"dependentRequired":     true,
+	"dependentSchemas":      true,
+	"unevaluatedItems":      true,
+	"unevaluatedProperties": true,
+	"deprecated":            true,
+	"writeOnly":             true,
+	"nullable":              true,
+}
+
+// ajvKeywordTypes maps type-specific keywords to their applicable types.
+var ajvKeywordTypes = map[string][]SimpleType{
+	"multipleOf":           {Number},
+	"maximum":              {Number},
+	"exclusiveMaximum":     {Number},
+	"minimum":              {Number},
+	"exclusiveMinimum":     {Number},
+	"maxLength":            {String},
+	"minLength":            {String},
+	"pattern":              {String},
+	"format":               {Number, String},
+	"additionalItems":      {Array},
+	"items":                {Array},
+	"maxItems":             {Array},
+	"minItems":             {Array},
+	"uniqueItems":          {Array},
+	"contains":             {Array},
+	"maxProperties":        {Object},
+	"minProperties":        {Object},
+	"required":             {Object},
+	"additionalProperties": {Object},
+	"properties":           {Object},
+	"patternProperties":    {Object},
+	"dependencies":         {Object},
+	"propertyNames":        {Object},
+}
+
+// typeKeywords returns type-specific keywords that are set in schema.
+func typeKeywords(s *Schema) []string {
+	set := map[string]bool{
+		"multipleOf":           s.MultipleOf != nil,
+		"maximum":              s.Maximum != nil,
+		"exclusiveMaximum":     s.ExclusiveMaximum != nil,
+		"minimum":              s.Minimum != nil,
+		"exclusiveMinimum":     s.ExclusiveMinimum != nil,
+		"maxLength":            s.MaxLength != nil,
+		"minLength":            s.MinLength != 0,
+		"pattern":              s.Pattern != nil,
+		"format":               s.Format != nil,
+		"additionalItems":      s.AdditionalItems != nil,
+		"items":                s.Items != nil,
+		"maxItems":             s.MaxItems != nil,
+		"minItems":             s.MinItems != 0,
+		"uniqueItems":          s.UniqueItems != nil,
+		"contains":             s.Contains != nil,
+		"maxProperties":        s.MaxProperties != nil,
+		"minProperties":        s.MinProperties != 0,
+		"required":             len(s.Required) > 0,
+		"additionalProperties": s.AdditionalProperties != nil,
+		"properties":           len(s.Properties) > 0,
+		"patternProperties":    len(s.PatternProperties) > 0,
+		"dependencies":         len(s.Dependencies) > 0,
+		"propertyNames":        s.PropertyNames != nil,
+	}
+
+	var res []string
+
+	for k, ok := range set {
+		if ok {
+			res = append(res, k)
+		}
+	}
+
+	sort.Strings(res)
+
+	return res
+}
+
+// moveTypeKeyword moves type-specific keyword from src to dst.
+func moveTypeKeyword(keyword string, src, dst *Schema) {
+	switch keyword {
+	case "multipleOf":
+		dst.MultipleOf, src.MultipleOf = src.MultipleOf, nil
+	case "maximum":
+		dst.Maximum, src.Maximum = src.Maximum, nil
+	case "exclusiveMaximum":
+		dst.ExclusiveMaximum, src.ExclusiveMaximum = src.ExclusiveMaximum, nil
+	case "minimum":
+		dst.Minimum, src.Minimum = src.Minimum, nil
+	case "exclusiveMinimum":
+		dst.ExclusiveMinimum, src.ExclusiveMinimum = src.ExclusiveMinimum, nil
+	case "maxLength":
+		dst.MaxLength, src.MaxLength = src.MaxLength, nil
+	case "minLength":
+		dst.MinLength, src.MinLength = src.MinLength, 0
+	case "pattern":
+		dst.Pattern, src.Pattern = src.Pattern, nil
+	case "format":
+		dst.Format, src.Format = src.Format, nil
+	case "additionalItems":
+		dst.AdditionalItems, src.AdditionalItems = src.AdditionalItems, nil
+	case "items":
+		dst.Items, src.Items = src.Items, nil
+	case "maxItems":
+		dst.MaxItems, src.MaxItems = src.MaxItems, nil
+	case "minItems":
+		dst.MinItems, src.MinItems = src.MinItems, 0
+	case "uniqueItems":
+		dst.UniqueItems, src.UniqueItems = src.UniqueItems, nil
+	case "contains":
+		dst.Contains, src.Contains = src.Contains, nil
+	case "maxProperties":
+		dst.MaxProperties, src.MaxProperties = src.MaxProperties, nil
+	case "minProperties":
+		dst.MinProperties, src.MinProperties = src.MinProperties, 0
+	case "required":
+		dst.Required, src.Required = src.Required, nil
+	case "additionalProperties":
+		dst.AdditionalProperties, src.AdditionalProperties = src.AdditionalProperties, nil
+	case "properties":
+		dst.Properties, src.Properties = src.Properties, nil
+		dst.PropertiesOrder, src.PropertiesOrder = src.PropertiesOrder, nil
+	case "patternProperties":
+		dst.PatternProperties, src.PatternProperties = src.PatternProperties, nil
+	case "dependencies":
+		dst.Dependencies, src.Dependencies = src.Dependencies, nil
+	case "propertyNames":
+		dst.PropertyNames, src.PropertyNames = src.PropertyNames, nil
+	}
+}
+
+func schemaTypes(s *Schema) []SimpleType {
+	if s.Type == nil {
+		return nil
+	}
+
+	if s.Type.SimpleTypes != nil {
+		return []SimpleType{*s.Type.SimpleTypes}
+	}
+
+	return s.Type.SliceOfSimpleTypeValues
+}
+
+// hasApplicableType checks if keyword type is applicable to any of schema types, number keywords apply to integers.
+func hasApplicableType(types []SimpleType, kt SimpleType) bool {
+	for _, t := range types {
+		if t == kt || (kt == Number && t == Integer) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// keywordApplies checks if type-specific keyword is applicable to any of schema types.
+func keywordApplies(keyword string, types []SimpleType) bool {
+	for _, kt := range ajvKeywordTypes[keyword] {
+		if hasApplicableType(types, kt) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// includesType checks if type is allowed by context types, integer is allowed by number.
+func includesType(types []SimpleType, t SimpleType) bool {
+	for _, ct := range types {
+		if ct == t || (t == Integer && ct == Number) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// toAJVStrict removes unknown keywords, replaces union types with `anyOf` and sets types of referenced
+// definitions to schemas with type-specific keywords (e.g. `allOf` with `$ref` and `minProperties`) in place.
+func toAJVStrict(schema *Schema, resolve func(ref string) *Schema) {
+	var all []*Schema
+
+	walkSchemas(schema, func(s *Schema) {
+		all = append(all, s)
+	})
+
+	for _, s := range all {
+		for k := range s.ExtraProperties {
+			if !ajvKnownExtraKeywords[k] {
+				delete(s.ExtraProperties, k)
+			}
+		}
+
+		ajvUnionTypeAnyOf(s)
+		ajvTypeFromRef(s, resolve)
+	}
+}
+
+// ajvTypeFromRef sets type of untyped schema with type-specific keywords from referenced definition,
+// this does not change validation, because instance has to be valid against the definition anyway.
+func ajvTypeFromRef(s *Schema, resolve func(ref string) *Schema) {
+	if s.Type != nil || len(typeKeywords(s)) == 0 {
+		return
+	}
+
+	refs := []*string{s.Ref}
+
+	for _, a := range s.AllOf {
+		if a.TypeObject != nil {
+			refs = append(refs, a.TypeObject.Ref)
+		}
+	}
+
+	for _, ref := range refs {
+		if ref == nil {
+			continue
+		}
+
+		if def := resolve(*ref); def != nil && def.Type != nil {
+			s.Type = &Type{SliceOfSimpleTypeValues: schemaTypes(def)}
+			if len(s.Type.SliceOfSimpleTypeValues) == 1 {
+				s.Type = &Type{SimpleTypes: &s.Type.SliceOfSimpleTypeValues[0]}
+			}
+
+			return
+		}
+	}
+}
+
+// ajvUnionTypeAnyOf replaces type with multiple non-null types with `anyOf` of single-typed schemas,
+// type-specific keywords are moved to schemas of their types.
+func ajvUnionTypeAnyOf(s *Schema) {
+	types := schemaTypes(s)
+
+	var nonNull []SimpleType
+
+	for _, t := range types {
+		if t != Null {
+			nonNull = append(nonNull, t)
+		}
+	}
+
+	if len(nonNull) < 2 {
+		return
+	}
+
+	anyOf := make([]SchemaOrBool, 0, len(types))
+	keywords := typeKeywords(s)
+
+	for _, t := range types {
+		ts := (&Schema{}).WithType(t.Type())
+
+		for _, k := range keywords {
+			if keywordApplies(k, []SimpleType{t}) {
+				copyTypeKeyword(k, s, ts)
+			}
+		}
+
+		anyOf = append(anyOf, ts.ToSchemaOrBool())
+	}
+
+	// Keywords are removed from schema by moving them to a discarded one.
+	for _, k := range keywords {
+		moveTypeKeyword(k, s, &Schema{})
+	}
+
+	s.Type = nil
+
+	if len(s.AnyOf) == 0 {
+		s.AnyOf = anyOf
+	} else {
+		s.AllOf = append(s.AllOf, (&Schema{AnyOf: anyOf}).ToSchemaOrBool())
+	}
+}
+
+// copyTypeKeyword copies type-specific keyword from src to dst, src keeps its value.
+func copyTypeKeyword(keyword string, src, dst *Schema) {
+	tmp := *src
+	moveTypeKeyword(keyword, &tmp, dst)
+}
+
+// CheckAJVStrict verifies that schema is compatible with AJV strict mode.
+//
+// It reports unknown keywords, union types (except nullable types), types that are not allowed by types of
+// parent schema, type-specific keywords without applicable type, required properties that are not defined,
+// tuples without exact size, ignored keywords (e.g. `then` without `if`) and arrays without items schema.
+//
+// Problems are sorted by pointer and keyword, references are not followed, definitions are checked
+// as separate schemas.
+func CheckAJVStrict(schema Schema) AJVStrictProblems {
+	c := ajvChecker{}
+	c.check(&schema, "", nil, nil)
+
+	sort.SliceStable(c.problems, func(i, j int) bool {
+		if c.problems[i].Pointer != c.problems[j].Pointer {
+			return c.problems[i].Pointer < c.problems[j].Pointer
+		}
+
+		return c.problems[i].Keyword < c.problems[j].Keyword
+	})
+
+	return c.problems
+}
+
+type ajvChecker struct {
+	problems AJVStrictProblems
+}
+
+func (c *ajvChecker) add(ptr, keyword, format string, args ...interface{}) {
+	c.problems = append(c.problems, AJVStrictProblem{
+		Pointer: ptr,
+		Keyword: keyword,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// check validates schema at pointer, context types and defined properties are inherited
+// from parent schemas of the same instance location (e.g. `allOf`).
+func (c *ajvChecker) check(s *Schema, ptr string, context []SimpleType, defined map[string]bool) {
+	if s == nil {
+		return
+	}
+
+	for k := range s.ExtraProperties {
+		if !ajvKnownExtraKeywords[k] {
+			c.add(ptr, k, "unknown keyword %s", k)
+		}
+	}
+
+	types := c.checkTypes(s, ptr, context)
+	c.checkKeywords(s, ptr, types)
+
+	if len(s.Properties) > 0 {
+		d := make(map[string]bool, len(defined)+len(s.Properties))
+
+		for k := range defined {
+			d[k] = true
+		}
+
+		for k := range s.Properties {
+			d[k] = true
+		}
+
+		defined = d
+	}
+
+	for _, name := range s.Required {
+		if !defined[name] {
+			c.add(ptr, "required", "required property %q is not defined", name)
+		}
+	}
+
+	c.checkArray(s, ptr, types)
+
+	if s.Then != nil && s.If == nil {
+		c.add(ptr, "then", "then without if is ignored")
+	}
+
+	if s.Else != nil && s.If == nil {
+		c.add(ptr, "else", "else without if is ignored")
+	}
+
+	if s.If != nil && s.Then == nil && s.Else == nil {
+		c.add(ptr, "if", "if without then and else is ignored")
+	}
+
+	c.subschemas(s, ptr, types, defined)
+}
+
+// checkTypes validates type keyword and returns types of instance.
+func (c *ajvChecker) checkTypes(s *Schema, ptr string, context []SimpleType) []SimpleType {
+	types := schemaTypes(s)
+	if len(types) == 0 {
+		return context
+	}
+
+	nonNull := 0
+
+	for _, t := range types {
+		if t != Null {
+			nonNull++
+		}
+	}
+
+	if nonNull > 1 {
+		c.add(ptr, "type", "union type %s is not allowed, use anyOf", jsonString(types))
+	}
+
+	if len(context) == 0 {
+		return types
+	}
+
+	var narrowed []SimpleType
+
+	for _, t := range types {
+		if includesType(context, t) {
+			narrowed = append(narrowed, t)
+		} else {
+			c.add(ptr, "type", "type %s is not allowed by context %s", t, jsonString(context))
+		}
+	}
+
+	return narrowed
+}
+
+func (c *ajvChecker) checkKeywords(s *Schema, ptr string, types []SimpleType) {
+	for _, k := range typeKeywords(s) {
+		if !keywordApplies(k, types) {
+			kt := ajvKeywordTypes[k]
+			names := make([]string, 0, len(kt))
+
+			for _, t := range kt {
+				names = append(names, string(t))
+			}
+
+			c.add(ptr, k, "missing type %s for keyword %s", strings.Join(names, ","), k)
+		}
+	}
+}
+
+func (c *ajvChecker) checkArray(s *Schema, ptr string, types []SimpleType) {
+	if s.Items != nil && s.Items.SchemaArray != nil {
+		n := int64(len(s.Items.SchemaArray))
+		closed := s.AdditionalItems != nil && s.AdditionalItems.TypeBoolean != nil && !*s.AdditionalItems.TypeBoolean
+
+		if s.MinItems != n || ((s.MaxItems == nil || *s.MaxItems != n) && !closed) {
+			c.add(ptr, "items", "items is a tuple of %d items, minItems and maxItems or additionalItems:false "+
+				"must match its size", n)
+		}
+	} else if s.AdditionalItems != nil {
+		c.add(ptr, "additionalItems", "additionalItems is ignored when items is not an array of schemas")
+	}
+
+	if s.Items == nil && len(schemaTypes(s)) > 0 && hasApplicableType(types, Array) {
+		if _, ok := s.ExtraProperties["prefixItems"]; !ok {
+			c.add(ptr, "items", "array without items schema")
+		}
+	}
+}
+
+func (c *ajvChecker) subschemas(s *Schema, ptr string, types []SimpleType, defined map[string]bool) {
+	// Subschemas of the same instance location inherit types and defined properties.
+	inPlace := func(sb *SchemaOrBool, p string) {
+		if sb != nil {
+			c.check(sb.TypeObject, p, types, defined)
+		}
+	}
+
+	// Subschemas of other instance locations are checked without context.
+	nested := func(sb *SchemaOrBool, p string) {
+		if sb != nil {
+			c.check(sb.TypeObject, p, nil, nil)
+		}
+	}
+
+	list := func(l []SchemaOrBool, p string, check func(sb *SchemaOrBool, p string)) {
+		for i := range l {
+			check(&l[i], p+"/"+fmt.Sprint(i))
+		}
+	}
+
+	dict := func(m map[string]SchemaOrBool, p string) {
+		for k, v := range m {
+			v := v
+			nested(&v, p+"/"+escapePointerToken(k))
+		}
+	}
+
+	dict(s.Definitions, ptr+"/definitions")
+	dict(s.Properties, ptr+"/properties")
+	dict(s.PatternProperties, ptr+"/patternProperties")
+	nested(s.AdditionalProperties, ptr+"/additionalProperties")
+	nested(s.PropertyNames, ptr+"/propertyNames")
+
+	if s.Items != nil {
+		nested(s.Items.SchemaOrBool, ptr+"/items")
+		list(s.Items.SchemaArray, ptr+"/items", nested)
+	}
+
+	nested(s.AdditionalItems, ptr+"/additionalItems")
+	nested(s.Contains, ptr+"/contains")
+
+	for k, d := range s.Dependencies {
+		inPlace(d.SchemaOrBool, ptr+"/dependencies/"+escapePointerToken(k))
+	}
+
+	inPlace(s.If, ptr+"/if")
+	inPlace(s.Then, ptr+"/then")
+	inPlace(s.Else, ptr+"/else")
+	list(s.AllOf, ptr+"/allOf", inPlace)
+	list(s.AnyOf, ptr+"/anyOf", inPlace)
+	list(s.OneOf, ptr+"/oneOf", inPlace)
+	inPlace(s.Not, ptr+"/not")
+}
+
+// applyAJVStrict converts reflected schema and definitions for AJV strict mode and checks remaining violations.
+func (rc *ReflectContext) applyAJVStrict(schema *Schema) error {
+	toAJVStrict(schema, rc.getDefinition)
+
+	problems := CheckAJVStrict(*schema)
+
+	for _, typeString := range rc.definitionTypes() {
+		def := rc.definitions[typeString]
+		toAJVStrict(def, rc.getDefinition)
+
+		ptr := strings.TrimPrefix(rc.DefinitionsPrefix, "#") + escapePointerToken(rc.definitionRefs[typeString].Name)
+
+		for _, p := range CheckAJVStrict(*def) {
+			p.Pointer = ptr + p.Pointer
+			problems = append(problems, p)
+		}
+	}
+
+	if len(problems) > 0 {
+		return problems
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/jsonschema-go"
+)
+
+func TestAJVStrict(t *testing.T) {
+	type Item struct {
+		Name string `json:"name" minLength:"1"`
+	}
+
+	type Order struct {
+		ID    string         `json:"id" types:"string,integer" minLength:"1" minimum:"1"`
+		Note  *string        `json:"note" maxLength:"10"`
+		Items []Item         `json:"items" itemsMinProperties:"1"`
+		Tags  map[string]int `json:"tags"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(Order{}, jsonschema.AJVStrict, jsonschema.InterceptProp(
+		func(params jsonschema.InterceptPropParams) error {
+			if params.Processed {
+				params.PropertySchema.WithExtraPropertiesItem("x-internal", true)
+			}
+
+			return nil
+		}))
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "definitions":{
+		"JsonschemaGoTestItem":{"properties":{"name":{"minLength":1,"type":"string"}},"type":"object"}
+	  },
+	  "properties":{
+		"id":{"anyOf":[{"minLength":1,"type":"string"},{"minimum":1,"type":"integer"}]},
+		"items":{
+		  "items":{"minProperties":1,"type":"object","allOf":[{"$ref":"#/definitions/JsonschemaGoTestItem"}]},
+		  "type":["array","null"]
+		},
+		"note":{"maxLength":10,"type":["null","string"]},
+		"tags":{"additionalProperties":{"type":"integer"},"type":["object","null"]}
+	  },
+	  "type":"object"
+	}`, s)
+
+	assert.Empty(t, jsonschema.CheckAJVStrict(s))
+
+	type Invalid struct {
+		Any interface{} `json:"any" minLength:"1"`
+	}
+
+	_, err = r.Reflect(Invalid{}, jsonschema.AJVStrict)
+	assert.EqualError(t, err, "AJV strict mode violations: /properties/any: missing type string for keyword minLength")
+}
+
+func TestCheckAJVStrict(t *testing.T) {
+	s := parseSchema(t, `{
+	  "type":"object",
+	  "x-go-type":"Order",
+	  "required":["id","missing"],
+	  "properties":{
+		"id":{"type":["string","integer"]},
+		"pair":{"type":"array","items":[{"type":"string"},{"type":"integer"}]},
+		"list":{"type":"array"},
+		"flag":{"then":{"type":"boolean"}},
+		"extra":{"type":"array","items":{"type":"string"},"additionalItems":false},
+		"point":{
+		  "type":"object",
+		  "allOf":[{"properties":{"x":{"type":"number"}},"required":["x"]},{"type":"string"}]
+		}
+	  }
+	}`)
+
+	var msgs []string
+	for _, p := range jsonschema.CheckAJVStrict(s) {
+		msgs = append(msgs, p.Error())
+	}
+
+	assert.Equal(t, []string{
+		`/: required property "missing" is not defined`,
+		"/: unknown keyword x-go-type",
+		"/properties/extra: additionalItems is ignored when items is not an array of schemas",
+		"/properties/flag: then without if is ignored",
+		`/properties/id: union type ["string","integer"] is not allowed, use anyOf`,
+		"/properties/list: array without items schema",
+		"/properties/pair: items is a tuple of 2 items, minItems and maxItems or additionalItems:false must match its size",
+		`/properties/point/allOf/1: type string is not allowed by context ["object"]`,
+	}, msgs)
+
+	assert.Empty(t, jsonschema.CheckAJVStrict(parseSchema(t, `{
+	  "type":["object","null"],
+	  "properties":{
+		"pair":{"type":"array","items":[{"type":"string"},{"type":"integer"}],"minItems":2,"additionalItems":false},
+		"size":{"type":"integer","minimum":0,"format":"int32"}
+	  },
+	  "allOf":[{"required":["size"]},{"if":{"required":["pair"]},"then":{"minProperties":2}}]
+	}`)))
+}
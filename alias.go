@@ -0,0 +1,126 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// PropertyAliases maps old property names to new ones during a rolling migration of an object schema.
+type PropertyAliases map[string]string
+
+// Schema returns a transitional copy of object schema that uses new property names.
+//
+// Every old name becomes a deprecated property with the schema of a new one, an object can have either
+// of names but not both, required new property is satisfied by either of names, e.g. for {"name": "full_name"}:
+//
+//	"allOf":[
+//	  {"anyOf":[{"required":["full_name"]},{"required":["name"]}]},
+//	  {"not":{"required":["name","full_name"]}}
+//	]
+func (a PropertyAliases) Schema(s Schema) Schema {
+	s = cloneSchema(s)
+
+	required := make(map[string]bool, len(s.Required))
+	for _, name := range s.Required {
+		required[name] = true
+	}
+
+	for _, oldName := range a.oldNames() {
+		newName := a[oldName]
+
+		prop, ok := s.Properties[newName]
+		if !ok {
+			continue
+		}
+
+		alias := cloneSchemaOrBool(prop)
+		if alias.TypeObject != nil {
+			alias.TypeObject.WithExtraPropertiesItem("deprecated", true)
+		}
+
+		s.WithPropertiesItem(oldName, alias)
+
+		if required[newName] {
+			delete(required, newName)
+
+			either := Schema{}
+			either.WithAnyOf(
+				(&Schema{}).WithRequired(newName).ToSchemaOrBool(),
+				(&Schema{}).WithRequired(oldName).ToSchemaOrBool(),
+			)
+
+			s.AllOf = append(s.AllOf, either.ToSchemaOrBool())
+		}
+
+		notBoth := Schema{}
+		notBoth.WithNot((&Schema{}).WithRequired(oldName, newName).ToSchemaOrBool())
+
+		s.AllOf = append(s.AllOf, notBoth.ToSchemaOrBool())
+	}
+
+	var res []string
+
+	for _, name := range s.Required {
+		if required[name] {
+			res = append(res, name)
+		}
+	}
+
+	s.Required = res
+
+	return s
+}
+
+// Normalize returns a copy of object instance with old property names replaced by new ones.
+//
+// If both names are present, value of new name is kept.
+func (a PropertyAliases) Normalize(instance map[string]interface{}) map[string]interface{} {
+	res := make(map[string]interface{}, len(instance))
+
+	for k, v := range instance {
+		res[k] = v
+	}
+
+	for oldName, newName := range a {
+		v, ok := res[oldName]
+		if !ok {
+			continue
+		}
+
+		delete(res, oldName)
+
+		if _, ok := res[newName]; !ok {
+			res[newName] = v
+		}
+	}
+
+	return res
+}
+
+// NormalizeJSON replaces old property names with new ones in JSON object.
+func (a PropertyAliases) NormalizeJSON(data []byte) ([]byte, error) {
+	var instance map[string]json.RawMessage
+
+	if err := json.Unmarshal(data, &instance); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON object: %w", err)
+	}
+
+	m := make(map[string]interface{}, len(instance))
+	for k, v := range instance {
+		m[k] = v
+	}
+
+	return json.Marshal(a.Normalize(m))
+}
+
+func (a PropertyAliases) oldNames() []string {
+	names := make([]string, 0, len(a))
+	for name := range a {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
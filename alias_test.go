@@ -0,0 +1,53 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/jsonschema-go"
+)
+
+func TestPropertyAliases(t *testing.T) {
+	type User struct {
+		FullName string `json:"full_name" required:"true"`
+		Email    string `json:"email"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(User{})
+	require.NoError(t, err)
+
+	aliases := jsonschema.PropertyAliases{"name": "full_name", "mail": "email"}
+	ts := aliases.Schema(s)
+
+	assertjson.EqMarshal(t, `{
+	  "properties":{
+		"email":{"type":"string"},"full_name":{"type":"string"},
+		"mail":{"type":"string","deprecated":true},"name":{"type":"string","deprecated":true}
+	  },
+	  "type":"object",
+	  "allOf":[
+		{"not":{"required":["mail","email"]}},
+		{"anyOf":[{"required":["full_name"]},{"required":["name"]}]},
+		{"not":{"required":["name","full_name"]}}
+	  ]
+	}`, ts)
+
+	v := jsonschema.NewValidator(ts)
+	assert.NoError(t, v.ValidateJSON([]byte(`{"name":"Jane"}`)))
+	assert.NoError(t, v.ValidateJSON([]byte(`{"full_name":"Jane","mail":"jane@example.com"}`)))
+	assert.Error(t, v.ValidateJSON([]byte(`{"name":"Jane","full_name":"Jane"}`)))
+	assert.Error(t, v.ValidateJSON([]byte(`{"email":"jane@example.com"}`)))
+
+	assert.Equal(t, map[string]interface{}{"full_name": "Jane", "email": "jane@example.com"},
+		aliases.Normalize(map[string]interface{}{"name": "Jane", "email": "jane@example.com"}))
+
+	j, err := aliases.NormalizeJSON([]byte(`{"name":"Jane","mail":"jane@example.com"}`))
+	require.NoError(t, err)
+	assert.Equal(t, `{"email":"jane@example.com","full_name":"Jane"}`, string(j))
+
+	assert.NoError(t, jsonschema.NewValidator(s).ValidateJSON(j))
+}
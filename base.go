@@ -0,0 +1,122 @@
+package jsonschema
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/swaggest/refl"
+)
+
+type baseSchema struct {
+	match  func(t reflect.Type) bool
+	sample interface{}
+	ref    string
+}
+
+// SetBaseSchemaFor makes schemas of struct types that embed sample type extend its schema, e.g.
+//
+//	type Pet struct { Name string `json:"name"` }
+//	type Dog struct {
+//		Pet
+//		Breed string `json:"breed"`
+//	}
+//
+// With r.SetBaseSchemaFor(Pet{}, "") Dog schema becomes
+// `{"allOf":[{"$ref":"#/definitions/Pet"},{"type":"object","properties":{"breed":{...}}}]}`
+// instead of flat object with properties of Pet, so that inheritance hierarchy is modeled consistently.
+//
+// If baseRef is empty, sample is reflected as a definition, otherwise baseRef is used as is,
+// e.g. to refer to a schema that is maintained elsewhere.
+func (r *Reflector) SetBaseSchemaFor(sample interface{}, baseRef string) {
+	t := refl.DeepIndirect(reflect.TypeOf(sample))
+
+	r.baseSchemas = append(r.baseSchemas, baseSchema{
+		match: func(st reflect.Type) bool {
+			return embedsType(st, t)
+		},
+		sample: sample,
+		ref:    baseRef,
+	})
+
+	if r.baseTypes == nil {
+		r.baseTypes = map[reflect.Type]bool{}
+	}
+
+	r.baseTypes[t] = true
+}
+
+// SetBaseSchemaMatching makes schemas of struct types accepted by match extend baseRef with `allOf`,
+// as in SetBaseSchemaFor.
+func (r *Reflector) SetBaseSchemaMatching(match func(t reflect.Type) bool, baseRef string) {
+	r.baseSchemas = append(r.baseSchemas, baseSchema{
+		match: match,
+		ref:   baseRef,
+	})
+}
+
+// extendBaseSchema moves own keywords of object schema into `allOf` after reference to its base schema.
+func (r *Reflector) extendBaseSchema(t reflect.Type, schema *Schema, rc *ReflectContext) error {
+	if t.Kind() != reflect.Struct || r.baseTypes[t] {
+		return nil
+	}
+
+	for _, b := range r.baseSchemas {
+		if !b.match(t) {
+			continue
+		}
+
+		base := Schema{}
+		base.WithRef(b.ref)
+
+		if b.ref == "" {
+			rc.enterPath("allOf", "", "allOf/0")
+
+			s, err := r.reflect(b.sample, rc, false, schema)
+			if err != nil {
+				return err
+			}
+
+			base = s
+		}
+
+		own := Schema{
+			Type:                 schema.Type,
+			Properties:           schema.Properties,
+			Required:             schema.Required,
+			AdditionalProperties: schema.AdditionalProperties,
+		}
+
+		schema.Type = nil
+		schema.Properties = nil
+		schema.Required = nil
+		schema.AdditionalProperties = nil
+
+		schema.AllOf = append([]SchemaOrBool{base.ToSchemaOrBool(), own.ToSchemaOrBool()}, schema.AllOf...)
+
+		return nil
+	}
+
+	return nil
+}
+
+// embedsType checks if struct type has embedded field of type t, including fields of flattened embedded structs.
+func embedsType(st, t reflect.Type) bool {
+	for i := 0; i < st.NumField(); i++ {
+		f := st.Field(i)
+		if !f.Anonymous || strings.Split(f.Tag.Get("json"), ",")[0] != "" {
+			continue
+		}
+
+		ft := refl.DeepIndirect(f.Type)
+		if ft == t || (ft.Kind() == reflect.Struct && embedsType(ft, t)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isBaseType checks if embedded field type is a base schema that is referenced instead of flattening.
+func (r *Reflector) isBaseType(t reflect.Type) bool {
+	return r.baseTypes[refl.DeepIndirect(t)]
+}
@@ -0,0 +1,68 @@
+package jsonschema_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/jsonschema-go"
+)
+
+type basePet struct {
+	Name string `json:"name" required:"true"`
+}
+
+type baseDog struct {
+	basePet
+	Breed string `json:"breed"`
+}
+
+type basePuppy struct {
+	*baseDog
+	Age int `json:"age"`
+}
+
+type baseCat struct {
+	Lives int `json:"lives"`
+}
+
+func TestReflector_SetBaseSchemaFor(t *testing.T) {
+	r := jsonschema.Reflector{}
+	r.SetBaseSchemaFor(basePet{}, "")
+
+	s, err := r.Reflect(basePuppy{}, jsonschema.StripDefinitionNamePrefix("JsonschemaGoTestBase"))
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "definitions":{
+		"Pet":{"required":["name"],"properties":{"name":{"type":"string"}},"type":"object"}
+	  },
+	  "allOf":[
+		{"$ref":"#/definitions/Pet"},
+		{"properties":{"age":{"type":"integer"},"breed":{"type":"string"}},"type":"object"}
+	  ]
+	}`, s)
+
+	s, err = r.Reflect(basePet{}, jsonschema.StripDefinitionNamePrefix("JsonschemaGoTestBase"))
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{"required":["name"],"properties":{"name":{"type":"string"}},"type":"object"}`, s)
+}
+
+func TestReflector_SetBaseSchemaMatching(t *testing.T) {
+	r := jsonschema.Reflector{}
+	r.SetBaseSchemaMatching(func(t reflect.Type) bool {
+		return t == reflect.TypeOf(baseCat{})
+	}, "#/components/schemas/Animal")
+
+	s, err := r.Reflect(baseCat{})
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "allOf":[
+		{"$ref":"#/components/schemas/Animal"},
+		{"properties":{"lives":{"type":"integer"}},"type":"object"}
+	  ]
+	}`, s)
+}
@@ -0,0 +1,230 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Bundle returns a copy of schema with references to definitions replaced by their targets,
+// so that every subschema is self-contained.
+//
+// Unlike Dereference, recursive references are kept and definitions that they refer to are kept
+// (with their own references inlined), so that the result is equivalent to the original schema.
+func (s Schema) Bundle() (Schema, error) {
+	kept := map[string]bool{}
+	keep := func(ref string, _ bool) SchemaOrBool {
+		kept[ref] = true
+
+		return (&Schema{}).WithRef(ref).ToSchemaOrBool()
+	}
+
+	d := dereferencer{document: NewDocument(s)}
+	d.Stop = keep
+
+	root := cloneSchema(s)
+	root.Definitions = nil
+
+	defs := extraDefinitions(root.ExtraProperties["$defs"])
+	delete(root.ExtraProperties, "$defs")
+
+	if len(root.ExtraProperties) == 0 {
+		root.ExtraProperties = nil
+	}
+
+	res, err := d.schema(root.ToSchemaOrBool(), nil)
+	if err != nil {
+		return s, err
+	}
+
+	bundled := *res.TypeObject
+	done := map[string]bool{}
+
+	for len(done) < len(kept) {
+		for ref := range kept {
+			if done[ref] {
+				continue
+			}
+
+			done[ref] = true
+
+			var name string
+
+			switch {
+			case strings.HasPrefix(ref, "#/definitions/"):
+				name = strings.TrimPrefix(ref, "#/definitions/")
+			case strings.HasPrefix(ref, "#/$defs/") && defs != nil:
+				name = strings.TrimPrefix(ref, "#/$defs/")
+			default:
+				// Other targets (e.g. root schema) stay in place.
+				continue
+			}
+
+			target, ok := d.resolve(ref)
+			if !ok {
+				continue
+			}
+
+			target, err := d.schema(cloneSchemaOrBool(target), []string{ref})
+			if err != nil {
+				return s, err
+			}
+
+			if strings.HasPrefix(ref, "#/definitions/") {
+				if bundled.Definitions == nil {
+					bundled.Definitions = map[string]SchemaOrBool{}
+				}
+
+				bundled.Definitions[name] = target
+			} else {
+				bundledDefs, _ := bundled.ExtraProperties["$defs"].(map[string]SchemaOrBool)
+				if bundledDefs == nil {
+					bundledDefs = map[string]SchemaOrBool{}
+					bundled.WithExtraPropertiesItem("$defs", bundledDefs)
+				}
+
+				bundledDefs[name] = target
+			}
+		}
+	}
+
+	return bundled, nil
+}
+
+// Flatten returns a copy of schema with inline object schemas (that have properties) moved to definitions
+// and replaced with references, e.g. for code generators that need named types.
+//
+// Names of new definitions are made of prefix (or name of enclosing definition) and CamelCase path of subschema,
+// e.g. "OrderAddress" for `properties/address` of root with "Order" prefix or "OrderItemsItem" for
+// `properties/items/items` of "Order" definition. Identical schemas share a definition.
+// Definitions are added to `$defs` if schema has them, or to `definitions` otherwise.
+func (s Schema) Flatten(prefix string) Schema {
+	root := cloneSchema(s)
+
+	f := flattener{
+		names:  map[string]bool{},
+		shared: map[string]string{},
+		defs:   root.Definitions,
+		refPfx: "#/definitions/",
+	}
+
+	if v, ok := root.ExtraProperties["$defs"]; ok {
+		f.defs = extraDefinitions(v)
+		f.refPfx = "#/$defs/"
+		root.ExtraProperties["$defs"] = f.defs
+	}
+
+	if f.defs == nil {
+		f.defs = map[string]SchemaOrBool{}
+	}
+
+	for name := range f.defs {
+		f.names[name] = true
+	}
+
+	f.walk(&root, prefix)
+
+	names := make([]string, 0, len(f.defs))
+	for name := range f.defs {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		if d := f.defs[name].TypeObject; d != nil && !f.hoisted[name] {
+			f.walk(d, name)
+		}
+	}
+
+	if len(f.defs) > 0 {
+		if f.refPfx == "#/$defs/" {
+			root.ExtraProperties["$defs"] = f.defs
+		} else {
+			root.Definitions = f.defs
+		}
+	}
+
+	return root
+}
+
+type flattener struct {
+	names   map[string]bool
+	shared  map[string]string // JSON of hoisted schema to definition name
+	hoisted map[string]bool
+	defs    map[string]SchemaOrBool
+	refPfx  string
+}
+
+func (f *flattener) walk(s *Schema, base string) {
+	for _, sub := range subschemas(s) {
+		obj := sub.schema.TypeObject
+		if sub.definition || obj == nil {
+			continue
+		}
+
+		name := base + flatName(sub.ptr)
+
+		if obj.Ref != nil || len(obj.Properties) == 0 {
+			f.walk(obj, name)
+
+			continue
+		}
+
+		def := *obj
+
+		f.walk(&def, name)
+
+		key := ""
+		if j, err := json.Marshal(def); err == nil {
+			key = string(j)
+		}
+
+		if existing, ok := f.shared[key]; ok && key != "" {
+			*obj = *(&Schema{}).WithRef(f.refPfx + existing)
+
+			continue
+		}
+
+		unique := name
+		for i := 2; f.names[unique]; i++ {
+			unique = name + strconv.Itoa(i)
+		}
+
+		f.names[unique] = true
+		f.shared[key] = unique
+
+		if f.hoisted == nil {
+			f.hoisted = map[string]bool{}
+		}
+
+		f.hoisted[unique] = true
+		f.defs[unique] = def.ToSchemaOrBool()
+
+		*obj = *(&Schema{}).WithRef(f.refPfx + unique)
+	}
+}
+
+// flatName makes CamelCase name of subschema pointer relative to parent, e.g. "Address" for "/properties/address".
+func flatName(ptr string) string {
+	tokens := strings.Split(strings.TrimPrefix(ptr, "/"), "/")
+
+	keyword := tokens[0]
+	key := ""
+
+	if len(tokens) > 1 {
+		key = strings.ReplaceAll(strings.ReplaceAll(tokens[1], "~1", "/"), "~0", "~")
+	}
+
+	switch keyword {
+	case "properties", "dependencies":
+		return toCamel(key)
+	case "patternProperties", "additionalProperties":
+		return "Value"
+	case "items":
+		return "Item" + key
+	default:
+		return toCamel(keyword) + key
+	}
+}
@@ -0,0 +1,106 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/jsonschema-go"
+)
+
+func TestSchema_Bundle(t *testing.T) {
+	var s jsonschema.Schema
+
+	require.NoError(t, s.UnmarshalJSON([]byte(`{
+	  "definitions":{
+		"Address":{"type":"object","properties":{"city":{"type":"string"}}},
+		"Node":{"type":"object","properties":{"address":{"$ref":"#/definitions/Address"},"next":{"$ref":"#/definitions/Node"}}},
+		"Unused":{"type":"string"}
+	  },
+	  "type":"object",
+	  "properties":{
+		"home":{"$ref":"#/definitions/Address"},
+		"head":{"$ref":"#/definitions/Node"}
+	  }
+	}`)))
+
+	b, err := s.Bundle()
+	require.NoError(t, err)
+	assertjson.EqMarshal(t, `{
+	  "definitions":{
+		"Node":{
+		  "properties":{
+			"address":{"properties":{"city":{"type":"string"}},"type":"object"},
+			"next":{"$ref":"#/definitions/Node"}
+		  },
+		  "type":"object"
+		}
+	  },
+	  "properties":{
+		"head":{
+		  "properties":{
+			"address":{"properties":{"city":{"type":"string"}},"type":"object"},
+			"next":{"$ref":"#/definitions/Node"}
+		  },
+		  "type":"object"
+		},
+		"home":{"properties":{"city":{"type":"string"}},"type":"object"}
+	  },
+	  "type":"object"
+	}`, b)
+}
+
+func TestSchema_Flatten(t *testing.T) {
+	var s jsonschema.Schema
+
+	require.NoError(t, s.UnmarshalJSON([]byte(`{
+	  "definitions":{
+		"Customer":{"type":"object","properties":{"address":{"type":"object","properties":{"city":{"type":"string"}}}}}
+	  },
+	  "type":"object",
+	  "properties":{
+		"customer":{"$ref":"#/definitions/Customer"},
+		"shipping":{"type":"object","properties":{"city":{"type":"string"}}},
+		"lines":{
+		  "type":"array",
+		  "items":{"type":"object","properties":{"sku":{"type":"string"},"meta":{"type":"object","properties":{"a":{}}}}}
+		},
+		"tags":{"type":"array","items":{"type":"string"}}
+	  }
+	}`)))
+
+	f := s.Flatten("Order")
+	assertjson.EqMarshal(t, `{
+	  "definitions":{
+		"Customer":{"properties":{"address":{"$ref":"#/definitions/OrderShipping"}},"type":"object"},
+		"OrderLinesItem":{
+		  "properties":{"meta":{"$ref":"#/definitions/OrderLinesItemMeta"},"sku":{"type":"string"}},
+		  "type":"object"
+		},
+		"OrderLinesItemMeta":{"properties":{"a":{}},"type":"object"},
+		"OrderShipping":{"properties":{"city":{"type":"string"}},"type":"object"}
+	  },
+	  "properties":{
+		"customer":{"$ref":"#/definitions/Customer"},
+		"lines":{"items":{"$ref":"#/definitions/OrderLinesItem"},"type":"array"},
+		"shipping":{"$ref":"#/definitions/OrderShipping"},
+		"tags":{"items":{"type":"string"},"type":"array"}
+	  },
+	  "type":"object"
+	}`, f)
+
+	// Original schema is not changed.
+	require.Nil(t, s.Properties["shipping"].TypeObject.Ref)
+
+	// Bundle reverts Flatten.
+	b, err := f.Bundle()
+	require.NoError(t, err)
+
+	expected, err := s.Bundle()
+	require.NoError(t, err)
+
+	ej, err := json.Marshal(expected)
+	require.NoError(t, err)
+	assertjson.EqMarshal(t, string(ej), b)
+}
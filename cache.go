@@ -0,0 +1,155 @@
+package jsonschema
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ResultCache is an LRU cache of validation results keyed by hashes of schema and instance.
+//
+// It is useful for idempotent re-validation, e.g. of replayed messages or retried requests.
+// Cache can be shared by validators and is safe for concurrent use, validators of equal schemas share results,
+// so they should have same reference resolvers and formats.
+type ResultCache struct {
+	// OnHit is called on cache hit, can be nil.
+	OnHit func()
+
+	// OnMiss is called on cache miss, can be nil.
+	OnMiss func()
+
+	// OnEvict is called when the least recently used result is evicted, can be nil.
+	OnEvict func()
+
+	mu    sync.Mutex
+	size  int
+	ll    *list.List
+	items map[resultKey]*list.Element
+	stats CacheStats
+}
+
+// CacheStats describes usage of ResultCache.
+type CacheStats struct {
+	Hits      int64
+	Misses    int64
+	Evictions int64
+	Len       int
+}
+
+type resultKey struct {
+	schema   [sha256.Size]byte
+	instance [sha256.Size]byte
+}
+
+type resultEntry struct {
+	key resultKey
+	err error
+}
+
+// NewResultCache creates cache that keeps up to size results.
+func NewResultCache(size int) *ResultCache {
+	if size < 1 {
+		size = 1
+	}
+
+	return &ResultCache{
+		size:  size,
+		ll:    list.New(),
+		items: make(map[resultKey]*list.Element, size),
+	}
+}
+
+// Stats returns cache usage counters.
+func (c *ResultCache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := c.stats
+	s.Len = c.ll.Len()
+
+	return s
+}
+
+func (c *ResultCache) get(k resultKey) (error, bool) { //nolint:revive // Error is a cached value here.
+	c.mu.Lock()
+
+	el, ok := c.items[k]
+	if ok {
+		c.ll.MoveToFront(el)
+		c.stats.Hits++
+	} else {
+		c.stats.Misses++
+	}
+
+	c.mu.Unlock()
+
+	if ok {
+		if c.OnHit != nil {
+			c.OnHit()
+		}
+
+		return el.Value.(*resultEntry).err, true //nolint:forcetypeassert // List only has entries.
+	}
+
+	if c.OnMiss != nil {
+		c.OnMiss()
+	}
+
+	return nil, false
+}
+
+func (c *ResultCache) put(k resultKey, err error) {
+	c.mu.Lock()
+
+	if el, ok := c.items[k]; ok {
+		c.ll.MoveToFront(el)
+		c.mu.Unlock()
+
+		return
+	}
+
+	c.items[k] = c.ll.PushFront(&resultEntry{key: k, err: err})
+
+	evicted := false
+
+	if c.ll.Len() > c.size {
+		last := c.ll.Back()
+		c.ll.Remove(last)
+		delete(c.items, last.Value.(*resultEntry).key) //nolint:forcetypeassert // List only has entries.
+		c.stats.Evictions++
+
+		evicted = true
+	}
+
+	c.mu.Unlock()
+
+	if evicted && c.OnEvict != nil {
+		c.OnEvict()
+	}
+}
+
+// schemaHash returns hash of validator schema and settings, it is computed once.
+func (v *Validator) schemaHash() [sha256.Size]byte {
+	v.hashOnce.Do(func() {
+		j, err := json.Marshal(v.root)
+		if err != nil {
+			// Unique value disables sharing of results with other validators.
+			j = []byte(fmt.Sprintf("%p", v))
+		}
+
+		j = append(j, 0)
+		j = append(j, strings.Join(v.SensitiveKeywords, ",")...)
+
+		v.hash = sha256.Sum256(j)
+	})
+
+	return v.hash
+}
+
+// cacheKey returns cache key of JSON instance.
+func (v *Validator) cacheKey(instance []byte) resultKey {
+	return resultKey{schema: v.schemaHash(), instance: sha256.Sum256(instance)}
+}
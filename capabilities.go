@@ -0,0 +1,22 @@
+package jsonschema
+
+// Capabilities describes optional features that are available in current build.
+//
+// Build tags `tinygo` and `jsonschema_lite` exclude features that depend on Go toolchain packages,
+// so that reflection and validation can be compiled to WebAssembly, e.g. for in-browser form validation.
+type Capabilities struct {
+	// SourceIndex is true if SourceIndex parses Go sources to provide SourcePositions,
+	// source positions of field errors and EnumsFromConstants.
+	SourceIndex bool
+
+	// HTTPLoader is true if HTTPLoader is available to load documents of Resolver with net/http.
+	HTTPLoader bool
+}
+
+// BuildCapabilities returns capabilities of current build.
+func BuildCapabilities() Capabilities {
+	return Capabilities{
+		SourceIndex: sourceIndexAvailable,
+		HTTPLoader:  httpLoaderAvailable,
+	}
+}
@@ -0,0 +1,90 @@
+// Command jsonschema-taglint reports malformed and contradictory JSON Schema struct field tags.
+//
+// Usage:
+//
+//	jsonschema-taglint [-json] [dir ...]
+//
+// Directory with "/..." suffix is checked recursively, current directory is checked by default.
+// Exit code is 1 if any problems are found.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/swaggest/jsonschema-go/taglint"
+)
+
+func main() {
+	asJSON := flag.Bool("json", false, "print findings as JSON")
+	flag.Parse()
+
+	dirs := flag.Args()
+	if len(dirs) == 0 {
+		dirs = []string{"."}
+	}
+
+	var findings []taglint.Finding
+
+	for _, dir := range dirs {
+		f, err := check(dir)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+
+		findings = append(findings, f...)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+
+		if err := enc.Encode(findings); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+	} else {
+		for _, f := range findings {
+			fmt.Println(f.String())
+		}
+	}
+
+	if len(findings) > 0 {
+		os.Exit(1)
+	}
+}
+
+func check(dir string) ([]taglint.Finding, error) {
+	if !strings.HasSuffix(dir, "/...") {
+		return taglint.CheckDir(dir)
+	}
+
+	var res []taglint.Finding
+
+	err := filepath.WalkDir(strings.TrimSuffix(dir, "/..."), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() {
+			return nil
+		}
+
+		if name := d.Name(); path != "." && (name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".")) {
+			return filepath.SkipDir
+		}
+
+		f, err := taglint.CheckDir(path)
+		res = append(res, f...)
+
+		return err
+	})
+
+	return res, err
+}
@@ -0,0 +1,56 @@
+package jsonschema
+
+import "encoding/json"
+
+// JSONCodec marshals and unmarshals JSON values.
+//
+// Implementation must respect json.Marshaler and json.Unmarshaler, as well as `json` field tags,
+// compatibility modes of jsoniter or go-json are suitable.
+type JSONCodec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+type stdJSONCodec struct{}
+
+func (stdJSONCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (stdJSONCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+var jsonCodec JSONCodec = stdJSONCodec{}
+
+// SetJSONCodec replaces encoding/json in marshaling and unmarshaling of Schema and SchemaOrBool, e.g.
+//
+//	jsonschema.SetJSONCodec(jsoniter.ConfigCompatibleWithStandardLibrary)
+//
+// Nil codec restores encoding/json. Codec is a global setting, it should be set up during initialization
+// before any schema is marshaled.
+func SetJSONCodec(c JSONCodec) {
+	if c == nil {
+		c = stdJSONCodec{}
+	}
+
+	jsonCodec = c
+}
+
+func jsonMarshal(v interface{}) ([]byte, error) {
+	// Schema properties are marshaled in order of PropertiesOrder, if it is set.
+	if ms, ok := v.(marshalSchema); ok && len(ms.PropertiesOrder) > 0 && len(ms.Properties) > 0 {
+		return marshalOrderedProperties(ms)
+	}
+
+	return jsonCodec.Marshal(v)
+}
+
+func jsonUnmarshal(data []byte, v interface{}) error {
+	// Schema accepts boolean exclusive bounds of draft-04 by converting them to numeric form.
+	if ms, ok := v.(*marshalSchema); ok {
+		return unmarshalSchema(data, ms)
+	}
+
+	return jsonCodec.Unmarshal(data, v)
+}
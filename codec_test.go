@@ -0,0 +1,50 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/jsonschema-go"
+)
+
+type countingCodec struct {
+	marshal, unmarshal int
+}
+
+func (c *countingCodec) Marshal(v interface{}) ([]byte, error) {
+	c.marshal++
+
+	return json.Marshal(v)
+}
+
+func (c *countingCodec) Unmarshal(data []byte, v interface{}) error {
+	c.unmarshal++
+
+	return json.Unmarshal(data, v)
+}
+
+func TestSetJSONCodec(t *testing.T) {
+	c := &countingCodec{}
+
+	jsonschema.SetJSONCodec(c)
+	defer jsonschema.SetJSONCodec(nil)
+
+	var s jsonschema.Schema
+
+	require.NoError(t, json.Unmarshal([]byte(`{"type":"object","properties":{"id":{"type":"integer"}},"x-foo":1}`), &s))
+	assert.Positive(t, c.unmarshal)
+
+	j, err := json.Marshal(s)
+	require.NoError(t, err)
+	assert.Equal(t, `{"properties":{"id":{"type":"integer"}},"type":"object","x-foo":1}`, string(j))
+	assert.Positive(t, c.marshal)
+
+	jsonschema.SetJSONCodec(nil)
+
+	c.marshal = 0
+	_, err = json.Marshal(s)
+	require.NoError(t, err)
+	assert.Zero(t, c.marshal)
+}
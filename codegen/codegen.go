@@ -0,0 +1,683 @@
+// Package codegen generates Go types from JSON Schema.
+//
+// Generated types follow conventions of jsonschema.Reflector, so that reflecting them produces
+// an equivalent schema: definitions become named types, optional properties have `omitempty`,
+// required properties have `required:"true"` tag, nullable scalars are pointers and validation
+// keywords are kept in field tags.
+package codegen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/swaggest/jsonschema-go"
+)
+
+// Options configures generation.
+type Options struct {
+	// PackageName is a name of generated package, default "schema".
+	PackageName string
+
+	// RootName is a name of root type, title of root schema or "Root" is used if empty.
+	RootName string
+}
+
+// Generate emits Go source with types of schema and its definitions.
+//
+// References are resolved against definitions of schema by the last segment of reference path, so that
+// "#/definitions/", "#/$defs/" and custom definitions prefixes are supported. Schemas that have no Go
+// counterpart (e.g. `anyOf` of different types) become interface{}.
+func Generate(schema jsonschema.Schema, options ...func(o *Options)) ([]byte, error) {
+	o := Options{PackageName: "schema"}
+
+	for _, option := range options {
+		option(&o)
+	}
+
+	if o.RootName == "" && schema.Title != nil {
+		o.RootName = goName(*schema.Title)
+	}
+
+	if o.RootName == "" {
+		o.RootName = "Root"
+	}
+
+	g := generator{
+		schema:  schema,
+		defs:    map[string]*definition{},
+		used:    map[string]bool{},
+		imports: map[string]bool{},
+	}
+
+	if schema.Definitions != nil {
+		g.definitions = schema.Definitions
+	} else if defs, ok := schema.ExtraProperties["$defs"].(map[string]jsonschema.SchemaOrBool); ok {
+		g.definitions = defs
+	}
+
+	g.root = &definition{goName: g.reserve(o.RootName)}
+	g.root.kind = g.kindOf(schema.ToSchemaOrBool())
+
+	if err := g.declare(g.root.goName, schema.ToSchemaOrBool()); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(g.definitions))
+	for name := range g.definitions {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, err := g.definitionType(name); err != nil {
+			return nil, err
+		}
+	}
+
+	return g.source(o.PackageName)
+}
+
+type kind int
+
+const (
+	kindAny kind = iota
+	kindScalar
+	kindStruct
+	kindSlice
+	kindMap
+)
+
+type definition struct {
+	goName string
+	kind   kind
+}
+
+type generator struct {
+	schema      jsonschema.Schema
+	definitions map[string]jsonschema.SchemaOrBool
+	defs        map[string]*definition
+	root        *definition
+	used        map[string]bool
+	imports     map[string]bool
+	decls       []string
+}
+
+// reserve returns unique type name.
+func (g *generator) reserve(name string) string {
+	res := name
+
+	for i := 2; g.used[res]; i++ {
+		res = name + strconv.Itoa(i)
+	}
+
+	g.used[res] = true
+
+	return res
+}
+
+// definitionType returns type name of definition, declaring it on first use.
+func (g *generator) definitionType(name string) (*definition, error) {
+	if d, ok := g.defs[name]; ok {
+		return d, nil
+	}
+
+	sb, ok := g.definitions[name]
+	if !ok {
+		return nil, fmt.Errorf("definition not found: %s", name)
+	}
+
+	d := &definition{goName: g.reserve(goName(name)), kind: g.kindOf(sb)}
+	g.defs[name] = d
+
+	if err := g.declare(d.goName, sb); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	return d, nil
+}
+
+func (g *generator) resolve(ref string) (*definition, error) {
+	if ref == "#" {
+		return g.root, nil
+	}
+
+	name := ref[strings.LastIndex(ref, "/")+1:]
+	name = strings.ReplaceAll(strings.ReplaceAll(name, "~1", "/"), "~0", "~")
+
+	if !strings.HasPrefix(ref, "#") {
+		return nil, fmt.Errorf("unsupported reference: %s", ref)
+	}
+
+	return g.definitionType(name)
+}
+
+// types returns JSON types of schema excluding null, and nullability.
+func types(s *jsonschema.Schema) ([]jsonschema.SimpleType, bool) {
+	if s.Type == nil {
+		return nil, false
+	}
+
+	var (
+		res      []jsonschema.SimpleType
+		nullable bool
+	)
+
+	all := s.Type.SliceOfSimpleTypeValues
+	if s.Type.SimpleTypes != nil {
+		all = []jsonschema.SimpleType{*s.Type.SimpleTypes}
+	}
+
+	for _, t := range all {
+		if t == jsonschema.Null {
+			nullable = true
+		} else {
+			res = append(res, t)
+		}
+	}
+
+	return res, nullable
+}
+
+func (g *generator) kindOf(sb jsonschema.SchemaOrBool) kind {
+	s := sb.TypeObject
+	if s == nil {
+		return kindAny
+	}
+
+	if s.Ref != nil {
+		if d, err := g.resolve(*s.Ref); err == nil {
+			return d.kind
+		}
+
+		return kindAny
+	}
+
+	tt, _ := types(s)
+
+	switch {
+	case len(tt) > 1:
+		return kindAny
+	case len(tt) == 1 && tt[0] == jsonschema.Array:
+		return kindSlice
+	case len(tt) == 1 && tt[0] != jsonschema.Object:
+		return kindScalar
+	case len(s.Properties) > 0 || len(s.AllOf) > 0:
+		return kindStruct
+	case len(tt) == 1 || s.AdditionalProperties != nil:
+		return kindMap
+	case s.Items != nil:
+		return kindSlice
+	}
+
+	return kindAny
+}
+
+// declare adds type declaration.
+func (g *generator) declare(name string, sb jsonschema.SchemaOrBool) error {
+	var (
+		b strings.Builder
+		s = sb.TypeObject
+	)
+
+	if s == nil {
+		s = &jsonschema.Schema{}
+	}
+
+	// Declaration keeps its position before nested types that are declared while processing schema.
+	idx := len(g.decls)
+	g.decls = append(g.decls, "")
+
+	writeComment(&b, name, s)
+
+	if g.kindOf(sb) == kindStruct && s.Ref == nil {
+		body, err := g.structBody(name, s)
+		if err != nil {
+			return err
+		}
+
+		b.WriteString("type " + name + " struct {\n" + body + "}\n")
+		g.decls[idx] = b.String()
+
+		return nil
+	}
+
+	typ, err := g.goType(sb, name)
+	if err != nil {
+		return err
+	}
+
+	b.WriteString("type " + name + " " + typ + "\n")
+
+	if len(s.Enum) > 0 {
+		enum, err := goLiteral(s.Enum)
+		if err != nil {
+			return err
+		}
+
+		b.WriteString("\n// Enum returns allowed values.\nfunc (" + name + ") Enum() []interface{} {\n" +
+			"return " + enum + "\n}\n")
+	}
+
+	g.decls[idx] = b.String()
+
+	return nil
+}
+
+func writeComment(b *strings.Builder, name string, s *jsonschema.Schema) {
+	text := ""
+
+	switch {
+	case s.Description != nil:
+		text = *s.Description
+	case s.Title != nil:
+		text = *s.Title
+	}
+
+	if text == "" || strings.EqualFold(text, name) {
+		return
+	}
+
+	if !strings.HasPrefix(text, name+" ") {
+		text = name + " is " + lowerFirst(text)
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		b.WriteString(strings.TrimSpace("// "+line) + "\n")
+	}
+}
+
+func lowerFirst(s string) string {
+	r := []rune(s)
+	if len(r) > 1 && unicode.IsUpper(r[1]) {
+		return s
+	}
+
+	r[0] = unicode.ToLower(r[0])
+
+	return string(r)
+}
+
+// structBody returns fields of struct type.
+func (g *generator) structBody(name string, s *jsonschema.Schema) (string, error) {
+	var (
+		b       strings.Builder
+		props   = map[string]jsonschema.SchemaOrBool{}
+		fields  = map[string]bool{}
+		require = map[string]bool{}
+	)
+
+	for _, r := range s.Required {
+		require[r] = true
+	}
+
+	for k, v := range s.Properties {
+		props[k] = v
+	}
+
+	for _, item := range s.AllOf {
+		is := item.TypeObject
+		if is == nil {
+			continue
+		}
+
+		if is.Ref != nil {
+			d, err := g.resolve(*is.Ref)
+			if err != nil {
+				return "", err
+			}
+
+			if d.kind == kindStruct {
+				fields[d.goName] = true
+
+				b.WriteString(d.goName + " `refer:\"true\"`\n")
+
+				continue
+			}
+		}
+
+		for k, v := range is.Properties {
+			props[k] = v
+		}
+
+		for _, r := range is.Required {
+			require[r] = true
+		}
+	}
+
+	names := make([]string, 0, len(props))
+	for n := range props {
+		names = append(names, n)
+	}
+
+	sort.Strings(names)
+
+	for _, prop := range names {
+		field := goName(prop)
+		for i := 2; fields[field]; i++ {
+			field = goName(prop) + strconv.Itoa(i)
+		}
+
+		fields[field] = true
+
+		line, err := g.field(name, field, prop, props[prop], require[prop])
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", prop, err)
+		}
+
+		b.WriteString(line)
+	}
+
+	return b.String(), nil
+}
+
+// field returns declaration of struct field.
+func (g *generator) field(owner, field, prop string, sb jsonschema.SchemaOrBool, required bool) (string, error) {
+	typ, err := g.goType(sb, owner+field)
+	if err != nil {
+		return "", err
+	}
+
+	s := sb.TypeObject
+	if s == nil {
+		s = &jsonschema.Schema{}
+	}
+
+	_, nullable := types(s)
+
+	switch g.kindOf(sb) {
+	case kindStruct:
+		if !required || nullable {
+			typ = "*" + typ
+		}
+	case kindScalar:
+		if nullable {
+			typ = "*" + typ
+		}
+	case kindAny, kindSlice, kindMap:
+	}
+
+	jsonTag := prop
+	if !required {
+		jsonTag += ",omitempty"
+	}
+
+	tags := []string{"json:" + strconv.Quote(jsonTag)}
+
+	if required {
+		tags = append(tags, `required:"true"`)
+	}
+
+	if typ == "time.Time" || typ == "*time.Time" {
+		s = withoutFormat(s)
+	}
+
+	if s.Ref == nil {
+		kt, err := keywordTags(s)
+		if err != nil {
+			return "", err
+		}
+
+		tags = append(tags, kt...)
+	}
+
+	tag := strings.Join(tags, " ")
+	if strings.Contains(tag, "`") {
+		tag = strconv.Quote(tag)
+	} else {
+		tag = "`" + tag + "`"
+	}
+
+	return field + " " + typ + " " + tag + "\n", nil
+}
+
+// withoutFormat returns copy of schema without format, which is implied by type.
+func withoutFormat(s *jsonschema.Schema) *jsonschema.Schema {
+	c := *s
+	c.Format = nil
+
+	return &c
+}
+
+// keywordTags returns field tags of schema keywords that are supported by reflector.
+func keywordTags(s *jsonschema.Schema) ([]string, error) {
+	var tags []string
+
+	str := func(name string, v *string) {
+		if v != nil {
+			tags = append(tags, name+":"+strconv.Quote(*v))
+		}
+	}
+
+	num := func(name string, v *float64) {
+		if v != nil {
+			tags = append(tags, name+":"+strconv.Quote(strconv.FormatFloat(*v, 'g', -1, 64)))
+		}
+	}
+
+	integer := func(name string, v *int64) {
+		if v != nil && *v != 0 {
+			tags = append(tags, name+":"+strconv.Quote(strconv.FormatInt(*v, 10)))
+		}
+	}
+
+	val := func(name string, v interface{}) error {
+		j, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+
+		tags = append(tags, name+":"+strconv.Quote(string(j)))
+
+		return nil
+	}
+
+	str("title", s.Title)
+	str("description", s.Description)
+	str("format", s.Format)
+	str("pattern", s.Pattern)
+	num("multipleOf", s.MultipleOf)
+	num("minimum", s.Minimum)
+	num("exclusiveMinimum", s.ExclusiveMinimum)
+	num("maximum", s.Maximum)
+	num("exclusiveMaximum", s.ExclusiveMaximum)
+	integer("minLength", &s.MinLength)
+	integer("maxLength", s.MaxLength)
+	integer("minItems", &s.MinItems)
+	integer("maxItems", s.MaxItems)
+	integer("minProperties", &s.MinProperties)
+	integer("maxProperties", s.MaxProperties)
+
+	if s.UniqueItems != nil && *s.UniqueItems {
+		tags = append(tags, `uniqueItems:"true"`)
+	}
+
+	if len(s.Enum) > 0 {
+		if err := val("enum", s.Enum); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.Const != nil {
+		if err := val("const", *s.Const); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.Default != nil {
+		if err := val("default", *s.Default); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(s.Examples) > 0 {
+		if err := val("examples", s.Examples); err != nil {
+			return nil, err
+		}
+	}
+
+	return tags, nil
+}
+
+// goType returns Go type of schema, hint is used to name nested struct types.
+func (g *generator) goType(sb jsonschema.SchemaOrBool, hint string) (string, error) {
+	s := sb.TypeObject
+	if s == nil {
+		return "interface{}", nil
+	}
+
+	if s.Ref != nil {
+		d, err := g.resolve(*s.Ref)
+		if err != nil {
+			return "", err
+		}
+
+		return d.goName, nil
+	}
+
+	tt, _ := types(s)
+
+	switch g.kindOf(sb) {
+	case kindAny:
+		return "interface{}", nil
+	case kindStruct:
+		name := g.reserve(hint)
+
+		return name, g.declare(name, sb)
+	case kindMap:
+		if s.AdditionalProperties == nil || s.AdditionalProperties.TypeObject == nil {
+			return "map[string]interface{}", nil
+		}
+
+		elem, err := g.goType(*s.AdditionalProperties, hint+"Value")
+		if err != nil {
+			return "", err
+		}
+
+		return "map[string]" + elem, nil
+	case kindSlice:
+		if s.Items == nil || s.Items.SchemaOrBool == nil {
+			return "[]interface{}", nil
+		}
+
+		elem, err := g.goType(*s.Items.SchemaOrBool, hint+"Item")
+		if err != nil {
+			return "", err
+		}
+
+		if _, nullable := types(s.Items.SchemaOrBool.TypeObject); nullable && g.kindOf(*s.Items.SchemaOrBool) == kindScalar {
+			elem = "*" + elem
+		}
+
+		return "[]" + elem, nil
+	case kindScalar:
+	}
+
+	switch tt[0] {
+	case jsonschema.String:
+		if s.Format != nil && *s.Format == "date-time" {
+			g.imports["time"] = true
+
+			return "time.Time", nil
+		}
+
+		return "string", nil
+	case jsonschema.Integer:
+		return "int64", nil
+	case jsonschema.Number:
+		return "float64", nil
+	case jsonschema.Boolean:
+		return "bool", nil
+	default:
+		return "interface{}", nil
+	}
+}
+
+func (g *generator) source(pkg string) ([]byte, error) {
+	var b bytes.Buffer
+
+	b.WriteString("// Code generated by github.com/swaggest/jsonschema-go/codegen, DO NOT EDIT.\n\n")
+	b.WriteString("package " + pkg + "\n\n")
+
+	if g.imports["time"] {
+		b.WriteString("import \"time\"\n\n")
+	}
+
+	b.WriteString(strings.Join(g.decls, "\n"))
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %w\n%s", err, b.String())
+	}
+
+	return src, nil
+}
+
+// goLiteral returns Go literal of []interface{} with JSON values.
+func goLiteral(values []interface{}) (string, error) {
+	items := make([]string, 0, len(values))
+
+	for _, v := range values {
+		switch v := v.(type) {
+		case string:
+			items = append(items, strconv.Quote(v))
+		case bool:
+			items = append(items, strconv.FormatBool(v))
+		case float64:
+			items = append(items, strconv.FormatFloat(v, 'g', -1, 64))
+		case json.Number:
+			items = append(items, v.String())
+		case nil:
+			items = append(items, "nil")
+		default:
+			j, err := json.Marshal(v)
+			if err != nil {
+				return "", err
+			}
+
+			items = append(items, strconv.Quote(string(j)))
+		}
+	}
+
+	return "[]interface{}{" + strings.Join(items, ", ") + "}", nil
+}
+
+var initialisms = map[string]bool{
+	"API": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true, "JSON": true,
+	"SQL": true, "URI": true, "URL": true, "UUID": true, "XML": true,
+}
+
+// goName converts a name to exported Go identifier, e.g. "created_at" to "CreatedAt" and "user-id" to "UserID".
+func goName(s string) string {
+	parts := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var b strings.Builder
+
+	for _, p := range parts {
+		if u := strings.ToUpper(p); initialisms[u] {
+			b.WriteString(u)
+
+			continue
+		}
+
+		r := []rune(p)
+		r[0] = unicode.ToUpper(r[0])
+		b.WriteString(string(r))
+	}
+
+	res := b.String()
+
+	if res == "" || !unicode.IsLetter([]rune(res)[0]) {
+		res = "X" + res
+	}
+
+	return res
+}
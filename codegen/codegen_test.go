@@ -0,0 +1,72 @@
+package codegen_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/jsonschema-go"
+	"github.com/swaggest/jsonschema-go/codegen"
+)
+
+func TestGenerate(t *testing.T) {
+	var s jsonschema.Schema
+
+	require.NoError(t, s.UnmarshalJSON([]byte(`{
+	  "title":"Order",
+	  "definitions":{
+		"Address":{"description":"Address is a postal address.","properties":{"city":{"type":"string","minLength":1}},"type":"object"},
+		"Status":{"enum":["new","paid"],"type":"string"},
+		"Base":{"properties":{"id":{"type":"integer","minimum":1}},"required":["id"],"type":"object"}
+	  },
+	  "allOf":[{"$ref":"#/definitions/Base"}],
+	  "properties":{
+		"created_at":{"type":"string","format":"date-time"},
+		"status":{"$ref":"#/definitions/Status"},
+		"billing":{"$ref":"#/definitions/Address"},
+		"shipping":{"$ref":"#/definitions/Address"},
+		"note":{"type":["null","string"],"description":"Free-form \"note\"."},
+		"tags":{"items":{"type":"string"},"type":["array","null"],"uniqueItems":true},
+		"lines":{"items":{"properties":{"sku":{"type":"string"},"qty":{"type":"integer"}},"type":"object"},"type":"array"},
+		"meta":{"additionalProperties":{"type":"number"},"type":"object"},
+		"extra":{}
+	  },
+	  "required":["billing","status"],
+	  "type":"object"
+	}`)))
+
+	src, err := codegen.Generate(s, func(o *codegen.Options) {
+		o.PackageName = "orders"
+	})
+	require.NoError(t, err)
+
+	expected, err := os.ReadFile("testdata/orders.go")
+	require.NoError(t, err)
+
+	assert.Equal(t, string(expected), string(src))
+}
+
+type node struct {
+	Name     string  `json:"name" required:"true"`
+	Children []*node `json:"children,omitempty"`
+	Parent   *node   `json:"parent"`
+}
+
+func TestGenerate_reflected(t *testing.T) {
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(node{}, jsonschema.StripDefinitionNamePrefix("CodegenTest"))
+	require.NoError(t, err)
+
+	src, err := codegen.Generate(s, func(o *codegen.Options) {
+		o.RootName = "Tree"
+	})
+	require.NoError(t, err)
+
+	assert.Contains(t, string(src), "type Tree struct {\n"+
+		"\tChildren []Tree `json:\"children,omitempty\"`\n"+
+		"\tName     string `json:\"name\" required:\"true\"`\n"+
+		"\tParent   *Tree  `json:\"parent,omitempty\"`\n"+
+		"}\n")
+}
@@ -0,0 +1,140 @@
+package jsonschema
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/swaggest/refl"
+)
+
+// DefinitionsOrder defines order of definitions delivered by CollectDefinitionsOrdered.
+type DefinitionsOrder int
+
+// Definitions orders.
+const (
+	// DefinitionsAlphabetical delivers definitions sorted by name.
+	DefinitionsAlphabetical DefinitionsOrder = iota + 1
+
+	// DefinitionsTopological delivers definitions after definitions they refer to,
+	// independent definitions and members of reference cycles are sorted by name.
+	DefinitionsTopological
+)
+
+// CollectDefinitionsOrdered enables collecting definitions with provided func instead of result schema.
+//
+// Unlike CollectDefinitions, definitions are buffered during reflection and delivered in a deterministic
+// order once Reflect is complete, together with the type that was passed to Reflect. Deliveries of concurrent
+// Reflect calls sharing the same option value are not interleaved, so f does not need to be synchronized.
+func CollectDefinitionsOrdered(order DefinitionsOrder, f func(root reflect.Type, name string, schema Schema)) func(*ReflectContext) {
+	mu := &sync.Mutex{}
+
+	return func(rc *ReflectContext) {
+		rc.CollectDefinitionsOrdered = f
+		rc.DefinitionsOrder = order
+		rc.collectMu = mu
+	}
+}
+
+// CollectDefinitionsWithRoot enables collecting definitions with provided func instead of result schema.
+//
+// As with CollectDefinitions, definitions are delivered once reflection is complete (in order defined by
+// OrderKeywords), but together with the type that was passed to Reflect and a sequence number of Reflect call.
+// Sequence starts with 1 and is incremented for every Reflect call with the same option value, so that consumers
+// of a shared Reflector can attribute definitions to reflect operations (e.g. per-service documents).
+// Deliveries of concurrent Reflect calls sharing the same option value are not interleaved.
+func CollectDefinitionsWithRoot(f func(root reflect.Type, seq int64, name string, schema Schema)) func(*ReflectContext) {
+	var (
+		mu  = &sync.Mutex{}
+		seq int64
+	)
+
+	return func(rc *ReflectContext) {
+		rc.CollectDefinitionsWithRoot = f
+		rc.collectSeq = atomic.AddInt64(&seq, 1)
+		rc.collectMu = mu
+	}
+}
+
+// collectsDefinitions returns true if definitions are delivered to a callback instead of result schema.
+func (rc *ReflectContext) collectsDefinitions() bool {
+	return rc.CollectDefinitions != nil || rc.CollectDefinitionsOrdered != nil || rc.CollectDefinitionsWithRoot != nil
+}
+
+// deliverDefinitionsWithRoot calls CollectDefinitionsWithRoot for all definitions of reflect operation.
+func (rc *ReflectContext) deliverDefinitionsWithRoot(root reflect.Type) {
+	types := rc.definitionTypes()
+
+	if rc.collectMu != nil {
+		rc.collectMu.Lock()
+		defer rc.collectMu.Unlock()
+	}
+
+	for _, typeString := range types {
+		rc.CollectDefinitionsWithRoot(root, rc.collectSeq, rc.definitionRefs[typeString].Name, *rc.definitions[typeString])
+	}
+}
+
+// deliverOrderedDefinitions calls CollectDefinitionsOrdered for all definitions of reflect operation.
+func (rc *ReflectContext) deliverOrderedDefinitions(root reflect.Type) {
+	types := rc.orderedDefinitionTypes()
+
+	if rc.collectMu != nil {
+		rc.collectMu.Lock()
+		defer rc.collectMu.Unlock()
+	}
+
+	for _, typeString := range types {
+		rc.CollectDefinitionsOrdered(root, rc.definitionRefs[typeString].Name, *rc.definitions[typeString])
+	}
+}
+
+func (rc *ReflectContext) orderedDefinitionTypes() []refl.TypeString {
+	res := make([]refl.TypeString, 0, len(rc.definitions))
+	for typeString := range rc.definitions {
+		res = append(res, typeString)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return rc.definitionRefs[res[i]].Name < rc.definitionRefs[res[j]].Name
+	})
+
+	if rc.DefinitionsOrder != DefinitionsTopological {
+		return res
+	}
+
+	byRef := make(map[string]refl.TypeString, len(res))
+	byName := make(map[string]refl.TypeString, len(res))
+
+	for _, typeString := range res {
+		ref := rc.definitionRefs[typeString]
+		byRef[ref.Path+ref.Name] = typeString
+		byName[ref.Name] = typeString
+	}
+
+	deps := make(map[string][]string, len(res))
+
+	for _, typeString := range res {
+		name := rc.definitionRefs[typeString].Name
+		deps[name] = nil
+
+		walkSchemas(rc.definitions[typeString], func(s *Schema) {
+			if s.Ref == nil || !strings.HasPrefix(*s.Ref, rc.DefinitionsPrefix) {
+				return
+			}
+
+			if dep, ok := byRef[*s.Ref]; ok {
+				deps[name] = append(deps[name], rc.definitionRefs[dep].Name)
+			}
+		})
+	}
+
+	sorted := make([]refl.TypeString, 0, len(res))
+	for _, name := range TopologicalOrder(deps) {
+		sorted = append(sorted, byName[name])
+	}
+
+	return sorted
+}
@@ -0,0 +1,161 @@
+package jsonschema_test
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/jsonschema-go"
+)
+
+type collectLeaf struct {
+	Value string `json:"value"`
+}
+
+type collectBranch struct {
+	Leaf collectLeaf `json:"leaf"`
+}
+
+type collectRoot struct {
+	Zeta   collectBranch `json:"zeta"`
+	Alpha  collectLeaf   `json:"alpha"`
+	Parent *collectRoot  `json:"parent"`
+}
+
+func TestCollectDefinitionsOrdered(t *testing.T) {
+	r := jsonschema.Reflector{}
+
+	collect := func(order jsonschema.DefinitionsOrder) []string {
+		var names []string
+
+		s, err := r.Reflect(collectRoot{}, jsonschema.RootRef,
+			jsonschema.CollectDefinitionsOrdered(order, func(root reflect.Type, name string, schema jsonschema.Schema) {
+				assert.Equal(t, reflect.TypeOf(collectRoot{}), root)
+
+				names = append(names, name)
+			}))
+		require.NoError(t, err)
+		assert.Empty(t, s.Definitions)
+
+		return names
+	}
+
+	assert.Equal(t, []string{
+		"JsonschemaGoTestCollectBranch", "JsonschemaGoTestCollectLeaf", "JsonschemaGoTestCollectRoot",
+	}, collect(jsonschema.DefinitionsAlphabetical))
+
+	assert.Equal(t, []string{
+		"JsonschemaGoTestCollectLeaf", "JsonschemaGoTestCollectBranch", "JsonschemaGoTestCollectRoot",
+	}, collect(jsonschema.DefinitionsTopological))
+}
+
+func TestCollectDefinitionsOrdered_concurrent(t *testing.T) {
+	var (
+		roots []reflect.Type
+		wg    sync.WaitGroup
+	)
+
+	option := jsonschema.CollectDefinitionsOrdered(jsonschema.DefinitionsTopological,
+		func(root reflect.Type, name string, schema jsonschema.Schema) {
+			roots = append(roots, root)
+		})
+
+	samples := []interface{}{collectRoot{}, collectBranch{}, collectRoot{}, collectBranch{}}
+
+	for _, sample := range samples {
+		sample := sample
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			r := jsonschema.Reflector{}
+			_, err := r.Reflect(sample, jsonschema.RootRef, option)
+			assert.NoError(t, err)
+		}()
+	}
+
+	wg.Wait()
+
+	// Definitions of each call are delivered together.
+	require.Len(t, roots, 10)
+
+	for i := 0; i < len(roots); {
+		n := 2
+		if roots[i] == reflect.TypeOf(collectRoot{}) {
+			n = 3
+		}
+
+		for j := i; j < i+n; j++ {
+			assert.Equal(t, roots[i], roots[j])
+		}
+
+		i += n
+	}
+}
+
+func TestCollectDefinitionsWithRoot(t *testing.T) {
+	type collected struct {
+		root reflect.Type
+		seq  int64
+		name string
+	}
+
+	var (
+		res []collected
+		wg  sync.WaitGroup
+	)
+
+	option := jsonschema.CollectDefinitionsWithRoot(func(root reflect.Type, seq int64, name string, schema jsonschema.Schema) {
+		res = append(res, collected{root: root, seq: seq, name: name})
+	})
+
+	samples := []interface{}{collectRoot{}, collectBranch{}, collectRoot{}, collectBranch{}}
+
+	for _, sample := range samples {
+		sample := sample
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			r := jsonschema.Reflector{}
+			s, err := r.Reflect(sample, jsonschema.RootRef, option)
+			assert.NoError(t, err)
+			assert.Empty(t, s.Definitions)
+		}()
+	}
+
+	wg.Wait()
+
+	require.Len(t, res, 10)
+
+	seen := map[int64]bool{}
+
+	for i := 0; i < len(res); {
+		n := 2
+		if res[i].root == reflect.TypeOf(collectRoot{}) {
+			n = 3
+		}
+
+		assert.False(t, seen[res[i].seq])
+		assert.GreaterOrEqual(t, res[i].seq, int64(1))
+		assert.LessOrEqual(t, res[i].seq, int64(4))
+
+		seen[res[i].seq] = true
+
+		// Definitions of each call are delivered together.
+		for j := i; j < i+n; j++ {
+			assert.Equal(t, res[i].root, res[j].root)
+			assert.Equal(t, res[i].seq, res[j].seq)
+		}
+
+		i += n
+	}
+
+	assert.Len(t, seen, 4)
+}
@@ -0,0 +1,79 @@
+package jsonschema
+
+import (
+	"reflect"
+	"strings"
+)
+
+// CommentsFromSource enables `description` of definitions and properties from doc comments of Go types
+// and struct fields, e.g. for
+//
+//	// Order is a purchase.
+//	type Order struct {
+//		// ID identifies order.
+//		ID int `json:"id"`
+//	}
+//
+// Order schema gets `"description":"Order is a purchase."` and `id` property gets `"description":"ID identifies order."`.
+// If type doc comment has several paragraphs, the first one is used as `title` and the rest as `description`.
+//
+// Comments have lower priority than field tags, Titled and Described. Index can be nil to use default one.
+func CommentsFromSource(si *SourceIndex) func(rc *ReflectContext) {
+	if si == nil {
+		si = NewSourceIndex()
+	}
+
+	return func(rc *ReflectContext) {
+		rc.CommentsIndex = si
+	}
+}
+
+func reflectTypeComments(t reflect.Type, schema *Schema, rc *ReflectContext) {
+	if rc.CommentsIndex == nil || t.Name() == "" {
+		return
+	}
+
+	doc, ok := rc.CommentsIndex.TypeDoc(t)
+	if !ok {
+		return
+	}
+
+	title, description := splitDocComment(doc)
+
+	if title != "" && schema.Title == nil {
+		schema.WithTitle(title)
+	}
+
+	if description != "" && schema.Description == nil {
+		schema.WithDescription(description)
+	}
+}
+
+func reflectFieldComments(owner reflect.Type, field reflect.StructField, schema *Schema, rc *ReflectContext) {
+	if rc.CommentsIndex == nil || owner == nil || schema.Description != nil {
+		return
+	}
+
+	doc, ok := rc.CommentsIndex.FieldDoc(owner, field.Name)
+	if !ok {
+		return
+	}
+
+	schema.WithDescription(strings.TrimSpace(doc))
+}
+
+// splitDocComment returns first paragraph of a multi-paragraph comment as title and the rest as description,
+// single paragraph comment is a description.
+func splitDocComment(doc string) (title, description string) {
+	doc = strings.TrimSpace(doc)
+
+	i := strings.Index(doc, "\n\n")
+	if i < 0 {
+		return "", doc
+	}
+
+	title = strings.Join(strings.Fields(doc[:i]), " ")
+	description = strings.TrimSpace(doc[i:])
+
+	return title, description
+}
@@ -0,0 +1,60 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/jsonschema-go"
+)
+
+// commentedOrder is a purchase.
+//
+// Order is created by customer
+// and fulfilled by warehouse.
+type commentedOrder struct {
+	// ID identifies order.
+	ID int `json:"id"`
+
+	Status string `json:"status"`                       // Status of fulfillment.
+	Note   string `json:"note" description:"Tag wins."` // Comment loses.
+
+	Item commentedItem `json:"item"`
+}
+
+// commentedItem is a purchased item.
+type commentedItem struct {
+	SKU string `json:"sku"`
+}
+
+func TestCommentsFromSource(t *testing.T) {
+	if !jsonschema.BuildCapabilities().SourceIndex {
+		t.Skip("source index is not available in this build")
+	}
+
+	idx := jsonschema.NewSourceIndex()
+	idx.AddDir("github.com/swaggest/jsonschema-go_test", ".")
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(commentedOrder{}, jsonschema.CommentsFromSource(idx))
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "title":"commentedOrder is a purchase.",
+	  "description":"Order is created by customer\nand fulfilled by warehouse.",
+	  "definitions":{
+		"JsonschemaGoTestCommentedItem":{
+		  "description":"commentedItem is a purchased item.",
+		  "properties":{"sku":{"type":"string"}},"type":"object"
+		}
+	  },
+	  "properties":{
+		"id":{"description":"ID identifies order.","type":"integer"},
+		"item":{"$ref":"#/definitions/JsonschemaGoTestCommentedItem"},
+		"note":{"description":"Tag wins.","type":"string"},
+		"status":{"description":"Status of fulfillment.","type":"string"}
+	  },
+	  "type":"object"
+	}`, s)
+}
@@ -0,0 +1,164 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// reflectConditionalTags configures conditional keywords of parent schema from tags of unnamed `_` field.
+//
+// Supported tags:
+//   - `if`, `then`, `else` contain a JSON schema or a shorthand, `if:"country=US"` requires property
+//     `country` to be equal to `"US"`, `then:"zip,state"` and `else:"zip"` list required properties,
+//   - `dependentRequired:"credit_card:billing_address,billing_zip;phone:phone_type"` lists properties
+//     that are required when property is present,
+//   - `dependentSchemas` contains a JSON object of schemas that apply when property is present.
+//
+// Dependencies are reflected as draft-07 `dependencies` and are converted for newer drafts, see TargetDraft.
+func reflectConditionalTags(parent *Schema, field reflect.StructField) error {
+	if v, ok := field.Tag.Lookup("dependentRequired"); ok {
+		for _, dep := range strings.Split(v, ";") {
+			if strings.TrimSpace(dep) == "" {
+				continue
+			}
+
+			pos := strings.Index(dep, ":")
+			if pos <= 0 {
+				return fmt.Errorf("invalid dependentRequired tag %q, property:required[,required] expected", dep)
+			}
+
+			addDependentRequired(parent, strings.TrimSpace(dep[:pos]), splitNames(dep[pos+1:]))
+		}
+	}
+
+	if v, ok := field.Tag.Lookup("dependentSchemas"); ok {
+		var schemas map[string]SchemaOrBool
+
+		if err := json.Unmarshal([]byte(v), &schemas); err != nil {
+			return fmt.Errorf("failed to parse dependentSchemas tag: %w", err)
+		}
+
+		for name, s := range schemas {
+			s := s
+			parent.WithDependenciesItem(name, DependenciesAdditionalProperties{SchemaOrBool: &s})
+		}
+	}
+
+	return reflectIfThenElse(parent, field.Tag)
+}
+
+// reflectDependentRequiredTag reads `dependentRequired` tag of a property that lists sibling properties
+// required when property is present, e.g. `dependentRequired:"billing_address,billing_zip"`.
+func reflectDependentRequiredTag(parent *Schema, propName string, field reflect.StructField) {
+	if v, ok := field.Tag.Lookup("dependentRequired"); ok {
+		if names := splitNames(v); len(names) > 0 {
+			addDependentRequired(parent, propName, names)
+		}
+	}
+}
+
+func reflectIfThenElse(parent *Schema, tag reflect.StructTag) error {
+	var (
+		cond  Schema
+		found bool
+	)
+
+	for _, k := range []string{"if", "then", "else"} {
+		v, ok := tag.Lookup(k)
+		if !ok {
+			continue
+		}
+
+		found = true
+
+		s, err := conditionSchema(k, v)
+		if err != nil {
+			return err
+		}
+
+		switch k {
+		case "if":
+			cond.If = &s
+		case "then":
+			cond.Then = &s
+		default:
+			cond.Else = &s
+		}
+	}
+
+	if !found {
+		return nil
+	}
+
+	if cond.If == nil {
+		return fmt.Errorf("then and else tags require if tag")
+	}
+
+	// Schema can only have one if/then/else, others are combined with allOf.
+	if parent.If == nil && parent.Then == nil && parent.Else == nil {
+		parent.If, parent.Then, parent.Else = cond.If, cond.Then, cond.Else
+	} else {
+		parent.AllOf = append(parent.AllOf, cond.ToSchemaOrBool())
+	}
+
+	return nil
+}
+
+// conditionSchema parses value of `if`, `then` or `else` tag.
+func conditionSchema(keyword, v string) (SchemaOrBool, error) {
+	var s SchemaOrBool
+
+	if strings.HasPrefix(strings.TrimSpace(v), "{") || v == "true" || v == "false" {
+		if err := json.Unmarshal([]byte(v), &s); err != nil {
+			return s, fmt.Errorf("failed to parse %s tag: %w", keyword, err)
+		}
+
+		return s, nil
+	}
+
+	if keyword != "if" {
+		return (&Schema{Required: splitNames(v)}).ToSchemaOrBool(), nil
+	}
+
+	pos := strings.Index(v, "=")
+	if pos <= 0 {
+		return s, fmt.Errorf("invalid if tag %q, property=value or JSON schema expected", v)
+	}
+
+	name := strings.TrimSpace(v[:pos])
+
+	var val interface{}
+	if err := json.Unmarshal([]byte(v[pos+1:]), &val); err != nil {
+		val = v[pos+1:]
+	}
+
+	return (&Schema{
+		Required:   []string{name},
+		Properties: map[string]SchemaOrBool{name: (&Schema{}).WithConst(val).ToSchemaOrBool()},
+	}).ToSchemaOrBool(), nil
+}
+
+func addDependentRequired(parent *Schema, name string, required []string) {
+	d := parent.Dependencies[name]
+
+	for _, r := range required {
+		d.StringArray = appendUnique(d.StringArray, r)
+	}
+
+	parent.WithDependenciesItem(name, d)
+}
+
+// splitNames returns non-empty trimmed items of comma-separated list.
+func splitNames(v string) []string {
+	var res []string
+
+	for _, n := range strings.Split(v, ",") {
+		if n = strings.TrimSpace(n); n != "" {
+			res = append(res, n)
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,85 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/jsonschema-go"
+)
+
+func TestReflector_Reflect_conditionalTags(t *testing.T) {
+	type Payment struct {
+		_ struct{} `dependentRequired:"credit_card:billing_address" if:"country=US" then:"zip,state" else:"postcode"`
+		_ struct{} `if:"{\"properties\":{\"amount\":{\"minimum\":1000}}}" then:"{\"required\":[\"approver\"]}"`
+
+		Country        string `json:"country"`
+		CreditCard     string `json:"credit_card,omitempty" dependentRequired:"billing_zip"`
+		BillingAddress string `json:"billing_address,omitempty"`
+		BillingZip     string `json:"billing_zip,omitempty"`
+		Amount         int    `json:"amount"`
+		Approver       string `json:"approver,omitempty"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(Payment{})
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "if":{"required":["country"],"properties":{"country":{"const":"US"}}},
+	  "then":{"required":["zip","state"]},"else":{"required":["postcode"]},
+	  "allOf":[
+		{
+		  "if":{"properties":{"amount":{"minimum":1000}}},
+		  "then":{"required":["approver"]}
+		}
+	  ],
+	  "properties":{
+		"amount":{"type":"integer"},"approver":{"type":"string"},
+		"billing_address":{"type":"string"},"billing_zip":{"type":"string"},
+		"country":{"type":"string"},"credit_card":{"type":"string"}
+	  },
+	  "type":"object",
+	  "dependencies":{"credit_card":["billing_address","billing_zip"]}
+	}`, s)
+
+	s, err = r.Reflect(Payment{}, jsonschema.TargetDraft(jsonschema.DraftVersion2020))
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string][]string{"credit_card": {"billing_address", "billing_zip"}},
+		s.ExtraProperties["dependentRequired"])
+}
+
+func TestReflector_Reflect_dependentSchemasTag(t *testing.T) {
+	type Order struct {
+		_ struct{} `dependentSchemas:"{\"gift\":{\"required\":[\"message\"]}}"`
+
+		Gift    bool   `json:"gift,omitempty"`
+		Message string `json:"message,omitempty"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(Order{})
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "properties":{"gift":{"type":"boolean"},"message":{"type":"string"}},
+	  "type":"object","dependencies":{"gift":{"required":["message"]}}
+	}`, s)
+}
+
+func TestReflector_Reflect_conditionalTags_invalid(t *testing.T) {
+	type Invalid struct {
+		_ struct{} `then:"zip"`
+
+		Zip string `json:"zip"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	_, err := r.Reflect(Invalid{})
+	assert.EqualError(t, err, "then and else tags require if tag")
+}
@@ -0,0 +1,165 @@
+package jsonschema
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/swaggest/refl"
+)
+
+// RegisterConstraints adds constraints to schema of a type of given sample.
+//
+// Constraints are applied everywhere the type is used, e.g.
+//
+//	type Percentage int
+//
+//	r.RegisterConstraints(Percentage(0), jsonschema.Minimum(0), jsonschema.Maximum(100))
+//
+// They are applied to reflected schema before InterceptSchema with processed schema and Preparer,
+// so that those can still alter the result. Registering constraints for the same type again appends them.
+func (r *Reflector) RegisterConstraints(sample interface{}, constraints ...func(s *Schema)) {
+	if r.constraints == nil {
+		r.constraints = map[reflect.Type][]func(s *Schema){}
+	}
+
+	t := refl.DeepIndirect(reflect.TypeOf(sample))
+	r.constraints[t] = append(r.constraints[t], constraints...)
+}
+
+// ConstraintsByRef makes schemas of types with registered constraints always shared definitions, even if
+// they would be inlined by InlineRefs, InlineScalars, Reflector.InlineDefinition or HideDefinitions,
+// so that constraints are declared in a single place and referenced from slices, maps and fields.
+//
+// Without this option, inlined schemas of constrained types (e.g. items of a []Percentage) carry
+// a copy of constraints, which are evaluated again for every occurrence.
+func ConstraintsByRef(rc *ReflectContext) {
+	rc.ConstraintsByRef = true
+}
+
+func (r *Reflector) hasConstraints(t reflect.Type) bool {
+	if t == nil || len(r.constraints) == 0 {
+		return false
+	}
+
+	return len(r.constraints[refl.DeepIndirect(t)]) > 0
+}
+
+func (r *Reflector) applyConstraints(t reflect.Type, s *Schema) {
+	for _, c := range r.constraints[t] {
+		c(s)
+	}
+}
+
+// Minimum is a constraint that sets `minimum`.
+func Minimum(val float64) func(s *Schema) {
+	return func(s *Schema) {
+		s.WithMinimum(val)
+	}
+}
+
+// Maximum is a constraint that sets `maximum`.
+func Maximum(val float64) func(s *Schema) {
+	return func(s *Schema) {
+		s.WithMaximum(val)
+	}
+}
+
+// MultipleOf is a constraint that sets `multipleOf`.
+func MultipleOf(val float64) func(s *Schema) {
+	return func(s *Schema) {
+		s.WithMultipleOf(val)
+	}
+}
+
+// MinLength is a constraint that sets `minLength`.
+func MinLength(val int64) func(s *Schema) {
+	return func(s *Schema) {
+		s.WithMinLength(val)
+	}
+}
+
+// MaxLength is a constraint that sets `maxLength`.
+func MaxLength(val int64) func(s *Schema) {
+	return func(s *Schema) {
+		s.WithMaxLength(val)
+	}
+}
+
+// Pattern is a constraint that sets `pattern`.
+func Pattern(val string) func(s *Schema) {
+	return func(s *Schema) {
+		s.WithPattern(val)
+	}
+}
+
+// Format is a constraint that sets `format`.
+func Format(val string) func(s *Schema) {
+	return func(s *Schema) {
+		s.WithFormat(val)
+	}
+}
+
+// MinItems is a constraint that sets `minItems`.
+func MinItems(val int64) func(s *Schema) {
+	return func(s *Schema) {
+		s.WithMinItems(val)
+	}
+}
+
+// MaxItems is a constraint that sets `maxItems`.
+func MaxItems(val int64) func(s *Schema) {
+	return func(s *Schema) {
+		s.WithMaxItems(val)
+	}
+}
+
+// Description is a constraint that sets `description`.
+func Description(val string) func(s *Schema) {
+	return func(s *Schema) {
+		s.WithDescription(val)
+	}
+}
+
+// RegisterPreset adds a named bundle of constraints that can be applied to a property with `preset` field tag, e.g.
+//
+//	r.RegisterPreset("slug", jsonschema.Pattern("^[a-z0-9-]+$"), jsonschema.MinLength(1), jsonschema.MaxLength(64))
+//
+//	type Article struct {
+//		Slug string `json:"slug" preset:"slug"`
+//	}
+//
+// Multiple presets can be listed with comma, e.g. `preset:"slug,documented"`.
+// Presets are applied before other field tags, so that tags can override preset values.
+func (r *Reflector) RegisterPreset(name string, constraints ...func(s *Schema)) {
+	if r.presets == nil {
+		r.presets = map[string][]func(s *Schema){}
+	}
+
+	r.presets[name] = constraints
+}
+
+func (r *Reflector) applyPresets(s *Schema, field reflect.StructField) error {
+	tag, ok := field.Tag.Lookup("preset")
+	if !ok {
+		return nil
+	}
+
+	for _, name := range strings.Split(tag, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		constraints, ok := r.presets[name]
+		if !ok {
+			return fmt.Errorf("unknown preset %q in field %s", name, field.Name)
+		}
+
+		for _, c := range constraints {
+			c(s)
+		}
+	}
+
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"reflect"
 	"strings"
+	"sync"
 
 	"github.com/swaggest/refl"
 )
@@ -79,6 +80,16 @@ type InterceptPropParams struct {
 	PropertySchema *Schema
 	ParentSchema   *Schema
 	Processed      bool
+
+	// OwnerType is a struct type that declares the field, it is a type of embedded struct for promoted fields.
+	OwnerType reflect.Type
+
+	// InstancePath is a JSON Pointer to property in instance document, array items and map values are
+	// denoted with "*", e.g. "/orders/*/id".
+	InstancePath string
+
+	// SchemaPath is a JSON Pointer to property schema in reflected schema, see ReflectContext.SchemaPointer.
+	SchemaPath string
 }
 
 // InterceptNullabilityParams defines InterceptNullabilityFunc parameters.
@@ -111,6 +122,76 @@ func InterceptNullability(f InterceptNullabilityFunc) func(reflectContext *Refle
 	}
 }
 
+// InterceptElemParams defines InterceptItemsFunc and InterceptAdditionalPropertiesFunc parameters.
+type InterceptElemParams struct {
+	Context *ReflectContext
+
+	// Path is a reflection path of element, it ends with "[]" for array items and with "{}" for map values.
+	Path []string
+
+	// InstancePath is a JSON Pointer to element in instance document, e.g. "/orders/*".
+	InstancePath string
+
+	// SchemaPath is a JSON Pointer to element schema in reflected schema, e.g. "#/properties/orders/items".
+	SchemaPath string
+
+	// Type is a Go type of element.
+	Type reflect.Type
+
+	// Schema is a processed element schema, it may be a reference to definition of named type.
+	Schema *Schema
+
+	// ParentSchema is a schema of array or map.
+	ParentSchema *Schema
+}
+
+// InterceptItemsFunc can intercept reflection of slice and array items to control or modify schema.
+type InterceptItemsFunc func(params InterceptElemParams) error
+
+// InterceptAdditionalPropertiesFunc can intercept reflection of map values to control or modify schema.
+type InterceptAdditionalPropertiesFunc func(params InterceptElemParams) error
+
+// InterceptItems adds hook to customize schema of slice and array items.
+//
+// Hook is called once with processed items schema, hooks are called in order of addition, chain stops on error.
+func InterceptItems(f InterceptItemsFunc) func(reflectContext *ReflectContext) {
+	return func(rc *ReflectContext) {
+		if rc.InterceptItems != nil {
+			prev := rc.InterceptItems
+			rc.InterceptItems = func(params InterceptElemParams) error {
+				if err := prev(params); err != nil {
+					return err
+				}
+
+				return f(params)
+			}
+		} else {
+			rc.InterceptItems = f
+		}
+	}
+}
+
+// InterceptAdditionalProperties adds hook to customize schema of map values.
+//
+// Hook is called once with processed additionalProperties schema, hooks are called in order of addition,
+// chain stops on error.
+func InterceptAdditionalProperties(f InterceptAdditionalPropertiesFunc) func(reflectContext *ReflectContext) {
+	return func(rc *ReflectContext) {
+		if rc.InterceptAdditionalProperties != nil {
+			prev := rc.InterceptAdditionalProperties
+			rc.InterceptAdditionalProperties = func(params InterceptElemParams) error {
+				if err := prev(params); err != nil {
+					return err
+				}
+
+				return f(params)
+			}
+		} else {
+			rc.InterceptAdditionalProperties = f
+		}
+	}
+}
+
 // InterceptType adds hook to customize schema.
 //
 // Deprecated: use InterceptSchema.
@@ -121,21 +202,69 @@ func InterceptType(f InterceptTypeFunc) func(*ReflectContext) {
 }
 
 // InterceptSchema adds hook to customize schema.
+//
+// Hooks are called in order of addition, chain stops on error or when hook returns true.
 func InterceptSchema(f InterceptSchemaFunc) func(*ReflectContext) {
+	return NamedInterceptSchema("", f)
+}
+
+// NamedInterceptSchema adds named hook to customize schema, the hook can be removed with RemoveIntercepts.
+func NamedInterceptSchema(name string, f InterceptSchemaFunc) func(*ReflectContext) {
 	return func(rc *ReflectContext) {
-		if rc.interceptSchema != nil {
-			prev := rc.interceptSchema
-			rc.interceptSchema = func(params InterceptSchemaParams) (b bool, err error) {
-				ret, err := prev(params)
-				if err != nil || ret {
-					return ret, err
-				}
+		rc.schemaHooks = append(rc.schemaHooks[:len(rc.schemaHooks):len(rc.schemaHooks)], schemaHook{name: name, f: f})
+		rc.composeIntercepts()
+	}
+}
 
-				return f(params)
+// PrependInterceptSchema adds hook to customize schema before previously added hooks.
+func PrependInterceptSchema(f InterceptSchemaFunc) func(*ReflectContext) {
+	return func(rc *ReflectContext) {
+		rc.schemaHooks = append([]schemaHook{{f: f}}, rc.schemaHooks...)
+		rc.composeIntercepts()
+	}
+}
+
+// ReplaceInterceptSchema removes previously added schema hooks and adds a new one.
+//
+// Built-in support of Exposer, RawExposer and Enum is not a hook, it is not affected.
+func ReplaceInterceptSchema(f InterceptSchemaFunc) func(*ReflectContext) {
+	return func(rc *ReflectContext) {
+		rc.schemaHooks = []schemaHook{{f: f}}
+		rc.composeIntercepts()
+	}
+}
+
+// InterceptFinalSchema adds hook that is called once per Go type with fully processed schema.
+//
+// Unlike InterceptSchema, the hook is not called with unprocessed schema. If a type is met again
+// in the same reflection, e.g. an inlined scalar type, hook is not called and a copy of the result
+// of the first call is used. Note, T and *T are different types.
+func InterceptFinalSchema(f func(t reflect.Type, s *Schema) error) func(*ReflectContext) {
+	return func(rc *ReflectContext) {
+		final := map[reflect.Type]Schema{}
+
+		InterceptSchema(func(params InterceptSchemaParams) (bool, error) {
+			t := params.Schema.ReflectType
+			if !params.Processed || t == nil {
+				return false, nil
 			}
-		} else {
-			rc.interceptSchema = f
-		}
+
+			if s, ok := final[t]; ok {
+				c := cloneSchema(s)
+				c.Parent = params.Schema.Parent
+				*params.Schema = c
+
+				return false, nil
+			}
+
+			if err := f(t, params.Schema); err != nil {
+				return false, err
+			}
+
+			final[t] = cloneSchema(*params.Schema)
+
+			return false, nil
+		})(rc)
 	}
 }
 
@@ -153,20 +282,127 @@ func InterceptProperty(f InterceptPropertyFunc) func(*ReflectContext) {
 }
 
 // InterceptProp adds a hook to customize property schema.
+//
+// Hooks are called in order of addition, chain stops on error.
 func InterceptProp(f InterceptPropFunc) func(reflectContext *ReflectContext) {
+	return NamedInterceptProp("", f)
+}
+
+// NamedInterceptProp adds named hook to customize property schema, the hook can be removed with RemoveIntercepts.
+func NamedInterceptProp(name string, f InterceptPropFunc) func(reflectContext *ReflectContext) {
 	return func(rc *ReflectContext) {
-		if rc.interceptProp != nil {
-			prev := rc.interceptProp
-			rc.interceptProp = func(params InterceptPropParams) error {
-				err := prev(params)
-				if err != nil {
-					return err
+		rc.propHooks = append(rc.propHooks[:len(rc.propHooks):len(rc.propHooks)], propHook{name: name, f: f})
+		rc.composeIntercepts()
+	}
+}
+
+// PrependInterceptProp adds a hook to customize property schema before previously added hooks.
+func PrependInterceptProp(f InterceptPropFunc) func(reflectContext *ReflectContext) {
+	return func(rc *ReflectContext) {
+		rc.propHooks = append([]propHook{{f: f}}, rc.propHooks...)
+		rc.composeIntercepts()
+	}
+}
+
+// ReplaceInterceptProp removes previously added property hooks and adds a new one,
+// e.g. to override hooks of a preset.
+func ReplaceInterceptProp(f InterceptPropFunc) func(reflectContext *ReflectContext) {
+	return func(rc *ReflectContext) {
+		rc.propHooks = []propHook{{f: f}}
+		rc.composeIntercepts()
+	}
+}
+
+// RemoveIntercepts removes schema and property hooks added with NamedInterceptSchema or NamedInterceptProp.
+func RemoveIntercepts(names ...string) func(reflectContext *ReflectContext) {
+	return func(rc *ReflectContext) {
+		remove := make(map[string]bool, len(names))
+		for _, name := range names {
+			remove[name] = true
+		}
+
+		var (
+			schemaHooks []schemaHook
+			propHooks   []propHook
+		)
+
+		for _, h := range rc.schemaHooks {
+			if h.name == "" || !remove[h.name] {
+				schemaHooks = append(schemaHooks, h)
+			}
+		}
+
+		for _, h := range rc.propHooks {
+			if h.name == "" || !remove[h.name] {
+				propHooks = append(propHooks, h)
+			}
+		}
+
+		rc.schemaHooks = schemaHooks
+		rc.propHooks = propHooks
+		rc.composeIntercepts()
+	}
+}
+
+type schemaHook struct {
+	name string
+	f    InterceptSchemaFunc
+}
+
+type propHook struct {
+	name string
+	f    InterceptPropFunc
+}
+
+// callInterceptSchema calls built-in schema hooks (Enum, Exposer and RawExposer support) and then
+// hooks added with InterceptSchema, built-in hooks are not affected by RemoveIntercepts,
+// ReplaceInterceptSchema and PrependInterceptSchema.
+func (rc *ReflectContext) callInterceptSchema(params InterceptSchemaParams) (bool, error) {
+	if ret, err := checkSchemaSetup(params); err != nil || ret {
+		return ret, err
+	}
+
+	if rc.interceptSchema != nil {
+		return rc.interceptSchema(params)
+	}
+
+	return false, nil
+}
+
+// composeIntercepts makes hook chains from lists of hooks.
+func (rc *ReflectContext) composeIntercepts() {
+	rc.interceptSchema = nil
+	rc.interceptProp = nil
+
+	if len(rc.schemaHooks) > 0 {
+		hooks := rc.schemaHooks
+
+		rc.interceptSchema = func(params InterceptSchemaParams) (bool, error) {
+			for i, h := range hooks {
+				rc.reportHookCall("schema", h.name, i)
+
+				if ret, err := h.f(params); err != nil || ret {
+					return ret, err
 				}
+			}
 
-				return f(params)
+			return false, nil
+		}
+	}
+
+	if len(rc.propHooks) > 0 {
+		hooks := rc.propHooks
+
+		rc.interceptProp = func(params InterceptPropParams) error {
+			for i, h := range hooks {
+				rc.reportHookCall("prop", h.name, i)
+
+				if err := h.f(params); err != nil {
+					return err
+				}
 			}
-		} else {
-			rc.interceptProp = f
+
+			return nil
 		}
 	}
 }
@@ -187,6 +423,195 @@ func InterceptDefName(f func(t reflect.Type, defaultDefName string) string) func
 	}
 }
 
+// NullableRefStrategy defines how nullability is expressed for a property that references a definition.
+type NullableRefStrategy int
+
+// Nullable reference strategies.
+const (
+	// NullableRefDefault keeps null type out of pointer references, array and map definitions may become nullable.
+	NullableRefDefault NullableRefStrategy = iota
+
+	// NullableRefAnyOf envelops reference with `"anyOf":[{"type":"null"},{"$ref":"..."}]`.
+	NullableRefAnyOf
+
+	// NullableRefInline replaces reference with a copy of definition that has null type added.
+	NullableRefInline
+
+	// NullableRefDrop keeps plain reference and does not allow null.
+	NullableRefDrop
+)
+
+// NullableRefs sets strategy to express nullability of referenced definitions.
+func NullableRefs(strategy NullableRefStrategy) func(rc *ReflectContext) {
+	return func(rc *ReflectContext) {
+		rc.NullableRef = strategy
+	}
+}
+
+// Ordering defines order of values in keywords where order has no meaning.
+type Ordering int
+
+// Orderings.
+const (
+	// OrderDeclaration keeps `required` and `enum` in order of declaration
+	// and delivers definitions to CollectDefinitions in order of creation.
+	OrderDeclaration Ordering = iota + 1
+
+	// OrderAlphabetical sorts `required`, `enum` and definitions alphabetically.
+	OrderAlphabetical
+)
+
+// PropertyOrdering defines order of properties in marshaled schema.
+type PropertyOrdering int
+
+// Property orderings.
+const (
+	// PropertiesDeclared keeps properties in order of struct fields.
+	PropertiesDeclared PropertyOrdering = iota + 1
+
+	// PropertiesAlphabetical sorts properties by name.
+	PropertiesAlphabetical
+
+	// PropertiesRequiredFirst puts required properties first, properties are kept in order of struct fields.
+	PropertiesRequiredFirst
+)
+
+// OrderProperties enables configured order of properties in marshaled schema, see Schema.PropertiesOrder.
+//
+// Properties that are not reflected from struct fields (e.g. added by interceptors) follow in alphabetical order.
+// Order is not preserved when schema is unmarshaled, by default properties are marshaled alphabetically.
+func OrderProperties(o PropertyOrdering) func(rc *ReflectContext) {
+	return func(rc *ReflectContext) {
+		rc.PropertiesOrder = o
+	}
+}
+
+// OrderKeywords enables deterministic order of `required`, `enum` and definitions.
+//
+// Duplicate `required` items are removed. Enum values are sorted by their JSON representation together
+// with `x-enum-names`. Definitions in resulting schema are always marshaled in alphabetical order of names,
+// ordering also applies to CollectDefinitions calls.
+func OrderKeywords(o Ordering) func(rc *ReflectContext) {
+	return func(rc *ReflectContext) {
+		rc.KeywordsOrder = o
+	}
+}
+
+// GoTypeAnnotations adds `x-go-type` to definitions and properties and `x-go-name` to properties.
+//
+// Type is recorded with full package path, e.g. "[]*github.com/acme/models.Order".
+func GoTypeAnnotations(rc *ReflectContext) {
+	rc.GoTypeAnnotations = true
+}
+
+// SourcePositions enables source positions of Go types and fields in definitions and errors.
+//
+// Definitions receive `x-go-source` extension with "file:line" of type declaration,
+// errors caused by struct fields are wrapped with SourceError.
+func SourcePositions(idx *SourceIndex) func(rc *ReflectContext) {
+	return func(rc *ReflectContext) {
+		rc.SourceIndex = idx
+	}
+}
+
+// UnionTypesAnyOf enables `anyOf` of single-typed schemas instead of type array for `types` field tag
+// with multiple types, for compatibility with tools that do not support type arrays.
+func UnionTypesAnyOf(rc *ReflectContext) {
+	rc.UnionTypesAnyOf = true
+}
+
+// GroupKeyword sets name of extension keyword for `group` field tag, default XGroup.
+func GroupKeyword(keyword string) func(rc *ReflectContext) {
+	return func(rc *ReflectContext) {
+		rc.GroupKeyword = keyword
+	}
+}
+
+// GroupsIndex enables `x-groups` index of property groups in object schemas, see PropertyGroup.
+func GroupsIndex(rc *ReflectContext) {
+	rc.GroupsIndex = true
+}
+
+// FieldEnabled adds a check to include a struct field, e.g. depending on feature flags.
+//
+// Path is a path to the property, it ends with property name, e.g. ["#", "order", "discount"].
+// Multiple checks are combined, field is included if all of them return true.
+func FieldEnabled(f func(field reflect.StructField, path []string) bool) func(rc *ReflectContext) {
+	return func(rc *ReflectContext) {
+		if prev := rc.FieldEnabled; prev != nil {
+			rc.FieldEnabled = func(field reflect.StructField, path []string) bool {
+				return prev(field, path) && f(field, path)
+			}
+		} else {
+			rc.FieldEnabled = f
+		}
+	}
+}
+
+// SensitiveMode defines handling of struct fields with `sensitive:"true"` tag.
+type SensitiveMode int
+
+// Sensitive modes.
+const (
+	// SensitiveAnnotate adds extension keyword (XSensitive by default) with true value to property.
+	SensitiveAnnotate SensitiveMode = iota + 1
+
+	// SensitiveWriteOnly adds `"writeOnly":true` to property.
+	SensitiveWriteOnly
+
+	// SensitiveDrop removes property from schema.
+	SensitiveDrop
+)
+
+// SensitiveFields enables handling of struct fields with `sensitive:"true"` tag, tag is ignored by default.
+func SensitiveFields(mode SensitiveMode) func(rc *ReflectContext) {
+	return func(rc *ReflectContext) {
+		rc.Sensitive = mode
+	}
+}
+
+// SensitiveKeyword sets name of extension keyword for SensitiveAnnotate mode.
+func SensitiveKeyword(keyword string) func(rc *ReflectContext) {
+	return func(rc *ReflectContext) {
+		rc.SensitiveKeyword = keyword
+	}
+}
+
+// SampleValuesMode defines how field values of reflected sample are documented.
+type SampleValuesMode int
+
+// Sample values modes.
+const (
+	// SampleValuesExamples adds field values to `examples`.
+	SampleValuesExamples SampleValuesMode = iota + 1
+
+	// SampleValuesDefault sets field values as `default` if it is not set with field tag.
+	SampleValuesDefault
+)
+
+// ValuesFromSample enables documenting field values of populated value passed to Reflect, e.g.
+//
+//	s, err := r.Reflect(fixtureOrder, jsonschema.ValuesFromSample(jsonschema.SampleValuesExamples))
+//
+// Zero values, values of sensitive fields and references are skipped, values are converted with
+// encoding/json rules. Definitions are reflected once, so values of the first met instance of a type are used.
+func ValuesFromSample(mode SampleValuesMode) func(rc *ReflectContext) {
+	return func(rc *ReflectContext) {
+		rc.SampleValues = mode
+	}
+}
+
+// HoistAnonymousStructs enables definitions for anonymous struct types instead of inlining them.
+//
+// Definitions are named after path, e.g. UserAddressInline1, optional name function can override
+// default name, empty name keeps struct inlined.
+func HoistAnonymousStructs(name func(t reflect.Type, path []string, defaultDefName string) string) func(rc *ReflectContext) {
+	return func(rc *ReflectContext) {
+		rc.HoistAnonymousStructs = true
+		rc.AnonymousStructName = name
+	}
+}
+
 // InlineRefs prevents references.
 func InlineRefs(rc *ReflectContext) {
 	rc.InlineRefs = true
@@ -221,6 +646,40 @@ func StripDefinitionNamePrefix(prefix ...string) func(rc *ReflectContext) {
 	}
 }
 
+// HideDefinitions inlines schemas of matching types instead of creating named definitions.
+//
+// It can be used to keep implementation details (e.g. internal wrapper types) out of published documents.
+// Recursive types keep their definitions, as they can not be inlined.
+func HideDefinitions(matcher func(t reflect.Type) bool) func(rc *ReflectContext) {
+	return func(rc *ReflectContext) {
+		if rc.HideDefinition != nil {
+			prev := rc.HideDefinition
+			rc.HideDefinition = func(t reflect.Type) bool {
+				return prev(t) || matcher(t)
+			}
+		} else {
+			rc.HideDefinition = matcher
+		}
+	}
+}
+
+// InlineScalars inlines schemas of scalar types (e.g. `type UserID string` with pattern) that have
+// up to maxKeywords keywords besides `type`, instead of creating named definitions.
+//
+// Trivial scalar schemas are always inlined, use math.MaxInt to inline all scalar schemas.
+func InlineScalars(maxKeywords int) func(rc *ReflectContext) {
+	return func(rc *ReflectContext) {
+		rc.InlineScalarKeywords = maxKeywords
+	}
+}
+
+// DropZeroDefaults removes `default` from properties with `omitempty` if the default is the zero value of field type.
+//
+// Such default is misleading, because zero value is never marshaled and only appears as absence of property.
+func DropZeroDefaults(rc *ReflectContext) {
+	rc.DropZeroDefaults = true
+}
+
 // PropertyNameMapping enables property name mapping from a struct field name.
 func PropertyNameMapping(mapping map[string]string) func(rc *ReflectContext) {
 	return func(rc *ReflectContext) {
@@ -255,6 +714,18 @@ type ReflectContext struct {
 	// Non-empty CollectDefinitions disables collection of definitions into resulting schema.
 	CollectDefinitions func(name string, schema Schema)
 
+	// CollectDefinitionsOrdered receives definitions in DefinitionsOrder once reflection is complete, can be nil.
+	// Non-empty CollectDefinitionsOrdered disables collection of definitions into resulting schema.
+	CollectDefinitionsOrdered func(root reflect.Type, name string, schema Schema)
+
+	// CollectDefinitionsWithRoot receives definitions with root type and sequence number of Reflect call
+	// once reflection is complete, can be nil, see CollectDefinitionsWithRoot.
+	// Non-empty CollectDefinitionsWithRoot disables collection of definitions into resulting schema.
+	CollectDefinitionsWithRoot func(root reflect.Type, seq int64, name string, schema Schema)
+
+	// DefinitionsOrder defines order of CollectDefinitionsOrdered calls.
+	DefinitionsOrder DefinitionsOrder
+
 	// DefinitionsPrefix defines location of named schemas, default #/definitions/.
 	DefinitionsPrefix string
 
@@ -278,11 +749,101 @@ type ReflectContext struct {
 	UnnamedFieldWithTag bool
 
 	// EnvelopNullability enables `anyOf` enveloping of "type":"null" instead of injecting into definition.
+	// It is equivalent to NullableRef set to NullableRefAnyOf.
 	EnvelopNullability bool
 
+	// NullableRef defines how nullability is expressed for references, NullableRefDefault is used if not set.
+	NullableRef NullableRefStrategy
+
+	// HoistAnonymousStructs enables definitions for anonymous struct types.
+	HoistAnonymousStructs bool
+
+	// AnonymousStructName overrides default names of hoisted anonymous structs.
+	AnonymousStructName func(t reflect.Type, path []string, defaultDefName string) string
+
 	// InlineRefs tries to inline all types without making references.
 	InlineRefs bool
 
+	// HideDefinition returns true for types that should be inlined instead of having named definitions, can be nil.
+	HideDefinition func(t reflect.Type) bool
+
+	// InlineScalarKeywords is a max number of keywords besides `type` in scalar schemas
+	// that are inlined instead of having named definitions, disabled if zero.
+	InlineScalarKeywords int
+
+	// KeywordsOrder enables deterministic order of `required`, `enum` and definitions, disabled if zero.
+	KeywordsOrder Ordering
+
+	// PropertiesOrder enables order of properties in marshaled schema, disabled if zero.
+	PropertiesOrder PropertyOrdering
+
+	// GoTypeAnnotations enables `x-go-type` and `x-go-name` extensions with originating Go types and field names.
+	GoTypeAnnotations bool
+
+	// ConstantsIndex enables `enum` from constants of named types, see EnumsFromConstants.
+	ConstantsIndex *SourceIndex
+
+	// Report enables generation report, see CollectGenerationReport.
+	Report *GenerationReport
+
+	// DiscriminatorProperty enables OpenAPI `discriminator` of "oneOf" schemas, see Discriminator.
+	DiscriminatorProperty string
+
+	// CommentsIndex enables `title` and `description` from Go doc comments, see CommentsFromSource.
+	CommentsIndex *SourceIndex
+
+	// CacheKey enables caching of Reflect results for calls with options, see CacheKey.
+	CacheKey string
+
+	// GenericDefName enables readable definition names of instantiated generic types, see GenericDefNames.
+	GenericDefName GenericNameFunc
+
+	// Overrides customize types and fields that can not be annotated with tags, see ApplyOverrides.
+	Overrides Overrides
+
+	// Fixtures are JSON documents of reflected value to collect property `examples` from, see ExamplesFromFixtures.
+	Fixtures [][]byte
+
+	// MaxFixtureExamples limits number of property examples collected from Fixtures, 3 is used if zero.
+	MaxFixtureExamples int
+
+	// EnumOneOfConst enables `oneOf` of `const` with `title` instead of named `enum`.
+	EnumOneOfConst bool
+
+	// SourceIndex enables source positions of types and fields, can be nil.
+	SourceIndex *SourceIndex
+
+	// UnionTypesAnyOf enables `anyOf` instead of type array for `types` field tag with multiple types.
+	UnionTypesAnyOf bool
+
+	// AJVStrict enables conversion and verification of reflected schemas for AJV strict mode, see AJVStrict.
+	AJVStrict bool
+
+	// GroupKeyword is a name of extension keyword for `group` field tag, XGroup is used if empty.
+	GroupKeyword string
+
+	// GroupsIndex enables `x-groups` index of property groups in object schemas.
+	GroupsIndex bool
+
+	// FieldEnabled is called for every struct field that would become a property,
+	// field is skipped if false is returned, can be nil.
+	FieldEnabled func(field reflect.StructField, path []string) bool
+
+	// Sensitive defines handling of fields with `sensitive:"true"` tag, tag is ignored if zero.
+	Sensitive SensitiveMode
+
+	// SensitiveKeyword is a name of extension keyword for SensitiveAnnotate, XSensitive is used if empty.
+	SensitiveKeyword string
+
+	// FormatRules enable format inference from property names, see InferFormats.
+	FormatRules []FormatRule
+
+	// ConfirmFormat receives inferred formats, format is assigned if it returns true.
+	ConfirmFormat func(f InferredFormat) bool
+
+	// SampleValues enables documenting non-zero field values of reflected sample, disabled if zero.
+	SampleValues SampleValuesMode
+
 	// RootRef exposes root schema as reference.
 	RootRef bool
 
@@ -310,29 +871,169 @@ type ReflectContext struct {
 	interceptProp        InterceptPropFunc
 	InterceptNullability InterceptNullabilityFunc
 
+	// InterceptItems is called with processed schema of slice and array items, see InterceptItems.
+	InterceptItems InterceptItemsFunc
+
+	// InterceptAdditionalProperties is called with processed schema of map values, see InterceptAdditionalProperties.
+	InterceptAdditionalProperties InterceptAdditionalPropertiesFunc
+
+	schemaHooks []schemaHook
+	propHooks   []propHook
+
+	// KeepNamedExamples keeps XNamedExamples keyword, only plain `examples` are kept by default.
+	KeepNamedExamples bool
+
 	// SkipNonConstraints disables parsing of `default` and `example` field tags.
 	SkipNonConstraints bool
 
+	// ConstraintsByRef keeps definitions of types with registered constraints, see ConstraintsByRef.
+	ConstraintsByRef bool
+
+	// DraftVersion defines JSON Schema draft of reflected schema, see TargetDraft.
+	DraftVersion DraftVersion
+
+	// TimeEncoding defines schema of time.Time, see TimeFormat.
+	TimeEncoding TimeEncoding
+
+	// DropZeroDefaults removes zero value `default` of properties with `omitempty`, see DropZeroDefaults.
+	DropZeroDefaults bool
+
+	// CollectFieldErrors enables reflection of remaining fields after a field fails, see CollectFieldErrors.
+	CollectFieldErrors bool
+
 	// SkipUnsupportedProperties skips properties with unsupported types (func, chan, etc...) instead of failing.
 	SkipUnsupportedProperties bool
 
-	Path           []string
-	definitions    map[refl.TypeString]*Schema // list of all definition objects
-	definitionRefs map[refl.TypeString]Ref
-	typeCycles     map[refl.TypeString]*Schema
-	rootDefName    string
+	Path []string
+
+	pathPointers []pathPointer
+
+	// componentsRefs enables references to "#/components/schemas/" for collected definitions of OpenAPI targets.
+	componentsRefs bool
+
+	packageOptions []packageOptions
+
+	*reflectState
+
+	collectMu  *sync.Mutex
+	collectSeq int64
+}
+
+// reflectState is shared between package scoped copies of ReflectContext.
+type reflectState struct {
+	definitions      map[refl.TypeString]*Schema // list of all definition objects
+	definitionsOrder []refl.TypeString
+	definitionRefs   map[refl.TypeString]Ref
+	refTypes         map[string]refl.TypeString // index of definitionRefs by reference
+	typeCycles       map[refl.TypeString]*Schema
+	rootDefName      string
+	referredDefs     []string
+	hiddenDefs       map[refl.TypeString]bool
+	hiddenInProgress map[refl.TypeString]bool
+	fieldErrors      FieldErrors
+
+	baseContext   *ReflectContext
+	activePackage string
+}
+
+type packageOptions struct {
+	prefix  string
+	options []func(rc *ReflectContext)
+}
+
+// PackageOptions applies options to types with package path that starts with prefix, e.g.
+//
+//	jsonschema.PackageOptions("github.com/acme/legacy/", jsonschema.PropertyNameTag("db"))
+//
+// Options are applied on top of other options of Reflect when such type is reflected, including its fields.
+// Nested types from other packages are reflected with their own options.
+// If multiple prefixes match, the longest one is used.
+func PackageOptions(pkgPathPrefix string, opts ...func(*ReflectContext)) func(*ReflectContext) {
+	return func(rc *ReflectContext) {
+		rc.packageOptions = append(rc.packageOptions, packageOptions{prefix: pkgPathPrefix, options: opts})
+	}
+}
+
+// enterPackage switches options for the package of a type, returned func restores previous options.
+func (rc *ReflectContext) enterPackage(t reflect.Type) func() {
+	if len(rc.packageOptions) == 0 || t.PkgPath() == "" {
+		return nil
+	}
+
+	match := -1
+
+	for i, po := range rc.packageOptions {
+		if strings.HasPrefix(t.PkgPath(), po.prefix) && (match == -1 || len(po.prefix) > len(rc.packageOptions[match].prefix)) {
+			match = i
+		}
+	}
+
+	prefix := ""
+	if match != -1 {
+		prefix = rc.packageOptions[match].prefix
+	}
+
+	if prefix == rc.activePackage {
+		return nil
+	}
+
+	if rc.baseContext == nil {
+		base := *rc
+		rc.baseContext = &base
+	}
+
+	saved := *rc
+	activePackage := rc.activePackage
+
+	*rc = *rc.baseContext
+	rc.Path = saved.Path
+	rc.pathPointers = saved.pathPointers
+	rc.reflectState = saved.reflectState
+	rc.activePackage = prefix
+
+	if match != -1 {
+		for _, o := range rc.packageOptions[match].options {
+			o(rc)
+		}
+	}
+
+	return func() {
+		path, pointers := rc.Path, rc.pathPointers
+		*rc = saved
+		rc.Path = path
+		rc.pathPointers = pointers
+		rc.activePackage = activePackage
+	}
+}
+
+func (rc *ReflectContext) nullableRefStrategy() NullableRefStrategy {
+	if rc.NullableRef == NullableRefDefault && rc.EnvelopNullability {
+		return NullableRefAnyOf
+	}
+
+	return rc.NullableRef
 }
 
 func (rc *ReflectContext) getDefinition(ref string) *Schema {
-	for ts, r := range rc.definitionRefs {
-		if r.Path+r.Name == ref {
-			return rc.definitions[ts]
+	if ts, ok := rc.refTypes[ref]; ok {
+		if def, ok := rc.definitions[ts]; ok {
+			return def
 		}
 	}
 
 	return &Schema{}
 }
 
+func (rc *ReflectContext) setDefinitionRef(typeString refl.TypeString, ref Ref) {
+	if rc.definitionRefs == nil {
+		rc.definitionRefs = make(map[refl.TypeString]Ref, 1)
+		rc.refTypes = make(map[string]refl.TypeString, 1)
+	}
+
+	rc.definitionRefs[typeString] = ref
+	rc.refTypes[ref.Path+ref.Name] = typeString
+}
+
 func (rc *ReflectContext) deprecatedFallback() {
 	if rc.InterceptType != nil {
 		f := rc.InterceptType
@@ -0,0 +1,196 @@
+package jsonschema
+
+import (
+	"sort"
+	"strings"
+)
+
+// Dependencies returns names of definitions that are referenced by each definition of doc.
+//
+// Every definition of doc is present in result, referenced names are unique and sorted. References are
+// recognized in `#/definitions/` and `#/$defs/` locations, references to missing definitions are ignored.
+func Dependencies(doc Schema) map[string][]string {
+	res := make(map[string][]string, len(doc.Definitions))
+
+	for name, def := range doc.Definitions {
+		seen := map[string]bool{}
+		deps := []string{}
+
+		walkSchemas(def.TypeObject, func(s *Schema) {
+			if s.Ref == nil {
+				return
+			}
+
+			dep, ok := localDefinitionName(*s.Ref)
+			if !ok || seen[dep] {
+				return
+			}
+
+			if _, found := doc.Definitions[dep]; !found {
+				return
+			}
+
+			seen[dep] = true
+			deps = append(deps, dep)
+		})
+
+		sort.Strings(deps)
+		res[name] = deps
+	}
+
+	return res
+}
+
+// localDefinitionName returns definition name from a local reference.
+func localDefinitionName(ref string) (string, bool) {
+	for _, prefix := range []string{"#/definitions/", "#/$defs/"} {
+		if strings.HasPrefix(ref, prefix) {
+			name := strings.ReplaceAll(strings.TrimPrefix(ref, prefix), "%25", "%")
+
+			return pointerUnescaper.Replace(name), true
+		}
+	}
+
+	return "", false
+}
+
+// TopologicalOrder returns names of deps so that each name follows names it depends on.
+//
+// Names that are only mentioned as dependencies are included too. Order is deterministic:
+// independent names and members of dependency cycles are sorted alphabetically,
+// cycles do not prevent ordering, use DependencyCycles to detect them.
+func TopologicalOrder(deps map[string][]string) []string {
+	res := make([]string, 0, len(deps))
+	visited := make(map[string]bool, len(deps))
+
+	var visit func(name string)
+
+	visit = func(name string) {
+		if visited[name] {
+			return
+		}
+
+		visited[name] = true
+
+		for _, dep := range sortedCopy(deps[name]) {
+			visit(dep)
+		}
+
+		res = append(res, name)
+	}
+
+	for _, name := range dependencyNames(deps) {
+		visit(name)
+	}
+
+	return res
+}
+
+// DependencyCycles returns groups of names that depend on each other, including names that depend on themselves.
+//
+// Names in a group and groups are sorted alphabetically.
+func DependencyCycles(deps map[string][]string) [][]string {
+	var (
+		res     [][]string
+		index   = map[string]int{}
+		lowLink = map[string]int{}
+		onStack = map[string]bool{}
+		stack   []string
+	)
+
+	// Tarjan's strongly connected components algorithm.
+	var connect func(name string)
+
+	connect = func(name string) {
+		index[name] = len(index)
+		lowLink[name] = index[name]
+
+		stack = append(stack, name)
+		onStack[name] = true
+
+		selfRef := false
+
+		for _, dep := range deps[name] {
+			if dep == name {
+				selfRef = true
+			}
+
+			if _, ok := index[dep]; !ok {
+				connect(dep)
+
+				if lowLink[dep] < lowLink[name] {
+					lowLink[name] = lowLink[dep]
+				}
+			} else if onStack[dep] && index[dep] < lowLink[name] {
+				lowLink[name] = index[dep]
+			}
+		}
+
+		if lowLink[name] != index[name] {
+			return
+		}
+
+		var group []string
+
+		for {
+			n := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[n] = false
+
+			group = append(group, n)
+
+			if n == name {
+				break
+			}
+		}
+
+		if len(group) > 1 || selfRef {
+			sort.Strings(group)
+			res = append(res, group)
+		}
+	}
+
+	for _, name := range dependencyNames(deps) {
+		if _, ok := index[name]; !ok {
+			connect(name)
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i][0] < res[j][0]
+	})
+
+	return res
+}
+
+// dependencyNames returns sorted unique names of deps keys and values.
+func dependencyNames(deps map[string][]string) []string {
+	seen := make(map[string]bool, len(deps))
+	names := make([]string, 0, len(deps))
+
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	for name, dd := range deps {
+		add(name)
+
+		for _, d := range dd {
+			add(d)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+func sortedCopy(l []string) []string {
+	res := append([]string(nil), l...)
+	sort.Strings(res)
+
+	return res
+}
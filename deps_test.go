@@ -0,0 +1,53 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/jsonschema-go"
+)
+
+type depsAddress struct {
+	City string `json:"city"`
+}
+
+type depsUser struct {
+	Address depsAddress `json:"address"`
+	Friends []depsUser  `json:"friends"`
+}
+
+type depsOrder struct {
+	Buyer    depsUser     `json:"buyer"`
+	Shipping *depsAddress `json:"shipping"`
+}
+
+func TestDependencies(t *testing.T) {
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(depsOrder{}, jsonschema.StripDefinitionNamePrefix("JsonschemaGoTestDeps"), jsonschema.RootRef)
+	require.NoError(t, err)
+
+	deps := jsonschema.Dependencies(s)
+	assert.Equal(t, map[string][]string{
+		"Address": {},
+		"Order":   {"Address", "User"},
+		"User":    {"Address", "User"},
+	}, deps)
+
+	assert.Equal(t, []string{"Address", "User", "Order"}, jsonschema.TopologicalOrder(deps))
+	assert.Equal(t, [][]string{{"User"}}, jsonschema.DependencyCycles(deps))
+}
+
+func TestDependencyCycles(t *testing.T) {
+	deps := map[string][]string{
+		"A": {"B"},
+		"B": {"C"},
+		"C": {"A", "D"},
+		"D": {},
+		"E": {"D"},
+	}
+
+	assert.Equal(t, [][]string{{"A", "B", "C"}}, jsonschema.DependencyCycles(deps))
+	assert.Equal(t, []string{"D", "C", "B", "A", "E"}, jsonschema.TopologicalOrder(deps))
+}
@@ -0,0 +1,145 @@
+package jsonschema
+
+import (
+	"fmt"
+)
+
+const (
+	// XCircularRef is the name of JSON property that replaces circular reference in dereferenced schema.
+	XCircularRef = "x-circular-ref"
+
+	// XTruncatedRef is the name of JSON property that replaces reference beyond depth limit in dereferenced schema.
+	XTruncatedRef = "x-truncated-ref"
+)
+
+// DereferenceOptions configures Dereference.
+type DereferenceOptions struct {
+	// MaxDepth limits nesting of inlined references, unlimited if zero.
+	MaxDepth int
+
+	// KeepDefinitions keeps definitions in dereferenced schema, they are removed by default.
+	KeepDefinitions bool
+
+	// Resolver resolves references that are not local to schema, can be nil.
+	Resolver func(ref string) (SchemaOrBool, bool)
+
+	// Stop returns replacement of reference that is not inlined because of a cycle or MaxDepth.
+	// By default, reference is replaced with schema that has XCircularRef or XTruncatedRef keyword
+	// with reference value, e.g. {"x-circular-ref":"#/definitions/Node"}.
+	Stop func(ref string, cycle bool) SchemaOrBool
+}
+
+// Dereference returns a copy of schema with references replaced by their targets,
+// for consumers that cannot follow $ref.
+//
+// Keywords of referencing schema (title, description, type, allOf, anyOf, oneOf) take precedence over target.
+// Recursive references are inlined until a cycle is detected, original schema is not modified.
+func Dereference(doc Schema, options ...func(o *DereferenceOptions)) (Schema, error) {
+	d := dereferencer{document: NewDocument(doc)}
+
+	for _, o := range options {
+		o(&d.DereferenceOptions)
+	}
+
+	if d.Stop == nil {
+		d.Stop = func(ref string, cycle bool) SchemaOrBool {
+			keyword := XTruncatedRef
+			if cycle {
+				keyword = XCircularRef
+			}
+
+			return (&Schema{}).WithExtraPropertiesItem(keyword, ref).ToSchemaOrBool()
+		}
+	}
+
+	root := cloneSchema(doc)
+	if !d.KeepDefinitions {
+		root.Definitions = nil
+	}
+
+	res, err := d.schema(root.ToSchemaOrBool(), nil)
+	if err != nil {
+		return doc, err
+	}
+
+	if res.TypeObject == nil {
+		return doc, fmt.Errorf("root schema is replaced with boolean: %v", *res.TypeBoolean)
+	}
+
+	return *res.TypeObject, nil
+}
+
+type dereferencer struct {
+	DereferenceOptions
+
+	document *Document
+}
+
+func (d *dereferencer) resolve(ref string) (SchemaOrBool, bool) {
+	if s, ok := d.document.Resolve(ref); ok {
+		return s, true
+	}
+
+	if d.Resolver != nil {
+		return d.Resolver(ref)
+	}
+
+	return SchemaOrBool{}, false
+}
+
+// schema dereferences cloned schema in place, stack holds references that are being inlined.
+func (d *dereferencer) schema(s SchemaOrBool, stack []string) (SchemaOrBool, error) {
+	if s.TypeObject == nil {
+		return s, nil
+	}
+
+	c := s.TypeObject
+
+	if c.Ref != nil {
+		ref := *c.Ref
+
+		for _, r := range stack {
+			if r == ref {
+				return d.Stop(ref, true), nil
+			}
+		}
+
+		if d.MaxDepth > 0 && len(stack) >= d.MaxDepth {
+			return d.Stop(ref, false), nil
+		}
+
+		target, ok := d.resolve(ref)
+		if !ok {
+			return s, fmt.Errorf("unresolved reference %q", ref)
+		}
+
+		target, err := d.schema(cloneSchemaOrBool(target), append(stack[:len(stack):len(stack)], ref))
+		if err != nil {
+			return s, err
+		}
+
+		c.Ref = nil
+
+		if target.TypeObject == nil {
+			return target, nil
+		}
+
+		// Target is already dereferenced, only own subschemas of referencing schema are left.
+		if err := mapSubschemas(c, func(sub SchemaOrBool) (SchemaOrBool, error) {
+			return d.schema(sub, stack)
+		}); err != nil {
+			return s, err
+		}
+
+		target.TypeObject.Definitions = nil
+		merged := mergeProjected(*c, *target.TypeObject)
+
+		return merged.ToSchemaOrBool(), nil
+	}
+
+	err := mapSubschemas(c, func(sub SchemaOrBool) (SchemaOrBool, error) {
+		return d.schema(sub, stack)
+	})
+
+	return c.ToSchemaOrBool(), err
+}
@@ -0,0 +1,66 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/jsonschema-go"
+)
+
+type derefNode struct {
+	Name     string      `json:"name"`
+	Children []derefNode `json:"children,omitempty"`
+}
+
+func TestDereference(t *testing.T) {
+	type Address struct {
+		City string `json:"city"`
+	}
+
+	type User struct {
+		Home Address   `json:"home" description:"Home address."`
+		Tree derefNode `json:"tree"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(User{})
+	require.NoError(t, err)
+
+	d, err := jsonschema.Dereference(s)
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "properties":{
+		"home":{"description":"Home address.","properties":{"city":{"type":"string"}},"type":"object"},
+		"tree":{
+		  "properties":{
+			"children":{"items":{"x-circular-ref":"#/definitions/JsonschemaGoTestDerefNode"},"type":"array"},
+			"name":{"type":"string"}
+		  },
+		  "type":"object"
+		}
+	  },
+	  "type":"object"
+	}`, d)
+
+	// Original schema is not modified.
+	assert.NotNil(t, s.Properties["home"].TypeObject.Ref)
+	assert.Len(t, s.Definitions, 2)
+
+	d, err = jsonschema.Dereference(s, func(o *jsonschema.DereferenceOptions) {
+		o.MaxDepth = 1
+		o.KeepDefinitions = true
+	})
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{"type":"string"}`, d.Properties["home"].TypeObject.Properties["city"])
+	assert.Len(t, d.Definitions, 2)
+
+	s.Properties["home"].TypeObject.WithRef("#/definitions/Missing")
+
+	_, err = jsonschema.Dereference(s)
+	assert.EqualError(t, err, `unresolved reference "#/definitions/Missing"`)
+}
@@ -0,0 +1,420 @@
+package jsonschema
+
+import (
+	"strings"
+)
+
+// Draft2020Schema is the URI of JSON Schema draft 2020-12 meta-schema.
+const Draft2020Schema = "https://json-schema.org/draft/2020-12/schema"
+
+// dialectView exposes keywords that have same meaning in draft-07 and draft 2020-12.
+type dialectView struct {
+	sb SchemaOrBool
+	s  *Schema
+}
+
+func newDialectView(sb SchemaOrBool) dialectView {
+	if sb.TypeObject != nil {
+		return dialectView{sb: sb, s: sb.TypeObject}
+	}
+
+	return dialectView{sb: sb, s: &Schema{}}
+}
+
+// SchemaOrBool returns underlying schema.
+func (v dialectView) SchemaOrBool() SchemaOrBool { return v.sb }
+
+// Bool returns value of boolean schema, ok is false for object schema.
+func (v dialectView) Bool() (val bool, ok bool) {
+	if v.sb.TypeBoolean == nil {
+		return false, false
+	}
+
+	return *v.sb.TypeBoolean, true
+}
+
+// ID returns `$id`.
+func (v dialectView) ID() *string { return v.s.ID }
+
+// Comment returns `$comment`.
+func (v dialectView) Comment() *string { return v.s.Comment }
+
+// Title returns `title`.
+func (v dialectView) Title() *string { return v.s.Title }
+
+// Description returns `description`.
+func (v dialectView) Description() *string { return v.s.Description }
+
+// Default returns `default`.
+func (v dialectView) Default() *interface{} { return v.s.Default }
+
+// ReadOnly returns `readOnly`.
+func (v dialectView) ReadOnly() *bool { return v.s.ReadOnly }
+
+// Examples returns `examples`.
+func (v dialectView) Examples() []interface{} { return v.s.Examples }
+
+// MultipleOf returns `multipleOf`.
+func (v dialectView) MultipleOf() *float64 { return v.s.MultipleOf }
+
+// Maximum returns `maximum`.
+func (v dialectView) Maximum() *float64 { return v.s.Maximum }
+
+// ExclusiveMaximum returns `exclusiveMaximum`.
+func (v dialectView) ExclusiveMaximum() *float64 { return v.s.ExclusiveMaximum }
+
+// Minimum returns `minimum`.
+func (v dialectView) Minimum() *float64 { return v.s.Minimum }
+
+// ExclusiveMinimum returns `exclusiveMinimum`.
+func (v dialectView) ExclusiveMinimum() *float64 { return v.s.ExclusiveMinimum }
+
+// MaxLength returns `maxLength`.
+func (v dialectView) MaxLength() *int64 { return v.s.MaxLength }
+
+// MinLength returns `minLength`.
+func (v dialectView) MinLength() int64 { return v.s.MinLength }
+
+// Pattern returns `pattern`.
+func (v dialectView) Pattern() *string { return v.s.Pattern }
+
+// MaxItems returns `maxItems`.
+func (v dialectView) MaxItems() *int64 { return v.s.MaxItems }
+
+// MinItems returns `minItems`.
+func (v dialectView) MinItems() int64 { return v.s.MinItems }
+
+// UniqueItems returns `uniqueItems`.
+func (v dialectView) UniqueItems() *bool { return v.s.UniqueItems }
+
+// MaxProperties returns `maxProperties`.
+func (v dialectView) MaxProperties() *int64 { return v.s.MaxProperties }
+
+// MinProperties returns `minProperties`.
+func (v dialectView) MinProperties() int64 { return v.s.MinProperties }
+
+// Required returns `required`.
+func (v dialectView) Required() []string { return v.s.Required }
+
+// Const returns `const`.
+func (v dialectView) Const() *interface{} { return v.s.Const }
+
+// Enum returns `enum`.
+func (v dialectView) Enum() []interface{} { return v.s.Enum }
+
+// Type returns `type`.
+func (v dialectView) Type() *Type { return v.s.Type }
+
+// Format returns `format`.
+func (v dialectView) Format() *string { return v.s.Format }
+
+// ContentMediaType returns `contentMediaType`.
+func (v dialectView) ContentMediaType() *string { return v.s.ContentMediaType }
+
+// ContentEncoding returns `contentEncoding`.
+func (v dialectView) ContentEncoding() *string { return v.s.ContentEncoding }
+
+// Extra returns value of extension or unknown keyword.
+func (v dialectView) Extra(keyword string) (interface{}, bool) {
+	val, ok := v.s.ExtraProperties[keyword]
+
+	return val, ok
+}
+
+// Draft7 is a read-only view of schema with keywords of JSON Schema draft-07.
+type Draft7 struct {
+	dialectView
+}
+
+// AsDraft7 returns draft-07 view of schema.
+func (s *Schema) AsDraft7() Draft7 {
+	return s.ToSchemaOrBool().AsDraft7()
+}
+
+// AsDraft7 returns draft-07 view of schema.
+func (s SchemaOrBool) AsDraft7() Draft7 {
+	return Draft7{dialectView: newDialectView(s)}
+}
+
+func draft7Ptr(sb *SchemaOrBool) *Draft7 {
+	if sb == nil {
+		return nil
+	}
+
+	v := sb.AsDraft7()
+
+	return &v
+}
+
+func draft7List(l []SchemaOrBool) []Draft7 {
+	if l == nil {
+		return nil
+	}
+
+	res := make([]Draft7, len(l))
+	for i, sb := range l {
+		res[i] = sb.AsDraft7()
+	}
+
+	return res
+}
+
+func draft7Map(m map[string]SchemaOrBool) map[string]Draft7 {
+	if m == nil {
+		return nil
+	}
+
+	res := make(map[string]Draft7, len(m))
+	for k, sb := range m {
+		res[k] = sb.AsDraft7()
+	}
+
+	return res
+}
+
+// Schema returns `$schema`.
+func (v Draft7) Schema() *string { return v.s.Schema }
+
+// Ref returns `$ref`.
+func (v Draft7) Ref() *string { return v.s.Ref }
+
+// Items returns `items` if it is a single schema.
+func (v Draft7) Items() *Draft7 {
+	if v.s.Items == nil {
+		return nil
+	}
+
+	return draft7Ptr(v.s.Items.SchemaOrBool)
+}
+
+// ItemsArray returns `items` if it is an array of schemas.
+func (v Draft7) ItemsArray() []Draft7 {
+	if v.s.Items == nil {
+		return nil
+	}
+
+	return draft7List(v.s.Items.SchemaArray)
+}
+
+// AdditionalItems returns `additionalItems`.
+func (v Draft7) AdditionalItems() *Draft7 { return draft7Ptr(v.s.AdditionalItems) }
+
+// Contains returns `contains`.
+func (v Draft7) Contains() *Draft7 { return draft7Ptr(v.s.Contains) }
+
+// AdditionalProperties returns `additionalProperties`.
+func (v Draft7) AdditionalProperties() *Draft7 { return draft7Ptr(v.s.AdditionalProperties) }
+
+// Definitions returns `definitions`.
+func (v Draft7) Definitions() map[string]Draft7 { return draft7Map(v.s.Definitions) }
+
+// Properties returns `properties`.
+func (v Draft7) Properties() map[string]Draft7 { return draft7Map(v.s.Properties) }
+
+// PatternProperties returns `patternProperties`.
+func (v Draft7) PatternProperties() map[string]Draft7 { return draft7Map(v.s.PatternProperties) }
+
+// Dependencies returns `dependencies`.
+func (v Draft7) Dependencies() map[string]DependenciesAdditionalProperties { return v.s.Dependencies }
+
+// PropertyNames returns `propertyNames`.
+func (v Draft7) PropertyNames() *Draft7 { return draft7Ptr(v.s.PropertyNames) }
+
+// If returns `if`.
+func (v Draft7) If() *Draft7 { return draft7Ptr(v.s.If) }
+
+// Then returns `then`.
+func (v Draft7) Then() *Draft7 { return draft7Ptr(v.s.Then) }
+
+// Else returns `else`.
+func (v Draft7) Else() *Draft7 { return draft7Ptr(v.s.Else) }
+
+// AllOf returns `allOf`.
+func (v Draft7) AllOf() []Draft7 { return draft7List(v.s.AllOf) }
+
+// AnyOf returns `anyOf`.
+func (v Draft7) AnyOf() []Draft7 { return draft7List(v.s.AnyOf) }
+
+// OneOf returns `oneOf`.
+func (v Draft7) OneOf() []Draft7 { return draft7List(v.s.OneOf) }
+
+// Not returns `not`.
+func (v Draft7) Not() *Draft7 { return draft7Ptr(v.s.Not) }
+
+// Draft2020 is a read-only view of schema with keywords of JSON Schema draft 2020-12.
+//
+// Keywords are converted on access: `definitions` become `$defs` (and references to them are rewritten),
+// array form of `items` becomes `prefixItems` with `additionalItems` as `items`,
+// `dependencies` are split into `dependentSchemas` and `dependentRequired`.
+type Draft2020 struct {
+	dialectView
+}
+
+// AsDraft2020 returns draft 2020-12 view of schema.
+func (s *Schema) AsDraft2020() Draft2020 {
+	return s.ToSchemaOrBool().AsDraft2020()
+}
+
+// AsDraft2020 returns draft 2020-12 view of schema.
+func (s SchemaOrBool) AsDraft2020() Draft2020 {
+	return Draft2020{dialectView: newDialectView(s)}
+}
+
+func draft2020Ptr(sb *SchemaOrBool) *Draft2020 {
+	if sb == nil {
+		return nil
+	}
+
+	v := sb.AsDraft2020()
+
+	return &v
+}
+
+func draft2020List(l []SchemaOrBool) []Draft2020 {
+	if l == nil {
+		return nil
+	}
+
+	res := make([]Draft2020, len(l))
+	for i, sb := range l {
+		res[i] = sb.AsDraft2020()
+	}
+
+	return res
+}
+
+func draft2020Map(m map[string]SchemaOrBool) map[string]Draft2020 {
+	if m == nil {
+		return nil
+	}
+
+	res := make(map[string]Draft2020, len(m))
+	for k, sb := range m {
+		res[k] = sb.AsDraft2020()
+	}
+
+	return res
+}
+
+// Schema returns `$schema`, draft-07 meta-schema URI is replaced with draft 2020-12 URI.
+func (v Draft2020) Schema() *string {
+	if v.s.Schema != nil && strings.Contains(*v.s.Schema, "draft-07") {
+		s := Draft2020Schema
+
+		return &s
+	}
+
+	return v.s.Schema
+}
+
+// Ref returns `$ref`, references to `#/definitions/` are rewritten to `#/$defs/`.
+func (v Draft2020) Ref() *string {
+	if v.s.Ref != nil && strings.HasPrefix(*v.s.Ref, "#/definitions/") {
+		ref := "#/$defs/" + strings.TrimPrefix(*v.s.Ref, "#/definitions/")
+
+		return &ref
+	}
+
+	return v.s.Ref
+}
+
+// Defs returns `$defs`.
+func (v Draft2020) Defs() map[string]Draft2020 { return draft2020Map(v.s.Definitions) }
+
+// PrefixItems returns `prefixItems`.
+func (v Draft2020) PrefixItems() []Draft2020 {
+	if v.s.Items == nil {
+		return nil
+	}
+
+	return draft2020List(v.s.Items.SchemaArray)
+}
+
+// Items returns `items`.
+func (v Draft2020) Items() *Draft2020 {
+	if v.s.Items == nil {
+		return nil
+	}
+
+	if v.s.Items.SchemaArray != nil {
+		return draft2020Ptr(v.s.AdditionalItems)
+	}
+
+	return draft2020Ptr(v.s.Items.SchemaOrBool)
+}
+
+// Contains returns `contains`.
+func (v Draft2020) Contains() *Draft2020 { return draft2020Ptr(v.s.Contains) }
+
+// AdditionalProperties returns `additionalProperties`.
+func (v Draft2020) AdditionalProperties() *Draft2020 { return draft2020Ptr(v.s.AdditionalProperties) }
+
+// Properties returns `properties`.
+func (v Draft2020) Properties() map[string]Draft2020 { return draft2020Map(v.s.Properties) }
+
+// PatternProperties returns `patternProperties`.
+func (v Draft2020) PatternProperties() map[string]Draft2020 {
+	return draft2020Map(v.s.PatternProperties)
+}
+
+// DependentSchemas returns `dependentSchemas`.
+func (v Draft2020) DependentSchemas() map[string]Draft2020 {
+	var res map[string]Draft2020
+
+	for k, d := range v.s.Dependencies {
+		if d.SchemaOrBool == nil {
+			continue
+		}
+
+		if res == nil {
+			res = make(map[string]Draft2020)
+		}
+
+		res[k] = d.SchemaOrBool.AsDraft2020()
+	}
+
+	return res
+}
+
+// DependentRequired returns `dependentRequired`.
+func (v Draft2020) DependentRequired() map[string][]string {
+	var res map[string][]string
+
+	for k, d := range v.s.Dependencies {
+		if d.StringArray == nil {
+			continue
+		}
+
+		if res == nil {
+			res = make(map[string][]string)
+		}
+
+		res[k] = d.StringArray
+	}
+
+	return res
+}
+
+// PropertyNames returns `propertyNames`.
+func (v Draft2020) PropertyNames() *Draft2020 { return draft2020Ptr(v.s.PropertyNames) }
+
+// If returns `if`.
+func (v Draft2020) If() *Draft2020 { return draft2020Ptr(v.s.If) }
+
+// Then returns `then`.
+func (v Draft2020) Then() *Draft2020 { return draft2020Ptr(v.s.Then) }
+
+// Else returns `else`.
+func (v Draft2020) Else() *Draft2020 { return draft2020Ptr(v.s.Else) }
+
+// AllOf returns `allOf`.
+func (v Draft2020) AllOf() []Draft2020 { return draft2020List(v.s.AllOf) }
+
+// AnyOf returns `anyOf`.
+func (v Draft2020) AnyOf() []Draft2020 { return draft2020List(v.s.AnyOf) }
+
+// OneOf returns `oneOf`.
+func (v Draft2020) OneOf() []Draft2020 { return draft2020List(v.s.OneOf) }
+
+// Not returns `not`.
+func (v Draft2020) Not() *Draft2020 { return draft2020Ptr(v.s.Not) }
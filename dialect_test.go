@@ -0,0 +1,184 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/jsonschema-go"
+)
+
+func TestSchema_AsDraft2020(t *testing.T) {
+	var s jsonschema.Schema
+
+	require.NoError(t, s.UnmarshalJSON([]byte(`{
+	  "$schema":"http://json-schema.org/draft-07/schema#",
+	  "definitions":{"Name":{"type":"string","minLength":1}},
+	  "properties":{
+		"name":{"$ref":"#/definitions/Name"},
+		"pair":{"items":[{"type":"integer"},{"type":"string"}],"additionalItems":false},
+		"tags":{"items":{"type":"string"}}
+	  },
+	  "dependencies":{"name":["tags"],"pair":{"required":["name"]}}
+	}`)))
+
+	d := s.AsDraft2020()
+
+	assert.Equal(t, jsonschema.Draft2020Schema, *d.Schema())
+	assert.Equal(t, int64(1), d.Defs()["Name"].MinLength())
+	assert.Equal(t, "#/$defs/Name", *d.Properties()["name"].Ref())
+
+	pair := d.Properties()["pair"]
+	assert.Len(t, pair.PrefixItems(), 2)
+
+	b, ok := pair.Items().Bool()
+	assert.True(t, ok)
+	assert.False(t, b)
+
+	assert.Equal(t, jsonschema.String, *d.Properties()["tags"].Items().Type().SimpleTypes)
+	assert.Nil(t, d.Properties()["tags"].PrefixItems())
+
+	assert.Equal(t, map[string][]string{"name": {"tags"}}, d.DependentRequired())
+	assert.Equal(t, []string{"name"}, d.DependentSchemas()["pair"].Required())
+
+	d7 := s.AsDraft7()
+	assert.Equal(t, "#/definitions/Name", *d7.Properties()["name"].Ref())
+	assert.Len(t, d7.Properties()["pair"].ItemsArray(), 2)
+	assert.Nil(t, d7.Properties()["pair"].Items())
+}
+
+type draftPair struct{}
+
+func (draftPair) JSONSchema() (jsonschema.Schema, error) {
+	s := jsonschema.Schema{}
+	s.AddType(jsonschema.Array)
+	s.Items = &jsonschema.Items{SchemaArray: []jsonschema.SchemaOrBool{
+		(&jsonschema.Schema{}).WithType(jsonschema.Integer.Type()).ToSchemaOrBool(),
+		(&jsonschema.Schema{}).WithType(jsonschema.String.Type()).ToSchemaOrBool(),
+	}}
+	s.WithAdditionalItems(jsonschema.SchemaOrBool{TypeBoolean: new(bool)})
+	s.WithDependencies(map[string]jsonschema.DependenciesAdditionalProperties{
+		"never": {StringArray: []string{"ever"}},
+	})
+
+	return s, nil
+}
+
+func TestTargetDraft(t *testing.T) {
+	type Address struct {
+		City string `json:"city"`
+	}
+
+	type Contact struct {
+		Address Address   `json:"address"`
+		Pair    draftPair `json:"pair"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(Contact{}, jsonschema.TargetDraft(jsonschema.DraftVersion2020))
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "$schema":"https://json-schema.org/draft/2020-12/schema",
+	  "properties":{
+		"address":{"$ref":"#/$defs/JsonschemaGoTestAddress"},
+		"pair":{"$ref":"#/$defs/JsonschemaGoTestDraftPair"}
+	  },
+	  "type":"object",
+	  "$defs":{
+		"JsonschemaGoTestDraftPair":{
+		  "items":false,"type":["array","null"],
+		  "prefixItems":[{"type":"integer"},{"type":"string"}],
+		  "dependentRequired":{"never":["ever"]}
+		},
+		"JsonschemaGoTestAddress":{"properties":{"city":{"type":"string"}},"type":"object"}
+	  }
+	}`, s)
+
+	s, err = r.Reflect(Contact{})
+	require.NoError(t, err)
+
+	assert.Nil(t, s.Schema)
+	assert.Equal(t, "#/definitions/JsonschemaGoTestAddress", *s.Properties["address"].TypeObject.Ref)
+}
+
+func TestExclusiveBounds(t *testing.T) {
+	type Range struct {
+		Ratio float64 `json:"ratio" minimum:"0" exclusiveMinimum:"true" maximum:"1" exclusiveMaximum:"false"`
+		Score int     `json:"score" exclusiveMaximum:"100"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(Range{})
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "properties":{
+		"ratio":{"exclusiveMinimum":0,"maximum":1,"type":"number"},
+		"score":{"exclusiveMaximum":100,"type":"integer"}
+	  },
+	  "type":"object"
+	}`, s)
+
+	s, err = r.Reflect(Range{}, jsonschema.TargetDraft(jsonschema.DraftVersion04))
+	require.NoError(t, err)
+
+	j, err := json.Marshal(s)
+	require.NoError(t, err)
+
+	assertjson.Equal(t, []byte(`{
+	  "$schema":"http://json-schema.org/draft-04/schema#",
+	  "properties":{
+		"ratio":{"minimum":0,"exclusiveMinimum":true,"maximum":1,"type":"number"},
+		"score":{"maximum":100,"exclusiveMaximum":true,"type":"integer"}
+	  },
+	  "type":"object"
+	}`), j)
+
+	var d4 jsonschema.Schema
+
+	require.NoError(t, json.Unmarshal(j, &d4))
+	assert.Equal(t, 0.0, *d4.Properties["ratio"].TypeObject.ExclusiveMinimum)
+	assert.Nil(t, d4.Properties["ratio"].TypeObject.Minimum)
+	assert.Equal(t, 1.0, *d4.Properties["ratio"].TypeObject.Maximum)
+	assert.Equal(t, 100.0, *d4.Properties["score"].TypeObject.ExclusiveMaximum)
+	assert.Nil(t, d4.Properties["score"].TypeObject.ExtraProperties)
+
+	// Only own keywords of schema are bounds, property names are not.
+	var mixed jsonschema.Schema
+
+	require.NoError(t, json.Unmarshal([]byte(`{
+	  "maximum":5,"exclusiveMaximum":true,"exclusiveMinimum":1,
+	  "properties":{"exclusiveMinimum":{"minimum":2,"exclusiveMinimum":false}}
+	}`), &mixed))
+	assert.Equal(t, 5.0, *mixed.ExclusiveMaximum)
+	assert.Nil(t, mixed.Maximum)
+	assert.Equal(t, 1.0, *mixed.ExclusiveMinimum)
+	assert.Equal(t, 2.0, *mixed.Properties["exclusiveMinimum"].TypeObject.Minimum)
+	assert.Nil(t, mixed.Properties["exclusiveMinimum"].TypeObject.ExclusiveMinimum)
+
+	assert.EqualError(t, json.Unmarshal([]byte(`{"exclusiveMinimum":"1"}`), &mixed),
+		"exclusiveMinimum: number or boolean expected, string received")
+}
+
+func TestExclusiveBounds_invalid(t *testing.T) {
+	type NoMinimum struct {
+		Ratio float64 `json:"ratio" exclusiveMinimum:"true"`
+	}
+
+	type NoMaximum struct {
+		Ratio float64 `json:"ratio" minimum:"0" exclusiveMaximum:"true"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	_, err := r.Reflect(NoMinimum{})
+	assert.EqualError(t, err, "boolean exclusiveMinimum tag requires minimum tag")
+
+	_, err = r.Reflect(NoMaximum{})
+	assert.EqualError(t, err, "boolean exclusiveMaximum tag requires maximum tag")
+}
@@ -0,0 +1,534 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// Change describes a difference between two schemas.
+type Change struct {
+	// Path is a JSON Pointer to the changed keyword in schema, e.g. "#/properties/name/maxLength".
+	Path string `json:"path"`
+
+	// Keyword is the name of changed keyword.
+	Keyword string `json:"keyword"`
+
+	// Old is a previous value of keyword, nil if keyword was added.
+	Old interface{} `json:"old,omitempty"`
+
+	// New is a new value of keyword, nil if keyword was removed.
+	New interface{} `json:"new,omitempty"`
+
+	// Narrows is true if new schema may reject instances that were valid with old schema.
+	// Such change breaks backward compatibility (readers with new schema can not read old data).
+	Narrows bool `json:"narrows,omitempty"`
+
+	// Widens is true if new schema may accept instances that were invalid with old schema.
+	// Such change breaks forward compatibility (readers with old schema can not read new data).
+	Widens bool `json:"widens,omitempty"`
+}
+
+// String implements fmt.Stringer.
+func (c Change) String() string {
+	switch {
+	case c.Old == nil:
+		return fmt.Sprintf("%s: %s added: %s", c.Path, c.Keyword, jsonString(c.New))
+	case c.New == nil:
+		return fmt.Sprintf("%s: %s removed: %s", c.Path, c.Keyword, jsonString(c.Old))
+	default:
+		return fmt.Sprintf("%s: %s changed: %s -> %s", c.Path, c.Keyword, jsonString(c.Old), jsonString(c.New))
+	}
+}
+
+// IsAnnotation is true if change does not affect validation.
+func (c Change) IsAnnotation() bool {
+	return !c.Narrows && !c.Widens
+}
+
+// Diff compares two schemas and returns list of changes in keywords.
+//
+// Definitions are compared by name, references are not followed.
+// Changes are sorted by path and keyword.
+func Diff(prev, next Schema) []Change {
+	d := differ{}
+	d.schema("#", prev.ToSchemaOrBool(), next.ToSchemaOrBool())
+
+	sort.SliceStable(d.changes, func(i, j int) bool {
+		if d.changes[i].Path == d.changes[j].Path {
+			return d.changes[i].Keyword < d.changes[j].Keyword
+		}
+
+		return d.changes[i].Path < d.changes[j].Path
+	})
+
+	return d.changes
+}
+
+type differ struct {
+	changes []Change
+}
+
+func (d *differ) add(path, keyword string, o, n interface{}, narrows, widens bool) {
+	d.changes = append(d.changes, Change{
+		Path:    path + "/" + keyword,
+		Keyword: keyword,
+		Old:     o,
+		New:     n,
+		Narrows: narrows,
+		Widens:  widens,
+	})
+}
+
+func (d *differ) schema(path string, o, n SchemaOrBool) {
+	if o.TypeBoolean != nil || n.TypeBoolean != nil {
+		ob, nb := o.TypeBoolean, n.TypeBoolean
+
+		if ob != nil && nb != nil && *ob == *nb {
+			return
+		}
+
+		if !jsonEqual(toJSONValue(o), toJSONValue(n)) {
+			oj, nj := toJSONValue(o), toJSONValue(n)
+			d.changes = append(d.changes, Change{
+				Path: path, Keyword: "", Old: oj, New: nj,
+				Narrows: (nb != nil && !*nb) || (ob != nil && *ob),
+				Widens:  (ob != nil && !*ob) || (nb != nil && *nb),
+			})
+		}
+
+		return
+	}
+
+	os, ns := o.TypeObject, n.TypeObject
+	if os == nil {
+		os = &Schema{}
+	}
+
+	if ns == nil {
+		ns = &Schema{}
+	}
+
+	d.annotations(path, os, ns)
+	d.types(path, os, ns)
+	d.numbers(path, os, ns)
+	d.stringKeywords(path, os, ns)
+	d.enum(path, os, ns)
+	d.objects(path, os, ns)
+	d.arrays(path, os, ns)
+	d.composition(path, os, ns)
+	d.definitions(path, os, ns)
+}
+
+func (d *differ) annotations(path string, os, ns *Schema) {
+	pairs := []struct {
+		keyword string
+		o, n    interface{}
+	}{
+		{"$id", os.ID, ns.ID},
+		{"$schema", os.Schema, ns.Schema},
+		{"$comment", os.Comment, ns.Comment},
+		{"title", os.Title, ns.Title},
+		{"description", os.Description, ns.Description},
+		{"default", os.Default, ns.Default},
+		{"readOnly", os.ReadOnly, ns.ReadOnly},
+		{"examples", os.Examples, ns.Examples},
+		{"contentMediaType", os.ContentMediaType, ns.ContentMediaType},
+		{"contentEncoding", os.ContentEncoding, ns.ContentEncoding},
+	}
+
+	for _, p := range pairs {
+		d.value(path, p.keyword, p.o, p.n, false, false, false, false)
+	}
+
+	keys := map[string]bool{}
+	for k := range os.ExtraProperties {
+		keys[k] = true
+	}
+
+	for k := range ns.ExtraProperties {
+		keys[k] = true
+	}
+
+	for _, k := range sortedKeys(keys) {
+		d.value(path, k, os.ExtraProperties[k], ns.ExtraProperties[k], false, false, false, false)
+	}
+}
+
+// value compares keyword values, effects are given for addition and removal,
+// modification is considered both narrowing and widening if any of effects is present.
+func (d *differ) value(path, keyword string, o, n interface{}, addNarrows, addWidens, rmNarrows, rmWidens bool) {
+	oj, nj := toJSONValue(o), toJSONValue(n)
+
+	switch {
+	case oj == nil && nj == nil:
+		return
+	case oj == nil:
+		d.add(path, keyword, nil, nj, addNarrows, addWidens)
+	case nj == nil:
+		d.add(path, keyword, oj, nil, rmNarrows, rmWidens)
+	case !jsonEqual(oj, nj):
+		effect := addNarrows || addWidens || rmNarrows || rmWidens
+		d.add(path, keyword, oj, nj, effect, effect)
+	}
+}
+
+func (d *differ) types(path string, os, ns *Schema) {
+	if os.Type == nil || ns.Type == nil {
+		d.value(path, "type", os.Type, ns.Type, true, false, false, true)
+
+		return
+	}
+
+	ot, nt := typeSet(os.Type), typeSet(ns.Type)
+	narrows, widens := false, false
+
+	for t := range ot {
+		if !nt[t] && !(t == Integer && nt[Number]) {
+			narrows = true
+		}
+	}
+
+	for t := range nt {
+		if !ot[t] && !(t == Integer && ot[Number]) {
+			widens = true
+		}
+	}
+
+	if narrows || widens {
+		d.add(path, "type", toJSONValue(os.Type), toJSONValue(ns.Type), narrows, widens)
+	}
+}
+
+func typeSet(t *Type) map[SimpleType]bool {
+	res := map[SimpleType]bool{}
+
+	if t.SimpleTypes != nil {
+		res[*t.SimpleTypes] = true
+	}
+
+	for _, st := range t.SliceOfSimpleTypeValues {
+		res[st] = true
+	}
+
+	return res
+}
+
+// lowerBound compares keywords that narrow when increased, e.g. minimum.
+func (d *differ) lowerBound(path, keyword string, o, n *float64) {
+	switch {
+	case o == nil && n == nil:
+	case o == nil:
+		d.add(path, keyword, nil, *n, true, false)
+	case n == nil:
+		d.add(path, keyword, *o, nil, false, true)
+	case *o != *n:
+		d.add(path, keyword, *o, *n, *n > *o, *n < *o)
+	}
+}
+
+// upperBound compares keywords that narrow when decreased, e.g. maximum.
+func (d *differ) upperBound(path, keyword string, o, n *float64) {
+	switch {
+	case o == nil && n == nil:
+	case o == nil:
+		d.add(path, keyword, nil, *n, true, false)
+	case n == nil:
+		d.add(path, keyword, *o, nil, false, true)
+	case *o != *n:
+		d.add(path, keyword, *o, *n, *n < *o, *n > *o)
+	}
+}
+
+func intPtr(i int64) *float64 {
+	if i == 0 {
+		return nil
+	}
+
+	f := float64(i)
+
+	return &f
+}
+
+func int64Ptr(i *int64) *float64 {
+	if i == nil {
+		return nil
+	}
+
+	f := float64(*i)
+
+	return &f
+}
+
+func (d *differ) numbers(path string, os, ns *Schema) {
+	d.lowerBound(path, "minimum", os.Minimum, ns.Minimum)
+	d.lowerBound(path, "exclusiveMinimum", os.ExclusiveMinimum, ns.ExclusiveMinimum)
+	d.upperBound(path, "maximum", os.Maximum, ns.Maximum)
+	d.upperBound(path, "exclusiveMaximum", os.ExclusiveMaximum, ns.ExclusiveMaximum)
+	d.value(path, "multipleOf", os.MultipleOf, ns.MultipleOf, true, false, false, true)
+}
+
+func (d *differ) stringKeywords(path string, os, ns *Schema) {
+	d.lowerBound(path, "minLength", intPtr(os.MinLength), intPtr(ns.MinLength))
+	d.upperBound(path, "maxLength", int64Ptr(os.MaxLength), int64Ptr(ns.MaxLength))
+	d.value(path, "pattern", os.Pattern, ns.Pattern, true, false, false, true)
+	d.value(path, "format", os.Format, ns.Format, true, false, false, true)
+}
+
+func (d *differ) enum(path string, os, ns *Schema) {
+	d.value(path, "const", os.Const, ns.Const, true, false, false, true)
+
+	if os.Enum == nil || ns.Enum == nil {
+		d.value(path, "enum", os.Enum, ns.Enum, true, false, false, true)
+
+		return
+	}
+
+	contains := func(items []interface{}, v interface{}) bool {
+		for _, i := range items {
+			if jsonEqual(i, v) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	narrows, widens := false, false
+
+	for _, v := range os.Enum {
+		if !contains(ns.Enum, v) {
+			narrows = true
+		}
+	}
+
+	for _, v := range ns.Enum {
+		if !contains(os.Enum, v) {
+			widens = true
+		}
+	}
+
+	if narrows || widens {
+		d.add(path, "enum", toJSONValue(os.Enum), toJSONValue(ns.Enum), narrows, widens)
+	}
+}
+
+func isClosed(s *Schema) bool {
+	return s.AdditionalProperties != nil && s.AdditionalProperties.TypeBoolean != nil && !*s.AdditionalProperties.TypeBoolean
+}
+
+func (d *differ) objects(path string, os, ns *Schema) {
+	d.lowerBound(path, "minProperties", intPtr(os.MinProperties), intPtr(ns.MinProperties))
+	d.upperBound(path, "maxProperties", int64Ptr(os.MaxProperties), int64Ptr(ns.MaxProperties))
+
+	oreq, nreq := map[string]bool{}, map[string]bool{}
+
+	for _, r := range os.Required {
+		oreq[r] = true
+	}
+
+	for _, r := range ns.Required {
+		nreq[r] = true
+	}
+
+	for _, r := range ns.Required {
+		if !oreq[r] {
+			d.changes = append(d.changes, Change{Path: path + "/required", Keyword: "required", New: r, Narrows: true})
+		}
+	}
+
+	for _, r := range os.Required {
+		if !nreq[r] {
+			d.changes = append(d.changes, Change{Path: path + "/required", Keyword: "required", Old: r, Widens: true})
+		}
+	}
+
+	// Adding property to open object narrows it, adding property to closed object widens it.
+	oClosed := isClosed(os)
+
+	d.schemaMap(path, "properties", os.Properties, ns.Properties, !oClosed, oClosed)
+	d.schemaMap(path, "patternProperties", os.PatternProperties, ns.PatternProperties, true, false)
+	d.optionalSchema(path, "additionalProperties", os.AdditionalProperties, ns.AdditionalProperties)
+	d.optionalSchema(path, "propertyNames", os.PropertyNames, ns.PropertyNames)
+
+	deps := map[string]bool{}
+	for k := range os.Dependencies {
+		deps[k] = true
+	}
+
+	for k := range ns.Dependencies {
+		deps[k] = true
+	}
+
+	for _, k := range sortedKeys(deps) {
+		o, oOk := os.Dependencies[k]
+		n, nOk := ns.Dependencies[k]
+
+		var ov, nv interface{}
+		if oOk {
+			ov = o
+		}
+
+		if nOk {
+			nv = n
+		}
+
+		d.value(path+"/dependencies", k, ov, nv, true, false, false, true)
+	}
+}
+
+func (d *differ) schemaMap(path, keyword string, o, n map[string]SchemaOrBool, addNarrows, addWidens bool) {
+	keys := map[string]bool{}
+	for k := range o {
+		keys[k] = true
+	}
+
+	for k := range n {
+		keys[k] = true
+	}
+
+	for _, k := range sortedKeys(keys) {
+		os, oOk := o[k]
+		ns, nOk := n[k]
+		p := path + "/" + keyword + "/" + escapePointerToken(k)
+
+		switch {
+		case !oOk:
+			d.changes = append(d.changes, Change{
+				Path: p, Keyword: keyword, New: toJSONValue(ns), Narrows: addNarrows, Widens: addWidens,
+			})
+		case !nOk:
+			d.changes = append(d.changes, Change{
+				Path: p, Keyword: keyword, Old: toJSONValue(os), Narrows: addWidens, Widens: addNarrows,
+			})
+		default:
+			d.schema(p, os, ns)
+		}
+	}
+}
+
+func (d *differ) optionalSchema(path, keyword string, o, n *SchemaOrBool) {
+	switch {
+	case o == nil && n == nil:
+	case o == nil:
+		d.schema(path+"/"+keyword, SchemaOrBool{TypeBoolean: boolPtr(true)}, *n)
+	case n == nil:
+		d.schema(path+"/"+keyword, *o, SchemaOrBool{TypeBoolean: boolPtr(true)})
+	default:
+		d.schema(path+"/"+keyword, *o, *n)
+	}
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func (d *differ) arrays(path string, os, ns *Schema) {
+	d.lowerBound(path, "minItems", intPtr(os.MinItems), intPtr(ns.MinItems))
+	d.upperBound(path, "maxItems", int64Ptr(os.MaxItems), int64Ptr(ns.MaxItems))
+	d.value(path, "uniqueItems", os.UniqueItems, ns.UniqueItems, true, false, false, true)
+	d.optionalSchema(path, "contains", os.Contains, ns.Contains)
+	d.optionalSchema(path, "additionalItems", os.AdditionalItems, ns.AdditionalItems)
+
+	switch {
+	case os.Items == nil && ns.Items == nil:
+	case os.Items != nil && ns.Items != nil && os.Items.SchemaOrBool != nil && ns.Items.SchemaOrBool != nil:
+		d.schema(path+"/items", *os.Items.SchemaOrBool, *ns.Items.SchemaOrBool)
+	case os.Items != nil && ns.Items != nil && len(os.Items.SchemaArray) == len(ns.Items.SchemaArray) &&
+		os.Items.SchemaOrBool == nil && ns.Items.SchemaOrBool == nil:
+		for i := range os.Items.SchemaArray {
+			d.schema(path+"/items/"+strconv.Itoa(i), os.Items.SchemaArray[i], ns.Items.SchemaArray[i])
+		}
+	default:
+		d.value(path, "items", os.Items, ns.Items, true, false, false, true)
+	}
+}
+
+func (d *differ) composition(path string, os, ns *Schema) {
+	d.value(path, "$ref", os.Ref, ns.Ref, true, false, false, true)
+	d.schemaList(path, "allOf", os.AllOf, ns.AllOf, true, false)
+	d.schemaList(path, "anyOf", os.AnyOf, ns.AnyOf, false, true)
+	d.schemaList(path, "oneOf", os.OneOf, ns.OneOf, true, true)
+	d.value(path, "not", os.Not, ns.Not, true, false, false, true)
+	d.value(path, "if", os.If, ns.If, true, true, true, true)
+	d.value(path, "then", os.Then, ns.Then, true, false, false, true)
+	d.value(path, "else", os.Else, ns.Else, true, false, false, true)
+}
+
+func (d *differ) schemaList(path, keyword string, o, n []SchemaOrBool, addNarrows, addWidens bool) {
+	for i := 0; i < len(o) || i < len(n); i++ {
+		p := path + "/" + keyword + "/" + strconv.Itoa(i)
+
+		switch {
+		case i >= len(o):
+			d.changes = append(d.changes, Change{
+				Path: p, Keyword: keyword, New: toJSONValue(n[i]), Narrows: addNarrows, Widens: addWidens,
+			})
+		case i >= len(n):
+			d.changes = append(d.changes, Change{
+				Path: p, Keyword: keyword, Old: toJSONValue(o[i]), Narrows: addWidens, Widens: addNarrows,
+			})
+		default:
+			d.schema(p, o[i], n[i])
+		}
+	}
+}
+
+func (d *differ) definitions(path string, os, ns *Schema) {
+	keys := map[string]bool{}
+	for k := range os.Definitions {
+		keys[k] = true
+	}
+
+	for k := range ns.Definitions {
+		keys[k] = true
+	}
+
+	for _, k := range sortedKeys(keys) {
+		o, oOk := os.Definitions[k]
+		n, nOk := ns.Definitions[k]
+		p := path + "/definitions/" + escapePointerToken(k)
+
+		switch {
+		case !oOk:
+			d.changes = append(d.changes, Change{Path: p, Keyword: "definitions", New: toJSONValue(n)})
+		case !nOk:
+			d.changes = append(d.changes, Change{Path: p, Keyword: "definitions", Old: toJSONValue(o)})
+		default:
+			d.schema(p, o, n)
+		}
+	}
+}
+
+func sortedKeys(m map[string]bool) []string {
+	res := make([]string, 0, len(m))
+	for k := range m {
+		res = append(res, k)
+	}
+
+	sort.Strings(res)
+
+	return res
+}
+
+// toJSONValue converts value to a result of JSON round trip, nil pointers and slices become nil.
+func toJSONValue(v interface{}) interface{} {
+	if rv := reflect.ValueOf(v); !rv.IsValid() ||
+		((rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Slice || rv.Kind() == reflect.Map) && rv.IsNil()) {
+		return nil
+	}
+
+	j, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+
+	var res interface{}
+	if err := json.Unmarshal(j, &res); err != nil {
+		return nil
+	}
+
+	return res
+}
@@ -0,0 +1,78 @@
+package jsonschema_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/jsonschema-go"
+)
+
+func TestDiff(t *testing.T) {
+	type ItemV1 struct {
+		Name string `json:"name" maxLength:"10"`
+	}
+
+	type OrderV1 struct {
+		ID     int      `json:"id" minimum:"1"`
+		Status string   `json:"status" enum:"new,paid" description:"Order status."`
+		Items  []ItemV1 `json:"items"`
+		Note   string   `json:"note"`
+	}
+
+	type ItemV2 struct {
+		Name string `json:"name" maxLength:"20"`
+	}
+
+	type OrderV2 struct {
+		ID     int      `json:"id" minimum:"10" required:"true"`
+		Status string   `json:"status" enum:"new,paid,shipped" description:"Status."`
+		Items  []ItemV2 `json:"items"`
+		Total  float64  `json:"total"`
+	}
+
+	r1, r2 := jsonschema.Reflector{}, jsonschema.Reflector{}
+
+	v1, err := r1.Reflect(OrderV1{}, jsonschema.StripDefinitionNamePrefix("JsonschemaGoTest"),
+		jsonschema.InterceptDefName(func(_ reflect.Type, defaultDefName string) string {
+			return strings.TrimSuffix(defaultDefName, "V1")
+		}))
+	require.NoError(t, err)
+
+	v2, err := r2.Reflect(OrderV2{}, jsonschema.StripDefinitionNamePrefix("JsonschemaGoTest"),
+		jsonschema.InterceptDefName(func(_ reflect.Type, defaultDefName string) string {
+			return strings.TrimSuffix(defaultDefName, "V2")
+		}))
+	require.NoError(t, err)
+
+	var res []string
+
+	for _, c := range jsonschema.Diff(v1, v2) {
+		res = append(res, c.String())
+
+		switch c.Path {
+		case "#/properties/status/description":
+			assert.True(t, c.IsAnnotation())
+		case "#/properties/id/minimum", "#/required", "#/properties/total":
+			assert.True(t, c.Narrows)
+			assert.False(t, c.Widens)
+		case "#/definitions/Item/properties/name/maxLength", "#/properties/status/enum", "#/properties/note":
+			assert.False(t, c.Narrows)
+			assert.True(t, c.Widens)
+		}
+	}
+
+	assert.Equal(t, []string{
+		`#/definitions/Item/properties/name/maxLength: maxLength changed: 10 -> 20`,
+		`#/properties/id/minimum: minimum changed: 1 -> 10`,
+		`#/properties/note: properties removed: {"type":"string"}`,
+		`#/properties/status/description: description changed: "Order status." -> "Status."`,
+		`#/properties/status/enum: enum changed: ["new","paid"] -> ["new","paid","shipped"]`,
+		`#/properties/total: properties added: {"type":"number"}`,
+		`#/required: required added: "id"`,
+	}, res)
+
+	assert.Empty(t, jsonschema.Diff(v1, v1))
+}
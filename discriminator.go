@@ -0,0 +1,103 @@
+package jsonschema
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// XDiscriminator is the name of JSON property to store OpenAPI discriminator object.
+const XDiscriminator = "discriminator"
+
+// RegisterOneOf makes interface type reflect as "oneOf" of its implementations, e.g. for a sum type
+//
+//	type Shape interface{ isShape() }
+//
+//	r.RegisterOneOf((*Shape)(nil), Circle{}, Square{})
+//
+// fields of Shape type get `{"oneOf":[{"$ref":"#/definitions/Circle"},{"$ref":"#/definitions/Square"}]}`
+// instead of an empty schema. Use Discriminator option to add discriminator mapping.
+func (r *Reflector) RegisterOneOf(iface interface{}, variants ...interface{}) error {
+	t := reflect.TypeOf(iface)
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Interface {
+		return fmt.Errorf("pointer to interface expected, %T received", iface)
+	}
+
+	for _, v := range variants {
+		if v == nil {
+			return fmt.Errorf("nil variant of %s", t.Elem())
+		}
+
+		if !reflect.TypeOf(v).Implements(t.Elem()) && !reflect.PtrTo(reflect.TypeOf(v)).Implements(t.Elem()) {
+			return fmt.Errorf("%T does not implement %s", v, t.Elem())
+		}
+	}
+
+	r.AddTypeMapping(iface, OneOf(variants...))
+
+	return nil
+}
+
+// Discriminator enables OpenAPI `discriminator` of "oneOf" schemas with property name, e.g. for
+//
+//	type Circle struct {
+//		Kind   string  `json:"kind" const:"circle"`
+//		Radius float64 `json:"radius"`
+//	}
+//
+// and similar Square with `const:"square"`, "oneOf" of Circle and Square gets
+// `"discriminator":{"propertyName":"kind","mapping":{"circle":"#/definitions/Circle","square":"#/definitions/Square"}}`.
+//
+// Discriminator is added if all variants are objects with the property, mapping is made of variants that are
+// references and have `const` (or single `enum`) value of the property.
+func Discriminator(propertyName string) func(rc *ReflectContext) {
+	return func(rc *ReflectContext) {
+		rc.DiscriminatorProperty = propertyName
+	}
+}
+
+func reflectDiscriminator(schema *Schema, rc *ReflectContext) {
+	if rc.DiscriminatorProperty == "" || len(schema.OneOf) == 0 {
+		return
+	}
+
+	mapping := map[string]string{}
+
+	for _, v := range schema.OneOf {
+		s := v.TypeObject
+		if s == nil {
+			return
+		}
+
+		if s.Ref != nil {
+			s = rc.getDefinition(*s.Ref)
+		}
+
+		p, ok := s.Properties[rc.DiscriminatorProperty]
+		if !ok || p.TypeObject == nil {
+			return
+		}
+
+		if v.TypeObject.Ref == nil {
+			continue
+		}
+
+		var value interface{}
+
+		if p.TypeObject.Const != nil {
+			value = *p.TypeObject.Const
+		} else if len(p.TypeObject.Enum) == 1 {
+			value = p.TypeObject.Enum[0]
+		}
+
+		if name, ok := value.(string); ok {
+			mapping[name] = *v.TypeObject.Ref
+		}
+	}
+
+	d := map[string]interface{}{"propertyName": rc.DiscriminatorProperty}
+	if len(mapping) > 0 {
+		d["mapping"] = mapping
+	}
+
+	schema.WithExtraPropertiesItem(XDiscriminator, d)
+}
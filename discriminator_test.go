@@ -0,0 +1,85 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/jsonschema-go"
+)
+
+type shape interface {
+	isShape()
+}
+
+type circle struct {
+	Kind   string  `json:"kind" const:"circle"`
+	Radius float64 `json:"radius"`
+}
+
+func (circle) isShape() {}
+
+type square struct {
+	Kind string  `json:"kind" enum:"square"`
+	Side float64 `json:"side"`
+}
+
+func (*square) isShape() {}
+
+func TestReflector_RegisterOneOf(t *testing.T) {
+	type drawing struct {
+		Shapes []shape `json:"shapes"`
+		Main   shape   `json:"main"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	assert.EqualError(t, r.RegisterOneOf(shape(nil), circle{}), "pointer to interface expected, <nil> received")
+	assert.EqualError(t, r.RegisterOneOf((*shape)(nil), 1), "int does not implement jsonschema_test.shape")
+	require.NoError(t, r.RegisterOneOf((*shape)(nil), circle{}, square{}))
+
+	s, err := r.Reflect(drawing{}, jsonschema.Discriminator("kind"),
+		jsonschema.StripDefinitionNamePrefix("JsonschemaGoTest"))
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "definitions":{
+		"Circle":{
+		  "properties":{"kind":{"const":"circle","type":"string"},"radius":{"type":"number"}},
+		  "type":"object"
+		},
+		"Square":{
+		  "properties":{"kind":{"enum":["square"],"type":"string"},"side":{"type":"number"}},
+		  "type":"object"
+		}
+	  },
+	  "properties":{
+		"main":{
+		  "discriminator":{
+			"mapping":{"circle":"#/definitions/Circle","square":"#/definitions/Square"},
+			"propertyName":"kind"
+		  },
+		  "oneOf":[{"$ref":"#/definitions/Circle"},{"$ref":"#/definitions/Square"}]
+		},
+		"shapes":{
+		  "items":{
+			"discriminator":{
+			  "mapping":{"circle":"#/definitions/Circle","square":"#/definitions/Square"},
+			  "propertyName":"kind"
+			},
+			"oneOf":[{"$ref":"#/definitions/Circle"},{"$ref":"#/definitions/Square"}]
+		  },
+		  "type":["array","null"]
+		}
+	  },
+	  "type":"object"
+	}`, s)
+
+	// Discriminator is not added without option.
+	s, err = r.Reflect(drawing{}, jsonschema.InlineRefs)
+	require.NoError(t, err)
+
+	_, ok := s.Properties["main"].TypeObject.ExtraProperties[jsonschema.XDiscriminator]
+	assert.False(t, ok)
+}
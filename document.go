@@ -0,0 +1,251 @@
+package jsonschema
+
+import (
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Document is a read-only view of a finished schema with precomputed indexes.
+//
+// Subschemas are indexed by JSON Pointer, definitions by name and references by target,
+// so lookups do not need to traverse the schema. Document is safe for concurrent use,
+// schema must not be modified after NewDocument.
+type Document struct {
+	schema   Schema
+	pointers map[string]SchemaOrBool
+	referrer map[string][]string
+	anchors  map[string]string
+}
+
+// NewDocument indexes schema.
+func NewDocument(schema Schema) *Document {
+	d := &Document{
+		schema:   schema,
+		pointers: map[string]SchemaOrBool{},
+		referrer: map[string][]string{},
+		anchors:  map[string]string{},
+	}
+
+	d.index("", schema.ToSchemaOrBool())
+
+	for _, l := range d.referrer {
+		sort.Strings(l)
+	}
+
+	return d
+}
+
+// Schema returns indexed schema.
+func (d *Document) Schema() Schema {
+	return d.schema
+}
+
+// Lookup returns subschema by JSON Pointer, e.g. "#/properties/id" or "/properties/id".
+func (d *Document) Lookup(pointer string) (SchemaOrBool, bool) {
+	s, ok := d.pointers[strings.TrimPrefix(pointer, "#")]
+
+	return s, ok
+}
+
+// Definition returns definition by name from `definitions` or `$defs`.
+func (d *Document) Definition(name string) (SchemaOrBool, bool) {
+	if s, ok := d.Lookup("/definitions/" + escapePointerToken(name)); ok {
+		return s, true
+	}
+
+	return d.Lookup("/$defs/" + escapePointerToken(name))
+}
+
+// Resolve returns target of local reference, e.g. "#/definitions/Foo" or "#foo" for a named anchor.
+//
+// It can be used as a reference resolver of NewValidator.
+func (d *Document) Resolve(ref string) (SchemaOrBool, bool) {
+	ptr, ok := d.refPointer(ref)
+	if !ok {
+		return SchemaOrBool{}, false
+	}
+
+	return d.pointers[ptr], true
+}
+
+// refPointer returns JSON Pointer of local reference target.
+func (d *Document) refPointer(ref string) (string, bool) {
+	if !strings.HasPrefix(ref, "#") {
+		return "", false
+	}
+
+	fragment := strings.TrimPrefix(ref, "#")
+
+	if fragment != "" && !strings.HasPrefix(fragment, "/") {
+		ptr, ok := d.anchors[fragment]
+
+		return ptr, ok
+	}
+
+	if _, ok := d.pointers[fragment]; ok {
+		return fragment, true
+	}
+
+	// Fragment can be percent-encoded, e.g. "%25" in definition names.
+	if unescaped, err := url.PathUnescape(fragment); err == nil {
+		if _, ok := d.pointers[unescaped]; ok {
+			return unescaped, true
+		}
+	}
+
+	return "", false
+}
+
+// ReferencedBy returns sorted JSON Pointers of subschemas that have reference to ref.
+func (d *Document) ReferencedBy(ref string) []string {
+	return d.referrer[ref]
+}
+
+// Pointers returns sorted JSON Pointers of all subschemas, root schema has empty pointer.
+func (d *Document) Pointers() []string {
+	res := make([]string, 0, len(d.pointers))
+	for p := range d.pointers {
+		res = append(res, p)
+	}
+
+	sort.Strings(res)
+
+	return res
+}
+
+func (d *Document) index(ptr string, s SchemaOrBool) {
+	if _, ok := d.pointers[ptr]; ok {
+		return
+	}
+
+	d.pointers[ptr] = s
+
+	schema := s.TypeObject
+	if schema == nil {
+		return
+	}
+
+	if schema.Ref != nil {
+		d.referrer[*schema.Ref] = append(d.referrer[*schema.Ref], ptr)
+	}
+
+	if anchor := schemaAnchor(schema); anchor != "" {
+		if _, ok := d.anchors[anchor]; !ok {
+			d.anchors[anchor] = ptr
+		}
+	}
+
+	for _, sub := range subschemas(schema) {
+		d.index(ptr+sub.ptr, sub.schema)
+	}
+}
+
+// schemaAnchor returns plain name fragment of `$anchor` or of `$id` (draft-07 style, e.g. "#foo").
+func schemaAnchor(schema *Schema) string {
+	if a, ok := schema.ExtraProperties["$anchor"].(string); ok {
+		return a
+	}
+
+	if schema.ID != nil && strings.HasPrefix(*schema.ID, "#") && !strings.HasPrefix(*schema.ID, "#/") {
+		return strings.TrimPrefix(*schema.ID, "#")
+	}
+
+	return ""
+}
+
+type subschema struct {
+	ptr        string // JSON Pointer relative to parent schema, e.g. "/properties/id"
+	schema     SchemaOrBool
+	definition bool // subschema is a member of `definitions` or `$defs`
+}
+
+// subschemas returns direct subschemas of schema in a deterministic order.
+func subschemas(schema *Schema) []subschema {
+	var res []subschema
+
+	sub := func(keyword string, sb *SchemaOrBool) {
+		if sb != nil {
+			res = append(res, subschema{ptr: "/" + keyword, schema: *sb})
+		}
+	}
+
+	list := func(keyword string, l []SchemaOrBool) {
+		for i, sb := range l {
+			res = append(res, subschema{ptr: "/" + keyword + "/" + strconv.Itoa(i), schema: sb})
+		}
+	}
+
+	dict := func(keyword string, m map[string]SchemaOrBool, definition bool) {
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			res = append(res, subschema{
+				ptr: "/" + keyword + "/" + escapePointerToken(k), schema: m[k], definition: definition,
+			})
+		}
+	}
+
+	sub("additionalItems", schema.AdditionalItems)
+
+	if schema.Items != nil {
+		sub("items", schema.Items.SchemaOrBool)
+		list("items", schema.Items.SchemaArray)
+	}
+
+	sub("contains", schema.Contains)
+	sub("additionalProperties", schema.AdditionalProperties)
+	dict("definitions", schema.Definitions, true)
+	dict("$defs", extraDefinitions(schema.ExtraProperties["$defs"]), true)
+	dict("properties", schema.Properties, false)
+	dict("patternProperties", schema.PatternProperties, false)
+
+	deps := make(map[string]SchemaOrBool, len(schema.Dependencies))
+
+	for k, dep := range schema.Dependencies {
+		if dep.SchemaOrBool != nil {
+			deps[k] = *dep.SchemaOrBool
+		}
+	}
+
+	dict("dependencies", deps, false)
+	sub("propertyNames", schema.PropertyNames)
+	sub("if", schema.If)
+	sub("then", schema.Then)
+	sub("else", schema.Else)
+	list("allOf", schema.AllOf)
+	list("anyOf", schema.AnyOf)
+	list("oneOf", schema.OneOf)
+	sub("not", schema.Not)
+
+	return res
+}
+
+// extraDefinitions returns `$defs` of draft 2020-12 that are stored in Schema.ExtraProperties,
+// either by TargetDraft or by unmarshaling.
+func extraDefinitions(v interface{}) map[string]SchemaOrBool {
+	switch defs := v.(type) {
+	case nil:
+		return nil
+	case map[string]SchemaOrBool:
+		return defs
+	default:
+		j, err := jsonMarshal(defs)
+		if err != nil {
+			return nil
+		}
+
+		var res map[string]SchemaOrBool
+		if err := jsonUnmarshal(j, &res); err != nil {
+			return nil
+		}
+
+		return res
+	}
+}
@@ -0,0 +1,57 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/jsonschema-go"
+)
+
+func TestNewDocument(t *testing.T) {
+	type Item struct {
+		Name string `json:"name" minLength:"1"`
+	}
+
+	type Order struct {
+		ID    int    `json:"id"`
+		Items []Item `json:"items"`
+		Main  *Item  `json:"main"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(Order{})
+	require.NoError(t, err)
+
+	d := jsonschema.NewDocument(s)
+
+	def, ok := d.Definition("JsonschemaGoTestItem")
+	require.True(t, ok)
+	assert.Equal(t, int64(1), def.TypeObject.Properties["name"].TypeObject.MinLength)
+
+	res, ok := d.Resolve("#/definitions/JsonschemaGoTestItem")
+	require.True(t, ok)
+	assert.Equal(t, def, res)
+
+	_, ok = d.Resolve("other.json#/definitions/JsonschemaGoTestItem")
+	assert.False(t, ok)
+
+	id, ok := d.Lookup("#/properties/id")
+	require.True(t, ok)
+	assert.True(t, id.TypeObject.HasType(jsonschema.Integer))
+
+	_, ok = d.Lookup("/properties/missing")
+	assert.False(t, ok)
+
+	assert.Equal(t, []string{"/properties/items/items", "/properties/main"},
+		d.ReferencedBy("#/definitions/JsonschemaGoTestItem"))
+
+	assert.Equal(t, []string{
+		"", "/definitions/JsonschemaGoTestItem", "/definitions/JsonschemaGoTestItem/properties/name",
+		"/properties/id", "/properties/items", "/properties/items/items", "/properties/main",
+	}, d.Pointers())
+
+	v := jsonschema.NewValidator(s, d.Resolve)
+	assert.Error(t, v.ValidateJSON([]byte(`{"main":{"name":""}}`)))
+}
@@ -0,0 +1,121 @@
+package jsonschema
+
+import "strings"
+
+// DraftVersion defines JSON Schema draft of reflected schema.
+type DraftVersion int
+
+// Draft versions.
+const (
+	// DraftVersion07 is JSON Schema draft-07, default.
+	DraftVersion07 DraftVersion = iota
+
+	// DraftVersion2020 is JSON Schema draft 2020-12.
+	DraftVersion2020
+
+	// DraftVersion04 is JSON Schema draft-04.
+	DraftVersion04
+
+	// DraftVersionOpenAPI30 is a JSON Schema subset of OpenAPI 3.0 Schema Object.
+	DraftVersionOpenAPI30
+)
+
+// TargetDraft sets up JSON Schema draft of reflected schema.
+//
+// With DraftVersion2020 definitions are stored in `$defs` (unless DefinitionsPrefix is changed),
+// `$schema` of root schema is set to Draft2020Schema, array form of `items` becomes `prefixItems`
+// with `additionalItems` as `items`, and `dependencies` are split into `dependentRequired` and `dependentSchemas`.
+// Keywords of 2020-12 are stored in Schema.ExtraProperties.
+//
+// With DraftVersion04 `$schema` of root schema is set to Draft04Schema and numeric `exclusiveMinimum`
+// and `exclusiveMaximum` are converted to boolean form along with `minimum` and `maximum`, other keywords
+// are not changed.
+//
+// With DraftVersionOpenAPI30 definitions are referenced in "#/components/schemas/" (unless DefinitionsPrefix
+// is changed) if they are collected with CollectDefinitions, CollectDefinitionsOrdered or CollectDefinitionsWithRoot,
+// otherwise definitions stay in `definitions` of reflected schema, so that references can be resolved.
+// `null` type is replaced with `nullable: true`, multiple types with `anyOf`, `const` with `enum`,
+// `examples` with `example`, exclusive bounds have boolean form and keywords that are not supported by
+// OpenAPI 3.0 (e.g. `if`, `patternProperties`, `$schema`) are removed.
+func TargetDraft(v DraftVersion) func(rc *ReflectContext) {
+	return func(rc *ReflectContext) {
+		rc.DraftVersion = v
+		rc.componentsRefs = v == DraftVersionOpenAPI30
+
+		if v == DraftVersion2020 && rc.DefinitionsPrefix == "#/definitions/" {
+			rc.DefinitionsPrefix = "#/$defs/"
+		}
+	}
+}
+
+// applyComponentsRefs references definitions in "#/components/schemas/" for OpenAPI targets
+// if definitions are collected out of reflected schema and DefinitionsPrefix is not changed.
+func (rc *ReflectContext) applyComponentsRefs() {
+	if !rc.componentsRefs || !rc.collectsDefinitions() {
+		return
+	}
+
+	if rc.DefinitionsPrefix == "#/definitions/" || (rc.DraftVersion == DraftVersion2020 && rc.DefinitionsPrefix == "#/$defs/") {
+		rc.DefinitionsPrefix = "#/components/schemas/"
+	}
+}
+
+// toDraft04 converts draft-07 exclusive bounds of schema and its subschemas in place.
+func toDraft04(schema *Schema) {
+	walkSchemas(schema, exclusiveBoundsToDraft04)
+}
+
+// toDraft2020 converts draft-07 keywords of schema and its subschemas in place.
+func toDraft2020(schema *Schema) {
+	var all []*Schema
+
+	walkSchemas(schema, func(s *Schema) {
+		all = append(all, s)
+	})
+
+	// Schemas are converted after walking, because converted subschemas are moved out of reach of walkSchemas.
+	for _, s := range all {
+		draft2020Keywords(s)
+	}
+}
+
+func draft2020Keywords(s *Schema) {
+	if s.Ref != nil && strings.HasPrefix(*s.Ref, "#/definitions/") {
+		s.WithRef("#/$defs/" + strings.TrimPrefix(*s.Ref, "#/definitions/"))
+	}
+
+	if s.Items != nil && s.Items.SchemaArray != nil {
+		s.WithExtraPropertiesItem("prefixItems", s.Items.SchemaArray)
+		s.Items = nil
+
+		if s.AdditionalItems != nil {
+			s.Items = &Items{SchemaOrBool: s.AdditionalItems}
+			s.AdditionalItems = nil
+		}
+	}
+
+	if len(s.Dependencies) == 0 {
+		return
+	}
+
+	required := map[string][]string{}
+	schemas := map[string]SchemaOrBool{}
+
+	for k, d := range s.Dependencies {
+		if d.SchemaOrBool != nil {
+			schemas[k] = *d.SchemaOrBool
+		} else {
+			required[k] = d.StringArray
+		}
+	}
+
+	if len(required) > 0 {
+		s.WithExtraPropertiesItem("dependentRequired", required)
+	}
+
+	if len(schemas) > 0 {
+		s.WithExtraPropertiesItem("dependentSchemas", schemas)
+	}
+
+	s.Dependencies = nil
+}
@@ -61,6 +61,7 @@ type Schema struct {
 	OneOf                []SchemaOrBool                              `json:"oneOf,omitempty"`
 	Not                  *SchemaOrBool                               `json:"not,omitempty"` // Core schema meta-schema.
 	ExtraProperties      map[string]interface{}                      `json:"-"`             // All unmatched properties.
+	PropertiesOrder      []string                                    `json:"-"`             // Order of marshaled properties.
 	ReflectType          reflect.Type                                `json:"-"`
 	Parent               *Schema                                     `json:"-"`
 }
@@ -542,14 +543,14 @@ func (s *Schema) UnmarshalJSON(data []byte) error {
 
 	ms := marshalSchema(*s)
 
-	err = json.Unmarshal(data, &ms)
+	err = jsonUnmarshal(data, &ms)
 	if err != nil {
 		return err
 	}
 
 	var rawMap map[string]json.RawMessage
 
-	err = json.Unmarshal(data, &rawMap)
+	err = jsonUnmarshal(data, &rawMap)
 	if err != nil {
 		rawMap = nil
 	}
@@ -579,7 +580,7 @@ func (s *Schema) UnmarshalJSON(data []byte) error {
 
 		var val interface{}
 
-		err = json.Unmarshal(rawValue, &val)
+		err = jsonUnmarshal(rawValue, &val)
 		if err != nil {
 			return err
 		}
@@ -595,7 +596,7 @@ func (s *Schema) UnmarshalJSON(data []byte) error {
 // MarshalJSON encodes JSON.
 func (s Schema) MarshalJSON() ([]byte, error) {
 	if len(s.ExtraProperties) == 0 {
-		return json.Marshal(marshalSchema(s))
+		return jsonMarshal(marshalSchema(s))
 	}
 
 	return marshalUnion(marshalSchema(s), s.ExtraProperties)
@@ -637,7 +638,7 @@ func (s *SchemaOrBool) UnmarshalJSON(data []byte) error {
 	typeValid := false
 
 	if !typeValid {
-		err = json.Unmarshal(data, &s.TypeObject)
+		err = jsonUnmarshal(data, &s.TypeObject)
 		if err != nil {
 			s.TypeObject = nil
 		} else {
@@ -646,7 +647,7 @@ func (s *SchemaOrBool) UnmarshalJSON(data []byte) error {
 	}
 
 	if !typeValid {
-		err = json.Unmarshal(data, &s.TypeBoolean)
+		err = jsonUnmarshal(data, &s.TypeBoolean)
 		if err != nil {
 			s.TypeBoolean = nil
 		} else {
@@ -665,9 +666,9 @@ func (s *SchemaOrBool) UnmarshalJSON(data []byte) error {
 func (s SchemaOrBool) MarshalJSON() ([]byte, error) {
 	switch {
 	case s.TypeObject != nil:
-		return json.Marshal(s.TypeObject)
+		return jsonMarshal(s.TypeObject)
 	case s.TypeBoolean != nil:
-		return json.Marshal(s.TypeBoolean)
+		return jsonMarshal(s.TypeBoolean)
 	}
 	return nil, errors.New("missing typed value")
 }
@@ -706,7 +707,7 @@ func (i *Items) UnmarshalJSON(data []byte) error {
 	anyOfErrors := make(map[string]error, 2)
 	anyOfValid := 0
 
-	err = json.Unmarshal(data, &i.SchemaOrBool)
+	err = jsonUnmarshal(data, &i.SchemaOrBool)
 	if err != nil {
 		anyOfErrors["SchemaOrBool"] = err
 		i.SchemaOrBool = nil
@@ -714,7 +715,7 @@ func (i *Items) UnmarshalJSON(data []byte) error {
 		anyOfValid++
 	}
 
-	err = json.Unmarshal(data, &i.SchemaArray)
+	err = jsonUnmarshal(data, &i.SchemaArray)
 	if err != nil {
 		anyOfErrors["SchemaArray"] = err
 		i.SchemaArray = nil
@@ -768,7 +769,7 @@ func (d *DependenciesAdditionalProperties) UnmarshalJSON(data []byte) error {
 	anyOfErrors := make(map[string]error, 2)
 	anyOfValid := 0
 
-	err = json.Unmarshal(data, &d.SchemaOrBool)
+	err = jsonUnmarshal(data, &d.SchemaOrBool)
 	if err != nil {
 		anyOfErrors["SchemaOrBool"] = err
 		d.SchemaOrBool = nil
@@ -776,7 +777,7 @@ func (d *DependenciesAdditionalProperties) UnmarshalJSON(data []byte) error {
 		anyOfValid++
 	}
 
-	err = json.Unmarshal(data, &d.StringArray)
+	err = jsonUnmarshal(data, &d.StringArray)
 	if err != nil {
 		anyOfErrors["StringArray"] = err
 		d.StringArray = nil
@@ -821,7 +822,7 @@ func (t *Type) UnmarshalJSON(data []byte) error {
 	anyOfErrors := make(map[string]error, 2)
 	anyOfValid := 0
 
-	err = json.Unmarshal(data, &t.SimpleTypes)
+	err = jsonUnmarshal(data, &t.SimpleTypes)
 	if err != nil {
 		anyOfErrors["SimpleTypes"] = err
 		t.SimpleTypes = nil
@@ -829,7 +830,7 @@ func (t *Type) UnmarshalJSON(data []byte) error {
 		anyOfValid++
 	}
 
-	err = json.Unmarshal(data, &t.SliceOfSimpleTypeValues)
+	err = jsonUnmarshal(data, &t.SliceOfSimpleTypeValues)
 	if err != nil {
 		anyOfErrors["SliceOfSimpleTypeValues"] = err
 		t.SliceOfSimpleTypeValues = nil
@@ -878,14 +879,14 @@ func (i SimpleType) MarshalJSON() ([]byte, error) {
 		return nil, fmt.Errorf("unexpected SimpleType value: %v", i)
 	}
 
-	return json.Marshal(string(i))
+	return jsonMarshal(string(i))
 }
 
 // UnmarshalJSON decodes JSON.
 func (i *SimpleType) UnmarshalJSON(data []byte) error {
 	var ii string
 
-	err := json.Unmarshal(data, &ii)
+	err := jsonUnmarshal(data, &ii)
 	if err != nil {
 		return err
 	}
@@ -915,7 +916,7 @@ func marshalUnion(maps ...interface{}) ([]byte, error) {
 	isObject := true
 
 	for _, m := range maps {
-		j, err := json.Marshal(m)
+		j, err := jsonMarshal(m)
 		if err != nil {
 			return nil, err
 		}
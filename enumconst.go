@@ -0,0 +1,111 @@
+package jsonschema
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/swaggest/refl"
+)
+
+// XEnumVarNames is the name of JSON property to store names of constants of enumerated values,
+// as recognized by OpenAPI Generator.
+const XEnumVarNames = "x-enum-varnames"
+
+// EnumsFromConstants enables `enum` of named scalar types from constants declared with them in Go sources,
+// with names of constants in XEnumVarNames, e.g. for
+//
+//	type Level int
+//
+//	const (
+//		Debug Level = iota
+//		Info
+//	)
+//
+// Level schema gets `"enum":[0,1],"x-enum-varnames":["Debug","Info"]`. Index can be nil to use default one,
+// types that have `enum` (e.g. with Enum or NamedEnum) are not changed.
+func EnumsFromConstants(si *SourceIndex) func(rc *ReflectContext) {
+	if si == nil {
+		si = NewSourceIndex()
+	}
+
+	return func(rc *ReflectContext) {
+		rc.ConstantsIndex = si
+	}
+}
+
+// RegisterEnum sets `enum` of a named scalar type to values, e.g.
+//
+//	r.RegisterEnum(Level(0), Debug, Info, Warning)
+//
+// as an alternative to EnumsFromConstants when Go sources are not available. Values must be of sample type,
+// registered enum takes precedence over EnumsFromConstants and `enum` of Enum or NamedEnum
+// implementations takes precedence over registered enum.
+func (r *Reflector) RegisterEnum(sample interface{}, values ...interface{}) error {
+	t := refl.DeepIndirect(reflect.TypeOf(sample))
+	if t == nil {
+		return fmt.Errorf("nil sample")
+	}
+
+	for _, v := range values {
+		if reflect.TypeOf(v) != t {
+			return fmt.Errorf("%T value %v does not match enum type %s", v, v, t)
+		}
+	}
+
+	enum := append([]interface{}(nil), values...)
+
+	r.RegisterConstraints(sample, func(s *Schema) {
+		if len(s.Enum) == 0 {
+			s.Enum = enum
+		}
+	})
+
+	return nil
+}
+
+// EnumOneOfConst replaces `enum` having names (XEnumVarNames or XEnumNames) with `oneOf` of `const` with `title`,
+// e.g. for OpenAPI 3.1 clients to show names instead of bare numbers.
+func EnumOneOfConst(rc *ReflectContext) {
+	rc.EnumOneOfConst = true
+}
+
+func reflectConstantsEnum(t reflect.Type, schema *Schema, rc *ReflectContext) {
+	if rc.ConstantsIndex == nil || len(schema.Enum) > 0 || t.Name() == "" {
+		return
+	}
+
+	values, names, ok := rc.ConstantsIndex.Constants(t)
+	if !ok {
+		return
+	}
+
+	schema.Enum = values
+	schema.WithExtraPropertiesItem(XEnumVarNames, names)
+}
+
+func enumOneOfConst(schema *Schema) {
+	if len(schema.Enum) == 0 {
+		return
+	}
+
+	names, _ := schema.ExtraProperties[XEnumVarNames].([]string)
+	if names == nil {
+		names, _ = schema.ExtraProperties[XEnumNames].([]string)
+	}
+
+	if len(names) != len(schema.Enum) {
+		return
+	}
+
+	oneOf := make([]SchemaOrBool, 0, len(schema.Enum))
+
+	for i, v := range schema.Enum {
+		oneOf = append(oneOf, (&Schema{}).WithConst(v).WithTitle(names[i]).ToSchemaOrBool())
+	}
+
+	schema.OneOf = append(schema.OneOf, oneOf...)
+	schema.Enum = nil
+
+	delete(schema.ExtraProperties, XEnumVarNames)
+	delete(schema.ExtraProperties, XEnumNames)
+}
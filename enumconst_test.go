@@ -0,0 +1,102 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/jsonschema-go"
+)
+
+type constLevel int
+
+const (
+	constLevelDebug constLevel = iota
+	constLevelInfo
+	_
+	constLevelError
+)
+
+type constHolder struct {
+	Level constLevel `json:"level"`
+}
+
+func TestEnumsFromConstants(t *testing.T) {
+	if !jsonschema.BuildCapabilities().SourceIndex {
+		t.Skip("source index is not available in this build")
+	}
+
+	idx := jsonschema.NewSourceIndex()
+	idx.AddDir("github.com/swaggest/jsonschema-go_test", ".")
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(constHolder{}, jsonschema.EnumsFromConstants(idx))
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "enum":[0,1,3],"type":"integer",
+	  "x-enum-varnames":["constLevelDebug","constLevelInfo","constLevelError"]
+	}`, s.Definitions["JsonschemaGoTestConstLevel"])
+
+	s, err = r.Reflect(constHolder{}, jsonschema.EnumsFromConstants(idx), jsonschema.EnumOneOfConst)
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "oneOf":[
+		{"const":0,"title":"constLevelDebug"},{"const":1,"title":"constLevelInfo"},
+		{"const":3,"title":"constLevelError"}
+	  ],
+	  "type":"integer"
+	}`, s.Definitions["JsonschemaGoTestConstLevel"])
+}
+
+type constRank int
+
+const (
+	constRankZed   constRank = 3
+	constRankAlpha constRank = 1
+	constRankMid   constRank = 2
+)
+
+func TestEnumsFromConstants_orderKeywords(t *testing.T) {
+	if !jsonschema.BuildCapabilities().SourceIndex {
+		t.Skip("source index is not available in this build")
+	}
+
+	idx := jsonschema.NewSourceIndex()
+	idx.AddDir("github.com/swaggest/jsonschema-go_test", ".")
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(constRank(0), jsonschema.EnumsFromConstants(idx),
+		jsonschema.OrderKeywords(jsonschema.OrderAlphabetical),
+		jsonschema.InterceptSchema(func(params jsonschema.InterceptSchemaParams) (bool, error) {
+			if params.Processed {
+				params.Schema.WithExtraPropertiesItem(jsonschema.XEnumDescriptions, []string{"Last", "First", "Middle"})
+			}
+
+			return false, nil
+		}))
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "enum":[1,2,3],"type":"integer",
+	  "x-enum-descriptions":["First","Middle","Last"],
+	  "x-enum-varnames":["constRankAlpha","constRankMid","constRankZed"]
+	}`, s)
+}
+
+func TestReflector_RegisterEnum(t *testing.T) {
+	r := jsonschema.Reflector{}
+
+	require.EqualError(t, r.RegisterEnum(constLevel(0), constLevelDebug, 3),
+		"int value 3 does not match enum type jsonschema_test.constLevel")
+	require.NoError(t, r.RegisterEnum(constLevel(0), constLevelDebug, constLevelError))
+
+	s, err := r.Reflect(constHolder{}, jsonschema.InlineRefs)
+	require.NoError(t, err)
+	assertjson.EqMarshal(t, `{
+	  "properties":{"level":{"enum":[0,3],"type":"integer"}},"type":"object"
+	}`, s)
+}
@@ -0,0 +1,279 @@
+package jsonschema
+
+import (
+	"html"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// XEnumDescriptions is the name of JSON property to store descriptions of enumerated values,
+// as recognized by OpenAPI Generator.
+const XEnumDescriptions = "x-enum-descriptions"
+
+// EnumTablesOptions configures EnumTables.
+type EnumTablesOptions struct {
+	// HTML enables HTML tables instead of Markdown.
+	HTML bool
+
+	// HeadingLevel is a level of headings with property paths, default 4.
+	HeadingLevel int
+}
+
+// EnumTables renders documentation tables of enumerated values of properties and definitions, e.g.
+//
+//	#### status
+//
+//	| Value    | Name    | Description       |
+//	|----------|---------|-------------------|
+//	| `"new"`  | New     | Order is created. |
+//
+// Names are taken from XEnumVarNames or XEnumNames (e.g. of NamedEnum), descriptions from XEnumDescriptions,
+// `oneOf` of `const` (see EnumOneOfConst) is rendered with titles and descriptions of alternatives.
+// Tables of root properties are followed by tables of definitions, paths of nested properties
+// are joined with ".", array items are marked with "[]".
+func EnumTables(schema Schema, options ...func(o *EnumTablesOptions)) string {
+	o := EnumTablesOptions{HeadingLevel: 4}
+
+	for _, option := range options {
+		option(&o)
+	}
+
+	et := enumTables{o: o}
+
+	root := schema
+	root.Definitions = nil
+
+	et.schema("", &root)
+
+	defs := schema.Definitions
+	if d, ok := schema.ExtraProperties["$defs"].(map[string]SchemaOrBool); ok && len(defs) == 0 {
+		defs = d
+	}
+
+	names := make([]string, 0, len(defs))
+	for name := range defs {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		et.schema(name, defs[name].TypeObject)
+	}
+
+	return et.b.String()
+}
+
+type enumRow struct {
+	value, name, description string
+}
+
+type enumTables struct {
+	o EnumTablesOptions
+	b strings.Builder
+}
+
+func (et *enumTables) schema(path string, s *Schema) {
+	if s == nil {
+		return
+	}
+
+	if rows := enumRows(s); len(rows) > 0 {
+		et.table(path, rows)
+	}
+
+	prefix := path
+	if prefix != "" {
+		prefix += "."
+	}
+
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		et.schema(prefix+name, s.Properties[name].TypeObject)
+	}
+
+	if s.Items != nil && s.Items.SchemaOrBool != nil {
+		et.schema(path+"[]", s.Items.SchemaOrBool.TypeObject)
+	}
+
+	if s.AdditionalProperties != nil {
+		et.schema(path+"{}", s.AdditionalProperties.TypeObject)
+	}
+}
+
+// enumRows collects enumerated values with names and descriptions.
+func enumRows(s *Schema) []enumRow {
+	var rows []enumRow
+
+	if len(s.Enum) > 0 {
+		names := stringList(s.ExtraProperties[XEnumVarNames])
+		if names == nil {
+			names = stringList(s.ExtraProperties[XEnumNames])
+		}
+
+		descriptions := stringList(s.ExtraProperties[XEnumDescriptions])
+
+		for i, v := range s.Enum {
+			r := enumRow{value: jsonString(v)}
+
+			if i < len(names) {
+				r.name = names[i]
+			}
+
+			if i < len(descriptions) {
+				r.description = descriptions[i]
+			}
+
+			rows = append(rows, r)
+		}
+
+		return rows
+	}
+
+	for _, alt := range s.OneOf {
+		a := alt.TypeObject
+		if a == nil || a.Const == nil {
+			return nil
+		}
+
+		r := enumRow{value: jsonString(*a.Const)}
+
+		if a.Title != nil {
+			r.name = *a.Title
+		}
+
+		if a.Description != nil {
+			r.description = *a.Description
+		}
+
+		rows = append(rows, r)
+	}
+
+	return rows
+}
+
+func (et *enumTables) table(path string, rows []enumRow) {
+	hasNames, hasDescriptions := false, false
+
+	for _, r := range rows {
+		hasNames = hasNames || r.name != ""
+		hasDescriptions = hasDescriptions || r.description != ""
+	}
+
+	if path == "" {
+		path = "(root)"
+	}
+
+	header := []string{"Value"}
+	if hasNames {
+		header = append(header, "Name")
+	}
+
+	if hasDescriptions {
+		header = append(header, "Description")
+	}
+
+	cells := func(r enumRow) []string {
+		c := []string{r.value}
+		if hasNames {
+			c = append(c, r.name)
+		}
+
+		if hasDescriptions {
+			c = append(c, r.description)
+		}
+
+		return c
+	}
+
+	if et.o.HTML {
+		et.htmlTable(path, header, rows, cells)
+
+		return
+	}
+
+	if et.b.Len() > 0 {
+		et.b.WriteString("\n")
+	}
+
+	if et.o.HeadingLevel > 0 {
+		et.b.WriteString(strings.Repeat("#", et.o.HeadingLevel) + " " + path + "\n\n")
+	}
+
+	et.b.WriteString("| " + strings.Join(header, " | ") + " |\n")
+	et.b.WriteString("|" + strings.Repeat("---|", len(header)) + "\n")
+
+	for _, r := range rows {
+		c := cells(r)
+		c[0] = "`" + c[0] + "`"
+
+		for i := range c {
+			c[i] = strings.ReplaceAll(strings.ReplaceAll(c[i], "|", `\|`), "\n", " ")
+		}
+
+		et.b.WriteString("| " + strings.Join(c, " | ") + " |\n")
+	}
+}
+
+func (et *enumTables) htmlTable(path string, header []string, rows []enumRow, cells func(r enumRow) []string) {
+	if et.o.HeadingLevel > 0 {
+		level := et.o.HeadingLevel
+		if level > 6 {
+			level = 6
+		}
+
+		h := "h" + strconv.Itoa(level)
+		et.b.WriteString("<" + h + ">" + html.EscapeString(path) + "</" + h + ">\n")
+	}
+
+	et.b.WriteString("<table>\n<thead><tr>")
+
+	for _, h := range header {
+		et.b.WriteString("<th>" + h + "</th>")
+	}
+
+	et.b.WriteString("</tr></thead>\n<tbody>\n")
+
+	for _, r := range rows {
+		et.b.WriteString("<tr>")
+
+		for i, c := range cells(r) {
+			c = html.EscapeString(c)
+			if i == 0 {
+				c = "<code>" + c + "</code>"
+			}
+
+			et.b.WriteString("<td>" + c + "</td>")
+		}
+
+		et.b.WriteString("</tr>\n")
+	}
+
+	et.b.WriteString("</tbody>\n</table>\n")
+}
+
+// stringList returns strings of []string or []interface{} value, e.g. of unmarshaled schema.
+func stringList(v interface{}) []string {
+	switch l := v.(type) {
+	case []string:
+		return l
+	case []interface{}:
+		res := make([]string, 0, len(l))
+
+		for _, i := range l {
+			s, _ := i.(string)
+			res = append(res, s)
+		}
+
+		return res
+	}
+
+	return nil
+}
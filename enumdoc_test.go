@@ -0,0 +1,74 @@
+package jsonschema_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/jsonschema-go"
+)
+
+type docStatus string
+
+func (docStatus) NamedEnum() ([]interface{}, []string) {
+	return []interface{}{"new", "paid"}, []string{"New", "Paid"}
+}
+
+type docOrder struct {
+	Status   docStatus `json:"status"`
+	Priority int       `json:"priority" enum:"[1,2,3]"`
+	Lines    []struct {
+		Kind string `json:"kind" enum:"item,fee|tax"`
+	} `json:"lines"`
+}
+
+func TestEnumTables(t *testing.T) {
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(docOrder{}, jsonschema.InterceptSchema(
+		func(params jsonschema.InterceptSchemaParams) (stop bool, err error) {
+			if params.Processed && params.Value.Type() == reflect.TypeOf(docStatus("")) {
+				params.Schema.WithExtraPropertiesItem(jsonschema.XEnumDescriptions,
+					[]string{"Order is created.", "Order is paid."})
+			}
+
+			return false, nil
+		}))
+	require.NoError(t, err)
+
+	assert.Equal(t, "#### lines[].kind\n\n"+
+		"| Value |\n"+
+		"|---|\n"+
+		"| `\"item\"` |\n"+
+		"| `\"fee\\|tax\"` |\n"+
+		"\n"+
+		"#### priority\n\n"+
+		"| Value |\n"+
+		"|---|\n"+
+		"| `1` |\n"+
+		"| `2` |\n"+
+		"| `3` |\n"+
+		"\n"+
+		"#### JsonschemaGoTestDocStatus\n\n"+
+		"| Value | Name | Description |\n"+
+		"|---|---|---|\n"+
+		"| `\"new\"` | New | Order is created. |\n"+
+		"| `\"paid\"` | Paid | Order is paid. |\n", jsonschema.EnumTables(s))
+
+	s, err = r.Reflect(docOrder{}, jsonschema.EnumOneOfConst)
+	require.NoError(t, err)
+
+	assert.Equal(t, `<h2>JsonschemaGoTestDocStatus</h2>
+<table>
+<thead><tr><th>Value</th><th>Name</th></tr></thead>
+<tbody>
+<tr><td><code>&#34;new&#34;</code></td><td>New</td></tr>
+<tr><td><code>&#34;paid&#34;</code></td><td>Paid</td></tr>
+</tbody>
+</table>
+`, jsonschema.EnumTables(jsonschema.Schema{Definitions: s.Definitions}, func(o *jsonschema.EnumTablesOptions) {
+		o.HTML = true
+		o.HeadingLevel = 2
+	}))
+}
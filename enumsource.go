@@ -0,0 +1,87 @@
+package jsonschema
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// RegisterEnumSource adds a named source of enum values that can be applied to a property
+// with `enumFrom` field tag, e.g.
+//
+//	r.RegisterEnumSource("plugins", jsonschema.MapKeys(plugins))
+//
+//	type Job struct {
+//		Plugin string `json:"plugin" enumFrom:"plugins"`
+//	}
+//
+// Values are evaluated during reflection, so that enums that are only known at startup
+// (e.g. names of registered plugins) stay up to date. Source takes precedence over `enum` tag.
+func (r *Reflector) RegisterEnumSource(name string, values func() []interface{}) {
+	if r.enumSources == nil {
+		r.enumSources = map[string]func() []interface{}{}
+	}
+
+	r.enumSources[name] = values
+}
+
+// MapKeys returns enum source with sorted keys of a map, map is read on every call.
+func MapKeys(m interface{}) func() []interface{} {
+	return func() []interface{} {
+		v := reflect.ValueOf(m)
+		for v.Kind() == reflect.Ptr {
+			v = v.Elem()
+		}
+
+		if v.Kind() != reflect.Map {
+			return nil
+		}
+
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return lessValue(keys[i], keys[j])
+		})
+
+		res := make([]interface{}, 0, len(keys))
+		for _, k := range keys {
+			res = append(res, k.Interface())
+		}
+
+		return res
+	}
+}
+
+func lessValue(a, b reflect.Value) bool {
+	//nolint:exhaustive // Other kinds are compared as strings.
+	switch a.Kind() {
+	case reflect.String:
+		return a.String() < b.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() < b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return a.Uint() < b.Uint()
+	case reflect.Float32, reflect.Float64:
+		return a.Float() < b.Float()
+	default:
+		return fmt.Sprint(a.Interface()) < fmt.Sprint(b.Interface())
+	}
+}
+
+func (r *Reflector) applyEnumSource(s *Schema, field reflect.StructField) error {
+	name, ok := field.Tag.Lookup("enumFrom")
+	if !ok {
+		return nil
+	}
+
+	name = strings.TrimSpace(name)
+
+	values, ok := r.enumSources[name]
+	if !ok {
+		return fmt.Errorf("unknown enum source %q in field %s", name, field.Name)
+	}
+
+	s.Enum = values()
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/jsonschema-go"
+)
+
+func TestReflector_RegisterEnumSource(t *testing.T) {
+	type Job struct {
+		Plugin   string `json:"plugin" enumFrom:"plugins"`
+		Priority int    `json:"priority" enumFrom:"priorities"`
+	}
+
+	plugins := map[string]func(){"resize": nil, "crop": nil}
+
+	r := jsonschema.Reflector{}
+	r.RegisterEnumSource("plugins", jsonschema.MapKeys(plugins))
+	r.RegisterEnumSource("priorities", jsonschema.MapKeys(map[int]string{10: "high", 2: "low"}))
+
+	s, err := r.Reflect(Job{})
+	require.NoError(t, err)
+	assertjson.EqMarshal(t, `{
+	  "properties":{
+		"plugin":{"enum":["crop","resize"],"type":"string"},
+		"priority":{"enum":[2,10],"type":"integer"}
+	  },
+	  "type":"object"
+	}`, s)
+
+	// Values are evaluated during reflection.
+	plugins["blur"] = nil
+
+	s, err = r.Reflect(Job{})
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"blur", "crop", "resize"}, s.Properties["plugin"].TypeObject.Enum)
+
+	_, err = r.Reflect(struct {
+		A string `json:"a" enumFrom:"missing"`
+	}{})
+	assert.EqualError(t, err, `unknown enum source "missing" in field A`)
+}
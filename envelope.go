@@ -0,0 +1,118 @@
+package jsonschema
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Envelope wraps a model schema into a standard response shape, e.g. {"data": model, "meta": {...}}.
+type Envelope struct {
+	// Name returns definition name of envelope by definition name of model, e.g. "OrderPage" for "Order".
+	Name func(modelName string) string
+
+	// Wrap returns envelope schema for a model schema, which is usually a reference to model definition.
+	Wrap func(model Schema) Schema
+}
+
+// DataEnvelope wraps model as `{"data": model, "meta": meta}`, envelope definition is named "<Model>Envelope".
+//
+// Meta schema is optional, `data` is required.
+func DataEnvelope(meta *Schema) Envelope {
+	return Envelope{
+		Name: func(modelName string) string { return modelName + "Envelope" },
+		Wrap: func(model Schema) Schema {
+			s := Schema{}
+			s.AddType(Object)
+			s.WithPropertiesItem("data", model.ToSchemaOrBool())
+			s.WithRequired("data")
+
+			if meta != nil {
+				m := cloneSchema(*meta)
+				s.WithPropertiesItem("meta", m.ToSchemaOrBool())
+			}
+
+			return s
+		},
+	}
+}
+
+// ListEnvelope wraps model as paginated list `{"items": [model], "next_cursor": "..."}`,
+// envelope definition is named "<Model>List", `next_cursor` is null on the last page.
+func ListEnvelope() Envelope {
+	return Envelope{
+		Name: func(modelName string) string { return modelName + "List" },
+		Wrap: func(model Schema) Schema {
+			items := Schema{}
+			items.AddType(Array)
+			items.WithItems(*(&Items{}).WithSchemaOrBool(model.ToSchemaOrBool()))
+
+			cursor := Schema{}
+			cursor.AddType(String)
+			cursor.AddType(Null)
+
+			s := Schema{}
+			s.AddType(Object)
+			s.WithPropertiesItem("items", items.ToSchemaOrBool())
+			s.WithPropertiesItem("next_cursor", cursor.ToSchemaOrBool())
+			s.WithRequired("items", "next_cursor")
+
+			return s
+		},
+	}
+}
+
+// ReflectEnvelope reflects model sample and wraps it with envelope.
+//
+// Model and envelope become definitions (or are passed to CollectDefinitions), resulting schema refers to envelope,
+// so all endpoints that use same model and envelope share definitions. Model schema is inlined into envelope
+// if model does not have a definition name (e.g. a slice of unnamed type).
+func (r *Reflector) ReflectEnvelope(sample interface{}, e Envelope, options ...func(rc *ReflectContext)) (Schema, error) {
+	model, err := r.Reflect(sample, append(options[:len(options):len(options)], RootRef)...)
+	if err != nil {
+		return model, err
+	}
+
+	rc := ReflectContext{DefinitionsPrefix: "#/definitions/"}
+
+	for _, option := range r.DefaultOptions {
+		option(&rc)
+	}
+
+	for _, option := range options {
+		option(&rc)
+	}
+
+	if model.Ref == nil || !strings.HasPrefix(*model.Ref, rc.DefinitionsPrefix) {
+		defs := model.Definitions
+		model.Definitions = nil
+		env := e.Wrap(model)
+		env.Definitions = defs
+
+		return env, nil
+	}
+
+	defs := model.Definitions
+	modelName := pointerUnescaper.Replace(strings.ReplaceAll(strings.TrimPrefix(*model.Ref, rc.DefinitionsPrefix), "%25", "%"))
+	envName := e.Name(modelName)
+	env := e.Wrap(Ref{Path: rc.DefinitionsPrefix, Name: modelName}.Schema())
+
+	switch {
+	case rc.CollectDefinitions != nil:
+		rc.CollectDefinitions(envName, env)
+	case rc.CollectDefinitionsOrdered != nil:
+		rc.CollectDefinitionsOrdered(reflect.TypeOf(sample), envName, env)
+	case rc.CollectDefinitionsWithRoot != nil:
+		rc.CollectDefinitionsWithRoot(reflect.TypeOf(sample), rc.collectSeq, envName, env)
+	default:
+		if defs == nil {
+			defs = map[string]SchemaOrBool{}
+		}
+
+		defs[envName] = env.ToSchemaOrBool()
+	}
+
+	s := Ref{Path: rc.DefinitionsPrefix, Name: envName}.Schema()
+	s.Definitions = defs
+
+	return s, nil
+}
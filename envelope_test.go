@@ -0,0 +1,66 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/jsonschema-go"
+)
+
+func TestReflector_ReflectEnvelope(t *testing.T) {
+	type Order struct {
+		ID int `json:"id"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	meta := jsonschema.Schema{}
+	meta.AddType(jsonschema.Object)
+	meta.WithPropertiesItem("request_id", jsonschema.String.ToSchemaOrBool())
+
+	s, err := r.ReflectEnvelope(Order{}, jsonschema.DataEnvelope(&meta),
+		jsonschema.StripDefinitionNamePrefix("JsonschemaGoTest"))
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "$ref":"#/definitions/OrderEnvelope",
+	  "definitions":{
+		"Order":{"properties":{"id":{"type":"integer"}},"type":"object"},
+		"OrderEnvelope":{
+		  "properties":{
+			"data":{"$ref":"#/definitions/Order"},
+			"meta":{"properties":{"request_id":{"type":"string"}},"type":"object"}
+		  },
+		  "required":["data"],"type":"object"
+		}
+	  }
+	}`, s)
+
+	collected := map[string]jsonschema.Schema{}
+
+	s, err = r.ReflectEnvelope(Order{}, jsonschema.ListEnvelope(),
+		jsonschema.DefinitionsPrefix("#/components/schemas/"),
+		jsonschema.CollectDefinitions(func(name string, schema jsonschema.Schema) {
+			collected[name] = schema
+		}))
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{"$ref":"#/components/schemas/JsonschemaGoTestOrderList"}`, s)
+	assertjson.EqMarshal(t, `{
+	  "properties":{
+		"items":{"items":{"$ref":"#/components/schemas/JsonschemaGoTestOrder"},"type":"array"},
+		"next_cursor":{"type":["string","null"]}
+	  },
+	  "required":["items","next_cursor"],"type":"object"
+	}`, collected["JsonschemaGoTestOrderList"])
+	require.Contains(t, collected, "JsonschemaGoTestOrder")
+
+	s, err = r.ReflectEnvelope([]int{}, jsonschema.DataEnvelope(nil))
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "properties":{"data":{"items":{"type":"integer"},"type":"array"}},
+	  "required":["data"],"type":"object"
+	}`, s)
+}
@@ -0,0 +1,178 @@
+package jsonschema
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/swaggest/refl"
+)
+
+// Draft04Schema is the URI of JSON Schema draft-04 meta-schema.
+const Draft04Schema = "http://json-schema.org/draft-04/schema#"
+
+// populateFieldsFromTags fills schema from field tags, boolean (draft-04) `exclusiveMinimum` and
+// `exclusiveMaximum` tags are accepted along with numeric ones.
+//
+// Boolean form applies to `minimum` or `maximum` of the same field, e.g. `maximum:"10" exclusiveMaximum:"true"`
+// is reflected as `"exclusiveMaximum":10`.
+func populateFieldsFromTags(s *Schema, tag reflect.StructTag) error {
+	tag, exclusiveMin := boolTagOut(tag, "exclusiveMinimum")
+	tag, exclusiveMax := boolTagOut(tag, "exclusiveMaximum")
+
+	if err := refl.PopulateFieldsFromTags(s, tag); err != nil {
+		return err
+	}
+
+	if exclusiveMin && s.Minimum == nil {
+		return fmt.Errorf("boolean exclusiveMinimum tag requires minimum tag")
+	}
+
+	if exclusiveMax && s.Maximum == nil {
+		return fmt.Errorf("boolean exclusiveMaximum tag requires maximum tag")
+	}
+
+	if exclusiveMin {
+		s.ExclusiveMinimum = s.Minimum
+		s.Minimum = nil
+	}
+
+	if exclusiveMax {
+		s.ExclusiveMaximum = s.Maximum
+		s.Maximum = nil
+	}
+
+	return nil
+}
+
+// boolTagOut removes tag with boolean value and returns that value.
+func boolTagOut(tag reflect.StructTag, key string) (reflect.StructTag, bool) {
+	v, ok := tag.Lookup(key)
+	if !ok {
+		return tag, false
+	}
+
+	if v != "true" && v != "false" {
+		return tag, false
+	}
+
+	return tagWithout(tag, key), v == "true"
+}
+
+// tagWithout returns tag without key.
+func tagWithout(tag reflect.StructTag, key string) reflect.StructTag {
+	var res []string
+
+	// Tag syntax is parsed in the same way as in reflect.StructTag.Lookup.
+	for t := string(tag); t != ""; {
+		i := 0
+		for i < len(t) && t[i] == ' ' {
+			i++
+		}
+
+		t = t[i:]
+		if t == "" {
+			break
+		}
+
+		i = 0
+		for i < len(t) && t[i] > ' ' && t[i] != ':' && t[i] != '"' && t[i] != 0x7f {
+			i++
+		}
+
+		if i == 0 || i+1 >= len(t) || t[i] != ':' || t[i+1] != '"' {
+			break
+		}
+
+		name := t[:i]
+		t = t[i+1:]
+
+		i = 1
+		for i < len(t) && t[i] != '"' {
+			if t[i] == '\\' {
+				i++
+			}
+
+			i++
+		}
+
+		if i >= len(t) {
+			break
+		}
+
+		if name != key {
+			res = append(res, name+":"+t[:i+1])
+		}
+
+		t = t[i+1:]
+	}
+
+	return reflect.StructTag(strings.Join(res, " "))
+}
+
+// draft04Bounds shadows numeric `exclusiveMinimum` and `exclusiveMaximum` of schema
+// to accept boolean (draft-04) form.
+type draft04Bounds struct {
+	*marshalSchema
+	ExclusiveMinimum interface{} `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum interface{} `json:"exclusiveMaximum,omitempty"`
+}
+
+// unmarshalSchema decodes schema converting boolean (draft-04) `exclusiveMinimum` and `exclusiveMaximum`
+// to numeric form, only own keywords of schema are checked, subschemas are converted when they are decoded.
+func unmarshalSchema(data []byte, ms *marshalSchema) error {
+	b := draft04Bounds{marshalSchema: ms}
+
+	if err := jsonCodec.Unmarshal(data, &b); err != nil {
+		return err
+	}
+
+	if err := exclusiveBoundFromDraft04("exclusiveMinimum", b.ExclusiveMinimum, &ms.ExclusiveMinimum, &ms.Minimum); err != nil {
+		return err
+	}
+
+	return exclusiveBoundFromDraft04("exclusiveMaximum", b.ExclusiveMaximum, &ms.ExclusiveMaximum, &ms.Maximum)
+}
+
+// exclusiveBoundFromDraft04 sets numeric exclusive bound from decoded value, boolean true value moves inclusive bound.
+func exclusiveBoundFromDraft04(name string, v interface{}, exclusive, inclusive **float64) error {
+	switch b := v.(type) {
+	case nil:
+	case float64:
+		*exclusive = &b
+	case bool:
+		*exclusive = nil
+
+		if b && *inclusive != nil {
+			*exclusive = *inclusive
+			*inclusive = nil
+		}
+	default:
+		return fmt.Errorf("%s: number or boolean expected, %T received", name, v)
+	}
+
+	return nil
+}
+
+// exclusiveBoundsToDraft04 converts numeric `exclusiveMinimum` and `exclusiveMaximum` to boolean (draft-04) form.
+//
+// If schema has both inclusive and exclusive bound, the stricter one is kept.
+func exclusiveBoundsToDraft04(s *Schema) {
+	if s.ExclusiveMinimum != nil {
+		if s.Minimum == nil || *s.ExclusiveMinimum >= *s.Minimum {
+			s.WithMinimum(*s.ExclusiveMinimum)
+			s.WithExtraPropertiesItem("exclusiveMinimum", true)
+		}
+
+		s.ExclusiveMinimum = nil
+	}
+
+	if s.ExclusiveMaximum != nil {
+		if s.Maximum == nil || *s.ExclusiveMaximum <= *s.Maximum {
+			s.WithMaximum(*s.ExclusiveMaximum)
+			s.WithExtraPropertiesItem("exclusiveMaximum", true)
+		}
+
+		s.ExclusiveMaximum = nil
+	}
+}
@@ -0,0 +1,74 @@
+package jsonschema
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// FieldError describes a struct field that failed reflection.
+//
+// Path and Pointer locate the owner of the field, not the property of the field itself.
+type FieldError struct {
+	// Path is a path to the property owner, e.g. ["#", "user"].
+	Path []string
+	// Pointer is a JSON Pointer of the property owner in instance document, e.g. "/user".
+	Pointer string
+	Owner   reflect.Type
+	Field   reflect.StructField
+	Err     error
+}
+
+// Error implements error.
+func (e FieldError) Error() string {
+	name := e.Field.Name
+	if e.Owner != nil && e.Owner.Name() != "" {
+		name = e.Owner.Name() + "." + name
+	}
+
+	return name + " at #" + e.Pointer + ": " + e.Err.Error()
+}
+
+// Unwrap returns underlying error.
+func (e FieldError) Unwrap() error {
+	return e.Err
+}
+
+// FieldErrors aggregates failures of struct fields collected with CollectFieldErrors option.
+type FieldErrors []FieldError
+
+// Error implements error.
+func (e FieldErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, fe := range e {
+		msgs = append(msgs, fe.Error())
+	}
+
+	return strconv.Itoa(len(e)) + " fields failed reflection: " + strings.Join(msgs, "; ")
+}
+
+// Unwrap returns field errors.
+func (e FieldErrors) Unwrap() []error {
+	res := make([]error, 0, len(e))
+	for _, fe := range e {
+		res = append(res, fe)
+	}
+
+	return res
+}
+
+// CollectFieldErrors enables reflection of remaining fields after a field fails,
+// Reflect returns FieldErrors with all failures and a schema without failed properties.
+func CollectFieldErrors(rc *ReflectContext) {
+	rc.CollectFieldErrors = true
+}
+
+func (rc *ReflectContext) addFieldError(owner reflect.Type, field reflect.StructField, err error) {
+	rc.fieldErrors = append(rc.fieldErrors, FieldError{
+		Path:    append([]string(nil), rc.Path...),
+		Pointer: rc.InstancePointer(),
+		Owner:   owner,
+		Field:   field,
+		Err:     err,
+	})
+}
@@ -0,0 +1,158 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// ExamplesFromFixtures enables property `examples` collected from JSON fixtures of reflected value,
+// e.g. recorded payloads of contract tests.
+//
+// Values of scalar properties are added as distinct examples (up to MaxFixtureExamples, including examples
+// from field tags) to property schemas or to definitions that they reference, nested objects and arrays
+// are walked along with their schemas. Sensitive properties (annotated or `writeOnly`) do not receive examples.
+func ExamplesFromFixtures(fixtures ...[]byte) func(*ReflectContext) {
+	return func(rc *ReflectContext) {
+		rc.Fixtures = append(rc.Fixtures, fixtures...)
+	}
+}
+
+type fixtureExamples struct {
+	rc   *ReflectContext
+	defs map[string]*Schema
+	max  int
+}
+
+// applyFixtureExamples adds examples from rc.Fixtures to schema and its definitions.
+func (rc *ReflectContext) applyFixtureExamples(schema *Schema) error {
+	if len(rc.Fixtures) == 0 {
+		return nil
+	}
+
+	fe := fixtureExamples{
+		rc:   rc,
+		defs: make(map[string]*Schema, len(rc.definitions)),
+		max:  rc.MaxFixtureExamples,
+	}
+
+	if fe.max == 0 {
+		fe.max = 3
+	}
+
+	for typeString, def := range rc.definitions {
+		fe.defs[rc.DefinitionsPrefix+rc.definitionRefs[typeString].Name] = def
+	}
+
+	for i, fixture := range rc.Fixtures {
+		var value interface{}
+
+		d := json.NewDecoder(bytes.NewReader(fixture))
+		d.UseNumber()
+
+		if err := d.Decode(&value); err != nil {
+			return fmt.Errorf("failed to decode fixture %d: %w", i, err)
+		}
+
+		fe.walk(schema, value)
+	}
+
+	return nil
+}
+
+// resolve returns referenced definition, or schema itself.
+func (fe fixtureExamples) resolve(s *Schema) *Schema {
+	for i := 0; s != nil && s.Ref != nil && i < 100; i++ {
+		def, ok := fe.defs[*s.Ref]
+		if !ok {
+			break
+		}
+
+		s = def
+	}
+
+	return s
+}
+
+func (fe fixtureExamples) walk(s *Schema, value interface{}) {
+	s = fe.resolve(s)
+	if s == nil || value == nil || fe.sensitive(s) {
+		return
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		fe.walkObject(s, v)
+	case []interface{}:
+		if s.Items == nil || s.Items.SchemaOrBool == nil || s.Items.SchemaOrBool.TypeObject == nil {
+			return
+		}
+
+		for _, item := range v {
+			fe.walk(s.Items.SchemaOrBool.TypeObject, item)
+		}
+	default:
+		fe.addExample(s, v)
+	}
+}
+
+func (fe fixtureExamples) walkObject(s *Schema, obj map[string]interface{}) {
+	// Properties are walked in order of names to collect examples deterministically.
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		if v, ok := obj[name]; ok && s.Properties[name].TypeObject != nil {
+			fe.walk(s.Properties[name].TypeObject, v)
+		}
+	}
+
+	// Properties of embedded structures can be composed with allOf.
+	for _, a := range s.AllOf {
+		if a.TypeObject != nil {
+			if as := fe.resolve(a.TypeObject); as != nil && len(as.Properties) > 0 {
+				fe.walkObject(as, obj)
+			}
+		}
+	}
+}
+
+func (fe fixtureExamples) addExample(s *Schema, value interface{}) {
+	if len(s.Examples) >= fe.max {
+		return
+	}
+
+	j, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+
+	for _, e := range s.Examples {
+		if ej, err := json.Marshal(e); err == nil && bytes.Equal(ej, j) {
+			return
+		}
+	}
+
+	s.Examples = append(s.Examples, value)
+}
+
+// sensitive checks if schema is marked as sensitive, see SensitiveFields.
+func (fe fixtureExamples) sensitive(s *Schema) bool {
+	keyword := fe.rc.SensitiveKeyword
+	if keyword == "" {
+		keyword = XSensitive
+	}
+
+	for _, k := range []string{keyword, "writeOnly"} {
+		if v, ok := s.ExtraProperties[k].(bool); ok && v {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,87 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/jsonschema-go"
+)
+
+type fixtureAddress struct {
+	City string `json:"city"`
+	Zip  string `json:"zip" example:"10115"`
+}
+
+type fixtureOrder struct {
+	ID       int              `json:"id"`
+	Status   string           `json:"status"`
+	Password string           `json:"password" sensitive:"true"`
+	Address  fixtureAddress   `json:"address"`
+	Items    []fixtureItem    `json:"items"`
+	Billing  *fixtureAddress  `json:"billing"`
+	Tags     map[string]int64 `json:"tags"`
+}
+
+type fixtureItem struct {
+	SKU   string  `json:"sku"`
+	Price float64 `json:"price"`
+}
+
+func TestExamplesFromFixtures(t *testing.T) {
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(fixtureOrder{},
+		jsonschema.SensitiveFields(jsonschema.SensitiveAnnotate),
+		jsonschema.ExamplesFromFixtures(
+			[]byte(`{"id":1,"status":"new","password":"secret","address":{"city":"Berlin","zip":"10115"},
+				"items":[{"sku":"A-1","price":9.99},{"sku":"B-2","price":1}],"billing":null}`),
+			[]byte(`{"id":2,"status":"new","password":"secret2","address":{"city":"Paris","zip":"75001"},
+				"items":[{"sku":"C-3","price":100}],"billing":{"city":"Lyon"},"unknown":true}`),
+		),
+		func(rc *jsonschema.ReflectContext) {
+			rc.MaxFixtureExamples = 2
+		},
+	)
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "definitions":{
+		"JsonschemaGoTestFixtureAddress":{
+		  "properties":{
+			"city":{"examples":["Berlin","Paris"],"type":"string"},
+			"zip":{"examples":["10115","75001"],"type":"string"}
+		  },
+		  "type":"object"
+		},
+		"JsonschemaGoTestFixtureItem":{
+		  "properties":{
+			"price":{"examples":[9.99,1],"type":"number"},
+			"sku":{"examples":["A-1","B-2"],"type":"string"}
+		  },
+		  "type":"object"
+		}
+	  },
+	  "properties":{
+		"address":{"$ref":"#/definitions/JsonschemaGoTestFixtureAddress"},
+		"billing":{"$ref":"#/definitions/JsonschemaGoTestFixtureAddress"},
+		"id":{"examples":[1,2],"type":"integer"},
+		"items":{
+		  "items":{"$ref":"#/definitions/JsonschemaGoTestFixtureItem"},
+		  "type":["array","null"]
+		},
+		"password":{"type":"string","x-sensitive":true},
+		"status":{"examples":["new"],"type":"string"},
+		"tags":{"additionalProperties":{"type":"integer"},"type":["object","null"]}
+	  },
+	  "type":"object"
+	}`, s)
+}
+
+func TestExamplesFromFixtures_invalid(t *testing.T) {
+	r := jsonschema.Reflector{}
+
+	_, err := r.Reflect(fixtureOrder{}, jsonschema.ExamplesFromFixtures([]byte(`{"id":`)))
+	assert.EqualError(t, err, "failed to decode fixture 0: unexpected EOF")
+}
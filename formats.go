@@ -0,0 +1,98 @@
+package jsonschema
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/swaggest/refl"
+)
+
+// Formats is a registry of custom string formats.
+//
+// Reflector uses it to set format of registered types and Validator uses it to check values of
+// registered formats, the same registry can be shared between them, it is safe for concurrent use.
+type Formats struct {
+	mu         sync.RWMutex
+	validators map[string]func(string) error
+	types      map[reflect.Type]string
+}
+
+// RegisterFormat adds a format with an optional validation callback and an optional Go type.
+//
+// Values of registered type are reflected as strings of the format. Validation callback overrides
+// built-in check of format with the same name, nil callback accepts any value.
+func (f *Formats) RegisterFormat(name string, validate func(string) error, forType reflect.Type) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.validators == nil {
+		f.validators = map[string]func(string) error{}
+		f.types = map[reflect.Type]string{}
+	}
+
+	f.validators[name] = validate
+
+	if forType != nil {
+		f.types[refl.DeepIndirect(forType)] = name
+	}
+}
+
+// TypeFormat returns format registered for a type.
+func (f *Formats) TypeFormat(t reflect.Type) (string, bool) {
+	if f == nil || t == nil {
+		return "", false
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	name, ok := f.types[refl.DeepIndirect(t)]
+
+	return name, ok
+}
+
+// CheckFormat validates a string value of a format.
+//
+// Registered formats are checked with their callbacks, built-in formats (e.g. "date-time", "uuid")
+// are checked by Validator, unknown formats are ignored.
+func (f *Formats) CheckFormat(format, s string) error {
+	if f != nil {
+		f.mu.RLock()
+		validate, ok := f.validators[format]
+		f.mu.RUnlock()
+
+		if ok {
+			if validate == nil {
+				return nil
+			}
+
+			return validate(s)
+		}
+	}
+
+	return checkFormat(format, s)
+}
+
+// RegisterFormat adds a custom format to Reflector.Formats, see Formats.RegisterFormat.
+//
+// Use same registry in Validator.Formats to enforce the format in validation.
+func (r *Reflector) RegisterFormat(name string, validate func(string) error, forType reflect.Type) {
+	if r.Formats == nil {
+		r.Formats = &Formats{}
+	}
+
+	r.Formats.RegisterFormat(name, validate, forType)
+}
+
+func (r *Reflector) checkTypeFormat(t reflect.Type, schema *Schema) bool {
+	format, ok := r.Formats.TypeFormat(t)
+	if !ok {
+		return false
+	}
+
+	schema.TypeEns().WithSimpleTypes(String)
+	schema.Type.SliceOfSimpleTypeValues = nil
+	schema.WithFormat(format)
+
+	return true
+}
@@ -0,0 +1,98 @@
+package jsonschema_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/jsonschema-go"
+)
+
+type formatUserID string
+
+func TestReflector_RegisterFormat(t *testing.T) {
+	type Session struct {
+		UserID  formatUserID   `json:"user_id"`
+		Owner   *formatUserID  `json:"owner"`
+		Members []formatUserID `json:"members"`
+		TTL     time.Duration  `json:"ttl"`
+		Email   string         `json:"email" format:"email"`
+	}
+
+	r := jsonschema.Reflector{}
+	r.RegisterFormat("user-id", func(s string) error {
+		if !strings.HasPrefix(s, "usr_") {
+			return errors.New("usr_ prefix expected")
+		}
+
+		return nil
+	}, reflect.TypeOf(formatUserID("")))
+	r.RegisterFormat("duration", func(s string) error {
+		_, err := time.ParseDuration(s)
+
+		return err
+	}, reflect.TypeOf(time.Duration(0)))
+
+	s, err := r.Reflect(Session{})
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "definitions":{
+		"JsonschemaGoTestFormatUserID":{"format":"user-id","type":"string"},
+		"TimeDuration":{"format":"duration","type":"string"}
+	  },
+	  "properties":{
+		"email":{"format":"email","type":"string"},
+		"members":{"items":{"$ref":"#/definitions/JsonschemaGoTestFormatUserID"},"type":["array","null"]},
+		"owner":{"$ref":"#/definitions/JsonschemaGoTestFormatUserID"},
+		"ttl":{"$ref":"#/definitions/TimeDuration"},
+		"user_id":{"$ref":"#/definitions/JsonschemaGoTestFormatUserID"}
+	  },
+	  "type":"object"
+	}`, s)
+
+	v := jsonschema.NewValidator(s)
+	v.Formats = r.Formats
+
+	assert.NoError(t, v.ValidateJSON([]byte(`{"user_id":"usr_1","members":["usr_2"],"ttl":"1m","email":"a@b.c"}`)))
+
+	err = v.ValidateJSON([]byte(`{"user_id":"1","owner":"usr_1","ttl":"1 min","email":"a"}`))
+	require.Error(t, err)
+
+	var errs jsonschema.ValidationErrors
+
+	require.True(t, errors.As(err, &errs))
+	require.Len(t, errs, 3)
+	assert.Equal(t, "/email", errs[0].InstancePath)
+	assert.Equal(t, "/ttl", errs[1].InstancePath)
+	assert.Equal(t, "/user_id", errs[2].InstancePath)
+
+	// Custom formats are not checked without registry.
+	assert.Error(t, jsonschema.NewValidator(s).ValidateJSON([]byte(`{"email":"a"}`)))
+	assert.NoError(t, jsonschema.NewValidator(s).ValidateJSON([]byte(`{"user_id":"1"}`)))
+}
+
+func TestFormats_CheckFormat(t *testing.T) {
+	f := jsonschema.Formats{}
+	f.RegisterFormat("uuid", nil, nil)
+	f.RegisterFormat("hex", func(s string) error {
+		if strings.Trim(s, "0123456789abcdef") != "" {
+			return errors.New("hex digits expected")
+		}
+
+		return nil
+	}, nil)
+
+	assert.NoError(t, f.CheckFormat("uuid", "not-a-uuid"))
+	assert.EqualError(t, f.CheckFormat("hex", "xyz"), "hex digits expected")
+	assert.Error(t, f.CheckFormat("date", "today"))
+	assert.NoError(t, f.CheckFormat("unknown", "any"))
+
+	_, ok := f.TypeFormat(reflect.TypeOf(""))
+	assert.False(t, ok)
+}
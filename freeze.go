@@ -0,0 +1,58 @@
+package jsonschema
+
+import (
+	"encoding/json"
+)
+
+// Frozen is an immutable snapshot of a schema that is safe to share between goroutines and requests.
+//
+// Snapshot is not reachable by consumers, every access returns a deep copy, so changes
+// made by interceptors or consumers never affect the snapshot. Frozen implements Exposer
+// and RawExposer, so it can be used in type mappings and as a value for reflection.
+type Frozen struct {
+	s   *Schema
+	j   []byte
+	err error
+}
+
+// Freeze returns an immutable deep copy of schema.
+func (s Schema) Freeze() Frozen {
+	c := cloneSchema(s)
+	c.Parent = nil
+
+	j, err := json.Marshal(c)
+
+	return Frozen{s: &c, j: j, err: err}
+}
+
+// Schema returns a deep copy of frozen schema that can be modified.
+func (f Frozen) Schema() Schema {
+	if f.s == nil {
+		return Schema{}
+	}
+
+	return cloneSchema(*f.s)
+}
+
+// JSONSchema implements Exposer.
+func (f Frozen) JSONSchema() (Schema, error) {
+	return f.Schema(), f.err
+}
+
+// JSONSchemaBytes implements RawExposer.
+func (f Frozen) JSONSchemaBytes() ([]byte, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	return append([]byte(nil), f.j...), nil
+}
+
+// MarshalJSON returns JSON of frozen schema, it is encoded once on Freeze.
+func (f Frozen) MarshalJSON() ([]byte, error) {
+	if f.s == nil {
+		return []byte("{}"), nil
+	}
+
+	return f.JSONSchemaBytes()
+}
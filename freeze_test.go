@@ -0,0 +1,43 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/jsonschema-go"
+)
+
+func TestSchema_Freeze(t *testing.T) {
+	s := jsonschema.Schema{}
+	s.AddType(jsonschema.Object)
+	s.WithPropertiesItem("name", (&jsonschema.Schema{}).WithType(jsonschema.String.Type()).ToSchemaOrBool())
+	s.WithRequired("name")
+
+	f := s.Freeze()
+
+	// Changes of original and copies do not affect frozen schema.
+	s.Properties["name"].TypeObject.WithMinLength(1)
+	s.Required[0] = "id"
+
+	c := f.Schema()
+	c.Properties["name"].TypeObject.WithMaxLength(10)
+	c.WithDescription("changed")
+
+	expected := `{"properties":{"name":{"type":"string"}},"required":["name"],"type":"object"}`
+
+	assertjson.EqMarshal(t, expected, f)
+	assertjson.EqMarshal(t, expected, f.Schema())
+
+	// Frozen schema can be used as a value for reflection.
+	r := jsonschema.Reflector{}
+
+	type Req struct {
+		Body jsonschema.Frozen `json:"body"`
+	}
+
+	rs, err := r.Reflect(Req{Body: f}, jsonschema.InlineRefs)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"name"}, rs.Properties["body"].TypeObject.Required)
+}
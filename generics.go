@@ -0,0 +1,96 @@
+package jsonschema
+
+import (
+	"strings"
+)
+
+// GenericNameFunc builds definition name of instantiated generic type from definition name of generic type
+// and readable names of type arguments, e.g. "Page" and ["User"].
+type GenericNameFunc func(base string, args []string) string
+
+// GenericNameOf joins names of type arguments with "Of" and "And", e.g. PageOfUser or PairOfStringAndUserList.
+func GenericNameOf(base string, args []string) string {
+	return base + "Of" + strings.Join(args, "And")
+}
+
+// GenericDefNames enables readable definition names of instantiated generic types, e.g. PageOfUser
+// instead of Page[User], f defaults to GenericNameOf.
+//
+// Type arguments are named without package, slices and arrays get "List" suffix,
+// maps are named with f("Map", [key, value]), e.g. MapOfStringAndUser.
+func GenericDefNames(f GenericNameFunc) func(*ReflectContext) {
+	if f == nil {
+		f = GenericNameOf
+	}
+
+	return func(rc *ReflectContext) {
+		rc.GenericDefName = f
+	}
+}
+
+// splitGenericName splits name of instantiated generic type into name of generic type and type arguments.
+func splitGenericName(name string) (string, []string) {
+	i := strings.Index(name, "[")
+	if i <= 0 || !strings.HasSuffix(name, "]") {
+		return name, nil
+	}
+
+	return name[:i], splitTypeArgs(name[i+1 : len(name)-1])
+}
+
+// genericArgName makes readable name of type argument, e.g. "[]github.com/acme/app.User" becomes "UserList".
+func genericArgName(arg string, f GenericNameFunc) string {
+	arg = strings.TrimSpace(arg)
+
+	switch {
+	case strings.HasPrefix(arg, "*"):
+		return genericArgName(arg[1:], f)
+	case strings.HasPrefix(arg, "["):
+		if end := strings.Index(arg, "]"); end > 0 {
+			return genericArgName(arg[end+1:], f) + "List"
+		}
+	case strings.HasPrefix(arg, "map["):
+		depth := 0
+
+		for i := 3; i < len(arg); i++ {
+			switch arg[i] {
+			case '[':
+				depth++
+			case ']':
+				depth--
+			}
+
+			if depth == 0 {
+				return f("Map", []string{genericArgName(arg[4:i], f), genericArgName(arg[i+1:], f)})
+			}
+		}
+	case strings.HasPrefix(arg, "interface {"):
+		return "Any"
+	case strings.HasPrefix(arg, "struct {"):
+		return "Struct"
+	}
+
+	base, args := splitGenericName(arg)
+	base = shortTypeName(base)
+
+	if p := strings.LastIndex(base, "/"); p >= 0 {
+		base = base[p+1:]
+	}
+
+	if p := strings.Index(base, "."); p >= 0 {
+		base = base[p+1:]
+	}
+
+	base = toCamel(strings.Title(base))
+
+	if len(args) == 0 {
+		return base
+	}
+
+	names := make([]string, 0, len(args))
+	for _, a := range args {
+		names = append(names, genericArgName(a, f))
+	}
+
+	return f(base, names)
+}
@@ -8,6 +8,7 @@ require (
 	github.com/swaggest/assertjson v1.9.0
 	github.com/swaggest/refl v1.3.0
 	github.com/yudai/gojsondiff v1.0.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -21,5 +22,4 @@ require (
 	github.com/yudai/golcs v0.0.0-20170316035057-ecda9a501e82 // indirect
 	golang.org/x/net v0.7.0 // indirect
 	golang.org/x/sys v0.5.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
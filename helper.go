@@ -1,16 +1,38 @@
 package jsonschema
 
 import (
-	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 )
 
 const (
 	// XEnumNames is the name of JSON property to store names of enumerated values.
 	XEnumNames = "x-enum-names"
+
+	// XGoType is the name of JSON property to store originating Go type.
+	XGoType = "x-go-type"
+
+	// XGoName is the name of JSON property to store originating Go struct field name.
+	XGoName = "x-go-name"
+
+	// XGroup is the default name of JSON property to store property group.
+	XGroup = "x-group"
+
+	// XSensitive is the default name of JSON property to mark sensitive properties.
+	XSensitive = "x-sensitive"
+
+	// XGroups is the name of JSON property to store index of property groups in object schema.
+	XGroups = "x-groups"
 )
 
+// PropertyGroup lists properties of a group in order of declaration.
+type PropertyGroup struct {
+	Name       string   `json:"name"`
+	Properties []string `json:"properties"`
+}
+
 // NamedEnum returns the enumerated acceptable values with according string names.
 type NamedEnum interface {
 	NamedEnum() ([]interface{}, []string)
@@ -71,17 +93,38 @@ type ElseExposer interface {
 	JSONSchemaElse() interface{}
 }
 
+// NullSchema returns schema that only accepts null, e.g. for tombstone messages.
+func NullSchema() Schema {
+	s := Schema{}
+	s.AddType(Null)
+
+	return s
+}
+
+// AnySchema returns empty schema that accepts any value.
+func AnySchema() Schema {
+	return Schema{}
+}
+
+// NothingSchema returns schema that accepts no value, `{"not":{}}`.
+func NothingSchema() Schema {
+	s := Schema{}
+	s.WithNot(SchemaOrBool{TypeObject: &Schema{}})
+
+	return s
+}
+
 // JSONSchema implements Exposer.
 func (s Schema) JSONSchema() (Schema, error) {
 	// Making a deep copy of Schema with JSON round trip to avoid unintentional sharing of pointer data.
-	j, err := json.Marshal(s)
+	j, err := jsonMarshal(s)
 	if err != nil {
 		return Schema{}, fmt.Errorf("deepcopy marshal: %w", err)
 	}
 
 	var c Schema
 
-	if err := json.Unmarshal(j, &c); err != nil {
+	if err := jsonUnmarshal(j, &c); err != nil {
 		return Schema{}, fmt.Errorf("deepcopy unmarshal: %w", err)
 	}
 
@@ -310,12 +353,12 @@ func (s *Schema) HasType(t SimpleType) bool {
 
 // JSONSchemaBytes exposes JSON Schema as raw JSON bytes.
 func (s SchemaOrBool) JSONSchemaBytes() ([]byte, error) {
-	return json.Marshal(s)
+	return jsonMarshal(s)
 }
 
 // JSONSchemaBytes exposes JSON Schema as raw JSON bytes.
 func (s Schema) JSONSchemaBytes() ([]byte, error) {
-	return json.Marshal(s)
+	return jsonMarshal(s)
 }
 
 // ToSimpleMap encodes JSON Schema as a map.
@@ -332,12 +375,12 @@ func (s SchemaOrBool) ToSimpleMap() (map[string]interface{}, error) {
 		}, nil
 	}
 
-	b, err := json.Marshal(s.TypeObject)
+	b, err := jsonMarshal(s.TypeObject)
 	if err != nil {
 		return nil, err
 	}
 
-	err = json.Unmarshal(b, &m)
+	err = jsonUnmarshal(b, &m)
 	if err != nil {
 		return nil, err
 	}
@@ -347,12 +390,81 @@ func (s SchemaOrBool) ToSimpleMap() (map[string]interface{}, error) {
 
 // FromSimpleMap decodes JSON Schema from a map.
 func (s *SchemaOrBool) FromSimpleMap(m map[string]interface{}) error {
-	j, err := json.Marshal(m)
+	j, err := jsonMarshal(m)
 	if err != nil {
 		return err
 	}
 
 	s.TypeBoolean = nil
 
-	return json.Unmarshal(j, s.TypeObjectEns())
+	return jsonUnmarshal(j, s.TypeObjectEns())
+}
+
+// PresentKeywords returns names of JSON Schema keywords that are set in Schema.
+//
+// Keywords are listed in order of Schema fields, followed by sorted names of ExtraProperties.
+// A keyword is present if its pointer, slice or map field is not nil, so that
+// explicitly set zero values (e.g. "minimum":0) are distinguished from absent keywords.
+// Integer keywords without pointer (minLength, minItems, minProperties) are present when non-zero.
+func (s Schema) PresentKeywords() []string {
+	var (
+		res []string
+		v   = reflect.ValueOf(marshalSchema(s))
+		t   = v.Type()
+	)
+
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+
+		fv := v.Field(i)
+
+		//nolint:exhaustive // Other kinds are not used in Schema.
+		switch fv.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+			if fv.IsNil() {
+				continue
+			}
+		case reflect.Int64:
+			if fv.Int() == 0 {
+				continue
+			}
+		}
+
+		res = append(res, name)
+	}
+
+	if len(s.ExtraProperties) > 0 {
+		extra := make([]string, 0, len(s.ExtraProperties))
+
+		for k := range s.ExtraProperties {
+			extra = append(extra, k)
+		}
+
+		sort.Strings(extra)
+
+		res = append(res, extra...)
+	}
+
+	return res
+}
+
+// HasKeyword checks if JSON Schema keyword is present in Schema.
+func (s Schema) HasKeyword(name string) bool {
+	for _, k := range s.PresentKeywords() {
+		if k == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsZero is true if Schema has no keywords set, i.e. it is equivalent to `{}`.
+//
+// Reflection metadata (ReflectType and Parent) is not taken into account.
+func (s Schema) IsZero() bool {
+	return len(s.PresentKeywords()) == 0
 }
@@ -2,11 +2,13 @@ package jsonschema_test
 
 import (
 	"encoding/json"
+	"reflect"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
 	"github.com/swaggest/jsonschema-go"
 )
 
@@ -176,3 +178,58 @@ func TestSchema_IsTrivial_recursiveTrivial(t *testing.T) {
 		return rs, found
 	}))
 }
+
+func TestSchema_PresentKeywords(t *testing.T) {
+	var s jsonschema.Schema
+
+	assert.True(t, s.IsZero())
+	assert.Empty(t, s.PresentKeywords())
+
+	s.ReflectType = reflect.TypeOf(0)
+	assert.True(t, s.IsZero())
+
+	require.NoError(t, json.Unmarshal([]byte(
+		`{"minimum":0,"type":"integer","minLength":0,"required":[],"x-foo":1,"description":""}`,
+	), &s))
+
+	assert.False(t, s.IsZero())
+	assert.Equal(t, []string{"description", "minimum", "required", "type", "x-foo"}, s.PresentKeywords())
+	assert.True(t, s.HasKeyword("minimum"))
+	assert.False(t, s.HasKeyword("maximum"))
+	assert.False(t, s.HasKeyword("minLength"))
+
+	s = jsonschema.Schema{}
+	s.WithMinItems(2)
+	assert.Equal(t, []string{"minItems"}, s.PresentKeywords())
+}
+
+func TestSpecialSchemas(t *testing.T) {
+	assertjson.EqMarshal(t, `{"type":"null"}`, jsonschema.NullSchema())
+	assertjson.EqMarshal(t, `{}`, jsonschema.AnySchema())
+	assertjson.EqMarshal(t, `{"not":{}}`, jsonschema.NothingSchema())
+
+	type Tombstone struct {
+		Key     string      `json:"key"`
+		Value   interface{} `json:"value" accept:"null" description:"Deleted value."`
+		Payload *Person     `json:"payload" accept:"any"`
+		Removed string      `json:"removed" accept:"nothing"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(Tombstone{})
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "properties":{
+		"key":{"type":"string"},"payload":{},"removed":{"not":{}},
+		"value":{"description":"Deleted value.","type":"null"}
+	  },
+	  "type":"object"
+	}`, s)
+
+	_, err = r.Reflect(struct {
+		A int `json:"a" accept:"some"`
+	}{})
+	assert.EqualError(t, err, `unknown value "some" in accept tag of field A, expected null, any or nothing`)
+}
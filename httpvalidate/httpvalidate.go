@@ -0,0 +1,258 @@
+// Package httpvalidate provides net/http middleware to validate requests against reflected JSON Schemas.
+package httpvalidate
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/swaggest/jsonschema-go"
+)
+
+// DefaultMaxBodyBytes limits size of request body to validate.
+const DefaultMaxBodyBytes = 1 << 20
+
+// ProblemContentType is a media type of error response.
+const ProblemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details response.
+type Problem struct {
+	Type   string                       `json:"type,omitempty"`
+	Title  string                       `json:"title"`
+	Status int                          `json:"status"`
+	Detail string                       `json:"detail,omitempty"`
+	Errors []jsonschema.ValidationError `json:"errors,omitempty"`
+}
+
+// Rules defines validation of a route.
+type Rules struct {
+	// Body validates JSON request body, can be nil.
+	Body *jsonschema.Validator
+
+	// Query validates URL query parameters, can be nil.
+	Query *jsonschema.Validator
+
+	// MaxBodyBytes limits size of request body, DefaultMaxBodyBytes is used if zero.
+	MaxBodyBytes int64
+
+	// OnError can replace default problem details response, can be nil.
+	OnError func(w http.ResponseWriter, r *http.Request, p Problem)
+
+	querySchema jsonschema.Schema
+}
+
+// NewRules reflects schemas of body and query samples and prepares validation rules.
+//
+// Query sample is reflected with "query" field tag, e.g.
+//
+//	type Filter struct {
+//		Limit int      `query:"limit" minimum:"1" maximum:"100"`
+//		Tags  []string `query:"tag"`
+//	}
+//
+// Nil sample disables validation of according part of request.
+func NewRules(r *jsonschema.Reflector, body, query interface{}) (Rules, error) {
+	var rules Rules
+
+	if body != nil {
+		s, err := r.Reflect(body)
+		if err != nil {
+			return rules, fmt.Errorf("reflecting body schema: %w", err)
+		}
+
+		rules.Body = jsonschema.NewValidator(s)
+	}
+
+	if query != nil {
+		s, err := r.Reflect(query, jsonschema.PropertyNameTag("query"), jsonschema.InlineRefs)
+		if err != nil {
+			return rules, fmt.Errorf("reflecting query schema: %w", err)
+		}
+
+		rules.Query = jsonschema.NewValidator(s)
+		rules.querySchema = s
+	}
+
+	return rules, nil
+}
+
+// Middleware creates middleware with validation rules.
+func (rules Rules) Middleware() func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return Handler(rules, next)
+	}
+}
+
+// Handler wraps next handler with request validation.
+//
+// Invalid requests are rejected with problem details response, valid requests are passed to next handler
+// with request body available for reading.
+func Handler(rules Rules, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if p, ok := rules.check(r); !ok {
+			if rules.OnError != nil {
+				rules.OnError(w, r, p)
+			} else {
+				WriteProblem(w, p)
+			}
+
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (rules Rules) check(r *http.Request) (Problem, bool) {
+	if rules.Query != nil {
+		q := QueryValue(r.URL.Query(), rules.querySchema)
+
+		if err := rules.Query.Validate(q); err != nil {
+			return validationProblem("Invalid query parameters", err), false
+		}
+	}
+
+	if rules.Body == nil {
+		return Problem{}, true
+	}
+
+	limit := rules.MaxBodyBytes
+	if limit == 0 {
+		limit = DefaultMaxBodyBytes
+	}
+
+	var body []byte
+
+	if r.Body != nil {
+		b, err := io.ReadAll(io.LimitReader(r.Body, limit+1))
+		if err != nil {
+			return Problem{
+				Title:  "Failed to read request body",
+				Status: http.StatusBadRequest,
+				Detail: err.Error(),
+			}, false
+		}
+
+		if err := r.Body.Close(); err != nil {
+			return Problem{
+				Title:  "Failed to read request body",
+				Status: http.StatusBadRequest,
+				Detail: err.Error(),
+			}, false
+		}
+
+		body = b
+	}
+
+	if int64(len(body)) > limit {
+		return Problem{
+			Title:  "Request body is too large",
+			Status: http.StatusRequestEntityTooLarge,
+			Detail: "maximum size is " + strconv.FormatInt(limit, 10) + " bytes",
+		}, false
+	}
+
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	if err := rules.Body.ValidateJSON(body); err != nil {
+		return validationProblem("Invalid request body", err), false
+	}
+
+	return Problem{}, true
+}
+
+func validationProblem(title string, err error) Problem {
+	p := Problem{
+		Title:  title,
+		Status: http.StatusBadRequest,
+	}
+
+	var ve jsonschema.ValidationErrors
+
+	if errors.As(err, &ve) {
+		p.Errors = ve
+	} else {
+		p.Detail = err.Error()
+	}
+
+	return p
+}
+
+// WriteProblem writes problem details response.
+func WriteProblem(w http.ResponseWriter, p Problem) {
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(p.Status)
+
+	_ = json.NewEncoder(w).Encode(p) //nolint:errchkjson // Error can not be handled after writing header.
+}
+
+// QueryValue converts URL query parameters to a JSON value suitable for validation.
+//
+// Parameter values are converted to types defined in according property schema,
+// parameters with array schema receive all provided values, others receive first value.
+// Values that can not be converted are kept as strings to fail validation.
+func QueryValue(q url.Values, schema jsonschema.Schema) map[string]interface{} {
+	res := make(map[string]interface{}, len(q))
+
+	for name, values := range q {
+		if len(values) == 0 {
+			continue
+		}
+
+		var ps *jsonschema.Schema
+
+		if p, ok := schema.Properties[name]; ok {
+			ps = p.TypeObject
+		}
+
+		if ps != nil && ps.HasType(jsonschema.Array) {
+			var is *jsonschema.Schema
+
+			if ps.Items != nil && ps.Items.SchemaOrBool != nil {
+				is = ps.Items.SchemaOrBool.TypeObject
+			}
+
+			items := make([]interface{}, 0, len(values))
+
+			for _, v := range values {
+				items = append(items, scalarValue(v, is))
+			}
+
+			res[name] = items
+
+			continue
+		}
+
+		res[name] = scalarValue(values[0], ps)
+	}
+
+	return res
+}
+
+func scalarValue(v string, s *jsonschema.Schema) interface{} {
+	if s == nil {
+		return v
+	}
+
+	switch {
+	case s.HasType(jsonschema.Integer):
+		if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return i
+		}
+	case s.HasType(jsonschema.Number):
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	case s.HasType(jsonschema.Boolean):
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+
+	return v
+}
@@ -0,0 +1,114 @@
+package httpvalidate_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/jsonschema-go"
+	"github.com/swaggest/jsonschema-go/httpvalidate"
+)
+
+type filter struct {
+	Limit  int      `query:"limit" minimum:"1" maximum:"100"`
+	Tags   []string `query:"tag" maxItems:"2"`
+	Strict bool     `query:"strict"`
+}
+
+type order struct {
+	ID    int    `json:"id" required:"true"`
+	Email string `json:"email" format:"email"`
+}
+
+func TestHandler(t *testing.T) {
+	rules, err := httpvalidate.NewRules(&jsonschema.Reflector{}, order{}, filter{})
+	require.NoError(t, err)
+
+	rules.MaxBodyBytes = 100
+
+	h := rules.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		_, err = w.Write(b)
+		assert.NoError(t, err)
+	}))
+
+	for _, tc := range []struct {
+		name   string
+		url    string
+		body   string
+		status int
+		resp   string
+	}{
+		{
+			name: "valid", url: "/?limit=10&tag=a&tag=b&strict=true", body: `{"id":1}`,
+			status: http.StatusOK, resp: `{"id":1}`,
+		},
+		{
+			name: "invalid query", url: "/?limit=0&tag=a&tag=b&tag=c&strict=foo", body: `{"id":1}`,
+			status: http.StatusBadRequest,
+			resp: `{
+			  "title":"Invalid query parameters","status":400,
+			  "errors":[
+				{
+				  "keyword":"minimum","instancePath":"/limit","schemaPath":"#/properties/limit/minimum",
+				  "message":"value 0 must be greater than or equal to 1"
+				},
+				{
+				  "keyword":"type","instancePath":"/strict","schemaPath":"#/properties/strict/type",
+				  "message":"expected boolean, got string"
+				},
+				{
+				  "keyword":"maxItems","instancePath":"/tag","schemaPath":"#/properties/tag/maxItems",
+				  "message":"array must have at most 2 items, got 3"
+				}
+			  ]
+			}`,
+		},
+		{
+			name: "invalid body", url: "/", body: `{"email":"foo@bar.baz"}`,
+			status: http.StatusBadRequest,
+			resp: `{
+			  "title":"Invalid request body","status":400,
+			  "errors":[
+				{
+				  "keyword":"required","instancePath":"","schemaPath":"#/required",
+				  "message":"missing required property \"id\""
+				}
+			  ]
+			}`,
+		},
+		{
+			name: "malformed body", url: "/", body: `{"id":`,
+			status: http.StatusBadRequest,
+			resp:   `{"title":"Invalid request body","status":400,"detail":"failed to decode JSON: unexpected EOF"}`,
+		},
+		{
+			name: "large body", url: "/", body: `{"id":1,"email":"` + strings.Repeat("a", 100) + `"}`,
+			status: http.StatusRequestEntityTooLarge,
+			resp:   `{"title":"Request body is too large","status":413,"detail":"maximum size is 100 bytes"}`,
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tc.url, strings.NewReader(tc.body))
+			rw := httptest.NewRecorder()
+
+			h.ServeHTTP(rw, req)
+
+			assert.Equal(t, tc.status, rw.Code)
+			assertjson.Equal(t, []byte(tc.resp), rw.Body.Bytes())
+
+			if tc.status != http.StatusOK {
+				assert.Equal(t, httpvalidate.ProblemContentType, rw.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
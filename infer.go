@@ -0,0 +1,118 @@
+package jsonschema
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// FormatRule assigns format to string properties with names ending with a word.
+type FormatRule struct {
+	// Suffix is a lowercase last word of property name, e.g. "email" matches
+	// "email", "user_email" and "userEmail".
+	Suffix string
+
+	// Format is assigned to matching properties, e.g. "email".
+	Format string
+}
+
+// DefaultFormatRules are used by InferFormats if no rules are provided.
+var DefaultFormatRules = []FormatRule{
+	{Suffix: "email", Format: "email"},
+	{Suffix: "url", Format: "uri"},
+	{Suffix: "uri", Format: "uri"},
+	{Suffix: "at", Format: "date-time"},
+}
+
+// InferredFormat describes format assigned by InferFormats.
+type InferredFormat struct {
+	// Path is a path to the property, it ends with property name, e.g. ["#", "user", "created_at"].
+	Path   []string
+	Field  reflect.StructField
+	Format string
+	Rule   FormatRule
+}
+
+// InferFormats enables assigning formats to string properties without explicit format by field name
+// conventions, e.g. `*_email` becomes "email", `*_url` becomes "uri", `*_at` becomes "date-time".
+//
+// Optional confirm callback receives every inferred format for diagnostics, format is only assigned if
+// callback returns true. DefaultFormatRules are used if rules are not provided, first matching rule wins.
+func InferFormats(confirm func(f InferredFormat) bool, rules ...FormatRule) func(rc *ReflectContext) {
+	if len(rules) == 0 {
+		rules = DefaultFormatRules
+	}
+
+	return func(rc *ReflectContext) {
+		rc.FormatRules = rules
+		rc.ConfirmFormat = confirm
+	}
+}
+
+func inferFormat(propertySchema *Schema, propName string, field reflect.StructField, rc *ReflectContext) {
+	if len(rc.FormatRules) == 0 || propertySchema.Format != nil || propertySchema.Ref != nil ||
+		!propertySchema.HasType(String) {
+		return
+	}
+
+	words := nameWords(propName)
+	if len(words) == 0 {
+		return
+	}
+
+	last := words[len(words)-1]
+
+	for _, rule := range rc.FormatRules {
+		if rule.Suffix != last {
+			continue
+		}
+
+		if rc.ConfirmFormat != nil && !rc.ConfirmFormat(InferredFormat{
+			Path:   append(rc.Path[:len(rc.Path):len(rc.Path)], propName),
+			Field:  field,
+			Format: rule.Format,
+			Rule:   rule,
+		}) {
+			return
+		}
+
+		propertySchema.WithFormat(rule.Format)
+
+		return
+	}
+}
+
+// nameWords splits snake_case, kebab-case and camelCase names into lowercase words.
+func nameWords(name string) []string {
+	var (
+		words []string
+		word  []rune
+	)
+
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, strings.ToLower(string(word)))
+			word = word[:0]
+		}
+	}
+
+	runes := []rune(name)
+
+	for i, c := range runes {
+		switch {
+		case !unicode.IsLetter(c) && !unicode.IsDigit(c):
+			flush()
+
+			continue
+		case unicode.IsUpper(c) && i > 0 && (unicode.IsLower(runes[i-1]) ||
+			(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))):
+			flush()
+		}
+
+		word = append(word, c)
+	}
+
+	flush()
+
+	return words
+}
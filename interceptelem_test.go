@@ -0,0 +1,82 @@
+package jsonschema_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/jsonschema-go"
+)
+
+func TestInterceptItems(t *testing.T) {
+	type Doc struct {
+		Tags   []string          `json:"tags"`
+		Matrix [][]int           `json:"matrix"`
+		Labels map[string]string `json:"labels"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	var paths []string
+
+	s, err := r.Reflect(Doc{},
+		jsonschema.InterceptItems(func(params jsonschema.InterceptElemParams) error {
+			paths = append(paths, params.InstancePath+" "+params.SchemaPath)
+
+			if params.Type.Kind() == reflect.String {
+				params.Schema.WithMaxLength(10)
+			}
+
+			if params.Type.Kind() == reflect.Int {
+				params.Schema.WithMinimum(0)
+			}
+
+			return nil
+		}),
+		jsonschema.InterceptAdditionalProperties(func(params jsonschema.InterceptElemParams) error {
+			paths = append(paths, params.InstancePath+" "+params.SchemaPath)
+
+			assert.Equal(t, []string{"#", "labels", "{}"}, params.Path)
+			assert.True(t, params.ParentSchema.HasType(jsonschema.Object))
+
+			params.Schema.WithMinLength(1)
+
+			return nil
+		}),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"/tags/* #/properties/tags/items",
+		"/matrix/*/* #/properties/matrix/items/items",
+		"/matrix/* #/properties/matrix/items",
+		"/labels/* #/properties/labels/additionalProperties",
+	}, paths)
+
+	assertjson.EqMarshal(t, `{
+	  "properties":{
+		"labels":{
+		  "additionalProperties":{"minLength":1,"type":"string"},
+		  "type":["object","null"]
+		},
+		"matrix":{
+		  "items":{"items":{"minimum":0,"type":"integer"},"type":"array"},
+		  "type":["array","null"]
+		},
+		"tags":{"items":{"maxLength":10,"type":"string"},"type":["array","null"]}
+	  },
+	  "type":"object"
+	}`, s)
+}
+
+func TestInterceptItems_error(t *testing.T) {
+	r := jsonschema.Reflector{}
+
+	_, err := r.Reflect([]int{}, jsonschema.InterceptItems(func(_ jsonschema.InterceptElemParams) error {
+		return errors.New("failed")
+	}))
+	assert.EqualError(t, err, "failed")
+}
@@ -0,0 +1,96 @@
+package jsonschema
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	itemsTagPrefix  = "items"
+	valuesTagPrefix = "additionalProperties"
+)
+
+// reflectItemsTags applies tags with "items" prefix to items schema of array property,
+// e.g. `itemsMinimum:"0"` sets `minimum` of items, nested arrays can be constrained with `itemsItemsMinimum`.
+func reflectItemsTags(s *Schema, tag reflect.StructTag, fieldName string) error {
+	it := prefixedTag(tag, itemsTagPrefix)
+	if it == "" {
+		return nil
+	}
+
+	if s.Items == nil || s.Items.SchemaOrBool == nil || s.Items.SchemaOrBool.TypeObject == nil {
+		return fmt.Errorf("items tags require inline array schema of %s", fieldName)
+	}
+
+	return reflectElemTags(s.Items.SchemaOrBool.TypeObject, it, fieldName)
+}
+
+// reflectValuesTags applies tags with "additionalProperties" prefix to values schema of map property,
+// e.g. `additionalPropertiesMinLength:"1"` sets `minLength` of values.
+func reflectValuesTags(s *Schema, tag reflect.StructTag, fieldName string) error {
+	vt := prefixedTag(tag, valuesTagPrefix)
+	if vt == "" {
+		return nil
+	}
+
+	if s.AdditionalProperties == nil || s.AdditionalProperties.TypeObject == nil {
+		return fmt.Errorf("additionalProperties tags require inline map schema of %s", fieldName)
+	}
+
+	return reflectElemTags(s.AdditionalProperties.TypeObject, vt, fieldName)
+}
+
+// reflectElemTags applies tags to schema of array item or map value, prefixed tags are applied to nested elements,
+// e.g. `itemsAdditionalPropertiesMinimum` for []map[string]int.
+//
+// Referenced schema is wrapped with `allOf` to keep constraints effective.
+func reflectElemTags(elem *Schema, tag reflect.StructTag, fieldName string) error {
+	if elem.Ref != nil {
+		ref := *elem
+		*elem = Schema{AllOf: []SchemaOrBool{ref.ToSchemaOrBool()}}
+	}
+
+	if err := populateFieldsFromTags(elem, tag); err != nil {
+		return fmt.Errorf("%s: %w", fieldName, err)
+	}
+
+	reflectEnum(elem, tag, nil)
+
+	if err := reflectValuesTags(elem, tag, fieldName); err != nil {
+		return err
+	}
+
+	return reflectItemsTags(elem, tag, fieldName)
+}
+
+// prefixedTag returns tag of nested schema from tags with prefix, e.g. `itemsMinimum:"0"` becomes `minimum:"0"`.
+func prefixedTag(tag reflect.StructTag, prefix string) reflect.StructTag {
+	var res []string
+
+	// Tag syntax is parsed in the same way as in reflect.StructTag.Lookup.
+	for s := strings.TrimLeft(string(tag), " "); s != ""; s = strings.TrimLeft(s, " ") {
+		i := strings.Index(s, ":")
+		if i <= 0 || i+1 >= len(s) || s[i+1] != '"' {
+			break
+		}
+
+		name := s[:i]
+
+		value, err := strconv.QuotedPrefix(s[i+1:])
+		if err != nil {
+			break
+		}
+
+		s = s[i+1+len(value):]
+
+		if len(name) > len(prefix) && strings.HasPrefix(name, prefix) && unicode.IsUpper(rune(name[len(prefix)])) {
+			name = name[len(prefix):]
+			res = append(res, strings.ToLower(name[:1])+name[1:]+":"+value)
+		}
+	}
+
+	return reflect.StructTag(strings.Join(res, " "))
+}
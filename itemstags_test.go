@@ -0,0 +1,120 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/jsonschema-go"
+)
+
+type itemsPoint struct {
+	X int `json:"x"`
+}
+
+func TestReflector_Reflect_itemsTags(t *testing.T) {
+	type Filter struct {
+		Scores []float64    `json:"scores" itemsMinimum:"0" itemsExclusiveMaximum:"100"`
+		Names  []string     `json:"names" minItems:"1" itemsPattern:"^[a-z]+$" itemsMaxLength:"16"`
+		IDs    []string     `json:"ids" itemsFormat:"uuid"`
+		Kinds  []string     `json:"kinds" itemsEnum:"a,b"`
+		Matrix [][]int      `json:"matrix" itemsMinItems:"2" itemsItemsMaximum:"9"`
+		Points []itemsPoint `json:"points" itemsMinProperties:"1"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(Filter{})
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "definitions":{"JsonschemaGoTestItemsPoint":{"properties":{"x":{"type":"integer"}},"type":"object"}},
+	  "properties":{
+		"ids":{"items":{"format":"uuid","type":"string"},"type":["array","null"]},
+		"kinds":{"items":{"enum":["a","b"],"type":"string"},"type":["array","null"]},
+		"matrix":{
+		  "items":{"items":{"maximum":9,"type":"integer"},"minItems":2,"type":"array"},
+		  "type":["array","null"]
+		},
+		"names":{
+		  "items":{"maxLength":16,"pattern":"^[a-z]+$","type":"string"},"minItems":1,
+		  "type":["array","null"]
+		},
+		"scores":{
+		  "items":{"exclusiveMaximum":100,"minimum":0,"type":"number"},"type":["array","null"]
+		},
+		"points":{
+		  "items":{"allOf":[{"$ref":"#/definitions/JsonschemaGoTestItemsPoint"}],"minProperties":1},
+		  "type":["array","null"]
+		}
+	  },
+	  "type":"object"
+	}`, s)
+}
+
+func TestReflector_Reflect_itemsTags_error(t *testing.T) {
+	type Invalid struct {
+		Name string `json:"name" itemsMinimum:"0"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	_, err := r.Reflect(Invalid{})
+	assert.EqualError(t, err, "items tags require inline array schema of Name")
+}
+
+func TestReflector_Reflect_additionalPropertiesTags(t *testing.T) {
+	type Config struct {
+		Links   map[string]string            `json:"links" additionalPropertiesFormat:"uri"`
+		Limits  map[string]int               `json:"limits" additionalPropertiesMinimum:"1" patternProperties:"^[a-z]+$"`
+		Points  map[string]itemsPoint        `json:"points" additionalPropertiesMinProperties:"1"`
+		Groups  map[string][]string          `json:"groups" additionalPropertiesMinItems:"1" additionalPropertiesItemsMinLength:"2"`
+		Weights []map[string]float64         `json:"weights" itemsAdditionalPropertiesMaximum:"1"`
+		Nested  map[string]map[string]string `json:"nested" additionalPropertiesAdditionalPropertiesEnum:"a,b"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(Config{})
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "definitions":{"JsonschemaGoTestItemsPoint":{"properties":{"x":{"type":"integer"}},"type":"object"}},
+	  "properties":{
+		"groups":{
+		  "additionalProperties":{"items":{"minLength":2,"type":"string"},"minItems":1,"type":"array"},
+		  "type":["object","null"]
+		},
+		"limits":{
+		  "additionalProperties":false,"patternProperties":{"^[a-z]+$":{"minimum":1,"type":"integer"}},
+		  "type":["object","null"]
+		},
+		"links":{"additionalProperties":{"format":"uri","type":"string"},"type":["object","null"]},
+		"nested":{
+		  "additionalProperties":{
+			"additionalProperties":{"enum":["a","b"],"type":"string"},"type":"object"
+		  },
+		  "type":["object","null"]
+		},
+		"points":{
+		  "additionalProperties":{
+			"allOf":[{"$ref":"#/definitions/JsonschemaGoTestItemsPoint"}],"minProperties":1
+		  },
+		  "type":["object","null"]
+		},
+		"weights":{
+		  "items":{"additionalProperties":{"maximum":1,"type":"number"},"type":"object"},
+		  "type":["array","null"]
+		}
+	  },
+	  "type":"object"
+	}`, s)
+
+	type Invalid struct {
+		Name string `json:"name" additionalPropertiesMinLength:"1"`
+	}
+
+	_, err = r.Reflect(Invalid{})
+	assert.EqualError(t, err, "additionalProperties tags require inline map schema of Name")
+}
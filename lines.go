@@ -0,0 +1,111 @@
+package jsonschema
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// DefaultMaxLineErrors is a default limit of reported invalid lines in ValidateLines.
+const DefaultMaxLineErrors = 100
+
+// LineError is a failure of a single line of JSON Lines (NDJSON) input.
+type LineError struct {
+	// Line is a 1-based line number.
+	Line int
+
+	// Err is a ValidationErrors or JSON decoding error.
+	Err error
+}
+
+// Error implements error.
+func (e LineError) Error() string {
+	return "line " + strconv.Itoa(e.Line) + ": " + e.Err.Error()
+}
+
+// Unwrap returns underlying error.
+func (e LineError) Unwrap() error {
+	return e.Err
+}
+
+// LineErrors aggregates failures of JSON Lines input.
+type LineErrors struct {
+	// Errors contains failures of first invalid lines, up to a limit.
+	Errors []LineError
+
+	// Invalid is a total number of invalid lines, it can exceed len(Errors).
+	Invalid int
+
+	// Lines is a total number of validated non-empty lines.
+	Lines int
+}
+
+// Error implements error.
+func (e LineErrors) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, le := range e.Errors {
+		msgs = append(msgs, le.Error())
+	}
+
+	res := strconv.Itoa(e.Invalid) + " of " + strconv.Itoa(e.Lines) + " lines are invalid: " + strings.Join(msgs, "; ")
+
+	if omitted := e.Invalid - len(e.Errors); omitted > 0 {
+		res += "; " + strconv.Itoa(omitted) + " more"
+	}
+
+	return res
+}
+
+// ValidateLines checks each line of JSON Lines (NDJSON) input against an item schema.
+//
+// Empty lines are skipped. Returned error is LineErrors if any line is invalid.
+func ValidateLines(r io.Reader, itemSchema Schema) error {
+	return NewValidator(itemSchema).ValidateLines(r)
+}
+
+// ValidateLines checks each line of JSON Lines (NDJSON) input against schema.
+//
+// Empty lines are skipped, lines are read without length limit. All lines are validated, but only
+// first MaxLineErrors failures are kept in returned LineErrors. Read failure is returned as is.
+func (v *Validator) ValidateLines(r io.Reader) error {
+	limit := v.MaxLineErrors
+	if limit == 0 {
+		limit = DefaultMaxLineErrors
+	}
+
+	var res LineErrors
+
+	br := bufio.NewReader(r)
+
+	for n := 1; ; n++ {
+		line, err := br.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+
+		if len(bytes.TrimSpace(line)) > 0 {
+			res.Lines++
+
+			if verr := v.ValidateJSON(line); verr != nil {
+				res.Invalid++
+
+				if limit < 0 || len(res.Errors) < limit {
+					res.Errors = append(res.Errors, LineError{Line: n, Err: verr})
+				}
+			}
+		}
+
+		if err != nil {
+			break
+		}
+	}
+
+	if res.Invalid > 0 {
+		return res
+	}
+
+	return nil
+}
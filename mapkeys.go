@@ -0,0 +1,88 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// KeyNamesExposer exposes schema of map keys as `propertyNames`, it is implemented by map key type, e.g.
+//
+//	type Locale string
+//
+//	func (Locale) JSONSchemaKeyNames() jsonschema.Schema {
+//		return *(&jsonschema.Schema{}).WithPattern("^[a-z]{2}(-[A-Z]{2})?$")
+//	}
+//
+// Schema of map[Locale]string is reflected with `"propertyNames":{"pattern":"^[a-z]{2}(-[A-Z]{2})?$"}`.
+type KeyNamesExposer interface {
+	JSONSchemaKeyNames() Schema
+}
+
+// reflectMapKeys sets up propertyNames from map key type that implements KeyNamesExposer.
+func reflectMapKeys(t reflect.Type, schema *Schema) {
+	kt := t.Key()
+	if kt.Kind() == reflect.Interface {
+		return
+	}
+
+	e, ok := reflect.Zero(kt).Interface().(KeyNamesExposer)
+	if !ok {
+		e, ok = reflect.New(kt).Interface().(KeyNamesExposer)
+	}
+
+	if ok {
+		s := e.JSONSchemaKeyNames()
+		schema.WithPropertyNames(s.ToSchemaOrBool())
+	}
+}
+
+// reflectMapTags applies `valuesSchemaRef`, `propertyNames`, `patternProperties` and values tags
+// (e.g. `additionalPropertiesMinLength`) of map field.
+//
+// Tag `valuesSchemaRef` contains a name of definition (see `refer` tag) that replaces `additionalProperties`, e.g.
+// to declare a contract of json.RawMessage values. Tag `propertyNames` contains a regular expression or a JSON schema
+// of keys, tag `patternProperties` contains a regular expression of keys, schema of values is moved from
+// `additionalProperties` to `patternProperties` and other keys are disallowed.
+func reflectMapTags(propertySchema *Schema, field reflect.StructField, rc *ReflectContext) error {
+	if name, ok := field.Tag.Lookup("valuesSchemaRef"); ok {
+		if propertySchema.AdditionalProperties == nil || name == "" {
+			return fmt.Errorf("valuesSchemaRef tag requires inline map schema and definition name of %s", field.Name)
+		}
+
+		ref := Ref{Path: rc.DefinitionsPrefix, Name: name}.Schema()
+		propertySchema.WithAdditionalProperties(ref.ToSchemaOrBool())
+		rc.referredDefs = append(rc.referredDefs, name)
+	}
+
+	if v, ok := field.Tag.Lookup("propertyNames"); ok {
+		s := Schema{}
+
+		if strings.HasPrefix(strings.TrimSpace(v), "{") {
+			if err := json.Unmarshal([]byte(v), &s); err != nil {
+				return fmt.Errorf("failed to parse propertyNames tag: %w", err)
+			}
+		} else {
+			s.WithPattern(v)
+		}
+
+		propertySchema.WithPropertyNames(s.ToSchemaOrBool())
+	}
+
+	// Values tags are applied before values schema is moved to patternProperties.
+	if err := reflectValuesTags(propertySchema, field.Tag, field.Name); err != nil {
+		return err
+	}
+
+	if v, ok := field.Tag.Lookup("patternProperties"); ok {
+		if propertySchema.AdditionalProperties == nil || propertySchema.AdditionalProperties.TypeObject == nil {
+			return fmt.Errorf("patternProperties tag requires inline map schema of %s", field.Name)
+		}
+
+		propertySchema.WithPatternPropertiesItem(v, *propertySchema.AdditionalProperties)
+		propertySchema.AdditionalProperties = (&SchemaOrBool{}).WithTypeBoolean(false)
+	}
+
+	return nil
+}
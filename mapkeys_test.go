@@ -0,0 +1,102 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/jsonschema-go"
+)
+
+type mapLocale string
+
+func (mapLocale) JSONSchemaKeyNames() jsonschema.Schema {
+	return *(&jsonschema.Schema{}).WithPattern("^[a-z]{2}$")
+}
+
+func TestReflector_Reflect_mapKeys(t *testing.T) {
+	type Catalog struct {
+		Titles  map[mapLocale]string `json:"titles"`
+		Labels  map[string]int       `json:"labels" propertyNames:"^[a-z]+$"`
+		Limits  map[string]int       `json:"limits" propertyNames:"{\"maxLength\":8}"`
+		Headers map[string]string    `json:"headers" patternProperties:"^X-"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(Catalog{})
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "properties":{
+		"headers":{
+		  "additionalProperties":false,"patternProperties":{"^X-":{"type":"string"}},
+		  "type":["object","null"]
+		},
+		"labels":{
+		  "additionalProperties":{"type":"integer"},"propertyNames":{"pattern":"^[a-z]+$"},
+		  "type":["object","null"]
+		},
+		"limits":{
+		  "additionalProperties":{"type":"integer"},"propertyNames":{"maxLength":8},
+		  "type":["object","null"]
+		},
+		"titles":{
+		  "additionalProperties":{"type":"string"},"propertyNames":{"pattern":"^[a-z]{2}$"},
+		  "type":["object","null"]
+		}
+	  },
+	  "type":"object"
+	}`, s)
+
+	v := jsonschema.NewValidator(s)
+	assert.NoError(t, v.ValidateJSON([]byte(`{"titles":{"en":"a"},"labels":{"a":1},"headers":{"X-Id":"1"}}`)))
+	assert.Error(t, v.ValidateJSON([]byte(`{"titles":{"eng":"a"}}`)))
+	assert.Error(t, v.ValidateJSON([]byte(`{"headers":{"Id":"1"}}`)))
+}
+
+func TestReflector_Reflect_mapKeys_error(t *testing.T) {
+	type Invalid struct {
+		Name string `json:"name" patternProperties:"^X-"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	_, err := r.Reflect(Invalid{})
+	assert.EqualError(t, err, "patternProperties tag requires inline map schema of Name")
+}
+
+func TestReflector_Reflect_valuesSchemaRef(t *testing.T) {
+	type Batch struct {
+		Events  map[string]json.RawMessage `json:"events" valuesSchemaRef:"Event"`
+		Headers map[string]json.RawMessage `json:"headers" valuesSchemaRef:"Header" patternProperties:"^X-"`
+	}
+
+	r := jsonschema.Reflector{}
+	r.AddDefinition("Event", *(&jsonschema.Schema{}).WithType(jsonschema.Object.Type()).WithRequired("type"))
+	r.AddDefinition("Header", *(&jsonschema.Schema{}).WithType(jsonschema.String.Type()))
+
+	s, err := r.Reflect(Batch{})
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "definitions":{"Event":{"required":["type"],"type":"object"},"Header":{"type":"string"}},
+	  "properties":{
+		"events":{"additionalProperties":{"$ref":"#/definitions/Event"},"type":["object","null"]},
+		"headers":{
+		  "additionalProperties":false,"patternProperties":{"^X-":{"$ref":"#/definitions/Header"}},
+		  "type":["object","null"]
+		}
+	  },
+	  "type":"object"
+	}`, s)
+
+	type Missing struct {
+		Events map[string]json.RawMessage `json:"events" valuesSchemaRef:"Unknown"`
+	}
+
+	_, err = r.Reflect(Missing{})
+	assert.EqualError(t, err, "referred definition not found: Unknown")
+}
@@ -0,0 +1,89 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// CandidateResult describes validation of a document against a candidate schema.
+type CandidateResult struct {
+	// Name is a name of candidate.
+	Name string `json:"name"`
+
+	// ErrorCount is a number of validation errors, 0 for a valid document.
+	ErrorCount int `json:"errorCount"`
+
+	// Errors are validation errors of candidate.
+	Errors ValidationErrors `json:"errors,omitempty"`
+}
+
+// Report describes evaluation of a document against multiple candidate schemas.
+type Report struct {
+	// Candidates are sorted from the best match by number of errors, then by name.
+	Candidates []CandidateResult `json:"candidates"`
+
+	// Valid is true if best match has no errors.
+	Valid bool `json:"valid"`
+
+	// DecodeError is set if document is not a valid JSON, candidates are not evaluated then.
+	DecodeError error `json:"-"`
+}
+
+// MatchSchema evaluates JSON document against candidate schemas and returns the name of the closest match,
+// e.g. to route untyped webhook payloads.
+//
+// Closest match is a candidate with the least number of validation errors, candidates with equal number
+// of errors are ordered by name. Report contains errors of every candidate, bestName is empty if document
+// can not be decoded or there are no candidates.
+func MatchSchema(doc []byte, candidates map[string]Schema) (bestName string, report Report) {
+	var value interface{}
+
+	d := json.NewDecoder(bytes.NewReader(doc))
+	d.UseNumber()
+
+	if err := d.Decode(&value); err != nil {
+		report.DecodeError = fmt.Errorf("failed to decode JSON: %w", err)
+
+		return "", report
+	}
+
+	report.Candidates = make([]CandidateResult, 0, len(candidates))
+
+	for name, schema := range candidates {
+		res := CandidateResult{Name: name}
+
+		if err := NewValidator(schema).Validate(value); err != nil {
+			var ve ValidationErrors
+			if errors.As(err, &ve) {
+				res.Errors = ve
+			} else {
+				res.Errors = ValidationErrors{{Message: err.Error()}}
+			}
+
+			res.ErrorCount = len(res.Errors)
+		}
+
+		report.Candidates = append(report.Candidates, res)
+	}
+
+	sort.Slice(report.Candidates, func(i, j int) bool {
+		ci, cj := report.Candidates[i], report.Candidates[j]
+		if ci.ErrorCount != cj.ErrorCount {
+			return ci.ErrorCount < cj.ErrorCount
+		}
+
+		return ci.Name < cj.Name
+	})
+
+	if len(report.Candidates) == 0 {
+		return "", report
+	}
+
+	best := report.Candidates[0]
+	report.Valid = best.ErrorCount == 0
+
+	return best.Name, report
+}
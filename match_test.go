@@ -0,0 +1,50 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/jsonschema-go"
+)
+
+func TestMatchSchema(t *testing.T) {
+	type PushEvent struct {
+		Ref     string   `json:"ref" required:"true"`
+		Commits []string `json:"commits" required:"true"`
+	}
+
+	type IssueEvent struct {
+		Action string `json:"action" required:"true" enum:"opened,closed"`
+		Issue  int    `json:"issue" required:"true"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	push, err := r.Reflect(PushEvent{})
+	require.NoError(t, err)
+
+	issue, err := r.Reflect(IssueEvent{})
+	require.NoError(t, err)
+
+	candidates := map[string]jsonschema.Schema{"push": push, "issue": issue}
+
+	name, report := jsonschema.MatchSchema([]byte(`{"action":"opened","issue":12}`), candidates)
+	assert.Equal(t, "issue", name)
+	assert.True(t, report.Valid)
+	require.Len(t, report.Candidates, 2)
+	assert.Equal(t, 0, report.Candidates[0].ErrorCount)
+	assert.Equal(t, "push", report.Candidates[1].Name)
+	assert.Equal(t, 2, report.Candidates[1].ErrorCount)
+
+	name, report = jsonschema.MatchSchema([]byte(`{"action":"reopened","issue":12}`), candidates)
+	assert.Equal(t, "issue", name)
+	assert.False(t, report.Valid)
+	assert.Equal(t, 1, report.Candidates[0].ErrorCount)
+	assert.Equal(t, "/action", report.Candidates[0].Errors[0].InstancePath)
+
+	name, report = jsonschema.MatchSchema([]byte(`{"action":`), candidates)
+	assert.Empty(t, name)
+	assert.Error(t, report.DecodeError)
+	assert.Empty(t, report.Candidates)
+}
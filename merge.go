@@ -0,0 +1,667 @@
+package jsonschema
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"regexp"
+)
+
+const (
+	// ErrNotMergeable indicates that schemas have keywords that can not be combined in a single schema,
+	// e.g. references or different patterns.
+	ErrNotMergeable = sentinelError("schemas can not be merged")
+
+	// ErrUnsatisfiable indicates that merged constraints contradict, so that no value is valid.
+	ErrUnsatisfiable = sentinelError("merged constraints are unsatisfiable")
+)
+
+// Merge combines two schemas into a single schema that is equivalent to `{"allOf":[a,b]}`.
+//
+// Types and enums are intersected, numeric, length and count bounds are tightened, `required` is a union,
+// properties, items and definitions are merged recursively, `allOf` items are concatenated.
+// Annotations (e.g. title, description, default, examples and extensions) of b take precedence.
+//
+// ErrNotMergeable is returned if schemas have keywords that can not be combined (e.g. `$ref`, different
+// `pattern` or `anyOf` in both schemas), ErrUnsatisfiable is returned for contradicting constraints.
+func Merge(a, b Schema) (Schema, error) {
+	return mergeSchemas(cloneSchema(a), cloneSchema(b), "")
+}
+
+// CollapseAllOf returns a copy of schema with `allOf` items merged into the schema, see Merge.
+//
+// Items that can not be merged (e.g. references) are kept in `allOf`, subschemas are collapsed too.
+// Annotations of the schema take precedence over annotations of `allOf` items.
+func (s Schema) CollapseAllOf() Schema {
+	c := cloneSchema(s)
+	collapseAllOf(&c)
+
+	return c
+}
+
+func collapseAllOf(s *Schema) {
+	_ = mapSubschemas(s, func(sb SchemaOrBool) (SchemaOrBool, error) { //nolint:errcheck // Callback does not fail.
+		if sb.TypeObject != nil {
+			collapseAllOf(sb.TypeObject)
+		}
+
+		return sb, nil
+	})
+
+	if len(s.AllOf) == 0 {
+		return
+	}
+
+	base := *s
+	base.AllOf = nil
+
+	var rest []SchemaOrBool
+
+	for _, item := range s.AllOf {
+		if item.TypeBoolean != nil && *item.TypeBoolean {
+			continue
+		}
+
+		if item.TypeObject == nil {
+			rest = append(rest, item)
+
+			continue
+		}
+
+		merged, err := mergeSchemas(cloneSchema(*item.TypeObject), base, "")
+		if err != nil {
+			rest = append(rest, item)
+
+			continue
+		}
+
+		base = merged
+	}
+
+	base.AllOf = append(base.AllOf, rest...)
+	*s = base
+}
+
+func mergeError(err error, path, keyword string) error {
+	return fmt.Errorf("%w: %s/%s", err, path, keyword)
+}
+
+func mergeSchemas(a, b Schema, path string) (Schema, error) {
+	if schemasEqual(a.ToSchemaOrBool(), b.ToSchemaOrBool()) {
+		return a, nil
+	}
+
+	if a.Ref != nil || b.Ref != nil {
+		return a, mergeError(ErrNotMergeable, path, "$ref")
+	}
+
+	res := a
+
+	mergeAnnotations(&res, b)
+
+	steps := []func(res *Schema, b Schema, path string) error{
+		mergeTypes, mergeEnum, mergeBounds, mergeStringKeywords, mergeObjects, mergeArrays, mergeComposition,
+	}
+
+	for _, step := range steps {
+		if err := step(&res, b, path); err != nil {
+			return res, err
+		}
+	}
+
+	return res, nil
+}
+
+func mergeAnnotations(res *Schema, b Schema) {
+	if b.ID != nil {
+		res.ID = b.ID
+	}
+
+	if b.Schema != nil {
+		res.Schema = b.Schema
+	}
+
+	if b.Comment != nil {
+		res.Comment = b.Comment
+	}
+
+	if b.Title != nil {
+		res.Title = b.Title
+	}
+
+	if b.Description != nil {
+		res.Description = b.Description
+	}
+
+	if b.Default != nil {
+		res.Default = b.Default
+	}
+
+	if b.ReadOnly != nil {
+		res.ReadOnly = b.ReadOnly
+	}
+
+	if len(b.Examples) > 0 {
+		res.Examples = b.Examples
+	}
+
+	for k, v := range b.ExtraProperties {
+		res.WithExtraPropertiesItem(k, v)
+	}
+
+	if res.ReflectType == nil {
+		res.ReflectType = b.ReflectType
+	}
+}
+
+func mergeTypes(res *Schema, b Schema, path string) error {
+	if b.Type == nil {
+		return nil
+	}
+
+	if res.Type == nil {
+		res.Type = copyType(b.Type)
+
+		return nil
+	}
+
+	at, bt := typeSet(res.Type), typeSet(b.Type)
+
+	var types []SimpleType
+
+	for _, t := range []SimpleType{Array, Boolean, Integer, Null, Number, Object, String} {
+		switch {
+		case at[t] && bt[t]:
+			types = append(types, t)
+		case t == Integer && (at[Integer] && bt[Number] || at[Number] && bt[Integer]):
+			types = append(types, t)
+		}
+	}
+
+	switch len(types) {
+	case 0:
+		return mergeError(ErrUnsatisfiable, path, "type")
+	case 1:
+		res.Type = &Type{SimpleTypes: &types[0]}
+	default:
+		res.Type = &Type{SliceOfSimpleTypeValues: types}
+	}
+
+	return nil
+}
+
+func mergeEnum(res *Schema, b Schema, path string) error {
+	if b.Const != nil {
+		if res.Const != nil && !jsonEqual(*res.Const, *b.Const) {
+			return mergeError(ErrUnsatisfiable, path, "const")
+		}
+
+		res.Const = b.Const
+	}
+
+	if b.Enum != nil {
+		if res.Enum == nil {
+			res.Enum = b.Enum
+		} else {
+			var enum []interface{}
+
+			for _, v := range res.Enum {
+				for _, bv := range b.Enum {
+					if jsonEqual(v, bv) {
+						enum = append(enum, v)
+
+						break
+					}
+				}
+			}
+
+			if len(enum) == 0 {
+				return mergeError(ErrUnsatisfiable, path, "enum")
+			}
+
+			// Names of enumerated values are not aligned with intersection.
+			if len(enum) != len(res.Enum) {
+				delete(res.ExtraProperties, XEnumNames)
+				delete(res.ExtraProperties, XEnumVarNames)
+				delete(res.ExtraProperties, XEnumDescriptions)
+			}
+
+			res.Enum = enum
+		}
+	}
+
+	if res.Const != nil && res.Enum != nil {
+		found := false
+
+		for _, v := range res.Enum {
+			found = found || jsonEqual(v, *res.Const)
+		}
+
+		if !found {
+			return mergeError(ErrUnsatisfiable, path, "const")
+		}
+	}
+
+	return nil
+}
+
+func mergeBounds(res *Schema, b Schema, path string) error {
+	res.Minimum = maxFloatPtr(res.Minimum, b.Minimum)
+	res.ExclusiveMinimum = maxFloatPtr(res.ExclusiveMinimum, b.ExclusiveMinimum)
+	res.Maximum = minFloatPtr(res.Maximum, b.Maximum)
+	res.ExclusiveMaximum = minFloatPtr(res.ExclusiveMaximum, b.ExclusiveMaximum)
+
+	res.MaxLength = minIntPtr(res.MaxLength, b.MaxLength)
+	res.MaxItems = minIntPtr(res.MaxItems, b.MaxItems)
+	res.MaxProperties = minIntPtr(res.MaxProperties, b.MaxProperties)
+
+	if b.MinLength > res.MinLength {
+		res.MinLength = b.MinLength
+	}
+
+	if b.MinItems > res.MinItems {
+		res.MinItems = b.MinItems
+	}
+
+	if b.MinProperties > res.MinProperties {
+		res.MinProperties = b.MinProperties
+	}
+
+	if b.MultipleOf != nil && res.MultipleOf != nil && *b.MultipleOf != *res.MultipleOf {
+		switch {
+		case isMultiple(*b.MultipleOf, *res.MultipleOf):
+			res.MultipleOf = b.MultipleOf
+		case !isMultiple(*res.MultipleOf, *b.MultipleOf):
+			return mergeError(ErrNotMergeable, path, "multipleOf")
+		}
+	} else if b.MultipleOf != nil {
+		res.MultipleOf = b.MultipleOf
+	}
+
+	if b.UniqueItems != nil && *b.UniqueItems {
+		res.UniqueItems = b.UniqueItems
+	}
+
+	for _, c := range []struct {
+		keyword  string
+		min, max *float64
+	}{
+		{"minimum", res.Minimum, res.Maximum},
+		{"minimum", res.Minimum, res.ExclusiveMaximum},
+		{"exclusiveMinimum", res.ExclusiveMinimum, res.Maximum},
+		{"exclusiveMinimum", res.ExclusiveMinimum, res.ExclusiveMaximum},
+		{"minLength", intPtr(res.MinLength), int64Ptr(res.MaxLength)},
+		{"minItems", intPtr(res.MinItems), int64Ptr(res.MaxItems)},
+		{"minProperties", intPtr(res.MinProperties), int64Ptr(res.MaxProperties)},
+	} {
+		if c.min != nil && c.max != nil && *c.min > *c.max {
+			return mergeError(ErrUnsatisfiable, path, c.keyword)
+		}
+	}
+
+	return nil
+}
+
+func mergeStringKeywords(res *Schema, b Schema, path string) error {
+	for _, k := range []struct {
+		keyword string
+		r       **string
+		b       *string
+	}{
+		{"pattern", &res.Pattern, b.Pattern},
+		{"format", &res.Format, b.Format},
+		{"contentMediaType", &res.ContentMediaType, b.ContentMediaType},
+		{"contentEncoding", &res.ContentEncoding, b.ContentEncoding},
+	} {
+		if k.b == nil {
+			continue
+		}
+
+		if *k.r != nil && **k.r != *k.b {
+			return mergeError(ErrNotMergeable, path, k.keyword)
+		}
+
+		*k.r = k.b
+	}
+
+	return nil
+}
+
+func mergeObjects(res *Schema, b Schema, path string) error {
+	a := *res
+
+	for _, r := range b.Required {
+		res.Required = appendUnique(res.Required, r)
+	}
+
+	if (len(a.PatternProperties) > 0 && b.AdditionalProperties != nil) ||
+		(len(b.PatternProperties) > 0 && a.AdditionalProperties != nil) {
+		return mergeError(ErrNotMergeable, path, "patternProperties")
+	}
+
+	props, err := mergeProperties(a, b, path)
+	if err != nil {
+		return err
+	}
+
+	res.Properties = props
+
+	res.PropertiesOrder = append([]string(nil), a.PropertiesOrder...)
+
+	for _, name := range b.PropertiesOrder {
+		res.PropertiesOrder = appendUnique(res.PropertiesOrder, name)
+	}
+
+	if res.PatternProperties, err = mergeSchemaMaps(a.PatternProperties, b.PatternProperties,
+		path+"/patternProperties", mergeSchemaOrBool); err != nil {
+		return err
+	}
+
+	if res.AdditionalProperties, err = mergeOptional(a.AdditionalProperties, b.AdditionalProperties,
+		path+"/additionalProperties", mergeSchemaOrBool); err != nil {
+		return err
+	}
+
+	if res.PropertyNames, err = mergeOptional(a.PropertyNames, b.PropertyNames,
+		path+"/propertyNames", mergeSchemaOrBool); err != nil {
+		return err
+	}
+
+	if res.Definitions, err = mergeSchemaMaps(a.Definitions, b.Definitions,
+		path+"/definitions", mergeEqual); err != nil {
+		return err
+	}
+
+	for name, d := range b.Dependencies {
+		ad, ok := res.Dependencies[name]
+		if !ok {
+			res.WithDependenciesItem(name, d)
+
+			continue
+		}
+
+		for _, r := range d.StringArray {
+			ad.StringArray = appendUnique(ad.StringArray, r)
+		}
+
+		if ad.SchemaOrBool, err = mergeOptional(ad.SchemaOrBool, d.SchemaOrBool,
+			path+"/dependencies/"+escapePointerToken(name), mergeSchemaOrBool); err != nil {
+			return err
+		}
+
+		res.Dependencies[name] = ad
+	}
+
+	return nil
+}
+
+// mergeProperties merges properties, properties that are missing in one of schemas are merged with
+// its additionalProperties.
+func mergeProperties(a, b Schema, path string) (map[string]SchemaOrBool, error) {
+	if a.Properties == nil && b.Properties == nil {
+		return nil, nil
+	}
+
+	res := make(map[string]SchemaOrBool, len(a.Properties)+len(b.Properties))
+
+	merge := func(name string, p SchemaOrBool, other Schema) error {
+		for pattern := range other.PatternProperties {
+			if re, err := regexp.Compile(pattern); err != nil || re.MatchString(name) {
+				return mergeError(ErrNotMergeable, path, "patternProperties")
+			}
+		}
+
+		if other.AdditionalProperties == nil {
+			res[name] = p
+
+			return nil
+		}
+
+		m, err := mergeSchemaOrBool(p, *other.AdditionalProperties, path+"/properties/"+escapePointerToken(name))
+		res[name] = m
+
+		return err
+	}
+
+	for name, p := range a.Properties {
+		bp, ok := b.Properties[name]
+		if !ok {
+			if err := merge(name, p, b); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		m, err := mergeSchemaOrBool(p, bp, path+"/properties/"+escapePointerToken(name))
+		if err != nil {
+			return nil, err
+		}
+
+		res[name] = m
+	}
+
+	for name, p := range b.Properties {
+		if _, ok := a.Properties[name]; !ok {
+			if err := merge(name, p, a); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return res, nil
+}
+
+func mergeArrays(res *Schema, b Schema, path string) error {
+	a := *res
+
+	var err error
+
+	if b.Items != nil {
+		switch {
+		case a.Items == nil:
+			res.Items = b.Items
+		case a.Items.SchemaOrBool != nil && b.Items.SchemaOrBool != nil:
+			items, err := mergeSchemaOrBool(*a.Items.SchemaOrBool, *b.Items.SchemaOrBool, path+"/items")
+			if err != nil {
+				return err
+			}
+
+			res.Items = &Items{SchemaOrBool: &items}
+		case !schemaListsEqual(a.Items.SchemaArray, b.Items.SchemaArray) || a.Items.SchemaOrBool != nil ||
+			b.Items.SchemaOrBool != nil:
+			return mergeError(ErrNotMergeable, path, "items")
+		}
+	}
+
+	if res.AdditionalItems, err = mergeOptional(a.AdditionalItems, b.AdditionalItems,
+		path+"/additionalItems", mergeEqual); err != nil {
+		return err
+	}
+
+	res.Contains, err = mergeOptional(a.Contains, b.Contains, path+"/contains", mergeEqual)
+
+	return err
+}
+
+func mergeComposition(res *Schema, b Schema, path string) error {
+	a := *res
+
+	res.AllOf = append(append([]SchemaOrBool(nil), a.AllOf...), b.AllOf...)
+
+	for _, l := range []struct {
+		keyword string
+		r       *[]SchemaOrBool
+		b       []SchemaOrBool
+	}{
+		{"anyOf", &res.AnyOf, b.AnyOf},
+		{"oneOf", &res.OneOf, b.OneOf},
+	} {
+		if l.b == nil {
+			continue
+		}
+
+		if *l.r != nil && !schemaListsEqual(*l.r, l.b) {
+			return mergeError(ErrNotMergeable, path, l.keyword)
+		}
+
+		*l.r = l.b
+	}
+
+	var err error
+
+	if res.Not, err = mergeOptional(a.Not, b.Not, path+"/not", mergeEqual); err != nil {
+		return err
+	}
+
+	if b.If == nil && b.Then == nil && b.Else == nil {
+		return nil
+	}
+
+	if a.If == nil && a.Then == nil && a.Else == nil {
+		res.If, res.Then, res.Else = b.If, b.Then, b.Else
+
+		return nil
+	}
+
+	for _, c := range [][2]*SchemaOrBool{{a.If, b.If}, {a.Then, b.Then}, {a.Else, b.Else}} {
+		if (c[0] == nil) != (c[1] == nil) || (c[0] != nil && !schemasEqual(*c[0], *c[1])) {
+			return mergeError(ErrNotMergeable, path, "if")
+		}
+	}
+
+	return nil
+}
+
+func mergeSchemaOrBool(a, b SchemaOrBool, path string) (SchemaOrBool, error) {
+	switch {
+	case a.TypeBoolean != nil && *a.TypeBoolean, b.TypeBoolean != nil && !*b.TypeBoolean:
+		return b, nil
+	case b.TypeBoolean != nil && *b.TypeBoolean, a.TypeBoolean != nil && !*a.TypeBoolean:
+		return a, nil
+	case a.TypeObject == nil:
+		return b, nil
+	case b.TypeObject == nil:
+		return a, nil
+	}
+
+	s, err := mergeSchemas(*a.TypeObject, *b.TypeObject, path)
+
+	return s.ToSchemaOrBool(), err
+}
+
+// mergeEqual merges subschemas that can only be combined if they are equal.
+func mergeEqual(a, b SchemaOrBool, path string) (SchemaOrBool, error) {
+	if !schemasEqual(a, b) {
+		return a, fmt.Errorf("%w: %s", ErrNotMergeable, path)
+	}
+
+	return a, nil
+}
+
+func mergeOptional(a, b *SchemaOrBool, path string,
+	merge func(a, b SchemaOrBool, path string) (SchemaOrBool, error),
+) (*SchemaOrBool, error) {
+	switch {
+	case b == nil:
+		return a, nil
+	case a == nil:
+		return b, nil
+	}
+
+	m, err := merge(*a, *b, path)
+
+	return &m, err
+}
+
+func mergeSchemaMaps(a, b map[string]SchemaOrBool, path string,
+	merge func(a, b SchemaOrBool, path string) (SchemaOrBool, error),
+) (map[string]SchemaOrBool, error) {
+	if a == nil {
+		return b, nil
+	}
+
+	res := a
+
+	for k, bv := range b {
+		av, ok := a[k]
+		if !ok {
+			res[k] = bv
+
+			continue
+		}
+
+		m, err := merge(av, bv, path+"/"+escapePointerToken(k))
+		if err != nil {
+			return nil, err
+		}
+
+		res[k] = m
+	}
+
+	return res, nil
+}
+
+// schemasEqual compares JSON representations of schemas.
+func schemasEqual(a, b SchemaOrBool) bool {
+	aj, err := jsonMarshal(a)
+	if err != nil {
+		return false
+	}
+
+	bj, err := jsonMarshal(b)
+	if err != nil {
+		return false
+	}
+
+	return bytes.Equal(aj, bj)
+}
+
+func schemaListsEqual(a, b []SchemaOrBool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if !schemasEqual(a[i], b[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func maxFloatPtr(a, b *float64) *float64 {
+	if a == nil || (b != nil && *b > *a) {
+		return b
+	}
+
+	return a
+}
+
+func minFloatPtr(a, b *float64) *float64 {
+	if a == nil || (b != nil && *b < *a) {
+		return b
+	}
+
+	return a
+}
+
+func minIntPtr(a, b *int64) *int64 {
+	if a == nil || (b != nil && *b < *a) {
+		return b
+	}
+
+	return a
+}
+
+// isMultiple checks if a is a multiple of b.
+func isMultiple(a, b float64) bool {
+	if b == 0 {
+		return false
+	}
+
+	q := a / b
+
+	return math.Abs(q-math.Round(q)) < 1e-9
+}
@@ -0,0 +1,138 @@
+package jsonschema_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/jsonschema-go"
+)
+
+func parseSchema(t *testing.T, data string) jsonschema.Schema {
+	t.Helper()
+
+	s, err := jsonschema.ParseSchema([]byte(data))
+	require.NoError(t, err)
+
+	return s
+}
+
+func TestMerge(t *testing.T) {
+	base := parseSchema(t, `{
+	  "title":"Base","type":"object","required":["id"],
+	  "properties":{
+		"id":{"type":["integer","string"]},
+		"status":{"type":"string","enum":["new","paid","void"]},
+		"amount":{"type":"number","minimum":0,"maximum":1000},
+		"tags":{"type":"array","items":{"type":"string","maxLength":20}},
+		"legacy":{"type":"string"}
+	  }
+	}`)
+
+	override := parseSchema(t, `{
+	  "title":"Override","required":["status"],
+	  "properties":{
+		"id":{"type":"number"},
+		"status":{"enum":["paid","void","refunded"]},
+		"amount":{"minimum":10,"maximum":5000,"multipleOf":0.5},
+		"tags":{"items":{"maxLength":10,"minLength":1},"uniqueItems":true},
+		"note":{"type":"string"}
+	  },
+	  "additionalProperties":false
+	}`)
+
+	m, err := jsonschema.Merge(base, override)
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "title":"Override","required":["id","status"],
+	  "properties":{
+		"amount":{"maximum":1000,"minimum":10,"multipleOf":0.5,"type":"number"},
+		"id":{"type":"integer"},
+		"legacy":false,
+		"note":{"type":"string"},
+		"status":{"enum":["paid","void"],"type":"string"},
+		"tags":{"items":{"maxLength":10,"minLength":1,"type":"string"},"uniqueItems":true,"type":"array"}
+	  },
+	  "additionalProperties":false,"type":"object"
+	}`, m)
+
+	// Inputs are not changed.
+	assert.Len(t, base.Required, 1)
+	assert.Len(t, override.Properties, 5)
+}
+
+func TestMerge_propertiesOrder(t *testing.T) {
+	base := parseSchema(t, `{"properties":{"b":{},"a":{}}}`)
+	base.PropertiesOrder = make([]string, 0, 4)
+	base.PropertiesOrder = append(base.PropertiesOrder, "b", "a")
+
+	override := parseSchema(t, `{"properties":{"c":{}}}`)
+	override.PropertiesOrder = []string{"c"}
+
+	m, err := jsonschema.Merge(base, override)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"b", "a", "c"}, m.PropertiesOrder)
+	assert.Equal(t, []string{"b", "a"}, base.PropertiesOrder)
+	assert.Equal(t, []string{"b", "a", ""}, base.PropertiesOrder[:3])
+	assertjson.EqMarshal(t, `{"properties":{"b":{},"a":{},"c":{}}}`, m)
+}
+
+func TestMerge_errors(t *testing.T) {
+	for _, tc := range []struct {
+		a, b string
+		err  error
+		msg  string
+	}{
+		{
+			a: `{"type":"string"}`, b: `{"type":"integer"}`,
+			err: jsonschema.ErrUnsatisfiable, msg: "merged constraints are unsatisfiable: /type",
+		},
+		{
+			a: `{"properties":{"n":{"minimum":5}}}`, b: `{"properties":{"n":{"maximum":1}}}`,
+			err: jsonschema.ErrUnsatisfiable, msg: "merged constraints are unsatisfiable: /properties/n/minimum",
+		},
+		{
+			a: `{"enum":["a"]}`, b: `{"enum":["b"]}`,
+			err: jsonschema.ErrUnsatisfiable, msg: "merged constraints are unsatisfiable: /enum",
+		},
+		{
+			a: `{"pattern":"^a"}`, b: `{"pattern":"^b"}`,
+			err: jsonschema.ErrNotMergeable, msg: "schemas can not be merged: /pattern",
+		},
+		{
+			a: `{"$ref":"#/definitions/A"}`, b: `{"type":"object"}`,
+			err: jsonschema.ErrNotMergeable, msg: "schemas can not be merged: /$ref",
+		},
+	} {
+		_, err := jsonschema.Merge(parseSchema(t, tc.a), parseSchema(t, tc.b))
+		assert.True(t, errors.Is(err, tc.err), tc.msg)
+		assert.EqualError(t, err, tc.msg)
+	}
+}
+
+func TestSchema_CollapseAllOf(t *testing.T) {
+	s := parseSchema(t, `{
+	  "description":"Order",
+	  "allOf":[
+		{"$ref":"#/definitions/Base"},
+		{"type":"object","required":["id"],"properties":{"id":{"type":"integer"}}},
+		{"required":["total"],"properties":{"total":{"allOf":[{"minimum":0},{"maximum":100}]}}},
+		true
+	  ],
+	  "definitions":{"Base":{"type":"object"}}
+	}`)
+
+	assertjson.EqMarshal(t, `{
+	  "description":"Order","required":["total","id"],
+	  "definitions":{"Base":{"type":"object"}},
+	  "properties":{"id":{"type":"integer"},"total":{"maximum":100,"minimum":0}},
+	  "type":"object",
+	  "allOf":[{"$ref":"#/definitions/Base"}]
+	}`, s.CollapseAllOf())
+
+	assert.Len(t, s.AllOf, 4)
+}
@@ -0,0 +1,77 @@
+package jsonschema
+
+import (
+	"reflect"
+	"strconv"
+
+	"github.com/swaggest/refl"
+)
+
+// MixinOptions configures mixin registration.
+type MixinOptions struct {
+	// AllOf adds mixin schema to `allOf` of target schema instead of merging mixin properties.
+	AllOf bool
+}
+
+// MixinAllOf adds mixin schema to `allOf` of target schema, see MixinOptions.AllOf.
+func MixinAllOf(o *MixinOptions) {
+	o.AllOf = true
+}
+
+type mixin struct {
+	sample interface{}
+	allOf  bool
+}
+
+// AddMixin adds properties of mixin struct type to schema of target struct type, e.g. for
+//
+//	type Audit struct {
+//		CreatedAt time.Time `json:"createdAt"`
+//		UpdatedBy string    `json:"updatedBy"`
+//	}
+//
+// r.AddMixin(Order{}, Audit{}) makes Order schema have `createdAt` and `updatedBy` properties
+// as if Audit was embedded in Order. Target type is expected to marshal mixin fields itself
+// (e.g. with custom MarshalJSON).
+//
+// With MixinAllOf option, mixin is reflected as a definition and referenced in `allOf` of target schema.
+// Several mixins can be added to the same target, they are applied in order of registration.
+func (r *Reflector) AddMixin(target, mixinSample interface{}, options ...func(o *MixinOptions)) {
+	o := MixinOptions{}
+
+	for _, option := range options {
+		option(&o)
+	}
+
+	if r.mixins == nil {
+		r.mixins = map[reflect.Type][]mixin{}
+	}
+
+	t := refl.DeepIndirect(reflect.TypeOf(target))
+
+	r.mixins[t] = append(r.mixins[t], mixin{sample: mixinSample, allOf: o.AllOf})
+}
+
+// applyMixins adds registered mixins to schema of struct type.
+func (r *Reflector) applyMixins(t reflect.Type, schema *Schema, rc *ReflectContext) error {
+	for _, m := range r.mixins[t] {
+		if !m.allOf {
+			if err := r.walkProperties(reflect.ValueOf(m.sample), schema, rc); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		rc.enterPath("", "", "allOf/"+strconv.Itoa(len(schema.AllOf)))
+
+		s, err := r.reflect(m.sample, rc, false, schema)
+		if err != nil {
+			return err
+		}
+
+		schema.AllOf = append(schema.AllOf, s.ToSchemaOrBool())
+	}
+
+	return nil
+}
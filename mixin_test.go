@@ -0,0 +1,53 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/jsonschema-go"
+)
+
+type mixinAudit struct {
+	CreatedBy string `json:"createdBy" required:"true"`
+	Revision  int    `json:"revision"`
+}
+
+type mixinOrder struct {
+	ID int `json:"id"`
+}
+
+func TestReflector_AddMixin(t *testing.T) {
+	r := jsonschema.Reflector{}
+	r.AddMixin(mixinOrder{}, mixinAudit{})
+
+	s, err := r.Reflect(mixinOrder{})
+	require.NoError(t, err)
+	assertjson.EqMarshal(t, `{
+	  "required":["createdBy"],
+	  "properties":{
+		"createdBy":{"type":"string"},"id":{"type":"integer"},"revision":{"type":"integer"}
+	  },
+	  "type":"object"
+	}`, s)
+
+	r = jsonschema.Reflector{}
+	r.AddMixin(&mixinOrder{}, mixinAudit{}, jsonschema.MixinAllOf)
+
+	s, err = r.Reflect([]mixinOrder{}, jsonschema.StripDefinitionNamePrefix("JsonschemaGoTest"))
+	require.NoError(t, err)
+	assertjson.EqMarshal(t, `{
+	  "items":{"$ref":"#/definitions/MixinOrder"},"type":"array",
+	  "definitions":{
+		"MixinAudit":{
+		  "required":["createdBy"],
+		  "properties":{"createdBy":{"type":"string"},"revision":{"type":"integer"}},
+		  "type":"object"
+		},
+		"MixinOrder":{
+		  "allOf":[{"$ref":"#/definitions/MixinAudit"}],
+		  "properties":{"id":{"type":"integer"}},"type":"object"
+		}
+	  }
+	}`, s)
+}
@@ -0,0 +1,107 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// XNamedExamples is the name of JSON property to store named examples.
+const XNamedExamples = "x-examples"
+
+// NamedExample is an example value with a name and summary, as in OpenAPI Example Object.
+type NamedExample struct {
+	Summary     string      `json:"summary,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Value       interface{} `json:"value"`
+}
+
+// WithNamedExample adds example value to `examples` and keeps its name and summary in XNamedExamples keyword.
+//
+// Reflect only keeps XNamedExamples with KeepNamedExamples option, so that pure JSON Schema
+// has plain `examples` and OpenAPI documents can have `examples` map of media type.
+func (s *Schema) WithNamedExample(name string, example NamedExample) *Schema {
+	s.Examples = append(s.Examples, example.Value)
+
+	named := s.NamedExamples()
+	if named == nil {
+		named = map[string]NamedExample{}
+	}
+
+	named[name] = example
+
+	return s.WithExtraPropertiesItem(XNamedExamples, named)
+}
+
+// NamedExamples returns examples from XNamedExamples keyword, e.g. to use them in OpenAPI media type.
+func (s Schema) NamedExamples() map[string]NamedExample {
+	v, ok := s.ExtraProperties[XNamedExamples]
+	if !ok {
+		return nil
+	}
+
+	if named, ok := v.(map[string]NamedExample); ok {
+		res := make(map[string]NamedExample, len(named))
+		for k, e := range named {
+			res[k] = e
+		}
+
+		return res
+	}
+
+	// Value of unmarshaled schema.
+	j, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+
+	var res map[string]NamedExample
+	if err := json.Unmarshal(j, &res); err != nil {
+		return nil
+	}
+
+	return res
+}
+
+// KeepNamedExamples keeps XNamedExamples keyword with names and summaries of examples, e.g. for OpenAPI.
+func KeepNamedExamples(rc *ReflectContext) {
+	rc.KeepNamedExamples = true
+}
+
+// reflectNamedExamples reads `namedExamples` field tag with JSON object of NamedExample by names.
+func reflectNamedExamples(propertySchema *Schema, field reflect.StructField) error {
+	value, ok := field.Tag.Lookup("namedExamples")
+	if !ok {
+		return nil
+	}
+
+	var named map[string]NamedExample
+	if err := json.Unmarshal([]byte(value), &named); err != nil {
+		return fmt.Errorf("failed to parse namedExamples in field %s: %w", field.Name, err)
+	}
+
+	names := make([]string, 0, len(named))
+	for name := range named {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		propertySchema.WithNamedExample(name, named[name])
+	}
+
+	return nil
+}
+
+// dropNamedExamples removes XNamedExamples keyword from schema and its subschemas.
+func dropNamedExamples(s *Schema) {
+	walkSchemas(s, func(s *Schema) {
+		delete(s.ExtraProperties, XNamedExamples)
+
+		if len(s.ExtraProperties) == 0 {
+			s.ExtraProperties = nil
+		}
+	})
+}
@@ -0,0 +1,105 @@
+package jsonschema
+
+// OpenAPI30 sets up reflection of OpenAPI 3.0 Schema Objects, see DraftVersionOpenAPI30.
+func OpenAPI30(rc *ReflectContext) {
+	TargetDraft(DraftVersionOpenAPI30)(rc)
+}
+
+// OpenAPI31 sets up reflection of OpenAPI 3.1 Schema Objects, which are JSON Schema draft 2020-12
+// with definitions in "#/components/schemas/" if they are collected, see DraftVersionOpenAPI30.
+func OpenAPI31(rc *ReflectContext) {
+	TargetDraft(DraftVersion2020)(rc)
+
+	rc.componentsRefs = true
+}
+
+// toOpenAPI30 converts schema and its subschemas to OpenAPI 3.0 Schema Objects in place.
+func toOpenAPI30(schema *Schema) {
+	var all []*Schema
+
+	walkSchemas(schema, func(s *Schema) {
+		all = append(all, s)
+	})
+
+	for _, s := range all {
+		openAPI30Keywords(s)
+	}
+}
+
+func openAPI30Keywords(s *Schema) {
+	openAPI30Type(s)
+	exclusiveBoundsToDraft04(s)
+
+	if s.Const != nil {
+		s.Enum = []interface{}{*s.Const}
+		s.Const = nil
+	}
+
+	if len(s.Examples) > 0 {
+		s.WithExtraPropertiesItem("example", s.Examples[0])
+		s.Examples = nil
+	}
+
+	if s.Items != nil && s.Items.SchemaArray != nil {
+		s.Items = nil
+	}
+
+	// Keywords that are not supported by OpenAPI 3.0.
+	s.Schema = nil
+	s.ID = nil
+	s.Comment = nil
+	s.AdditionalItems = nil
+	s.Contains = nil
+	s.PatternProperties = nil
+	s.Dependencies = nil
+	s.PropertyNames = nil
+	s.If = nil
+	s.Then = nil
+	s.Else = nil
+	s.ContentMediaType = nil
+	s.ContentEncoding = nil
+}
+
+// openAPI30Type replaces `null` type with `nullable: true` and multiple types with `anyOf`.
+func openAPI30Type(s *Schema) {
+	if s.Type == nil {
+		return
+	}
+
+	tt := s.Type.SliceOfSimpleTypeValues
+	if s.Type.SimpleTypes != nil {
+		tt = []SimpleType{*s.Type.SimpleTypes}
+	}
+
+	var types []SimpleType
+
+	for _, t := range tt {
+		if t == Null {
+			s.WithExtraPropertiesItem("nullable", true)
+		} else {
+			types = append(types, t)
+		}
+	}
+
+	s.Type = nil
+
+	switch len(types) {
+	case 0:
+		if len(tt) > 0 {
+			s.Enum = []interface{}{nil}
+		}
+	case 1:
+		s.WithType(types[0].Type())
+	default:
+		anyOf := make([]SchemaOrBool, 0, len(types))
+		for _, t := range types {
+			anyOf = append(anyOf, (&Schema{}).WithType(t.Type()).ToSchemaOrBool())
+		}
+
+		if len(s.AnyOf) == 0 {
+			s.AnyOf = anyOf
+		} else {
+			s.AllOf = append(s.AllOf, (&Schema{AnyOf: anyOf}).ToSchemaOrBool())
+		}
+	}
+}
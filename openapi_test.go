@@ -0,0 +1,76 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/jsonschema-go"
+)
+
+func TestOpenAPI30(t *testing.T) {
+	type Customer struct {
+		Name string `json:"name"`
+	}
+
+	type Order struct {
+		ID       int       `json:"id" exclusiveMinimum:"0" examples:"[1,2]"`
+		Note     *string   `json:"note"`
+		Tags     []string  `json:"tags"`
+		Kind     string    `json:"kind" const:"order"`
+		Value    string    `json:"value" types:"string,integer"`
+		Customer *Customer `json:"customer"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	var defs []string
+
+	s, err := r.Reflect(Order{}, jsonschema.OpenAPI30, jsonschema.CollectDefinitions(func(name string, schema jsonschema.Schema) {
+		defs = append(defs, name)
+	}))
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "properties":{
+		"customer":{"$ref":"#/components/schemas/JsonschemaGoTestCustomer"},
+		"id":{"minimum":0,"exclusiveMinimum":true,"type":"integer","example":1},
+		"kind":{"enum":["order"],"type":"string"},
+		"note":{"type":"string","nullable":true},
+		"tags":{"items":{"type":"string"},"type":"array","nullable":true},
+		"value":{"anyOf":[{"type":"string"},{"type":"integer"}]}
+	  },
+	  "type":"object"
+	}`, s)
+
+	require.Equal(t, []string{"JsonschemaGoTestCustomer"}, defs)
+
+	s, err = r.Reflect(Order{}, jsonschema.OpenAPI31, jsonschema.CollectDefinitions(func(string, jsonschema.Schema) {}))
+	require.NoError(t, err)
+
+	require.Equal(t, "#/components/schemas/JsonschemaGoTestCustomer", *s.Properties["customer"].TypeObject.Ref)
+	require.Equal(t, jsonschema.Draft2020Schema, *s.Schema)
+}
+
+func TestOpenAPI30_embeddedDefinitions(t *testing.T) {
+	type Customer struct {
+		Name string `json:"name"`
+	}
+
+	type Order struct {
+		Customer *Customer  `json:"customer"`
+		Previous []Customer `json:"previous"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	for _, o := range []func(rc *jsonschema.ReflectContext){jsonschema.OpenAPI30, jsonschema.OpenAPI31} {
+		s, err := r.Reflect(Order{}, o)
+		require.NoError(t, err)
+
+		// Definitions are not collected, so references point to definitions in reflected schema.
+		assert.Empty(t, jsonschema.NewDocument(s).CheckRefs())
+		assert.NotContains(t, *s.Properties["customer"].TypeObject.Ref, "components")
+	}
+}
@@ -0,0 +1,101 @@
+package jsonschema
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/swaggest/refl"
+)
+
+// RegisterOptional makes schema of an optional wrapper type a nullable schema of its value field,
+// as if the field was a pointer, e.g. for
+//
+//	type Opt[T any] struct {
+//		Valid bool
+//		Value T
+//	}
+//
+//	r.RegisterOptional(Opt[int]{}, "Value")
+//
+// a field of Opt[int] type is reflected as `{"type":["null","integer"]}`. Registration applies to all instances
+// of a generic type, e.g. to Opt[string] too. Wrapper is expected to marshal to JSON as null or as its value.
+func (r *Reflector) RegisterOptional(sample interface{}, valueField string) error {
+	t := refl.DeepIndirect(reflect.TypeOf(sample))
+	if t == nil {
+		return errors.New("nil sample")
+	}
+
+	if t.Kind() != reflect.Struct {
+		return fmt.Errorf("%s: struct expected", t)
+	}
+
+	if _, ok := t.FieldByName(valueField); !ok {
+		return fmt.Errorf("%s: field %s not found", t, valueField)
+	}
+
+	if r.optionals == nil {
+		r.optionals = map[string]string{}
+	}
+
+	r.optionals[optionalTypeKey(t)] = valueField
+
+	return nil
+}
+
+// optionalTypeKey returns full name of a type without type arguments.
+func optionalTypeKey(t reflect.Type) string {
+	name := t.Name()
+	if i := strings.Index(name, "["); i >= 0 {
+		name = name[:i]
+	}
+
+	return t.PkgPath() + "." + name
+}
+
+// optionalField returns value field of registered optional wrapper type.
+func (r *Reflector) optionalField(t reflect.Type) (reflect.StructField, bool) {
+	if len(r.optionals) == 0 || t == nil {
+		return reflect.StructField{}, false
+	}
+
+	t = refl.DeepIndirect(t)
+	if t.Kind() != reflect.Struct || t.Name() == "" {
+		return reflect.StructField{}, false
+	}
+
+	fieldName, ok := r.optionals[optionalTypeKey(t)]
+	if !ok {
+		return reflect.StructField{}, false
+	}
+
+	return t.FieldByName(fieldName)
+}
+
+// optionalValue returns a pointer to value of registered optional wrapper, or false if type is not registered.
+//
+// Pointer is nil for nil wrapper.
+func (r *Reflector) optionalValue(t reflect.Type, v reflect.Value) (reflect.Value, bool) {
+	field, ok := r.optionalField(t)
+	if !ok {
+		return reflect.Value{}, false
+	}
+
+	for v.IsValid() && v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return reflect.Zero(reflect.PtrTo(field.Type)), true
+		}
+
+		v = v.Elem()
+	}
+
+	if !v.IsValid() || v.Type() != refl.DeepIndirect(t) {
+		return reflect.Zero(reflect.PtrTo(field.Type)), true
+	}
+
+	p := reflect.New(field.Type)
+	p.Elem().Set(v.FieldByIndex(field.Index))
+
+	return p, true
+}
@@ -0,0 +1,247 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"github.com/swaggest/refl"
+)
+
+// definitionTypes returns type strings of definitions in configured order.
+func (rc *ReflectContext) definitionTypes() []refl.TypeString {
+	res := make([]refl.TypeString, 0, len(rc.definitions))
+
+	if rc.KeywordsOrder == 0 {
+		for typeString := range rc.definitions {
+			res = append(res, typeString)
+		}
+
+		return res
+	}
+
+	for _, typeString := range rc.definitionsOrder {
+		if _, ok := rc.definitions[typeString]; ok {
+			res = append(res, typeString)
+		}
+	}
+
+	if rc.KeywordsOrder == OrderAlphabetical {
+		sort.SliceStable(res, func(i, j int) bool {
+			return rc.definitionRefs[res[i]].Name < rc.definitionRefs[res[j]].Name
+		})
+	}
+
+	return res
+}
+
+// orderKeywords applies KeywordsOrder to schema and definitions.
+func (rc *ReflectContext) orderKeywords(schema *Schema) {
+	visit := func(s *Schema) {
+		s.Required = uniqueStrings(s.Required)
+
+		if rc.KeywordsOrder == OrderAlphabetical {
+			sort.Strings(s.Required)
+			sortEnum(s)
+		}
+	}
+
+	walkSchemas(schema, visit)
+
+	for _, def := range rc.definitions {
+		walkSchemas(def, visit)
+	}
+}
+
+// orderProperties applies PropertiesOrder to schema and definitions.
+func (rc *ReflectContext) orderProperties(schema *Schema) {
+	visit := func(s *Schema) {
+		if len(s.Properties) == 0 {
+			return
+		}
+
+		order := orderedPropertyNames(s.Properties, s.PropertiesOrder)
+
+		switch rc.PropertiesOrder {
+		case PropertiesAlphabetical:
+			sort.Strings(order)
+		case PropertiesRequiredFirst:
+			sort.SliceStable(order, func(i, j int) bool {
+				return hasString(s.Required, order[i]) && !hasString(s.Required, order[j])
+			})
+		case PropertiesDeclared:
+		}
+
+		s.PropertiesOrder = order
+	}
+
+	walkSchemas(schema, visit)
+
+	for _, def := range rc.definitions {
+		walkSchemas(def, visit)
+	}
+}
+
+// marshalOrderedProperties marshals schema fields with properties in order of Schema.PropertiesOrder.
+func marshalOrderedProperties(s marshalSchema) ([]byte, error) {
+	props := s.Properties
+	order := s.PropertiesOrder
+
+	s.Properties = nil
+	s.PropertiesOrder = nil
+
+	head, err := jsonCodec.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+
+	body := []byte("{")
+
+	for i, name := range orderedPropertyNames(props, order) {
+		k, err := jsonMarshal(name)
+		if err != nil {
+			return nil, err
+		}
+
+		v, err := jsonMarshal(props[name])
+		if err != nil {
+			return nil, err
+		}
+
+		if i > 0 {
+			body = append(body, ',')
+		}
+
+		body = append(body, k...)
+		body = append(body, ':')
+		body = append(body, v...)
+	}
+
+	body = append(body, '}')
+
+	return marshalUnion(json.RawMessage(head), map[string]json.RawMessage{"properties": body})
+}
+
+// orderedPropertyNames returns names of properties in given order followed by other names in alphabetical order.
+func orderedPropertyNames(props map[string]SchemaOrBool, order []string) []string {
+	names := make([]string, 0, len(props))
+
+	for _, name := range order {
+		if _, ok := props[name]; ok {
+			names = appendUnique(names, name)
+		}
+	}
+
+	rest := make([]string, 0, len(props)-len(names))
+
+	for name := range props {
+		if !hasString(names, name) {
+			rest = append(rest, name)
+		}
+	}
+
+	sort.Strings(rest)
+
+	return append(names, rest...)
+}
+
+func hasString(items []string, item string) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+
+	return false
+}
+
+func appendUnique(items []string, item string) []string {
+	if hasString(items, item) {
+		return items
+	}
+
+	return append(items[:len(items):len(items)], item)
+}
+
+func uniqueStrings(items []string) []string {
+	if len(items) < 2 {
+		return items
+	}
+
+	seen := make(map[string]bool, len(items))
+	res := make([]string, 0, len(items))
+
+	for _, item := range items {
+		if seen[item] {
+			continue
+		}
+
+		seen[item] = true
+
+		res = append(res, item)
+	}
+
+	return res
+}
+
+// enumValueKeywords are extension keywords with an item for every enum value, e.g. names of values.
+var enumValueKeywords = []string{XEnumNames, XEnumVarNames, XEnumDescriptions}
+
+// sortEnum sorts enum values by JSON representation, lists of enumValueKeywords are reordered accordingly.
+func sortEnum(s *Schema) {
+	if len(s.Enum) < 2 {
+		return
+	}
+
+	lists := make(map[string]reflect.Value, len(enumValueKeywords))
+
+	for _, k := range enumValueKeywords {
+		v, ok := s.ExtraProperties[k]
+		if !ok {
+			continue
+		}
+
+		l := reflect.ValueOf(v)
+		if l.Kind() != reflect.Slice || l.Len() != len(s.Enum) {
+			// Items can not be matched to values.
+			return
+		}
+
+		lists[k] = l
+	}
+
+	keys := make([]string, len(s.Enum))
+	idx := make([]int, len(s.Enum))
+
+	for i, v := range s.Enum {
+		j, err := jsonMarshal(v)
+		if err != nil {
+			return
+		}
+
+		keys[i] = string(j)
+		idx[i] = i
+	}
+
+	sort.SliceStable(idx, func(i, j int) bool {
+		return keys[idx[i]] < keys[idx[j]]
+	})
+
+	enum := make([]interface{}, len(idx))
+
+	for i, k := range idx {
+		enum[i] = s.Enum[k]
+	}
+
+	s.Enum = enum
+
+	for name, l := range lists {
+		sorted := reflect.MakeSlice(l.Type(), len(idx), len(idx))
+
+		for i, k := range idx {
+			sorted.Index(i).Set(l.Index(k))
+		}
+
+		s.ExtraProperties[name] = sorted.Interface()
+	}
+}
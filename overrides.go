@@ -0,0 +1,197 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/swaggest/refl"
+)
+
+// Overrides customize reflection of types that can not be annotated with field tags, e.g. vendored or
+// third-party types, keyed by full type name, e.g. "github.com/acme/vendor.Config".
+//
+// Schemas of such types are reflected from `json` tags and Go types, overrides add naming, descriptions
+// and constraints, see ApplyOverrides.
+type Overrides map[string]TypeOverride
+
+// TypeOverride describes a type.
+type TypeOverride struct {
+	// Name replaces definition name.
+	Name string `json:"name,omitempty"`
+
+	// Title sets `title` of type schema.
+	Title string `json:"title,omitempty"`
+
+	// Description sets `description` of type schema.
+	Description string `json:"description,omitempty"`
+
+	// Schema contains keywords that are set in type schema, e.g. {"additionalProperties":false}.
+	Schema map[string]interface{} `json:"schema,omitempty"`
+
+	// Fields are keyed by Go name of struct field.
+	Fields map[string]FieldOverride `json:"fields,omitempty"`
+}
+
+// FieldOverride describes a struct field.
+type FieldOverride struct {
+	// Name replaces property name.
+	Name string `json:"name,omitempty"`
+
+	// Description sets `description` of property.
+	Description string `json:"description,omitempty"`
+
+	// Required marks property as required or optional.
+	Required *bool `json:"required,omitempty"`
+
+	// Skip excludes property from schema.
+	Skip bool `json:"skip,omitempty"`
+
+	// Tags are applied as field tags, e.g. {"minimum":"1","format":"email"}, replacing tags of field.
+	Tags map[string]string `json:"tags,omitempty"`
+}
+
+// LoadOverrides reads JSON file with Overrides, unknown keys are reported as errors to catch misspellings.
+func LoadOverrides(path string) (Overrides, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // Path is provided by user.
+	if err != nil {
+		return nil, err
+	}
+
+	var o Overrides
+
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.DisallowUnknownFields()
+
+	if err := d.Decode(&o); err != nil {
+		return nil, fmt.Errorf("failed to decode overrides %s: %w", path, err)
+	}
+
+	return o, nil
+}
+
+// ApplyOverrides enables customization of types and fields with Overrides.
+//
+// Field overrides are applied as field tags, so that they have same effect as tags in sources,
+// e.g. `"tags":{"enum":"a,b"}` is equivalent to `enum:"a,b"`.
+func ApplyOverrides(o Overrides) func(*ReflectContext) {
+	return func(rc *ReflectContext) {
+		rc.Overrides = o
+
+		InterceptDefName(func(t reflect.Type, defaultDefName string) string {
+			if to, ok := o[overrideTypeKey(t)]; ok && to.Name != "" {
+				return to.Name
+			}
+
+			return defaultDefName
+		})(rc)
+
+		InterceptSchema(func(params InterceptSchemaParams) (bool, error) {
+			if !params.Processed || !params.Value.IsValid() {
+				return false, nil
+			}
+
+			to, ok := o[overrideTypeKey(params.Value.Type())]
+			if !ok {
+				return false, nil
+			}
+
+			return false, to.apply(params.Schema)
+		})(rc)
+	}
+}
+
+func (to TypeOverride) apply(s *Schema) error {
+	if len(to.Schema) > 0 {
+		j, err := json.Marshal(to.Schema)
+		if err != nil {
+			return err
+		}
+
+		if err := s.UnmarshalJSON(j); err != nil {
+			return fmt.Errorf("failed to apply schema override: %w", err)
+		}
+	}
+
+	if to.Title != "" {
+		s.WithTitle(to.Title)
+	}
+
+	if to.Description != "" {
+		s.WithDescription(to.Description)
+	}
+
+	return nil
+}
+
+// overrideTypeKey returns full name of named type, e.g. "github.com/acme/vendor.Config".
+func overrideTypeKey(t reflect.Type) string {
+	t = refl.DeepIndirect(t)
+
+	if t.Name() == "" {
+		return ""
+	}
+
+	return t.PkgPath() + "." + t.Name()
+}
+
+// overrideField returns field with tags of FieldOverride.
+func (rc *ReflectContext) overrideField(owner reflect.Type, field reflect.StructField) reflect.StructField {
+	fo, ok := rc.Overrides[overrideTypeKey(owner)].Fields[field.Name]
+	if !ok {
+		return field
+	}
+
+	tags := make(map[string]string, len(fo.Tags)+3)
+	for k, v := range fo.Tags {
+		tags[k] = v
+	}
+
+	if fo.Name != "" {
+		name := fo.Name
+
+		// Tag options, e.g. omitempty, are kept.
+		if tag, ok := field.Tag.Lookup(rc.PropertyNameTag); ok {
+			if i := strings.Index(tag, ","); i >= 0 {
+				name += tag[i:]
+			}
+		}
+
+		tags[rc.PropertyNameTag] = name
+	}
+
+	if fo.Skip {
+		tags[rc.PropertyNameTag] = "-"
+	}
+
+	if fo.Description != "" {
+		tags["description"] = fo.Description
+	}
+
+	if fo.Required != nil {
+		tags["required"] = strconv.FormatBool(*fo.Required)
+	}
+
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	tag := field.Tag
+
+	for _, k := range keys {
+		tag = tagWithout(tag, k)
+		tag = reflect.StructTag(strings.TrimSpace(string(tag) + " " + k + ":" + strconv.Quote(tags[k])))
+	}
+
+	field.Tag = tag
+
+	return field
+}
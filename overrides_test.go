@@ -0,0 +1,90 @@
+package jsonschema_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/jsonschema-go"
+)
+
+// vendorConfig imitates a third-party type that can not be annotated.
+type vendorConfig struct {
+	Endpoint string         `json:"endpoint"`
+	Timeout  int            `json:"timeout,omitempty"`
+	Secret   string         `json:"secret"`
+	Retry    vendorRetry    `json:"retry"`
+	Extra    map[string]int `json:"extra"`
+}
+
+type vendorRetry struct {
+	Attempts int `json:"attempts"`
+}
+
+type appConfig struct {
+	Vendor vendorConfig `json:"vendor"`
+}
+
+func TestApplyOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "overrides.json")
+
+	require.NoError(t, os.WriteFile(path, []byte(`{
+	  "github.com/swaggest/jsonschema-go_test.vendorConfig": {
+		"name": "VendorConfig",
+		"description": "Vendor client configuration.",
+		"schema": {"additionalProperties": false},
+		"fields": {
+		  "Endpoint": {"description": "Service URL.", "required": true, "tags": {"format": "uri"}},
+		  "Timeout": {"name": "timeout_ms", "tags": {"minimum": "0", "default": "1000"}},
+		  "Secret": {"skip": true}
+		}
+	  },
+	  "github.com/swaggest/jsonschema-go_test.vendorRetry": {
+		"title": "Retry policy",
+		"fields": {"Attempts": {"tags": {"maximum": "5"}}}
+	  }
+	}`), 0o600))
+
+	o, err := jsonschema.LoadOverrides(path)
+	require.NoError(t, err)
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(appConfig{}, jsonschema.ApplyOverrides(o))
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "definitions":{
+		"JsonschemaGoTestVendorRetry":{
+		  "title":"Retry policy",
+		  "properties":{"attempts":{"maximum":5,"type":"integer"}},
+		  "type":"object"
+		},
+		"VendorConfig":{
+		  "description":"Vendor client configuration.","required":["endpoint"],
+		  "additionalProperties":false,
+		  "properties":{
+			"endpoint":{"description":"Service URL.","type":"string","format":"uri"},
+			"extra":{"additionalProperties":{"type":"integer"},"type":["object","null"]},
+			"retry":{"$ref":"#/definitions/JsonschemaGoTestVendorRetry"},
+			"timeout_ms":{"default":1000,"minimum":0,"type":"integer"}
+		  },
+		  "type":"object"
+		}
+	  },
+	  "properties":{"vendor":{"$ref":"#/definitions/VendorConfig"}},
+	  "type":"object"
+	}`, s)
+}
+
+func TestLoadOverrides_unknownKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "overrides.json")
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"a.B":{"fields":{"C":{"descripton":"typo"}}}}`), 0o600))
+
+	_, err := jsonschema.LoadOverrides(path)
+	assert.ErrorContains(t, err, `unknown field "descripton"`)
+}
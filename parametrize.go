@@ -0,0 +1,52 @@
+package jsonschema
+
+import (
+	"strings"
+)
+
+// ParametrizeRef instantiates a template schema by rewriting references, e.g. a page container with
+// `{"items":{"type":"array","items":{"$ref":"#/definitions/{T}"}}}` can be instantiated for many item types
+// without reflecting every Page[X].
+//
+// Placeholder key matches a whole `$ref` value (e.g. "#/definitions/Item") or a `{key}` token in reference
+// and definition names (e.g. "{T}" in "#/definitions/{T}" or "Page{T}"). Template is not modified.
+func ParametrizeRef(template Schema, placeholders map[string]string) Schema {
+	pairs := make([]string, 0, 2*len(placeholders))
+	escaped := make([]string, 0, 2*len(placeholders))
+
+	for k, v := range placeholders {
+		pairs = append(pairs, "{"+k+"}", v)
+
+		// Substituted reference tokens are escaped as JSON Pointer.
+		escaped = append(escaped, "{"+k+"}", escapePointerToken(v))
+	}
+
+	tokens := strings.NewReplacer(pairs...)
+	refTokens := strings.NewReplacer(escaped...)
+
+	res := cloneSchema(template)
+
+	walkSchemas(&res, func(s *Schema) {
+		if s.Ref != nil {
+			ref, ok := placeholders[*s.Ref]
+			if !ok {
+				ref = refTokens.Replace(*s.Ref)
+			}
+
+			s.Ref = &ref
+		}
+
+		if len(s.Definitions) == 0 {
+			return
+		}
+
+		defs := make(map[string]SchemaOrBool, len(s.Definitions))
+		for name, d := range s.Definitions {
+			defs[tokens.Replace(name)] = d
+		}
+
+		s.Definitions = defs
+	})
+
+	return res
+}
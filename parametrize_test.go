@@ -0,0 +1,48 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/jsonschema-go"
+)
+
+func TestParametrizeRef(t *testing.T) {
+	var page jsonschema.Schema
+
+	require.NoError(t, page.UnmarshalJSON([]byte(`{
+	  "$ref":"#/definitions/Page{T}",
+	  "definitions":{
+		"Page{T}":{
+		  "type":"object",
+		  "properties":{
+			"items":{"type":"array","items":{"$ref":"#/definitions/{T}"}},
+			"cursor":{"$ref":"#/definitions/Cursor"}
+		  }
+		}
+	  }
+	}`)))
+
+	s := jsonschema.ParametrizeRef(page, map[string]string{
+		"T":                    "Order",
+		"#/definitions/Cursor": "#/components/schemas/Cursor",
+	})
+
+	assertjson.EqMarshal(t, `{
+	  "$ref":"#/definitions/PageOrder",
+	  "definitions":{
+		"PageOrder":{
+		  "properties":{
+			"cursor":{"$ref":"#/components/schemas/Cursor"},
+			"items":{"items":{"$ref":"#/definitions/Order"},"type":"array"}
+		  },
+		  "type":"object"
+		}
+	  }
+	}`, s)
+
+	// Template is not modified.
+	assertjson.EqMarshal(t, `{"$ref":"#/definitions/{T}"}`,
+		page.Definitions["Page{T}"].TypeObject.Properties["items"].TypeObject.Items.SchemaOrBool)
+}
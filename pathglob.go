@@ -0,0 +1,122 @@
+package jsonschema
+
+import (
+	"reflect"
+	"strings"
+)
+
+// ExcludePaths drops properties with instance paths that match any of JSON Pointer patterns, e.g. "/internal/*".
+//
+// Pattern token "*" matches any single token (including array index and map key), "**" matches any number of tokens,
+// for example "**/debug" drops "debug" property at any depth. Definitions are reflected once, so patterns
+// are checked against the path where a type is met first, use InlineRefs for path-specific filtering of shared types.
+func ExcludePaths(globs ...string) func(rc *ReflectContext) {
+	patterns := compileGlobs(globs)
+
+	return func(rc *ReflectContext) {
+		FieldEnabled(func(_ reflect.StructField, path []string) bool {
+			ip := rc.instanceTokens(path)
+
+			for _, p := range patterns {
+				if globMatchAncestor(p, ip) {
+					return false
+				}
+			}
+
+			return true
+		})(rc)
+	}
+}
+
+// IncludePaths keeps only properties with instance paths that match any of JSON Pointer patterns,
+// their ancestors and descendants, see ExcludePaths for pattern syntax.
+func IncludePaths(globs ...string) func(rc *ReflectContext) {
+	patterns := compileGlobs(globs)
+
+	return func(rc *ReflectContext) {
+		FieldEnabled(func(_ reflect.StructField, path []string) bool {
+			ip := rc.instanceTokens(path)
+
+			for _, p := range patterns {
+				if globMatchPrefix(p, ip) || globMatchAncestor(p, ip) {
+					return true
+				}
+			}
+
+			return false
+		})(rc)
+	}
+}
+
+func compileGlobs(globs []string) [][]string {
+	res := make([][]string, 0, len(globs))
+
+	for _, g := range globs {
+		g = strings.TrimPrefix(strings.TrimPrefix(g, "#"), "/")
+
+		var tokens []string
+
+		if g != "" {
+			tokens = strings.Split(g, "/")
+		}
+
+		res = append(res, tokens)
+	}
+
+	return res
+}
+
+// globMatch checks if pattern matches whole path.
+func globMatch(pattern, path []string) bool {
+	if len(pattern) == 0 {
+		return len(path) == 0
+	}
+
+	if pattern[0] == "**" {
+		for i := 0; i <= len(path); i++ {
+			if globMatch(pattern[1:], path[i:]) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if len(path) == 0 || (pattern[0] != "*" && pattern[0] != path[0]) {
+		return false
+	}
+
+	return globMatch(pattern[1:], path[1:])
+}
+
+// globMatchPrefix checks if path can be extended to match pattern.
+func globMatchPrefix(pattern, path []string) bool {
+	if len(path) == 0 {
+		return true
+	}
+
+	if len(pattern) == 0 {
+		return false
+	}
+
+	if pattern[0] == "**" {
+		return true
+	}
+
+	if pattern[0] != "*" && pattern[0] != path[0] {
+		return false
+	}
+
+	return globMatchPrefix(pattern[1:], path[1:])
+}
+
+// globMatchAncestor checks if pattern matches path or any of its ancestors.
+func globMatchAncestor(pattern, path []string) bool {
+	for i := 1; i <= len(path); i++ {
+		if globMatch(pattern, path[:i]) {
+			return true
+		}
+	}
+
+	return false
+}
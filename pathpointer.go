@@ -0,0 +1,109 @@
+package jsonschema
+
+import "strings"
+
+// pathPointer holds JSON Pointer tokens of a reflection path element.
+type pathPointer struct {
+	token    string // element of ReflectContext.Path
+	instance string // escaped instance document token, empty if element does not change instance location
+	schema   string // escaped schema document tokens
+}
+
+// enterPath appends token to rc.Path together with its instance and schema pointer tokens.
+func (rc *ReflectContext) enterPath(token, instance, schema string) {
+	rc.Path = append(rc.Path, token)
+
+	idx := len(rc.Path) - 1
+	for len(rc.pathPointers) < idx {
+		rc.pathPointers = append(rc.pathPointers, rc.pointer(len(rc.pathPointers)))
+	}
+
+	rc.pathPointers = append(rc.pathPointers[:idx], pathPointer{token: token, instance: instance, schema: schema})
+}
+
+// pointer returns pointer tokens of i-th element of rc.Path.
+//
+// Elements that were added to Path directly are treated as property names unless they are
+// array item ("[]") or map value ("{}") markers.
+func (rc *ReflectContext) pointer(i int) pathPointer {
+	token := rc.Path[i]
+
+	if i < len(rc.pathPointers) && rc.pathPointers[i].token == token {
+		return rc.pathPointers[i]
+	}
+
+	return defaultPathPointer(token)
+}
+
+// defaultPathPointer returns pointer tokens of a path element that was not added with enterPath.
+func defaultPathPointer(token string) pathPointer {
+	switch token {
+	case "#", "":
+		return pathPointer{token: token}
+	case "[]":
+		return pathPointer{token: token, instance: "*", schema: "items"}
+	case "{}":
+		return pathPointer{token: token, instance: "*", schema: "additionalProperties"}
+	default:
+		return pathPointer{
+			token:    token,
+			instance: escapePointerToken(token),
+			schema:   "properties/" + escapePointerToken(token),
+		}
+	}
+}
+
+// InstancePointer returns JSON Pointer of current reflection location in instance document,
+// array items and map values are denoted with "*", e.g. "/orders/*/id".
+//
+// Composition keywords (allOf, anyOf, oneOf, not, if, then, else) do not change instance location.
+func (rc *ReflectContext) InstancePointer() string {
+	var sb strings.Builder
+
+	for _, tok := range rc.instanceTokens(rc.Path) {
+		sb.WriteString("/" + tok)
+	}
+
+	return sb.String()
+}
+
+// instanceTokens returns escaped instance document tokens of a path that starts with current rc.Path,
+// elements beyond rc.Path (e.g. a property name of FieldEnabled path) are treated as property names.
+func (rc *ReflectContext) instanceTokens(path []string) []string {
+	res := make([]string, 0, len(path))
+
+	for i := 1; i < len(path); i++ {
+		var p pathPointer
+
+		if i < len(rc.Path) && rc.Path[i] == path[i] {
+			p = rc.pointer(i)
+		} else {
+			p = defaultPathPointer(path[i])
+		}
+
+		if p.instance != "" {
+			res = append(res, p.instance)
+		}
+	}
+
+	return res
+}
+
+// SchemaPointer returns JSON Pointer (in URI fragment form) of current reflection location
+// in reflected schema, e.g. "#/properties/orders/items/properties/id".
+//
+// Pointer follows nesting of reflected types and does not account for references, so location
+// within a type that becomes a definition is given as if the definition was inlined.
+func (rc *ReflectContext) SchemaPointer() string {
+	var sb strings.Builder
+
+	sb.WriteString("#")
+
+	for i := 1; i < len(rc.Path); i++ {
+		if p := rc.pointer(i); p.schema != "" {
+			sb.WriteString("/" + p.schema)
+		}
+	}
+
+	return sb.String()
+}
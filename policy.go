@@ -0,0 +1,203 @@
+package jsonschema
+
+import (
+	"strings"
+)
+
+// PolicyRule is a kind of schema change that is checked by Policy.
+type PolicyRule string
+
+// Policy rules.
+const (
+	// RuleAddOptional is an addition of property that is not required.
+	RuleAddOptional = PolicyRule("add-optional")
+
+	// RuleAddRequired is an addition of required property or a requirement of existing property.
+	RuleAddRequired = PolicyRule("add-required")
+
+	// RuleRemoveProperty is a removal of property.
+	RuleRemoveProperty = PolicyRule("remove-property")
+
+	// RuleTypeChange is a change of `type`.
+	RuleTypeChange = PolicyRule("type-change")
+
+	// RuleNarrowing is any other change that may reject instances that were valid.
+	RuleNarrowing = PolicyRule("narrowing")
+
+	// RuleWidening is any other change that may accept instances that were invalid.
+	RuleWidening = PolicyRule("widening")
+)
+
+// Severity is a level of policy violation.
+type Severity string
+
+// Severities.
+const (
+	SeverityError   = Severity("error")
+	SeverityWarning = Severity("warning")
+	SeverityInfo    = Severity("info")
+)
+
+// Policy defines compatibility rules of schema evolution.
+//
+// Zero value allows any change.
+type Policy struct {
+	// AllowAddOptional exempts additions of optional properties from ForbidNarrowing and ForbidWidening.
+	AllowAddOptional bool
+
+	// ForbidRemove forbids removal of properties.
+	ForbidRemove bool
+
+	// ForbidTypeChange forbids changes of `type`, including compatible ones (e.g. integer to number).
+	ForbidTypeChange bool
+
+	// ForbidNewRequired forbids new required properties.
+	ForbidNewRequired bool
+
+	// ForbidNarrowing forbids changes that may reject instances that were valid (breaking backward compatibility).
+	ForbidNarrowing bool
+
+	// ForbidWidening forbids changes that may accept instances that were invalid (breaking forward compatibility).
+	ForbidWidening bool
+
+	// Severities overrides SeverityError of violated rules.
+	Severities map[PolicyRule]Severity
+}
+
+// Violation is a change that violates Policy.
+type Violation struct {
+	Rule     PolicyRule `json:"rule"`
+	Severity Severity   `json:"severity"`
+	Change   Change     `json:"change"`
+}
+
+// String implements fmt.Stringer.
+func (v Violation) String() string {
+	return string(v.Severity) + ": " + string(v.Rule) + ": " + v.Change.String()
+}
+
+// Violations is a list of policy violations.
+type Violations []Violation
+
+// Error implements error.
+func (vs Violations) Error() string {
+	msgs := make([]string, 0, len(vs))
+
+	for _, v := range vs {
+		msgs = append(msgs, v.String())
+	}
+
+	return "policy violations: " + strings.Join(msgs, ", ")
+}
+
+// HasErrors is true if there are violations with SeverityError.
+func (vs Violations) HasErrors() bool {
+	for _, v := range vs {
+		if v.Severity == SeverityError {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Check compares schemas with Diff and evaluates changes.
+func (p Policy) Check(prev, next Schema) Violations {
+	return p.Evaluate(Diff(prev, next))
+}
+
+// Evaluate returns changes that violate policy.
+func (p Policy) Evaluate(changes []Change) Violations {
+	var res Violations
+
+	// Properties that are added as required are reported once.
+	added := map[string]bool{}
+
+	for _, c := range changes {
+		if c.Keyword == "properties" && c.Old == nil {
+			added[c.Path] = true
+		}
+	}
+
+	required := map[string]bool{}
+
+	for _, c := range changes {
+		if c.Keyword == "required" && c.New != nil {
+			if name, ok := c.New.(string); ok {
+				required[strings.TrimSuffix(c.Path, "/required")+"/properties/"+escapePointerToken(name)] = true
+			}
+		}
+	}
+
+	for _, c := range changes {
+		rule := p.rule(c, required)
+
+		if c.Keyword == "required" && rule == RuleAddRequired {
+			if name, ok := c.New.(string); ok &&
+				added[strings.TrimSuffix(c.Path, "/required")+"/properties/"+escapePointerToken(name)] {
+				continue
+			}
+		}
+
+		if rule == "" || !p.violates(rule, c) {
+			continue
+		}
+
+		severity := SeverityError
+		if s, ok := p.Severities[rule]; ok {
+			severity = s
+		}
+
+		res = append(res, Violation{Rule: rule, Severity: severity, Change: c})
+	}
+
+	return res
+}
+
+// rule classifies change, empty rule is returned for annotations.
+func (p Policy) rule(c Change, required map[string]bool) PolicyRule {
+	switch {
+	case c.Keyword == "properties" && c.Old == nil:
+		if required[c.Path] {
+			return RuleAddRequired
+		}
+
+		return RuleAddOptional
+	case c.Keyword == "properties" && c.New == nil:
+		return RuleRemoveProperty
+	case c.Keyword == "required" && c.New != nil:
+		return RuleAddRequired
+	case c.Keyword == "type":
+		return RuleTypeChange
+	case c.Narrows:
+		return RuleNarrowing
+	case c.Widens:
+		return RuleWidening
+	}
+
+	return ""
+}
+
+func (p Policy) violates(rule PolicyRule, c Change) bool {
+	switch rule {
+	case RuleAddOptional:
+		if p.AllowAddOptional {
+			return false
+		}
+	case RuleAddRequired:
+		if p.ForbidNewRequired {
+			return true
+		}
+	case RuleRemoveProperty:
+		if p.ForbidRemove {
+			return true
+		}
+	case RuleTypeChange:
+		if p.ForbidTypeChange {
+			return true
+		}
+	case RuleNarrowing, RuleWidening:
+	}
+
+	return (p.ForbidNarrowing && c.Narrows) || (p.ForbidWidening && c.Widens)
+}
@@ -0,0 +1,86 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/jsonschema-go"
+)
+
+func TestPolicy_Check(t *testing.T) {
+	prev, err := jsonschema.ParseSchema([]byte(`{
+	  "type":"object","required":["id"],
+	  "properties":{
+		"id":{"type":"integer","minimum":1},
+		"name":{"type":"string"},
+		"note":{"type":"string"},
+		"amount":{"type":"integer"}
+	  }
+	}`))
+	require.NoError(t, err)
+
+	next, err := jsonschema.ParseSchema([]byte(`{
+	  "type":"object","required":["id","name","currency"],
+	  "properties":{
+		"id":{"type":"integer","minimum":10,"description":"ID."},
+		"name":{"type":"string"},
+		"amount":{"type":"number"},
+		"currency":{"type":"string"},
+		"tag":{"type":"string"}
+	  }
+	}`))
+	require.NoError(t, err)
+
+	strs := func(vs jsonschema.Violations) []string {
+		res := make([]string, 0, len(vs))
+		for _, v := range vs {
+			res = append(res, v.String())
+		}
+
+		return res
+	}
+
+	assert.Empty(t, jsonschema.Policy{}.Check(prev, next))
+
+	p := jsonschema.Policy{
+		AllowAddOptional:  true,
+		ForbidRemove:      true,
+		ForbidTypeChange:  true,
+		ForbidNewRequired: true,
+		ForbidNarrowing:   true,
+		Severities: map[jsonschema.PolicyRule]jsonschema.Severity{
+			jsonschema.RuleTypeChange: jsonschema.SeverityWarning,
+		},
+	}
+
+	vs := p.Check(prev, next)
+	assert.Equal(t, []string{
+		`warning: type-change: #/properties/amount/type: type changed: "integer" -> "number"`,
+		`error: add-required: #/properties/currency: properties added: {"type":"string"}`,
+		`error: narrowing: #/properties/id/minimum: minimum changed: 1 -> 10`,
+		`error: remove-property: #/properties/note: properties removed: {"type":"string"}`,
+		`error: add-required: #/required: required added: "name"`,
+	}, strs(vs))
+	assert.True(t, vs.HasErrors())
+	assert.Contains(t, vs.Error(), "policy violations: ")
+
+	p = jsonschema.Policy{ForbidNarrowing: true}
+	assert.Equal(t, []string{
+		`error: add-required: #/properties/currency: properties added: {"type":"string"}`,
+		`error: narrowing: #/properties/id/minimum: minimum changed: 1 -> 10`,
+		`error: add-optional: #/properties/tag: properties added: {"type":"string"}`,
+		`error: add-required: #/required: required added: "name"`,
+	}, strs(p.Check(prev, next)))
+
+	p = jsonschema.Policy{ForbidWidening: true, Severities: map[jsonschema.PolicyRule]jsonschema.Severity{
+		jsonschema.RuleRemoveProperty: jsonschema.SeverityInfo,
+		jsonschema.RuleTypeChange:     jsonschema.SeverityInfo,
+	}}
+	vs = p.Check(prev, next)
+	assert.Equal(t, []string{
+		`info: type-change: #/properties/amount/type: type changed: "integer" -> "number"`,
+		`info: remove-property: #/properties/note: properties removed: {"type":"string"}`,
+	}, strs(vs))
+	assert.False(t, vs.HasErrors())
+}
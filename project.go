@@ -0,0 +1,225 @@
+package jsonschema
+
+import (
+	"strings"
+)
+
+// Project returns a reduced schema that only contains properties listed as JSON Pointers, e.g. "/id", "/user/name".
+//
+// Listed property is kept with its whole subschema, intermediate properties only keep listed children.
+// Pointer tokens are applied to items of array schemas, so "/items/id" selects "id" of every item.
+// Local references ("#/definitions/...") on a partially selected path are inlined with projected definitions,
+// `required` is pruned to kept properties, patternProperties and schema-valued additionalProperties are removed,
+// definitions that are no longer referenced are removed.
+func Project(schema Schema, jsonPointers ...string) Schema {
+	p := projector{root: &schema, selection: &selection{}}
+
+	for _, ptr := range jsonPointers {
+		p.selection.add(ptr)
+	}
+
+	res := p.schema(schema.ToSchemaOrBool(), p.selection, map[string]bool{})
+	if res.TypeObject == nil {
+		return schema
+	}
+
+	s := *res.TypeObject
+	s.Definitions = p.usedDefinitions(&s)
+
+	return s
+}
+
+type selection struct {
+	all      bool
+	children map[string]*selection
+}
+
+func (s *selection) add(ptr string) {
+	ptr = strings.TrimPrefix(ptr, "#")
+	if ptr == "" || ptr == "/" {
+		s.all = true
+
+		return
+	}
+
+	cur := s
+
+	for _, tok := range strings.Split(strings.TrimPrefix(ptr, "/"), "/") {
+		tok = pointerUnescaper.Replace(tok)
+
+		if cur.children == nil {
+			cur.children = map[string]*selection{}
+		}
+
+		next, ok := cur.children[tok]
+		if !ok {
+			next = &selection{}
+			cur.children[tok] = next
+		}
+
+		cur = next
+	}
+
+	cur.all = true
+}
+
+type projector struct {
+	root      *Schema
+	selection *selection
+}
+
+func (p *projector) definition(ref string) (SchemaOrBool, bool) {
+	if !strings.HasPrefix(ref, "#/definitions/") {
+		return SchemaOrBool{}, false
+	}
+
+	d, ok := p.root.Definitions[definitionName(ref)]
+
+	return d, ok
+}
+
+func definitionName(ref string) string {
+	return pointerUnescaper.Replace(strings.TrimPrefix(ref, "#/definitions/"))
+}
+
+// schema projects a copy of schema, inProgress prevents infinite inlining of recursive references.
+func (p *projector) schema(s SchemaOrBool, sel *selection, inProgress map[string]bool) SchemaOrBool {
+	if sel.all || s.TypeObject == nil {
+		return s
+	}
+
+	c := *s.TypeObject
+
+	var def *Schema
+
+	if c.Ref != nil {
+		if d, ok := p.definition(*c.Ref); ok && d.TypeObject != nil && !inProgress[*c.Ref] {
+			ref := *c.Ref
+			inProgress[ref] = true
+			pd := p.schema(d, sel, inProgress)
+
+			delete(inProgress, ref)
+
+			c.Ref = nil
+			def = pd.TypeObject
+		}
+	}
+
+	if c.Properties != nil {
+		props := make(map[string]SchemaOrBool, len(sel.children))
+
+		for name, ps := range c.Properties {
+			if child, ok := sel.children[name]; ok {
+				props[name] = p.schema(ps, child, inProgress)
+			}
+		}
+
+		c.Properties = props
+
+		var required []string
+
+		for _, name := range c.Required {
+			if _, ok := props[name]; ok {
+				required = append(required, name)
+			}
+		}
+
+		c.Required = required
+	}
+
+	c.PatternProperties = nil
+	c.Dependencies = nil
+
+	if c.AdditionalProperties != nil && c.AdditionalProperties.TypeObject != nil {
+		c.AdditionalProperties = nil
+	}
+
+	if c.Items != nil && c.Items.SchemaOrBool != nil {
+		items := p.schema(*c.Items.SchemaOrBool, sel, inProgress)
+		c.Items = &Items{SchemaOrBool: &items}
+	}
+
+	c.AllOf = p.list(c.AllOf, sel, inProgress)
+	c.AnyOf = p.list(c.AnyOf, sel, inProgress)
+	c.OneOf = p.list(c.OneOf, sel, inProgress)
+	c.Definitions = nil
+
+	if def != nil {
+		c = mergeProjected(c, *def)
+	}
+
+	return c.ToSchemaOrBool()
+}
+
+func (p *projector) list(l []SchemaOrBool, sel *selection, inProgress map[string]bool) []SchemaOrBool {
+	if l == nil {
+		return nil
+	}
+
+	res := make([]SchemaOrBool, len(l))
+	for i, s := range l {
+		res[i] = p.schema(s, sel, inProgress)
+	}
+
+	return res
+}
+
+// mergeProjected fills keywords of referencing schema with keywords of inlined definition.
+func mergeProjected(s, def Schema) Schema {
+	res := def
+
+	if s.Title != nil {
+		res.Title = s.Title
+	}
+
+	if s.Description != nil {
+		res.Description = s.Description
+	}
+
+	if s.Type != nil {
+		res.Type = s.Type
+	}
+
+	res.AllOf = append(res.AllOf, s.AllOf...)
+	res.AnyOf = append(res.AnyOf, s.AnyOf...)
+	res.OneOf = append(res.OneOf, s.OneOf...)
+
+	return res
+}
+
+// usedDefinitions returns definitions that are transitively referenced from schema.
+func (p *projector) usedDefinitions(s *Schema) map[string]SchemaOrBool {
+	if len(p.root.Definitions) == 0 {
+		return nil
+	}
+
+	used := map[string]SchemaOrBool{}
+	queue := []*Schema{s}
+
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+
+		walkSchemas(cur, func(ws *Schema) {
+			if ws.Ref == nil {
+				return
+			}
+
+			name := definitionName(*ws.Ref)
+			if _, ok := used[name]; ok {
+				return
+			}
+
+			if d, ok := p.definition(*ws.Ref); ok {
+				used[name] = d
+				queue = append(queue, d.TypeObject)
+			}
+		})
+	}
+
+	if len(used) == 0 {
+		return nil
+	}
+
+	return used
+}
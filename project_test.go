@@ -0,0 +1,68 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/jsonschema-go"
+)
+
+func TestProject(t *testing.T) {
+	type Address struct {
+		City   string `json:"city" required:"true"`
+		Street string `json:"street" required:"true"`
+	}
+
+	type Tag struct {
+		Name  string `json:"name"`
+		Color string `json:"color"`
+	}
+
+	type User struct {
+		ID      int               `json:"id" required:"true"`
+		Name    string            `json:"name" required:"true"`
+		Address Address           `json:"address"`
+		Billing Address           `json:"billing"`
+		Tags    []Tag             `json:"tags"`
+		Labels  map[string]string `json:"labels"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(User{})
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "required":["id"],
+	  "definitions":{
+		"JsonschemaGoTestAddress":{
+		  "required":["city","street"],
+		  "properties":{"city":{"type":"string"},"street":{"type":"string"}},
+		  "type":"object"
+		}
+	  },
+	  "properties":{
+		"address":{
+		  "required":["city"],"properties":{"city":{"type":"string"}},
+		  "type":"object"
+		},
+		"billing":{"$ref":"#/definitions/JsonschemaGoTestAddress"},
+		"id":{"type":"integer"},
+		"tags":{
+		  "items":{"properties":{"name":{"type":"string"}},"type":"object"},
+		  "type":["array","null"]
+		}
+	  },
+	  "type":"object"
+	}`, jsonschema.Project(s, "/id", "/address/city", "/billing", "/tags/name"))
+
+	// Original schema is not changed.
+	assert.Len(t, s.Properties, 6)
+	assert.Len(t, s.Definitions, 2)
+
+	assertjson.EqMarshal(t, `{
+	  "required":["id"],"properties":{"id":{"type":"integer"}},"type":"object"
+	}`, jsonschema.Project(s, "/id", "/unknown"))
+}
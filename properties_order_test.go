@@ -0,0 +1,96 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/jsonschema-go"
+)
+
+type orderedBase struct {
+	Created string `json:"created"`
+}
+
+type orderedUser struct {
+	Name  string `json:"name"`
+	Email string `json:"email" required:"true"`
+	orderedBase
+	Age     int          `json:"age"`
+	ID      int          `json:"id" required:"true"`
+	Address orderedPlace `json:"address"`
+}
+
+type orderedPlace struct {
+	Zip  string `json:"zip"`
+	City string `json:"city"`
+}
+
+func TestOrderProperties(t *testing.T) {
+	for _, tc := range []struct {
+		order    jsonschema.PropertyOrdering
+		expected string
+	}{
+		{
+			order: jsonschema.PropertiesDeclared,
+			expected: `{"required":["email","id"],"definitions":{"JsonschemaGoTestOrderedPlace":{"type":"object",` +
+				`"properties":{"zip":{"type":"string"},"city":{"type":"string"}}}},` +
+				`"type":"object","properties":{"name":{"type":"string"},` +
+				`"email":{"type":"string"},"created":{"type":"string"},"age":{"type":"integer"},` +
+				`"id":{"type":"integer"},"address":{"$ref":"#/definitions/JsonschemaGoTestOrderedPlace"},` +
+				`"x-extra":{"type":"boolean"}}}`,
+		},
+		{
+			order: jsonschema.PropertiesAlphabetical,
+			expected: `{"required":["email","id"],"definitions":{"JsonschemaGoTestOrderedPlace":{"type":"object",` +
+				`"properties":{"city":{"type":"string"},"zip":{"type":"string"}}}},` +
+				`"type":"object","properties":{` +
+				`"address":{"$ref":"#/definitions/JsonschemaGoTestOrderedPlace"},"age":{"type":"integer"},` +
+				`"created":{"type":"string"},"email":{"type":"string"},"id":{"type":"integer"},` +
+				`"name":{"type":"string"},"x-extra":{"type":"boolean"}}}`,
+		},
+		{
+			order: jsonschema.PropertiesRequiredFirst,
+			expected: `{"required":["email","id"],"definitions":{"JsonschemaGoTestOrderedPlace":{"type":"object",` +
+				`"properties":{"zip":{"type":"string"},"city":{"type":"string"}}}},` +
+				`"type":"object","properties":{"email":{"type":"string"},` +
+				`"id":{"type":"integer"},"name":{"type":"string"},"created":{"type":"string"},` +
+				`"age":{"type":"integer"},"address":{"$ref":"#/definitions/JsonschemaGoTestOrderedPlace"},` +
+				`"x-extra":{"type":"boolean"}}}`,
+		},
+	} {
+		r := jsonschema.Reflector{}
+
+		s, err := r.Reflect(orderedUser{}, jsonschema.OrderProperties(tc.order),
+			jsonschema.InterceptSchema(func(params jsonschema.InterceptSchemaParams) (stop bool, err error) {
+				if params.Processed && params.Value.Type() == reflect.TypeOf(orderedUser{}) {
+					params.Schema.Properties["x-extra"] = jsonschema.Boolean.ToSchemaOrBool()
+				}
+
+				return false, nil
+			}),
+		)
+		require.NoError(t, err)
+
+		j, err := json.Marshal(s)
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, string(j), tc.order)
+	}
+}
+
+func TestSchema_PropertiesOrder(t *testing.T) {
+	s := jsonschema.Schema{}
+	s.WithTitle("Point").
+		WithPropertiesItem("y", jsonschema.Integer.ToSchemaOrBool()).
+		WithPropertiesItem("x", jsonschema.Integer.ToSchemaOrBool()).
+		WithExtraPropertiesItem("x-kind", "point")
+
+	s.PropertiesOrder = []string{"y", "missing", "y"}
+
+	j, err := json.Marshal(s)
+	require.NoError(t, err)
+	assert.Equal(t, `{"title":"Point","properties":{"y":{"type":"integer"},"x":{"type":"integer"}},"x-kind":"point"}`,
+		string(j))
+}
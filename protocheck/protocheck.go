@@ -0,0 +1,348 @@
+// Package protocheck compares reflected JSON Schemas with protobuf JSON mapping of messages.
+//
+// Protobuf messages are described with Descriptor and Field to avoid dependency on protobuf runtime,
+// an adapter for protoreflect.MessageDescriptor can be as simple as:
+//
+//	func descriptor(md protoreflect.MessageDescriptor) protocheck.Descriptor {
+//		m := protocheck.Descriptor{FullName: string(md.FullName())}
+//
+//		for i := 0; i < md.Fields().Len(); i++ {
+//			fd := md.Fields().Get(i)
+//			f := protocheck.Field{
+//				Name:     string(fd.Name()),
+//				JSONName: fd.JSONName(),
+//				Kind:     protocheck.Kind(fd.Kind().String()),
+//				Repeated: fd.IsList(),
+//				Map:      fd.IsMap(),
+//				Optional: fd.HasPresence(),
+//			}
+//			// Fill f.Message for message fields and f.Kind/f.Message of map values.
+//			m.Fields = append(m.Fields, f)
+//		}
+//
+//		return m
+//	}
+package protocheck
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/swaggest/jsonschema-go"
+)
+
+// Kind is a protobuf field kind, values match protoreflect.Kind names.
+type Kind string
+
+// Protobuf field kinds.
+const (
+	Bool     = Kind("bool")
+	Enum     = Kind("enum")
+	Int32    = Kind("int32")
+	Sint32   = Kind("sint32")
+	Uint32   = Kind("uint32")
+	Int64    = Kind("int64")
+	Sint64   = Kind("sint64")
+	Uint64   = Kind("uint64")
+	Sfixed32 = Kind("sfixed32")
+	Fixed32  = Kind("fixed32")
+	Float    = Kind("float")
+	Sfixed64 = Kind("sfixed64")
+	Fixed64  = Kind("fixed64")
+	Double   = Kind("double")
+	String   = Kind("string")
+	Bytes    = Kind("bytes")
+	Message  = Kind("message")
+	Group    = Kind("group")
+)
+
+// Is64Bit is true for integer kinds that are encoded as JSON strings.
+func (k Kind) Is64Bit() bool {
+	switch k { //nolint:exhaustive // Only 64-bit kinds are of interest.
+	case Int64, Sint64, Uint64, Sfixed64, Fixed64:
+		return true
+	}
+
+	return false
+}
+
+// Descriptor describes protobuf message.
+type Descriptor struct {
+	FullName string
+	Fields   []Field
+}
+
+// Field describes protobuf message field.
+type Field struct {
+	// Name is a field name in proto file, e.g. "user_id".
+	Name string
+
+	// JSONName is a lowerCamelCase name used by proto JSON mapping, e.g. "userId".
+	JSONName string
+
+	// Kind is a kind of field or map value.
+	Kind Kind
+
+	// Repeated is true for repeated fields.
+	Repeated bool
+
+	// Map is true for map fields, Kind and Message describe map value.
+	Map bool
+
+	// Optional is true for fields with explicit presence, such fields are omitted when unset
+	// even with EmitUnpopulated.
+	Optional bool
+
+	// Message describes message or map value message, can be nil for other kinds.
+	Message *Descriptor
+}
+
+// Problem kinds.
+const (
+	MissingProperty = "missing_property"
+	UnknownProperty = "unknown_property"
+	TypeMismatch    = "type_mismatch"
+	Int64Encoding   = "int64_encoding"
+	Optionality     = "optionality"
+)
+
+// Mismatch describes difference between JSON Schema and proto JSON mapping.
+type Mismatch struct {
+	// Path is a JSON Pointer to the property in instance document.
+	Path string
+
+	// Kind is one of MissingProperty, UnknownProperty, TypeMismatch, Int64Encoding, Optionality.
+	Kind string
+
+	// Message is a human-readable description.
+	Message string
+}
+
+// String implements fmt.Stringer.
+func (m Mismatch) String() string {
+	return m.Path + ": " + m.Message
+}
+
+// Options configures comparison.
+type Options struct {
+	// UseProtoNames enables original proto field names instead of lowerCamelCase JSON names,
+	// as with protojson.MarshalOptions{UseProtoNames: true}.
+	UseProtoNames bool
+
+	// EmitUnpopulated is true if zero values are marshaled, as with protojson.MarshalOptions{EmitUnpopulated: true}.
+	// Otherwise, required properties are reported for fields that may be omitted.
+	EmitUnpopulated bool
+}
+
+// Check compares schema with proto JSON mapping of a message.
+//
+// References to definitions are resolved within schema.
+func Check(schema jsonschema.Schema, msg Descriptor, options ...func(o *Options)) []Mismatch {
+	c := checker{root: schema}
+
+	for _, o := range options {
+		o(&c.Options)
+	}
+
+	c.visited = map[string]bool{}
+	c.checkMessage("", &schema, msg)
+
+	return c.mismatches
+}
+
+type checker struct {
+	Options
+
+	root       jsonschema.Schema
+	visited    map[string]bool
+	mismatches []Mismatch
+}
+
+func (c *checker) add(path, kind, format string, args ...interface{}) {
+	c.mismatches = append(c.mismatches, Mismatch{Path: path, Kind: kind, Message: fmt.Sprintf(format, args...)})
+}
+
+func (c *checker) resolve(s *jsonschema.Schema) *jsonschema.Schema {
+	for i := 0; i < 100 && s != nil && s.Ref != nil; i++ {
+		ref := *s.Ref
+
+		switch {
+		case ref == "#":
+			s = &c.root
+		case strings.HasPrefix(ref, "#/definitions/"):
+			d, ok := c.root.Definitions[strings.TrimPrefix(ref, "#/definitions/")]
+			if !ok || d.TypeObject == nil {
+				return nil
+			}
+
+			s = d.TypeObject
+		default:
+			return nil
+		}
+	}
+
+	// Nullable envelope, e.g. {"anyOf":[{"type":"null"},{"$ref":"..."}]}.
+	if s != nil && s.Type == nil && len(s.AnyOf) == 2 {
+		for _, a := range s.AnyOf {
+			if a.TypeObject != nil && !isNullOnly(a.TypeObject) {
+				return c.resolve(a.TypeObject)
+			}
+		}
+	}
+
+	return s
+}
+
+func isNullOnly(s *jsonschema.Schema) bool {
+	return s.Type != nil && s.Type.SimpleTypes != nil && *s.Type.SimpleTypes == jsonschema.Null
+}
+
+func (c *checker) checkMessage(path string, s *jsonschema.Schema, msg Descriptor) {
+	s = c.resolve(s)
+	if s == nil {
+		c.add(path, TypeMismatch, "unresolved schema for message %s", msg.FullName)
+
+		return
+	}
+
+	key := path + "|" + msg.FullName
+	if c.visited[key] {
+		return
+	}
+
+	c.visited[key] = true
+
+	if s.Type != nil && !s.HasType(jsonschema.Object) {
+		c.add(path, TypeMismatch, "message %s is an object in proto JSON, schema type is not object", msg.FullName)
+
+		return
+	}
+
+	required := map[string]bool{}
+	for _, r := range s.Required {
+		required[r] = true
+	}
+
+	known := map[string]bool{}
+
+	for _, f := range msg.Fields {
+		name := f.JSONName
+		if c.UseProtoNames || name == "" {
+			name = f.Name
+		}
+
+		known[name] = true
+		fp := path + "/" + name
+
+		ps, ok := s.Properties[name]
+		if !ok || ps.TypeObject == nil {
+			c.add(fp, MissingProperty, "field %s.%s is missing in schema", msg.FullName, f.Name)
+
+			continue
+		}
+
+		if required[name] && (!c.EmitUnpopulated || f.Optional) {
+			c.add(fp, Optionality, "property is required, but proto JSON omits unpopulated field %s", f.Name)
+		}
+
+		c.checkField(fp, ps.TypeObject, f)
+	}
+
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		if !known[name] {
+			c.add(path+"/"+name, UnknownProperty, "property has no field in %s", msg.FullName)
+		}
+	}
+}
+
+func (c *checker) checkField(path string, ps *jsonschema.Schema, f Field) {
+	ps = c.resolve(ps)
+	if ps == nil {
+		c.add(path, TypeMismatch, "unresolved schema")
+
+		return
+	}
+
+	switch {
+	case f.Map:
+		if ps.Type != nil && !ps.HasType(jsonschema.Object) {
+			c.add(path, TypeMismatch, "map field is an object in proto JSON, schema type is not object")
+
+			return
+		}
+
+		if ps.AdditionalProperties != nil && ps.AdditionalProperties.TypeObject != nil {
+			c.checkValue(path+"/{}", ps.AdditionalProperties.TypeObject, f)
+		}
+	case f.Repeated:
+		if ps.Type != nil && !ps.HasType(jsonschema.Array) {
+			c.add(path, TypeMismatch, "repeated field is an array in proto JSON, schema type is not array")
+
+			return
+		}
+
+		if ps.Items != nil && ps.Items.SchemaOrBool != nil && ps.Items.SchemaOrBool.TypeObject != nil {
+			c.checkValue(path+"/[]", ps.Items.SchemaOrBool.TypeObject, f)
+		}
+	default:
+		c.checkValue(path, ps, f)
+	}
+}
+
+func (c *checker) checkValue(path string, s *jsonschema.Schema, f Field) {
+	s = c.resolve(s)
+	if s == nil {
+		c.add(path, TypeMismatch, "unresolved schema")
+
+		return
+	}
+
+	if f.Kind == Message || f.Kind == Group {
+		if f.Message != nil {
+			c.checkMessage(path, s, *f.Message)
+		}
+
+		return
+	}
+
+	if s.Type == nil {
+		return
+	}
+
+	expected := []jsonschema.SimpleType{}
+
+	switch {
+	case f.Kind.Is64Bit():
+		if !s.HasType(jsonschema.String) {
+			c.add(path, Int64Encoding, "%s is encoded as string in proto JSON, schema does not allow string", f.Kind)
+		}
+
+		return
+	case f.Kind == Bool:
+		expected = append(expected, jsonschema.Boolean)
+	case f.Kind == String, f.Kind == Bytes:
+		expected = append(expected, jsonschema.String)
+	case f.Kind == Enum:
+		expected = append(expected, jsonschema.String, jsonschema.Integer)
+	case f.Kind == Float, f.Kind == Double:
+		expected = append(expected, jsonschema.Number)
+	default:
+		expected = append(expected, jsonschema.Integer, jsonschema.Number)
+	}
+
+	for _, t := range expected {
+		if s.HasType(t) {
+			return
+		}
+	}
+
+	c.add(path, TypeMismatch, "%s field expects %s in schema", f.Kind, expected[0])
+}
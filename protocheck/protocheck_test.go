@@ -0,0 +1,94 @@
+package protocheck_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/jsonschema-go"
+	"github.com/swaggest/jsonschema-go/protocheck"
+)
+
+type Address struct {
+	City string `json:"city"`
+	Zip  int    `json:"zip"`
+}
+
+type User struct {
+	ID        int64              `json:"id"`
+	Name      string             `json:"name" required:"true"`
+	Addresses []Address          `json:"addresses"`
+	Labels    map[string]string  `json:"labels"`
+	Primary   *Address           `json:"primaryAddress"`
+	Debug     bool               `json:"debug"`
+	Scores    map[string]float64 `json:"scores"`
+	Nickname  string             `json:"nickname" required:"true"`
+}
+
+func TestCheck(t *testing.T) {
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(User{})
+	require.NoError(t, err)
+
+	address := protocheck.Descriptor{
+		FullName: "acme.Address",
+		Fields: []protocheck.Field{
+			{Name: "city", JSONName: "city", Kind: protocheck.String},
+			{Name: "zip", JSONName: "zip", Kind: protocheck.String},
+		},
+	}
+
+	user := protocheck.Descriptor{
+		FullName: "acme.User",
+		Fields: []protocheck.Field{
+			{Name: "id", JSONName: "id", Kind: protocheck.Int64},
+			{Name: "name", JSONName: "name", Kind: protocheck.String},
+			{Name: "addresses", JSONName: "addresses", Kind: protocheck.Message, Repeated: true, Message: &address},
+			{Name: "labels", JSONName: "labels", Kind: protocheck.String, Map: true},
+			{Name: "primary_address", JSONName: "primaryAddress", Kind: protocheck.Message, Message: &address},
+			{Name: "scores", JSONName: "scores", Kind: protocheck.Double, Map: true},
+			{Name: "created_at", JSONName: "createdAt", Kind: protocheck.String},
+			{Name: "nickname", JSONName: "nickname", Kind: protocheck.String, Optional: true},
+		},
+	}
+
+	assert.Equal(t, []protocheck.Mismatch{
+		{
+			Path: "/id", Kind: protocheck.Int64Encoding,
+			Message: "int64 is encoded as string in proto JSON, schema does not allow string",
+		},
+		{
+			Path: "/name", Kind: protocheck.Optionality,
+			Message: "property is required, but proto JSON omits unpopulated field name",
+		},
+		{
+			Path: "/addresses/[]/zip", Kind: protocheck.TypeMismatch,
+			Message: "string field expects string in schema",
+		},
+		{
+			Path: "/primaryAddress/zip", Kind: protocheck.TypeMismatch,
+			Message: "string field expects string in schema",
+		},
+		{
+			Path: "/createdAt", Kind: protocheck.MissingProperty,
+			Message: "field acme.User.created_at is missing in schema",
+		},
+		{
+			Path: "/nickname", Kind: protocheck.Optionality,
+			Message: "property is required, but proto JSON omits unpopulated field nickname",
+		},
+		{Path: "/debug", Kind: protocheck.UnknownProperty, Message: "property has no field in acme.User"},
+	}, protocheck.Check(s, user))
+
+	mm := protocheck.Check(s, user, func(o *protocheck.Options) {
+		o.UseProtoNames = true
+		o.EmitUnpopulated = true
+	})
+
+	assert.Len(t, mm, 7)
+	assert.Equal(t, "/primary_address: field acme.User.primary_address is missing in schema", mm[2].String())
+
+	// Fields with explicit presence are omitted when unset even with EmitUnpopulated.
+	assert.Equal(t, "/nickname: property is required, but proto JSON omits unpopulated field nickname", mm[4].String())
+}
@@ -0,0 +1,220 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// RedactedValue replaces sensitive values in Validator.Redact by default.
+const RedactedValue = "[REDACTED]"
+
+// MaskFunc returns replacement of sensitive value at instance path (JSON Pointer, e.g. "/card/number").
+type MaskFunc func(instancePath string, value interface{}) interface{}
+
+// MaskPartial returns MaskFunc that keeps last visible characters of strings, e.g. "************1234",
+// other values are replaced with RedactedValue.
+func MaskPartial(visible int) MaskFunc {
+	return func(_ string, value interface{}) interface{} {
+		s, ok := value.(string)
+		if !ok {
+			return RedactedValue
+		}
+
+		n := utf8.RuneCountInString(s)
+		if n <= visible {
+			return strings.Repeat("*", n)
+		}
+
+		r := []rune(s)
+
+		return strings.Repeat("*", n-visible) + string(r[n-visible:])
+	}
+}
+
+// Redact returns a copy of decoded JSON value with values of sensitive schemas replaced, e.g. for logging.
+//
+// Schemas are sensitive if they have any of SensitiveKeywords set to true, XSensitive and "writeOnly" are used
+// if SensitiveKeywords is empty. Subschemas are applied same way as in validation: references are resolved,
+// anyOf/oneOf alternatives and if/then/else branches are applied if they match the value.
+// Nil mask replaces values with RedactedValue.
+func (v *Validator) Redact(value interface{}, mask MaskFunc) interface{} {
+	paths := map[string]bool{}
+
+	v.sensitivePaths(v.root, value, "", "#", 0, paths)
+
+	if len(paths) == 0 {
+		return value
+	}
+
+	if mask == nil {
+		mask = func(_ string, _ interface{}) interface{} {
+			return RedactedValue
+		}
+	}
+
+	return redactValue(value, "", paths, mask)
+}
+
+// RedactJSON redacts sensitive values of JSON document, see Redact.
+func (v *Validator) RedactJSON(data []byte, mask MaskFunc) ([]byte, error) {
+	var value interface{}
+
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+
+	if err := d.Decode(&value); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON: %w", err)
+	}
+
+	return json.Marshal(v.Redact(value, mask))
+}
+
+func (v *Validator) sensitiveKeywords() []string {
+	if len(v.SensitiveKeywords) > 0 {
+		return v.SensitiveKeywords
+	}
+
+	return []string{XSensitive, "writeOnly"}
+}
+
+// sensitivePaths collects instance paths of values with sensitive schemas.
+func (v *Validator) sensitivePaths(s SchemaOrBool, value interface{}, ip, sp string, depth int, paths map[string]bool) {
+	schema := s.TypeObject
+	if schema == nil || value == nil || depth > maxValidationDepth || paths[ip] {
+		return
+	}
+
+	for _, k := range v.sensitiveKeywords() {
+		if b, ok := schema.ExtraProperties[k].(bool); ok && b {
+			paths[ip] = true
+
+			return
+		}
+	}
+
+	if schema.Ref != nil {
+		if rs, err := v.resolveRef(*schema.Ref); err == nil {
+			v.sensitivePaths(rs, value, ip, *schema.Ref, depth+1, paths)
+		}
+	}
+
+	for i, sub := range schema.AllOf {
+		v.sensitivePaths(sub, value, ip, sp+"/allOf/"+strconv.Itoa(i), depth+1, paths)
+	}
+
+	for i, sub := range schema.AnyOf {
+		if v.matches(sub, value, ip, sp+"/anyOf/"+strconv.Itoa(i), depth+1) {
+			v.sensitivePaths(sub, value, ip, sp+"/anyOf/"+strconv.Itoa(i), depth+1, paths)
+		}
+	}
+
+	for i, sub := range schema.OneOf {
+		if v.matches(sub, value, ip, sp+"/oneOf/"+strconv.Itoa(i), depth+1) {
+			v.sensitivePaths(sub, value, ip, sp+"/oneOf/"+strconv.Itoa(i), depth+1, paths)
+		}
+	}
+
+	if schema.If != nil {
+		if v.matches(*schema.If, value, ip, sp+"/if", depth+1) {
+			if schema.Then != nil {
+				v.sensitivePaths(*schema.Then, value, ip, sp+"/then", depth+1, paths)
+			}
+		} else if schema.Else != nil {
+			v.sensitivePaths(*schema.Else, value, ip, sp+"/else", depth+1, paths)
+		}
+	}
+
+	switch val := value.(type) {
+	case map[string]interface{}:
+		v.sensitiveProperties(schema, val, ip, sp, depth, paths)
+	case []interface{}:
+		if schema.Items == nil {
+			return
+		}
+
+		for i, item := range val {
+			iip := ip + "/" + strconv.Itoa(i)
+
+			switch {
+			case schema.Items.SchemaOrBool != nil:
+				v.sensitivePaths(*schema.Items.SchemaOrBool, item, iip, sp+"/items", depth+1, paths)
+			case i < len(schema.Items.SchemaArray):
+				v.sensitivePaths(schema.Items.SchemaArray[i], item, iip, sp+"/items/"+strconv.Itoa(i), depth+1, paths)
+			case schema.AdditionalItems != nil:
+				v.sensitivePaths(*schema.AdditionalItems, item, iip, sp+"/additionalItems", depth+1, paths)
+			}
+		}
+	}
+}
+
+func (v *Validator) sensitiveProperties(schema *Schema, obj map[string]interface{}, ip, sp string, depth int, paths map[string]bool) {
+	for k, val := range obj {
+		pip := ip + "/" + escapePointerToken(k)
+		matched := false
+
+		if ps, ok := schema.Properties[k]; ok {
+			matched = true
+
+			v.sensitivePaths(ps, val, pip, sp+"/properties/"+escapePointerToken(k), depth+1, paths)
+		}
+
+		for pattern, ps := range schema.PatternProperties {
+			if re, err := v.pattern(pattern); err == nil && re.MatchString(k) {
+				matched = true
+
+				v.sensitivePaths(ps, val, pip, sp+"/patternProperties/"+escapePointerToken(pattern), depth+1, paths)
+			}
+		}
+
+		if !matched && schema.AdditionalProperties != nil {
+			v.sensitivePaths(*schema.AdditionalProperties, val, pip, sp+"/additionalProperties", depth+1, paths)
+		}
+
+		if dep, ok := schema.Dependencies[k]; ok && dep.SchemaOrBool != nil {
+			v.sensitivePaths(*dep.SchemaOrBool, obj, ip, sp+"/dependencies/"+escapePointerToken(k), depth+1, paths)
+		}
+	}
+}
+
+// matches checks if value is valid against subschema, failures of SensitiveKeywords are ignored.
+func (v *Validator) matches(s SchemaOrBool, value interface{}, ip, sp string, depth int) bool {
+	for _, e := range v.validate(s, value, ip, sp, depth) {
+		if !hasString(v.SensitiveKeywords, e.Keyword) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// redactValue returns a copy of value with values at paths replaced by mask.
+func redactValue(value interface{}, ip string, paths map[string]bool, mask MaskFunc) interface{} {
+	if paths[ip] {
+		return mask(ip, value)
+	}
+
+	switch val := value.(type) {
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(val))
+
+		for k, item := range val {
+			res[k] = redactValue(item, ip+"/"+escapePointerToken(k), paths, mask)
+		}
+
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(val))
+
+		for i, item := range val {
+			res[i] = redactValue(item, ip+"/"+strconv.Itoa(i), paths, mask)
+		}
+
+		return res
+	}
+
+	return value
+}
@@ -0,0 +1,74 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/jsonschema-go"
+)
+
+func TestValidator_Redact(t *testing.T) {
+	type Card struct {
+		Number string `json:"number" sensitive:"true"`
+		Holder string `json:"holder"`
+	}
+
+	type Account struct {
+		Login    string            `json:"login"`
+		Password string            `json:"password" sensitive:"true"`
+		Cards    []Card            `json:"cards"`
+		Secrets  map[string]string `json:"secrets" sensitive:"true"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(Account{}, jsonschema.SensitiveFields(jsonschema.SensitiveAnnotate))
+	require.NoError(t, err)
+
+	v := jsonschema.NewValidator(s)
+
+	doc := []byte(`{
+	  "login":"jdoe","password":"qwerty","secrets":{"a":"b"},
+	  "cards":[{"number":"4111111111111111","holder":"J. Doe"}]
+	}`)
+
+	redacted, err := v.RedactJSON(doc, nil)
+	require.NoError(t, err)
+
+	assertjson.Equal(t, []byte(`{
+	  "login":"jdoe","password":"[REDACTED]","secrets":"[REDACTED]",
+	  "cards":[{"number":"[REDACTED]","holder":"J. Doe"}]
+	}`), redacted)
+
+	masked, err := v.RedactJSON(doc, jsonschema.MaskPartial(4))
+	require.NoError(t, err)
+
+	assertjson.Equal(t, []byte(`{
+	  "login":"jdoe","password":"**erty","secrets":"[REDACTED]",
+	  "cards":[{"number":"************1111","holder":"J. Doe"}]
+	}`), masked)
+
+	// Original value is not changed.
+	value := map[string]interface{}{"login": "jdoe", "password": "qwerty"}
+	assert.Equal(t, map[string]interface{}{"login": "jdoe", "password": "[REDACTED]"}, v.Redact(value, nil))
+	assert.Equal(t, "qwerty", value["password"])
+}
+
+func TestValidator_Redact_composition(t *testing.T) {
+	s, err := jsonschema.ParseSchema([]byte(`{
+	  "oneOf":[
+		{"properties":{"kind":{"const":"token"},"value":{"writeOnly":true}},"required":["kind"]},
+		{"properties":{"kind":{"const":"name"}},"required":["kind"]}
+	  ]
+	}`))
+	require.NoError(t, err)
+
+	v := jsonschema.NewValidator(s)
+
+	assert.Equal(t, map[string]interface{}{"kind": "token", "value": "[REDACTED]"},
+		v.Redact(map[string]interface{}{"kind": "token", "value": "abc"}, nil))
+	assert.Equal(t, map[string]interface{}{"kind": "name", "value": "abc"},
+		v.Redact(map[string]interface{}{"kind": "name", "value": "abc"}, nil))
+}
@@ -0,0 +1,169 @@
+package jsonschema
+
+import (
+	"sort"
+	"strings"
+)
+
+// RefProblemKind is a kind of reference graph problem.
+type RefProblemKind string
+
+// Reference graph problem kinds.
+const (
+	// UnresolvedRef is a local reference that does not point to a subschema, e.g. to a missing definition.
+	UnresolvedRef = RefProblemKind("unresolved")
+
+	// UnreachableDefinition is a definition that can not be reached with references from root schema.
+	UnreachableDefinition = RefProblemKind("unreachable")
+)
+
+// RefProblem describes a problem of reference graph.
+type RefProblem struct {
+	Kind RefProblemKind `json:"kind"`
+
+	// Pointer is a JSON Pointer of subschema with unresolved reference or of unreachable definition,
+	// e.g. "/properties/id" or "/definitions/Foo".
+	Pointer string `json:"pointer"`
+
+	// Ref is an unresolved reference, empty for unreachable definition.
+	Ref string `json:"ref,omitempty"`
+}
+
+// Error implements error.
+func (p RefProblem) Error() string {
+	ptr := p.Pointer
+	if ptr == "" {
+		ptr = "/"
+	}
+
+	if p.Kind == UnresolvedRef {
+		return ptr + ": unresolved reference " + p.Ref
+	}
+
+	return ptr + ": unreachable definition"
+}
+
+// RefProblems is a list of reference graph problems.
+type RefProblems []RefProblem
+
+// Error implements error.
+func (e RefProblems) Error() string {
+	msgs := make([]string, 0, len(e))
+
+	for _, p := range e {
+		msgs = append(msgs, p.Error())
+	}
+
+	return "invalid references: " + strings.Join(msgs, ", ")
+}
+
+// CheckRefs verifies that local references of document resolve and that all definitions
+// (in `definitions` or `$defs`) are reachable from root schema.
+//
+// Non-local references (e.g. to other documents) are not checked. Problems are sorted by pointer.
+func (d *Document) CheckRefs() RefProblems {
+	var res RefProblems
+
+	reachable := map[string]bool{}
+
+	var visit func(ptr string)
+
+	visit = func(ptr string) {
+		if reachable[ptr] {
+			return
+		}
+
+		reachable[ptr] = true
+
+		s := d.pointers[ptr].TypeObject
+		if s == nil {
+			return
+		}
+
+		if s.Ref != nil && strings.HasPrefix(*s.Ref, "#") {
+			if target, ok := d.refPointer(*s.Ref); ok {
+				visit(target)
+			}
+		}
+
+		for _, sub := range subschemas(s) {
+			if !sub.definition {
+				visit(ptr + sub.ptr)
+			}
+		}
+	}
+
+	visit("")
+
+	for _, ptr := range d.Pointers() {
+		s := d.pointers[ptr].TypeObject
+		if s == nil {
+			continue
+		}
+
+		if s.Ref != nil && strings.HasPrefix(*s.Ref, "#") {
+			if _, ok := d.refPointer(*s.Ref); !ok {
+				res = append(res, RefProblem{Kind: UnresolvedRef, Pointer: ptr, Ref: *s.Ref})
+			}
+		}
+
+		for _, sub := range subschemas(s) {
+			if sub.definition && !reachable[ptr+sub.ptr] {
+				res = append(res, RefProblem{Kind: UnreachableDefinition, Pointer: ptr + sub.ptr})
+			}
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Pointer < res[j].Pointer
+	})
+
+	return res
+}
+
+// ParseOptions configures ParseSchema.
+type ParseOptions struct {
+	// CheckRefs enables verification of reference graph, see Document.CheckRefs.
+	CheckRefs bool
+
+	// AllowUnreachable skips reporting of unreachable definitions, e.g. for a library of shared definitions.
+	AllowUnreachable bool
+}
+
+// ParseSchema unmarshals schema document.
+//
+// With ParseOptions.CheckRefs, reference graph is verified and RefProblems error is returned
+// together with parsed schema if there are problems.
+func ParseSchema(data []byte, options ...func(o *ParseOptions)) (Schema, error) {
+	o := ParseOptions{}
+
+	for _, option := range options {
+		option(&o)
+	}
+
+	var s Schema
+
+	if err := s.UnmarshalJSON(data); err != nil {
+		return s, err
+	}
+
+	if !o.CheckRefs {
+		return s, nil
+	}
+
+	var problems RefProblems
+
+	for _, p := range NewDocument(s).CheckRefs() {
+		if p.Kind == UnreachableDefinition && o.AllowUnreachable {
+			continue
+		}
+
+		problems = append(problems, p)
+	}
+
+	if len(problems) > 0 {
+		return s, problems
+	}
+
+	return s, nil
+}
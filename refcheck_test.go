@@ -0,0 +1,60 @@
+package jsonschema_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/jsonschema-go"
+)
+
+func TestParseSchema(t *testing.T) {
+	doc := []byte(`{
+	  "definitions":{
+		"Item":{"properties":{"tag":{"$ref":"#tag"}}},
+		"Tag":{"$id":"#tag","type":"string"},
+		"Orphan":{"properties":{"next":{"$ref":"#/definitions/Missing"}}},
+		"Pct%":{"type":"number"}
+	  },
+	  "$defs":{"Unused":{"type":"integer"},"Used":{"type":"boolean"}},
+	  "properties":{
+		"items":{"items":{"$ref":"#/definitions/Item"}},
+		"ratio":{"$ref":"#/definitions/Pct%25"},
+		"flag":{"$ref":"#/$defs/Used"},
+		"self":{"$ref":"#"},
+		"remote":{"$ref":"other.json#/definitions/Foo"},
+		"broken":{"$ref":"#/properties/nope"}
+	  }
+	}`)
+
+	s, err := jsonschema.ParseSchema(doc)
+	require.NoError(t, err)
+	assert.NotNil(t, s.Properties["items"])
+
+	_, err = jsonschema.ParseSchema(doc, func(o *jsonschema.ParseOptions) {
+		o.CheckRefs = true
+	})
+
+	var problems jsonschema.RefProblems
+
+	require.True(t, errors.As(err, &problems))
+	assert.Equal(t, jsonschema.RefProblems{
+		{Kind: jsonschema.UnreachableDefinition, Pointer: "/$defs/Unused"},
+		{Kind: jsonschema.UnreachableDefinition, Pointer: "/definitions/Orphan"},
+		{Kind: jsonschema.UnresolvedRef, Pointer: "/definitions/Orphan/properties/next", Ref: "#/definitions/Missing"},
+		{Kind: jsonschema.UnresolvedRef, Pointer: "/properties/broken", Ref: "#/properties/nope"},
+	}, problems)
+	assert.Equal(t, "invalid references: /$defs/Unused: unreachable definition, "+
+		"/definitions/Orphan: unreachable definition, "+
+		"/definitions/Orphan/properties/next: unresolved reference #/definitions/Missing, "+
+		"/properties/broken: unresolved reference #/properties/nope", err.Error())
+
+	_, err = jsonschema.ParseSchema(doc, func(o *jsonschema.ParseOptions) {
+		o.CheckRefs = true
+		o.AllowUnreachable = true
+	})
+
+	require.True(t, errors.As(err, &problems))
+	assert.Len(t, problems, 2)
+}
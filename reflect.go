@@ -8,9 +8,10 @@ import (
 	"fmt"
 	"path"
 	"reflect"
-	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/swaggest/refl"
@@ -90,10 +91,27 @@ func (r Ref) Schema() Schema {
 
 // Reflector creates JSON Schemas from Go values.
 type Reflector struct {
-	DefaultOptions   []func(*ReflectContext)
+	DefaultOptions []func(*ReflectContext)
+
+	// Formats is a registry of custom formats of types, see RegisterFormat, can be nil.
+	Formats *Formats
+
 	typesMap         map[reflect.Type]interface{}
 	inlineDefinition map[refl.TypeString]bool
 	defNameTypes     map[string]reflect.Type
+	namedDefinitions map[string]Schema
+	constraints      map[reflect.Type][]func(s *Schema)
+	presets          map[string][]func(s *Schema)
+	stringers        map[reflect.Type]bool
+	optionals        map[string]string
+	mixins           map[reflect.Type][]mixin
+	enumSources      map[string]func() []interface{}
+	baseSchemas      []baseSchema
+	baseTypes        map[reflect.Type]bool
+	cache            *reflectCache
+
+	// mu protects defNameTypes that are shared between concurrent Reflect calls.
+	mu sync.Mutex
 }
 
 // AddTypeMapping creates substitution link between types of src and dst when reflecting JSON Schema.
@@ -118,6 +136,17 @@ func (r *Reflector) InlineDefinition(sample interface{}) {
 	r.inlineDefinition[refl.GoType(refl.DeepIndirect(reflect.TypeOf(sample)))] = true
 }
 
+// AddDefinition registers a named schema that can be referenced with `refer:"Name"` field tag.
+//
+// Registered definition is added to resulting schema only if it is referenced.
+func (r *Reflector) AddDefinition(name string, schema Schema) {
+	if r.namedDefinitions == nil {
+		r.namedDefinitions = map[string]Schema{}
+	}
+
+	r.namedDefinitions[name] = schema
+}
+
 // InterceptDefName allows modifying reflected definition names.
 //
 // Deprecated: add jsonschema.InterceptDefName to DefaultOptions.
@@ -219,6 +248,22 @@ func checkSchemaSetup(params InterceptSchemaParams) (bool, error) {
 //     https://json-schema.org/draft-04/json-schema-validation.html#rfc.section.5.5.1
 //   - `required`, boolean, marks property as required
 //   - `nullable`, boolean, overrides nullability of a property
+//   - `sensitive`, boolean, marks property as sensitive, see SensitiveFields
+//   - `group` (or `section`), name of property group for form layout, emitted as `x-group`
+//   - `accept`, replaces reflected property schema with special one: `null` (only null), `any` (empty schema)
+//     or `nothing` (`{"not":{}}`)
+//   - `propertyNames`, regular expression or JSON schema of map keys, `patternProperties`, regular expression
+//     of map keys that replaces `additionalProperties` of map values, see also KeyNamesExposer
+//   - `valuesSchemaRef`, definition name to reference as schema of map values, e.g. for json.RawMessage values
+//   - `items` prefix applies a tag to items schema of array, e.g. `itemsMinimum:"0"` or `itemsPattern:"^[a-z]+$"`
+//   - `additionalProperties` prefix applies a tag to schema of map values, e.g. `additionalPropertiesMinLength:"1"`
+//   - `types`, comma-separated list of JSON types that replaces reflected type, e.g. `types:"string,integer"`
+//   - `preset`, comma-separated names of constraint presets registered with Reflector.RegisterPreset
+//   - `refer`, definition name to reference instead of reflecting field type, the definition has to be
+//     registered with Reflector.AddDefinition or to be created during reflection
+//   - `jsonschema`, `[name,]include[,readOnly|writeOnly]` documents a field that is excluded with `json:"-"`,
+//     e.g. a property produced by custom MarshalJSON, embedded struct field can have `jsonschema:"allOf"`
+//     or `jsonschema:"flatten"` to force composition or flattening
 //
 // Unnamed fields can be used to configure parent schema:
 //
@@ -247,6 +292,7 @@ func checkSchemaSetup(params InterceptSchemaParams) (bool, error) {
 // Available options:
 //
 //		CollectDefinitions
+//		CollectDefinitionsOrdered
 //		DefinitionsPrefix
 //		PropertyNameTag
 //		InterceptNullability
@@ -271,10 +317,9 @@ func (r *Reflector) Reflect(i interface{}, options ...func(rc *ReflectContext))
 	rc.DefinitionsPrefix = "#/definitions/"
 	rc.PropertyNameTag = "json"
 	rc.Path = []string{"#"}
+	rc.reflectState = &reflectState{}
 	rc.typeCycles = make(map[refl.TypeString]*Schema)
 
-	InterceptSchema(checkSchemaSetup)(&rc)
-
 	for _, option := range r.DefaultOptions {
 		option(&rc)
 	}
@@ -284,13 +329,82 @@ func (r *Reflector) Reflect(i interface{}, options ...func(rc *ReflectContext))
 	}
 
 	rc.deprecatedFallback()
+	rc.applyComponentsRefs()
+
+	cacheKey := reflectCacheKey{t: reflect.TypeOf(i), key: rc.CacheKey}
+	cached := r.cache != nil && (len(options) == 0 || rc.CacheKey != "") &&
+		!rc.collectsDefinitions() && rc.Report == nil && isZeroSample(i)
+
+	if cached {
+		if s, ok := r.cache.load(cacheKey); ok {
+			return s, nil
+		}
+	}
 
 	schema, err := r.reflect(i, &rc, false, nil)
-	if err == nil && len(rc.definitions) > 0 {
+	if err == nil {
+		err = r.addReferredDefinitions(&rc)
+	}
+
+	if err == nil {
+		err = rc.applyFixtureExamples(&schema)
+	}
+
+	if err == nil && rc.KeywordsOrder != 0 {
+		rc.orderKeywords(&schema)
+	}
+
+	if err == nil && rc.PropertiesOrder != 0 {
+		rc.orderProperties(&schema)
+	}
+
+	if err == nil && !rc.KeepNamedExamples {
+		dropNamedExamples(&schema)
+
+		for _, def := range rc.definitions {
+			dropNamedExamples(def)
+		}
+	}
+
+	if err == nil && rc.DraftVersion == DraftVersion04 {
+		toDraft04(&schema)
+		schema.WithSchema(Draft04Schema)
+
+		for _, def := range rc.definitions {
+			toDraft04(def)
+		}
+	}
+
+	if err == nil && rc.DraftVersion == DraftVersionOpenAPI30 {
+		toOpenAPI30(&schema)
+
+		for _, def := range rc.definitions {
+			toOpenAPI30(def)
+		}
+	}
+
+	if err == nil && rc.DraftVersion == DraftVersion2020 {
+		toDraft2020(&schema)
+		schema.WithSchema(Draft2020Schema)
+
+		for _, def := range rc.definitions {
+			toDraft2020(def)
+		}
+	}
+
+	if err == nil && rc.AJVStrict {
+		err = rc.applyAJVStrict(&schema)
+	}
+
+	if err == nil && rc.CollectDefinitionsOrdered != nil {
+		rc.deliverOrderedDefinitions(reflect.TypeOf(i))
+	} else if err == nil && rc.CollectDefinitionsWithRoot != nil {
+		rc.deliverDefinitionsWithRoot(reflect.TypeOf(i))
+	} else if err == nil && len(rc.definitions) > 0 {
 		schema.Definitions = make(map[string]SchemaOrBool, len(rc.definitions))
 
-		for typeString, def := range rc.definitions {
-			def := def
+		for _, typeString := range rc.definitionTypes() {
+			def := rc.definitions[typeString]
 			ref := rc.definitionRefs[typeString]
 
 			if rc.CollectDefinitions != nil {
@@ -301,9 +415,54 @@ func (r *Reflector) Reflect(i interface{}, options ...func(rc *ReflectContext))
 		}
 	}
 
+	if err == nil && rc.DraftVersion == DraftVersion2020 && schema.Definitions != nil {
+		schema.WithExtraPropertiesItem("$defs", schema.Definitions)
+		schema.Definitions = nil
+	}
+
+	if err == nil && len(rc.fieldErrors) > 0 {
+		err = rc.fieldErrors
+	}
+
+	rc.finishReport(err)
+
+	if cached && err == nil {
+		r.cache.store(cacheKey, schema)
+	}
+
 	return schema, err
 }
 
+// addReferredDefinitions checks definitions referenced by name with `refer` field tag
+// and adds registered definitions that were referenced.
+func (r *Reflector) addReferredDefinitions(rc *ReflectContext) error {
+	for _, name := range rc.referredDefs {
+		if _, found := rc.refTypes[rc.DefinitionsPrefix+name]; found {
+			continue
+		}
+
+		if def, ok := r.namedDefinitions[name]; ok {
+			if rc.definitions == nil {
+				rc.definitions = make(map[refl.TypeString]*Schema, 1)
+			}
+
+			typeString := refl.TypeString("refer." + name)
+			rc.definitions[typeString] = &def
+			rc.definitionsOrder = append(rc.definitionsOrder, typeString)
+			rc.setDefinitionRef(typeString, Ref{Path: rc.DefinitionsPrefix, Name: name})
+
+			continue
+		}
+
+		// Definitions that are collected externally may be available elsewhere.
+		if !rc.collectsDefinitions() {
+			return fmt.Errorf("referred definition not found: %s", name)
+		}
+	}
+
+	return nil
+}
+
 func removeNull(t *Type) {
 	if t.SimpleTypes != nil && *t.SimpleTypes == Null {
 		t.SimpleTypes = nil
@@ -332,11 +491,14 @@ func (r *Reflector) reflectDefer(defName string, typeString refl.TypeString, rc
 		return schema
 	}
 
-	if rc.InlineRefs {
+	// Constrained types keep definitions to have constraints in a single place.
+	byRef := rc.ConstraintsByRef && defName != "" && r.hasConstraints(schema.ReflectType)
+
+	if rc.InlineRefs && !byRef {
 		return schema
 	}
 
-	if r.inlineDefinition[typeString] {
+	if (r.inlineDefinition[typeString] || rc.hiddenDefs[typeString]) && !byRef {
 		return schema
 	}
 
@@ -355,18 +517,35 @@ func (r *Reflector) reflectDefer(defName string, typeString refl.TypeString, rc
 	}
 
 	// Inlining trivial scalar schemas.
-	if schema.IsTrivial() && schema.Type != nil && !schema.HasType(Object) && !schema.HasType(Array) {
+	if schema.IsTrivial() && schema.Type != nil && !schema.HasType(Object) && !schema.HasType(Array) && !byRef {
+		return schema
+	}
+
+	if rc.InlineScalarKeywords > 0 && isInlineScalar(schema, rc.InlineScalarKeywords) && !byRef {
 		return schema
 	}
 
 	if rc.definitions == nil {
 		rc.definitions = make(map[refl.TypeString]*Schema, 1)
-		rc.definitionRefs = make(map[refl.TypeString]Ref, 1)
+	}
+
+	if _, ok := rc.definitions[typeString]; !ok {
+		rc.definitionsOrder = append(rc.definitionsOrder, typeString)
+	}
+
+	if rc.GoTypeAnnotations && schema.ReflectType != nil {
+		schema.WithExtraPropertiesItem(XGoType, goTypeString(refl.DeepIndirect(schema.ReflectType)))
+	}
+
+	if rc.SourceIndex != nil && schema.ReflectType != nil {
+		if pos, ok := rc.SourceIndex.TypePosition(refl.DeepIndirect(schema.ReflectType)); ok {
+			schema.WithExtraPropertiesItem(XGoSource, pos.String())
+		}
 	}
 
 	rc.definitions[typeString] = &schema
 	ref := Ref{Path: rc.DefinitionsPrefix, Name: defName}
-	rc.definitionRefs[typeString] = ref
+	rc.setDefinitionRef(typeString, ref)
 
 	s := ref.Schema()
 
@@ -379,6 +558,34 @@ func (r *Reflector) reflectDefer(defName string, typeString refl.TypeString, rc
 	return s
 }
 
+// isInlineScalar checks if schema is scalar without subschemas and has up to maxKeywords keywords besides type.
+func isInlineScalar(schema Schema, maxKeywords int) bool {
+	if schema.Type == nil || schema.HasType(Object) || schema.HasType(Array) {
+		return false
+	}
+
+	hasSubschemas := false
+
+	walkSchemas(&schema, func(s *Schema) {
+		if s != &schema {
+			hasSubschemas = true
+		}
+	})
+
+	if hasSubschemas {
+		return false
+	}
+
+	m, err := schema.ToSchemaOrBool().ToSimpleMap()
+	if err != nil {
+		return false
+	}
+
+	delete(m, "type")
+
+	return len(m) <= maxKeywords
+}
+
 func (r *Reflector) checkTitle(v reflect.Value, s *Struct, schema *Schema) {
 	if vd, ok := safeInterface(v).(Described); ok {
 		schema.WithDescription(vd.Description())
@@ -408,13 +615,24 @@ func (r *Reflector) reflect(i interface{}, rc *ReflectContext, keepType bool, pa
 		s          *Struct
 		typeString refl.TypeString
 		defName    string
+		restore    func()
 	)
 
 	if st, ok := i.(withStruct); ok {
 		s = st.structPtr()
 	}
 
+	if p, ok := r.optionalValue(t, v); ok && s == nil {
+		i = p.Interface()
+		t = p.Type()
+		v = p
+	}
+
 	defer func() {
+		if restore != nil {
+			defer restore()
+		}
+
 		rc.Path = rc.Path[:len(rc.Path)-1]
 
 		if t == nil {
@@ -447,13 +665,23 @@ func (r *Reflector) reflect(i interface{}, rc *ReflectContext, keepType bool, pa
 		return schema, nil
 	}
 
+	constrainedType := t
+	restore = rc.enterPackage(t)
+
 	typeString = refl.GoType(t)
 	defName = r.defName(rc, t)
 
+	rc.reportType(t)
+
 	if s != nil {
 		defName, typeString = s.names()
 	}
 
+	if rc.HoistAnonymousStructs && defName == "" && s == nil && t.Kind() == reflect.Struct && t.Name() == "" &&
+		len(rc.Path) > 1 {
+		defName = r.anonymousDefName(rc, t, typeString)
+	}
+
 	if mappedTo, found := r.typesMap[t]; found && s == nil {
 		t = refl.DeepIndirect(reflect.TypeOf(mappedTo))
 		v = reflect.ValueOf(mappedTo)
@@ -464,6 +692,17 @@ func (r *Reflector) reflect(i interface{}, rc *ReflectContext, keepType bool, pa
 		}
 	}
 
+	if rc.HideDefinition != nil && defName != "" && rc.HideDefinition(t) {
+		if rc.hiddenDefs == nil {
+			rc.hiddenDefs = map[refl.TypeString]bool{}
+			rc.hiddenInProgress = map[refl.TypeString]bool{}
+		}
+
+		if _, seen := rc.hiddenDefs[typeString]; !seen {
+			rc.hiddenDefs[typeString] = true
+		}
+	}
+
 	if len(rc.Path) == 1 {
 		rc.rootDefName = defName
 	}
@@ -477,28 +716,38 @@ func (r *Reflector) reflect(i interface{}, rc *ReflectContext, keepType bool, pa
 
 	sp := &schema
 
-	if rc.interceptSchema != nil {
-		if ret, err := rc.interceptSchema(InterceptSchemaParams{
-			Context:   rc,
-			Value:     v,
-			Schema:    sp,
-			Processed: false,
-		}); err != nil || ret {
-			return schema, err
-		}
+	if ret, err := rc.callInterceptSchema(InterceptSchemaParams{
+		Context:   rc,
+		Value:     v,
+		Schema:    sp,
+		Processed: false,
+	}); err != nil || ret {
+		return schema, err
 	}
 
-	if r.isWellKnownType(t, sp) {
+	if r.isWellKnownType(t, sp, rc) {
 		return schema, nil
 	}
 
-	isTextMarshaler := checkTextMarshaler(t, &schema)
+	isTextMarshaler := r.checkTypeFormat(t, &schema) || checkTextMarshaler(t, &schema) ||
+		r.checkStringer(t, &schema)
 
 	if def, ok := rc.definitions[typeString]; ok && defName != "" {
 		return *def, nil
 	}
 
-	if rc.typeCycles[typeString] != nil && !rc.InlineRefs {
+	if rc.hiddenDefs[typeString] {
+		if rc.hiddenInProgress[typeString] {
+			// Recursive type can not be inlined, so it keeps its definition.
+			rc.hiddenDefs[typeString] = false
+		} else {
+			rc.hiddenInProgress[typeString] = true
+
+			defer delete(rc.hiddenInProgress, typeString)
+		}
+	}
+
+	if rc.typeCycles[typeString] != nil && !rc.InlineRefs && !rc.hiddenDefs[typeString] {
 		return *rc.typeCycles[typeString], nil
 	}
 
@@ -506,6 +755,7 @@ func (r *Reflector) reflect(i interface{}, rc *ReflectContext, keepType bool, pa
 		rc.typeCycles[typeString] = sp
 	}
 
+	reflectTypeComments(constrainedType, sp, rc)
 	r.checkTitle(v, s, sp)
 
 	if err := r.applySubSchemas(v, rc, sp); err != nil {
@@ -518,15 +768,20 @@ func (r *Reflector) reflect(i interface{}, rc *ReflectContext, keepType bool, pa
 		}
 	}
 
-	if rc.interceptSchema != nil {
-		if ret, err := rc.interceptSchema(InterceptSchemaParams{
-			Context:   rc,
-			Value:     v,
-			Schema:    sp,
-			Processed: true,
-		}); err != nil || ret {
-			return schema, err
-		}
+	r.applyConstraints(constrainedType, sp)
+	reflectConstantsEnum(constrainedType, sp, rc)
+
+	if rc.EnumOneOfConst {
+		enumOneOfConst(sp)
+	}
+
+	if ret, err := rc.callInterceptSchema(InterceptSchemaParams{
+		Context:   rc,
+		Value:     v,
+		Schema:    sp,
+		Processed: true,
+	}); err != nil || ret {
+		return schema, err
 	}
 
 	if preparer, ok := safeInterface(v).(Preparer); ok {
@@ -542,6 +797,123 @@ func (r *Reflector) reflect(i interface{}, rc *ReflectContext, keepType bool, pa
 	return schema, nil
 }
 
+// reflectMapValues sets up additionalProperties from map value type.
+//
+// Map entries are used as samples in order of keys, so that values that implement Exposer or RawExposer
+// are reflected same way as struct fields. If value type is a non-empty interface (e.g. Exposer)
+// and entries have different dynamic types, additionalProperties is an anyOf of their schemas.
+func (r *Reflector) reflectMapValues(t reflect.Type, v reflect.Value, schema *Schema, rc *ReflectContext) error {
+	elemType := t.Elem()
+	itemValue := reflect.Zero(elemType).Interface()
+
+	if itemValue == nil && elemType != typeOfEmptyInterface {
+		itemValue = reflect.New(elemType).Interface()
+	}
+
+	samples := []interface{}{itemValue}
+
+	for v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+
+	if v.Kind() == reflect.Map && v.Len() > 0 {
+		samples = mapSamples(v)
+	}
+
+	schema.AddType(Object)
+	reflectMapKeys(t, schema)
+
+	var (
+		variants []SchemaOrBool
+		elem     InterceptElemParams
+	)
+
+	for i, sample := range samples {
+		rc.enterPath("{}", "*", "additionalProperties")
+
+		if i == 0 {
+			elem = rc.elemParams(elemType, schema)
+		}
+
+		additionalPropertiesSchema, err := r.reflect(sample, rc, false, schema)
+		if err != nil {
+			return err
+		}
+
+		variants = append(variants, additionalPropertiesSchema.ToSchemaOrBool())
+	}
+
+	additionalProperties := (&Schema{}).WithAnyOf(variants...)
+	if len(variants) == 1 {
+		additionalProperties = variants[0].TypeObject
+	}
+
+	if rc.InterceptAdditionalProperties != nil {
+		elem.Schema = additionalProperties
+
+		if err := rc.InterceptAdditionalProperties(elem); err != nil {
+			return err
+		}
+	}
+
+	schema.WithAdditionalProperties(additionalProperties.ToSchemaOrBool())
+
+	return nil
+}
+
+// elemParams captures location of array item or map value that is entered in path.
+func (rc *ReflectContext) elemParams(t reflect.Type, parent *Schema) InterceptElemParams {
+	if rc.InterceptItems == nil && rc.InterceptAdditionalProperties == nil {
+		return InterceptElemParams{}
+	}
+
+	return InterceptElemParams{
+		Context:      rc,
+		Path:         append([]string(nil), rc.Path...),
+		InstancePath: rc.InstancePointer(),
+		SchemaPath:   rc.SchemaPointer(),
+		Type:         t,
+		ParentSchema: parent,
+	}
+}
+
+// mapSamples returns first map value in order of keys, for values of non-empty interface type
+// first value of each dynamic type is returned.
+func mapSamples(v reflect.Value) []interface{} {
+	keys := v.MapKeys()
+
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+	})
+
+	if elemType := v.Type().Elem(); elemType.Kind() != reflect.Interface || elemType.NumMethod() == 0 {
+		return []interface{}{v.MapIndex(keys[0]).Interface()}
+	}
+
+	var (
+		samples []interface{}
+		seen    = map[reflect.Type]bool{}
+	)
+
+	for _, k := range keys {
+		item := v.MapIndex(k).Interface()
+		if item == nil {
+			continue
+		}
+
+		if t := reflect.TypeOf(item); !seen[t] {
+			seen[t] = true
+			samples = append(samples, item)
+		}
+	}
+
+	if len(samples) == 0 {
+		samples = append(samples, v.MapIndex(keys[0]).Interface())
+	}
+
+	return samples
+}
+
 func checkTextMarshaler(t reflect.Type, schema *Schema) bool {
 	if (t.Implements(typeOfTextUnmarshaler) || reflect.PtrTo(t).Implements(typeOfTextUnmarshaler)) &&
 		(t.Implements(typeOfTextMarshaler) || reflect.PtrTo(t).Implements(typeOfTextMarshaler)) {
@@ -593,8 +965,8 @@ func (r *Reflector) applySubSchemas(v reflect.Value, rc *ReflectContext, schema
 	if oe != nil {
 		var schemas []SchemaOrBool
 
-		for _, item := range oe.JSONSchemaOneOf() {
-			rc.Path = append(rc.Path, "oneOf")
+		for i, item := range oe.JSONSchemaOneOf() {
+			rc.enterPath("oneOf", "", "oneOf/"+strconv.Itoa(i))
 
 			s, err := r.reflect(item, rc, false, schema)
 			if err != nil {
@@ -605,6 +977,7 @@ func (r *Reflector) applySubSchemas(v reflect.Value, rc *ReflectContext, schema
 		}
 
 		schema.OneOf = schemas
+		reflectDiscriminator(schema, rc)
 	}
 
 	var ane AnyOfExposer
@@ -617,8 +990,8 @@ func (r *Reflector) applySubSchemas(v reflect.Value, rc *ReflectContext, schema
 	if ane != nil {
 		var schemas []SchemaOrBool
 
-		for _, item := range ane.JSONSchemaAnyOf() {
-			rc.Path = append(rc.Path, "anyOf")
+		for i, item := range ane.JSONSchemaAnyOf() {
+			rc.enterPath("anyOf", "", "anyOf/"+strconv.Itoa(i))
 
 			s, err := r.reflect(item, rc, false, schema)
 			if err != nil {
@@ -641,8 +1014,8 @@ func (r *Reflector) applySubSchemas(v reflect.Value, rc *ReflectContext, schema
 	if ale != nil {
 		var schemas []SchemaOrBool
 
-		for _, item := range ale.JSONSchemaAllOf() {
-			rc.Path = append(rc.Path, "allOf")
+		for i, item := range ale.JSONSchemaAllOf() {
+			rc.enterPath("allOf", "", "allOf/"+strconv.Itoa(i))
 
 			s, err := r.reflect(item, rc, false, schema)
 			if err != nil {
@@ -663,7 +1036,7 @@ func (r *Reflector) applySubSchemas(v reflect.Value, rc *ReflectContext, schema
 	}
 
 	if ne != nil {
-		rc.Path = append(rc.Path, "not")
+		rc.enterPath("not", "", "not")
 
 		s, err := r.reflect(ne.JSONSchemaNot(), rc, false, schema)
 		if err != nil {
@@ -681,7 +1054,7 @@ func (r *Reflector) applySubSchemas(v reflect.Value, rc *ReflectContext, schema
 	}
 
 	if ie != nil {
-		rc.Path = append(rc.Path, "if")
+		rc.enterPath("if", "", "if")
 
 		s, err := r.reflect(ie.JSONSchemaIf(), rc, false, schema)
 		if err != nil {
@@ -699,7 +1072,7 @@ func (r *Reflector) applySubSchemas(v reflect.Value, rc *ReflectContext, schema
 	}
 
 	if te != nil {
-		rc.Path = append(rc.Path, "if")
+		rc.enterPath("if", "", "then")
 
 		s, err := r.reflect(te.JSONSchemaThen(), rc, false, schema)
 		if err != nil {
@@ -717,7 +1090,7 @@ func (r *Reflector) applySubSchemas(v reflect.Value, rc *ReflectContext, schema
 	}
 
 	if ee != nil {
-		rc.Path = append(rc.Path, "if")
+		rc.enterPath("if", "", "else")
 
 		s, err := r.reflect(ee.JSONSchemaElse(), rc, false, schema)
 		if err != nil {
@@ -730,10 +1103,9 @@ func (r *Reflector) applySubSchemas(v reflect.Value, rc *ReflectContext, schema
 	return nil
 }
 
-func (r *Reflector) isWellKnownType(t reflect.Type, schema *Schema) bool {
+func (r *Reflector) isWellKnownType(t reflect.Type, schema *Schema, rc *ReflectContext) bool {
 	if t == typeOfTime {
-		schema.AddType(String)
-		schema.WithFormat("date-time")
+		timeSchema(schema, rc.TimeEncoding)
 
 		return true
 	}
@@ -748,7 +1120,105 @@ func (r *Reflector) isWellKnownType(t reflect.Type, schema *Schema) bool {
 	return false
 }
 
-var baseNameRegex = regexp.MustCompile(`\[(.+\/)*([^\/]+)·\d+\]`)
+// genericTypeName simplifies names of type arguments of generic type name with package base names,
+// e.g. "Tree[github.com/acme/app.item·1]" becomes "Tree[app.item]", nested type arguments are also simplified,
+// e.g. "Tree[github.com/acme/app.Tree[github.com/acme/app.item·1]·2]" becomes "Tree[app.Tree[app.item]]".
+func genericTypeName(name string) string {
+	// Composite type prefixes, e.g. "[]*" or "map[K]", are kept before element type.
+	switch {
+	case strings.HasPrefix(name, "*"):
+		return "*" + genericTypeName(name[1:])
+	case strings.HasPrefix(name, "["), strings.HasPrefix(name, "map["):
+		open := strings.Index(name, "[")
+
+		if end := closingBracket(name, open); end > 0 {
+			if open == 0 {
+				return name[:end+1] + genericTypeName(name[end+1:])
+			}
+
+			return "map[" + genericTypeName(name[open+1:end]) + "]" + genericTypeName(name[end+1:])
+		}
+	}
+
+	i := strings.Index(name, "[")
+	if i < 0 {
+		return shortTypeName(name)
+	}
+
+	end := closingBracket(name, i)
+	if end < 0 {
+		return shortTypeName(name)
+	}
+
+	args := splitTypeArgs(name[i+1 : end])
+	for j, a := range args {
+		args[j] = genericTypeName(a)
+	}
+
+	// Index of function-local generic type follows type arguments, e.g. "app.Tree[app.item·1]·2".
+	return shortTypeName(name[:i]+name[end+1:]) + "[" + strings.Join(args, ",") + "]"
+}
+
+// closingBracket returns index of bracket that closes bracket at open index, or -1.
+func closingBracket(s string, open int) int {
+	depth := 0
+
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+// shortTypeName removes package path (except last element) and index of function-local type from type name,
+// e.g. "github.com/acme/app.item·1" becomes "app.item", same as package of definition name.
+func shortTypeName(name string) string {
+	if k := strings.Index(name, "·"); k >= 0 {
+		name = name[:k]
+	}
+
+	if p := strings.LastIndex(name, "/"); p >= 0 {
+		// Package path starts after composite type prefix, e.g. "[]*".
+		start := strings.LastIndexAny(name[:p], "]*") + 1
+		name = name[:start] + name[p+1:]
+	}
+
+	return name
+}
+
+// splitTypeArgs splits comma-separated type arguments, nested brackets are respected.
+func splitTypeArgs(s string) []string {
+	var (
+		res   []string
+		depth int
+		start int
+	)
+
+	for i, c := range s {
+		switch c {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				res = append(res, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+
+	return append(res, s[start:])
+}
 
 func (r *Reflector) defName(rc *ReflectContext, t reflect.Type) string {
 	if t.PkgPath() == "" || t == typeOfTime || t == typeOfJSONRawMsg || t == typeOfDate {
@@ -763,6 +1233,9 @@ func (r *Reflector) defName(rc *ReflectContext, t reflect.Type) string {
 		return ""
 	}
 
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	if r.defNameTypes == nil {
 		r.defNameTypes = map[string]reflect.Type{}
 	}
@@ -773,7 +1246,14 @@ func (r *Reflector) defName(rc *ReflectContext, t reflect.Type) string {
 
 	for {
 		tn := t.Name()
-		tn = baseNameRegex.ReplaceAllString(tn, "[$2]")
+
+		var typeArgs []string
+
+		if rc.GenericDefName != nil {
+			tn, typeArgs = splitGenericName(tn)
+		}
+
+		tn = genericTypeName(tn)
 
 		if t.PkgPath() == "main" {
 			defName = toCamel(strings.Title(tn))
@@ -781,6 +1261,15 @@ func (r *Reflector) defName(rc *ReflectContext, t reflect.Type) string {
 			defName = toCamel(path.Base(t.PkgPath()) + strings.Title(tn))
 		}
 
+		if len(typeArgs) > 0 {
+			names := make([]string, 0, len(typeArgs))
+			for _, a := range typeArgs {
+				names = append(names, genericArgName(a, rc.GenericDefName))
+			}
+
+			defName = rc.GenericDefName(defName, names)
+		}
+
 		if rc.DefName != nil {
 			defName = rc.DefName(t, defName)
 		}
@@ -809,6 +1298,55 @@ func (r *Reflector) defName(rc *ReflectContext, t reflect.Type) string {
 	}
 }
 
+// anonymousDefName makes definition name for anonymous struct from names of parent type and properties,
+// e.g. UserAddressInline1.
+func (r *Reflector) anonymousDefName(rc *ReflectContext, t reflect.Type, typeString refl.TypeString) string {
+	if ref, ok := rc.definitionRefs[typeString]; ok {
+		return ref.Name
+	}
+
+	base := rc.rootDefName
+
+	for _, p := range rc.Path[1:] {
+		switch p {
+		case "[]":
+			base += "Item"
+		case "{}":
+			base += "Value"
+		default:
+			base += toCamel(p)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.defNameTypes == nil {
+		r.defNameTypes = map[string]reflect.Type{}
+	}
+
+	for try := 1; ; try++ {
+		defName := base + "Inline" + strconv.Itoa(try)
+
+		if rc.AnonymousStructName != nil {
+			defName = rc.AnonymousStructName(t, rc.Path, defName)
+			if defName == "" {
+				return ""
+			}
+
+			if try > 1 {
+				defName += "Type" + strconv.Itoa(try)
+			}
+		}
+
+		if tt, ok := r.defNameTypes[defName]; !ok || tt == t {
+			r.defNameTypes[defName] = t
+
+			return defName
+		}
+	}
+}
+
 func (r *Reflector) kindSwitch(t reflect.Type, v reflect.Value, schema *Schema, rc *ReflectContext) error {
 	//nolint:exhaustive // Covered with default case.
 	switch t.Kind() {
@@ -824,6 +1362,16 @@ func (r *Reflector) kindSwitch(t reflect.Type, v reflect.Value, schema *Schema,
 			if err != nil {
 				return err
 			}
+
+			err = r.applyMixins(t, schema, rc)
+			if err != nil {
+				return err
+			}
+
+			err = r.extendBaseSchema(t, schema, rc)
+			if err != nil {
+				return err
+			}
 		}
 
 	case reflect.Slice, reflect.Array:
@@ -833,7 +1381,8 @@ func (r *Reflector) kindSwitch(t reflect.Type, v reflect.Value, schema *Schema,
 
 		elemType := t.Elem()
 
-		rc.Path = append(rc.Path, "[]")
+		rc.enterPath("[]", "*", "items")
+		elem := rc.elemParams(elemType, schema)
 		itemValue := reflect.Zero(elemType).Interface()
 
 		if itemValue == nil && elemType != typeOfEmptyInterface {
@@ -853,40 +1402,22 @@ func (r *Reflector) kindSwitch(t reflect.Type, v reflect.Value, schema *Schema,
 			return err
 		}
 
+		if rc.InterceptItems != nil {
+			elem.Schema = &itemsSchema
+
+			if err := rc.InterceptItems(elem); err != nil {
+				return err
+			}
+		}
+
 		schema.AddType(Array)
 		schema.WithItems(*(&Items{}).WithSchemaOrBool(itemsSchema.ToSchemaOrBool()))
 
 	case reflect.Map:
-		elemType := t.Elem()
-
-		rc.Path = append(rc.Path, "{}")
-		itemValue := reflect.Zero(elemType).Interface()
-
-		if itemValue == nil && elemType != typeOfEmptyInterface {
-			itemValue = reflect.New(elemType).Interface()
+		if err := r.reflectMapValues(t, v, schema, rc); err != nil {
+			return err
 		}
 
-		for v.Kind() == reflect.Ptr {
-			v = v.Elem()
-		}
-
-		if v.Kind() == reflect.Map {
-			rng := v.MapRange()
-			for rng.Next() {
-				itemValue = rng.Value().Interface()
-
-				break
-			}
-		}
-
-		additionalPropertiesSchema, err := r.reflect(itemValue, rc, false, schema)
-		if err != nil {
-			return err
-		}
-
-		schema.AddType(Object)
-		schema.WithAdditionalProperties(additionalPropertiesSchema.ToSchemaOrBool())
-
 	case reflect.Bool:
 		schema.AddType(Boolean)
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
@@ -1003,189 +1534,409 @@ func (r *Reflector) makeFields(v reflect.Value) ([]reflect.StructField, []reflec
 
 func (r *Reflector) walkProperties(v reflect.Value, parent *Schema, rc *ReflectContext) error {
 	fields, values := r.makeFields(v)
+	owner := refl.DeepIndirect(v.Type())
 
 	for i, field := range fields {
-		tag, tagFound := r.propertyTag(rc, field)
+		pathLen := len(rc.Path)
+		requiredLen := len(parent.Required)
 
-		// Skip explicitly discarded field.
-		if tag == "-" {
-			continue
+		if err := r.walkProperty(field, values[i], owner, parent, rc); err != nil {
+			err = rc.fieldError(owner, field, err)
+
+			if !rc.CollectFieldErrors {
+				return err
+			}
+
+			// Failed property is not added to schema, so it can not be required.
+			parent.Required = parent.Required[:requiredLen]
+			rc.Path = rc.Path[:pathLen]
+			rc.addFieldError(owner, field, err)
 		}
+	}
 
-		deepIndirect := refl.DeepIndirect(field.Type)
+	return nil
+}
 
-		if tag == "" && field.Anonymous &&
-			(field.Type.Kind() == reflect.Struct || deepIndirect.Kind() == reflect.Struct) {
-			forceReference := (field.Type.Implements(typeOfEmbedReferencer) && field.Tag.Get("refer") == "") ||
-				field.Tag.Get("refer") == "true"
+func (r *Reflector) walkProperty(
+	field reflect.StructField, value reflect.Value, owner reflect.Type, parent *Schema, rc *ReflectContext,
+) error {
+	if rc.Overrides != nil {
+		field = rc.overrideField(owner, field)
+	}
 
-			if forceReference {
-				rc.Path = append(rc.Path, "")
+	tag, tagFound := r.propertyTag(rc, field)
+	include := includeTag(field)
 
-				s, err := r.reflect(values[i].Interface(), rc, false, parent)
-				if err != nil {
-					return err
-				}
+	// Field that is not encoded with encoding/json, but documented, e.g. produced by custom MarshalJSON.
+	if tag == "-" && include.ok {
+		tag = include.name
+	}
 
-				parent.AllOf = append(parent.AllOf, s.ToSchemaOrBool())
-			} else if err := r.walkProperties(values[i], parent, rc); err != nil {
-				return err
-			}
+	// Skip explicitly discarded field.
+	if tag == "-" {
+		rc.reportSkippedField(owner, field, SkipReasonIgnored)
 
-			continue
+		return nil
+	}
+
+	deepIndirect := refl.DeepIndirect(field.Type)
+	propName := strings.Split(tag, ",")[0]
+
+	// Embedded struct without name is flattened (or composed with allOf), named one becomes a property,
+	// as with encoding/json.
+	if propName == "" && field.Anonymous &&
+		(field.Type.Kind() == reflect.Struct || deepIndirect.Kind() == reflect.Struct) {
+		// Base schema is referenced in allOf of owner, see SetBaseSchemaFor.
+		if r.isBaseType(deepIndirect) {
+			return nil
 		}
 
-		// Use unnamed fields to configure parent schema.
-		if field.Name == "_" && (!rc.UnnamedFieldWithTag || tagFound) {
-			if err := refl.PopulateFieldsFromTags(parent, field.Tag); err != nil {
-				return err
+		forceReference := (field.Type.Implements(typeOfEmbedReferencer) && field.Tag.Get("refer") == "" &&
+			!include.flatten) || field.Tag.Get("refer") == "true" || include.allOf
+
+		if forceReference {
+			rc.enterPath("", "", "allOf/"+strconv.Itoa(len(parent.AllOf)))
+
+			ev := value
+			if !ev.CanInterface() {
+				ev = reflect.Zero(field.Type)
 			}
 
-			var additionalProperties *bool
-			if err := refl.ReadBoolPtrTag(field.Tag, "additionalProperties", &additionalProperties); err != nil {
+			s, err := r.reflect(ev.Interface(), rc, false, parent)
+			if err != nil {
 				return err
 			}
 
-			if additionalProperties != nil {
-				parent.AdditionalProperties = &SchemaOrBool{TypeBoolean: additionalProperties}
-			}
+			parent.AllOf = append(parent.AllOf, s.ToSchemaOrBool())
+		} else if err := r.walkProperties(value, parent, rc); err != nil {
+			return err
+		}
 
-			continue
+		return nil
+	}
+
+	// Use unnamed fields to configure parent schema.
+	if field.Name == "_" && (!rc.UnnamedFieldWithTag || tagFound) {
+		if err := populateFieldsFromTags(parent, field.Tag); err != nil {
+			return err
 		}
 
-		// Skip the field if tag is not set.
-		if !rc.ProcessWithoutTags && !tagFound {
-			continue
+		var additionalProperties *bool
+		if err := refl.ReadBoolPtrTag(field.Tag, "additionalProperties", &additionalProperties); err != nil {
+			return err
 		}
 
-		// Skip the field if it's non-exported.  There is field.IsExported() method, but it was introduced in go 1.17
-		// and will break backward compatibility.
-		if field.PkgPath != "" {
-			continue
+		if additionalProperties != nil {
+			parent.AdditionalProperties = &SchemaOrBool{TypeBoolean: additionalProperties}
 		}
 
-		propName := strings.Split(tag, ",")[0]
-		omitEmpty := strings.Contains(tag, ",omitempty")
-		required := false
+		return reflectConditionalTags(parent, field)
+	}
 
-		var nullable *bool
+	// Skip the field if tag is not set.
+	if !rc.ProcessWithoutTags && !tagFound {
+		rc.reportSkippedField(owner, field, SkipReasonNoTag)
 
-		if propName == "" {
-			propName = field.Name
-		}
+		return nil
+	}
 
-		if err := refl.ReadBoolTag(field.Tag, "required", &required); err != nil {
-			return err
-		}
+	// Skip the field if it's non-exported.  There is field.IsExported() method, but it was introduced in go 1.17
+	// and will break backward compatibility.
+	// Named embedded struct of non-exported type is encoded by encoding/json.
+	if field.PkgPath != "" && (!field.Anonymous || propName == "" || deepIndirect.Kind() != reflect.Struct) {
+		rc.reportSkippedField(owner, field, SkipReasonUnexported)
 
-		if err := refl.ReadBoolPtrTag(field.Tag, "nullable", &nullable); err != nil {
-			return err
-		}
+		return nil
+	}
 
-		if required {
-			parent.Required = append(parent.Required, propName)
-		}
+	omitEmpty := strings.Contains(tag, ",omitempty")
+	required := false
 
-		ft := field.Type
-		fieldVal := r.fieldVal(values[i], ft)
+	var nullable *bool
 
-		rc.Path = append(rc.Path, propName)
+	if propName == "" {
+		propName = field.Name
+	}
 
-		if rc.interceptProp != nil {
-			if err := rc.interceptProp(InterceptPropParams{
-				Context:      rc,
-				Path:         rc.Path,
-				Name:         propName,
-				Field:        field,
-				ParentSchema: parent,
-			}); err != nil {
-				if errors.Is(err, ErrSkipProperty) {
-					rc.Path = rc.Path[:len(rc.Path)-1]
+	if rc.FieldEnabled != nil && !rc.FieldEnabled(field, append(rc.Path[:len(rc.Path):len(rc.Path)], propName)) {
+		rc.reportSkippedField(owner, field, SkipReasonDisabled)
 
-					continue
-				}
+		return nil
+	}
 
-				return err
+	sensitive := false
+	if err := refl.ReadBoolTag(field.Tag, "sensitive", &sensitive); err != nil {
+		return err
+	}
+
+	if sensitive && rc.Sensitive == SensitiveDrop {
+		rc.reportSkippedField(owner, field, SkipReasonSensitive)
+
+		return nil
+	}
+
+	if err := refl.ReadBoolTag(field.Tag, "required", &required); err != nil {
+		return err
+	}
+
+	if err := refl.ReadBoolPtrTag(field.Tag, "nullable", &nullable); err != nil {
+		return err
+	}
+
+	if required {
+		parent.Required = append(parent.Required, propName)
+	}
+
+	ft := field.Type
+	fv := value
+
+	if !fv.CanInterface() {
+		fv = reflect.Zero(ft)
+	}
+
+	fieldVal := r.fieldVal(fv, ft)
+
+	rc.enterPath(propName, escapePointerToken(propName), "properties/"+escapePointerToken(propName))
+
+	instancePath, schemaPath := rc.InstancePointer(), rc.SchemaPointer()
+
+	if rc.interceptProp != nil {
+		if err := rc.interceptProp(InterceptPropParams{
+			Context:      rc,
+			Path:         rc.Path,
+			Name:         propName,
+			Field:        field,
+			ParentSchema: parent,
+			OwnerType:    owner,
+			InstancePath: instancePath,
+			SchemaPath:   schemaPath,
+		}); err != nil {
+			if errors.Is(err, ErrSkipProperty) {
+				rc.Path = rc.Path[:len(rc.Path)-1]
+				rc.reportSkippedField(owner, field, SkipReasonIntercepted)
+
+				return nil
 			}
+
+			return err
+		}
+	}
+
+	var (
+		propertySchema Schema
+		err            error
+	)
+
+	accept, accepts := field.Tag.Lookup("accept")
+
+	if accepts {
+		propertySchema, err = acceptSchema(accept, field)
+		if err != nil {
+			return err
 		}
 
-		propertySchema, err := r.reflect(fieldVal, rc, true, parent)
+		propertySchema.ReflectType = ft
+		rc.Path = rc.Path[:len(rc.Path)-1]
+	} else if referName := field.Tag.Get("refer"); referName != "" && referName != "true" && referName != "false" {
+		propertySchema = Ref{Path: rc.DefinitionsPrefix, Name: referName}.Schema()
+		propertySchema.ReflectType = ft
+		rc.referredDefs = append(rc.referredDefs, referName)
+		rc.Path = rc.Path[:len(rc.Path)-1]
+	} else {
+		propertySchema, err = r.reflect(fieldVal, rc, true, parent)
 		if err != nil {
 			if errors.Is(err, ErrSkipProperty) {
-				continue
+				rc.reportSkippedField(owner, field, SkipReasonUnsupported)
+
+				return nil
 			}
 
 			return err
 		}
+	}
+
+	if !accepts {
+		if of, ok := r.optionalField(ft); ok {
+			// Optional wrapper is nullable like a pointer to its value.
+			ft = reflect.PtrTo(of.Type)
+		}
 
 		checkNullability(&propertySchema, rc, ft, omitEmpty, nullable)
+	}
 
-		if !rc.SkipNonConstraints {
-			err = checkInlineValue(&propertySchema, field, "default", propertySchema.WithDefault)
-			if err != nil {
-				return fmt.Errorf("%s: %w", strings.Join(append(rc.Path[1:], field.Name), "."), err)
-			}
+	if !rc.SkipNonConstraints {
+		err = checkInlineValue(&propertySchema, field, "default", propertySchema.WithDefault)
+		if err != nil {
+			return fmt.Errorf("%s: %w", strings.Join(append(rc.Path[1:], field.Name), "."), err)
 		}
 
-		err = checkInlineValue(&propertySchema, field, "const", propertySchema.WithConst)
-		if err != nil {
-			return err
+		if rc.DropZeroDefaults && omitEmpty && propertySchema.Default != nil &&
+			isZeroDefault(ft, *propertySchema.Default) {
+			propertySchema.Default = nil
 		}
+	}
 
-		if err := refl.PopulateFieldsFromTags(&propertySchema, field.Tag); err != nil {
+	err = checkInlineValue(&propertySchema, field, "const", propertySchema.WithConst)
+	if err != nil {
+		return err
+	}
+
+	if err := r.applyPresets(&propertySchema, field); err != nil {
+		return err
+	}
+
+	if err := populateFieldsFromTags(&propertySchema, field.Tag); err != nil {
+		return err
+	}
+
+	if err := reflectMapTags(&propertySchema, field, rc); err != nil {
+		return err
+	}
+
+	if err := reflectItemsTags(&propertySchema, field.Tag, field.Name); err != nil {
+		return err
+	}
+
+	reflectFieldComments(owner, field, &propertySchema, rc)
+	checkTimeFormat(&propertySchema, ft)
+
+	if include.readOnly {
+		propertySchema.WithReadOnly(true)
+	}
+
+	if include.writeOnly {
+		propertySchema.WithExtraPropertiesItem("writeOnly", true)
+	}
+
+	deprecated := false
+	if err := refl.ReadBoolTag(field.Tag, "deprecated", &deprecated); err != nil {
+		return err
+	} else if deprecated {
+		propertySchema.WithExtraPropertiesItem("deprecated", true)
+	}
+
+	if !rc.SkipNonConstraints {
+		if err := reflectExamples(&propertySchema, field); err != nil {
 			return err
 		}
 
-		deprecated := false
-		if err := refl.ReadBoolTag(field.Tag, "deprecated", &deprecated); err != nil {
+		if err := reflectNamedExamples(&propertySchema, field); err != nil {
 			return err
-		} else if deprecated {
-			propertySchema.WithExtraPropertiesItem("deprecated", true)
 		}
+	}
 
-		if !rc.SkipNonConstraints {
-			if err := reflectExamples(&propertySchema, field); err != nil {
-				return err
-			}
+	if !rc.SkipNonConstraints && !sensitive {
+		if err := sampleValue(&propertySchema, fv, rc.SampleValues); err != nil {
+			return err
 		}
+	}
 
-		reflectEnum(&propertySchema, field.Tag, nil)
+	reflectEnum(&propertySchema, field.Tag, nil)
 
-		// Remove temporary kept type from referenced schema.
-		if propertySchema.Ref != nil {
-			propertySchema.Type = nil
-		}
+	if err := r.applyEnumSource(&propertySchema, field); err != nil {
+		return err
+	}
 
-		if rc.interceptProp != nil {
-			if err := rc.interceptProp(InterceptPropParams{
-				Context:        rc,
-				Path:           rc.Path,
-				Name:           propName,
-				Field:          field,
-				PropertySchema: &propertySchema,
-				ParentSchema:   parent,
-				Processed:      true,
-			}); err != nil {
-				if errors.Is(err, ErrSkipProperty) {
-					continue
-				}
+	if err := reflectTypeTag(&propertySchema, field, rc.UnionTypesAnyOf); err != nil {
+		return err
+	}
 
-				return err
+	if err := reflectAnyOfTag(&propertySchema, field); err != nil {
+		return err
+	}
+
+	// Remove temporary kept type from referenced schema.
+	if propertySchema.Ref != nil {
+		propertySchema.Type = nil
+	}
+
+	inferFormat(&propertySchema, propName, field, rc)
+
+	if rc.GoTypeAnnotations {
+		propertySchema.WithExtraPropertiesItem(XGoType, goTypeString(ft))
+		propertySchema.WithExtraPropertiesItem(XGoName, field.Name)
+	}
+
+	reflectGroup(&propertySchema, parent, propName, field, rc)
+
+	if sensitive {
+		markSensitive(&propertySchema, rc)
+	}
+
+	if rc.interceptProp != nil {
+		if err := rc.interceptProp(InterceptPropParams{
+			Context:        rc,
+			Path:           rc.Path,
+			Name:           propName,
+			Field:          field,
+			PropertySchema: &propertySchema,
+			ParentSchema:   parent,
+			Processed:      true,
+			OwnerType:      owner,
+			InstancePath:   instancePath,
+			SchemaPath:     schemaPath,
+		}); err != nil {
+			if errors.Is(err, ErrSkipProperty) {
+				return nil
 			}
-		}
 
-		if parent.Properties == nil {
-			parent.Properties = make(map[string]SchemaOrBool, 1)
+			return err
 		}
+	}
 
-		parent.Properties[propName] = SchemaOrBool{
-			TypeObject: &propertySchema,
-		}
+	if parent.Properties == nil {
+		parent.Properties = make(map[string]SchemaOrBool, 1)
+	}
+
+	parent.Properties[propName] = SchemaOrBool{
+		TypeObject: &propertySchema,
+	}
+
+	if rc.PropertiesOrder != 0 {
+		parent.PropertiesOrder = appendUnique(parent.PropertiesOrder, propName)
 	}
 
+	reflectDependentRequiredTag(parent, propName, field)
+
 	return nil
 }
 
+// isZeroDefault checks if default value corresponds to an empty value of a type that is omitted with `omitempty`.
+func isZeroDefault(t reflect.Type, d interface{}) bool {
+	zero := reflect.Zero(t)
+	if !isEmptyValue(zero) {
+		return false
+	}
+
+	switch t.Kind() { //nolint:exhaustive // Scalars are compared by JSON value.
+	case reflect.Interface, reflect.Ptr:
+		return d == nil
+	case reflect.Array, reflect.Map, reflect.Slice:
+		switch dv := d.(type) {
+		case nil:
+			return true
+		case []interface{}:
+			return len(dv) == 0
+		case map[string]interface{}:
+			return len(dv) == 0
+		}
+
+		return false
+	}
+
+	zj, err := json.Marshal(zero.Interface())
+	if err != nil {
+		return false
+	}
+
+	dj, err := json.Marshal(d)
+	if err != nil {
+		return false
+	}
+
+	return string(zj) == string(dj)
+}
+
 func checkInlineValue(propertySchema *Schema, field reflect.StructField, tag string, setter func(interface{}) *Schema) error {
 	var (
 		val interface{}
@@ -1256,7 +2007,8 @@ func checkInlineValue(propertySchema *Schema, field reflect.StructField, tag str
 // would be absent instead of having `null`.
 //
 // Shared definitions (used by $ref) are not nullable by default, so that they can be set to nullable
-// where necessary with `"anyOf":[{"type":"null"},{"$ref":"..."}]` (see ReflectContext.EnvelopNullability).
+// where necessary with `"anyOf":[{"type":"null"},{"$ref":"..."}]` (see ReflectContext.EnvelopNullability)
+// or with other strategy (see ReflectContext.NullableRef).
 //
 // Nullability cases include:
 //   - Array, slice accepts `null` as a value.
@@ -1295,11 +2047,23 @@ func checkNullability(propertySchema *Schema, rc *ReflectContext, ft reflect.Typ
 		return
 	}
 
+	if propertySchema.Ref != nil {
+		// Type is shared with definition, it is copied to keep definition intact.
+		propertySchema.Type = copyType(propertySchema.Type)
+	}
+
 	if propertySchema.HasType(Array) ||
 		(propertySchema.HasType(Object) && len(propertySchema.Properties) == 0 && propertySchema.Ref == nil) {
 		propertySchema.AddType(Null)
 
 		in.NullAdded = true
+
+		// Definitions of named slices are nullable by default.
+		if propertySchema.Ref != nil && rc.nullableRefStrategy() == NullableRefDefault {
+			if def := rc.getDefinition(*propertySchema.Ref); def != nil {
+				def.AddType(Null)
+			}
+		}
 	}
 
 	if ft.Kind() == reflect.Ptr && propertySchema.Ref == nil && ft.Elem() != typeOfJSONRawMsg {
@@ -1313,18 +2077,75 @@ func checkNullability(propertySchema *Schema, rc *ReflectContext, ft reflect.Typ
 		in.RefDef = def
 
 		if (def.HasType(Array) || def.HasType(Object) || ft.Kind() == reflect.Ptr) && !def.HasType(Null) {
-			if rc.EnvelopNullability {
+			switch rc.nullableRefStrategy() {
+			case NullableRefAnyOf:
 				refSchema := *propertySchema
+				refSchema.Type = nil
 				propertySchema.Ref = nil
+				propertySchema.Type = nil
 				propertySchema.AnyOf = []SchemaOrBool{
 					Null.ToSchemaOrBool(),
 					refSchema.ToSchemaOrBool(),
 				}
+
+				in.NullAdded = true
+			case NullableRefInline:
+				inlined := cloneSchema(*def)
+				inlined.ReflectType = propertySchema.ReflectType
+				inlined.AddType(Null)
+				*propertySchema = inlined
+
+				in.NullAdded = true
+			case NullableRefDefault, NullableRefDrop:
 			}
 		}
 	}
 }
 
+func copyType(t *Type) *Type {
+	if t == nil {
+		return nil
+	}
+
+	c := *t
+	if t.SimpleTypes != nil {
+		st := *t.SimpleTypes
+		c.SimpleTypes = &st
+	}
+
+	c.SliceOfSimpleTypeValues = append([]SimpleType(nil), t.SliceOfSimpleTypeValues...)
+
+	return &c
+}
+
+// sampleValue documents non-zero field value according to mode.
+func sampleValue(propertySchema *Schema, fv reflect.Value, mode SampleValuesMode) error {
+	if mode == 0 || propertySchema.Ref != nil || !fv.IsValid() || fv.IsZero() || isEmptyValue(fv) {
+		return nil
+	}
+
+	// Properties of structs are documented separately.
+	if refl.DeepIndirect(fv.Type()).Kind() == reflect.Struct && !propertySchema.HasType(String) {
+		return nil
+	}
+
+	val, err := jsonValueOf(fv)
+	if err != nil || val == nil {
+		return err
+	}
+
+	switch mode {
+	case SampleValuesExamples:
+		propertySchema.Examples = append(propertySchema.Examples, val)
+	case SampleValuesDefault:
+		if propertySchema.Default == nil {
+			propertySchema.WithDefault(val)
+		}
+	}
+
+	return nil
+}
+
 func reflectExamples(propertySchema *Schema, field reflect.StructField) error {
 	if err := reflectExample(propertySchema, field); err != nil {
 		return err
@@ -1401,6 +2222,221 @@ func reflectExample(propertySchema *Schema, field reflect.StructField) error {
 	return nil
 }
 
+func markSensitive(propertySchema *Schema, rc *ReflectContext) {
+	switch rc.Sensitive {
+	case SensitiveAnnotate:
+		keyword := rc.SensitiveKeyword
+		if keyword == "" {
+			keyword = XSensitive
+		}
+
+		propertySchema.WithExtraPropertiesItem(keyword, true)
+	case SensitiveWriteOnly:
+		propertySchema.WithExtraPropertiesItem("writeOnly", true)
+	case SensitiveDrop:
+	}
+}
+
+type includeOptions struct {
+	ok        bool
+	name      string
+	readOnly  bool
+	writeOnly bool
+	allOf     bool
+	flatten   bool
+}
+
+// includeTag reads `jsonschema:"[name,]include[,readOnly|writeOnly]"` field tag,
+// embedded fields can have `jsonschema:"allOf"` or `jsonschema:"flatten"`.
+func includeTag(field reflect.StructField) includeOptions {
+	var res includeOptions
+
+	tag, ok := field.Tag.Lookup("jsonschema")
+	if !ok {
+		return res
+	}
+
+	for i, opt := range strings.Split(tag, ",") {
+		switch strings.TrimSpace(opt) {
+		case "include":
+			res.ok = true
+		case "readOnly":
+			res.readOnly = true
+		case "writeOnly":
+			res.writeOnly = true
+		case "allOf":
+			res.allOf = true
+		case "flatten":
+			res.flatten = true
+		default:
+			if i == 0 {
+				res.name = strings.TrimSpace(opt)
+			}
+		}
+	}
+
+	if res.name == "" {
+		res.name = field.Name
+	}
+
+	return res
+}
+
+// reflectGroup adds property to a group named in `group` (or `section`) field tag.
+func reflectGroup(propertySchema, parent *Schema, propName string, field reflect.StructField, rc *ReflectContext) {
+	group, ok := field.Tag.Lookup("group")
+	if !ok {
+		group, ok = field.Tag.Lookup("section")
+	}
+
+	if !ok || group == "" {
+		return
+	}
+
+	keyword := rc.GroupKeyword
+	if keyword == "" {
+		keyword = XGroup
+	}
+
+	propertySchema.WithExtraPropertiesItem(keyword, group)
+
+	if !rc.GroupsIndex {
+		return
+	}
+
+	groups, _ := parent.ExtraProperties[XGroups].([]PropertyGroup)
+
+	for i, g := range groups {
+		if g.Name == group {
+			groups[i].Properties = append(groups[i].Properties, propName)
+			parent.ExtraProperties[XGroups] = groups
+
+			return
+		}
+	}
+
+	parent.WithExtraPropertiesItem(XGroups, append(groups, PropertyGroup{Name: group, Properties: []string{propName}}))
+}
+
+// acceptSchema returns one of special schemas named in `accept` field tag.
+func acceptSchema(accept string, field reflect.StructField) (Schema, error) {
+	switch accept {
+	case "null":
+		return NullSchema(), nil
+	case "any":
+		return AnySchema(), nil
+	case "nothing":
+		return NothingSchema(), nil
+	default:
+		return Schema{}, fmt.Errorf("unknown value %q in accept tag of field %s, expected null, any or nothing",
+			accept, field.Name)
+	}
+}
+
+// reflectTypeTag replaces reflected type with types listed in `types` field tag, e.g. `types:"string,integer"`.
+func reflectTypeTag(schema *Schema, field reflect.StructField, anyOf bool) error {
+	tag, ok := field.Tag.Lookup("types")
+	if !ok || tag == "" {
+		return nil
+	}
+
+	var types []SimpleType
+
+	for _, name := range strings.Split(tag, ",") {
+		st := SimpleType(strings.TrimSpace(name))
+
+		switch st {
+		case Array, Boolean, Integer, Null, Number, Object, String:
+			types = append(types, st)
+		default:
+			return fmt.Errorf("unknown type %q in types tag of field %s", st, field.Name)
+		}
+	}
+
+	nullable := schema.HasType(Null)
+
+	schema.Ref = nil
+	schema.Type = nil
+
+	if anyOf && len(types) > 1 {
+		for _, st := range types {
+			schema.AnyOf = append(schema.AnyOf, st.ToSchemaOrBool())
+		}
+
+		if nullable && !hasSimpleType(types, Null) {
+			schema.AnyOf = append(schema.AnyOf, Null.ToSchemaOrBool())
+		}
+
+		return nil
+	}
+
+	for _, st := range types {
+		schema.AddType(st)
+	}
+
+	if nullable {
+		schema.AddType(Null)
+	}
+
+	return nil
+}
+
+// reflectAnyOfTag replaces reflected type with `anyOf` alternatives listed in `anyOf` field tag,
+// tag value must be a JSON array of schemas or a semicolon-separated list of types, e.g. `anyOf:"string;integer"`.
+func reflectAnyOfTag(schema *Schema, field reflect.StructField) error {
+	tag, ok := field.Tag.Lookup("anyOf")
+	if !ok || tag == "" {
+		return nil
+	}
+
+	var alternatives []SchemaOrBool
+
+	if strings.HasPrefix(strings.TrimSpace(tag), "[") {
+		if err := json.Unmarshal([]byte(tag), &alternatives); err != nil {
+			return fmt.Errorf("failed to parse anyOf tag of field %s: %w", field.Name, err)
+		}
+	} else {
+		for _, name := range strings.Split(tag, ";") {
+			st := SimpleType(strings.TrimSpace(name))
+
+			switch st {
+			case Array, Boolean, Integer, Null, Number, Object, String:
+				alternatives = append(alternatives, st.ToSchemaOrBool())
+			default:
+				return fmt.Errorf("unknown type %q in anyOf tag of field %s", st, field.Name)
+			}
+		}
+	}
+
+	nullable := schema.HasType(Null)
+
+	schema.Ref = nil
+	schema.Type = nil
+	schema.AnyOf = alternatives
+
+	for _, a := range alternatives {
+		if a.TypeObject != nil && a.TypeObject.HasType(Null) {
+			nullable = false
+		}
+	}
+
+	if nullable {
+		schema.AnyOf = append(schema.AnyOf, Null.ToSchemaOrBool())
+	}
+
+	return nil
+}
+
+func hasSimpleType(types []SimpleType, t SimpleType) bool {
+	for _, st := range types {
+		if st == t {
+			return true
+		}
+	}
+
+	return false
+}
+
 func reflectEnum(schema *Schema, fieldTag reflect.StructTag, fieldVal interface{}) {
 	enum := enum{}
 	enum.loadFromField(fieldTag, fieldVal)
@@ -1530,3 +2566,27 @@ func (o allOf) JSONSchemaAllOf() []interface{} {
 
 // InlineJSONSchema implements SchemaInliner.
 func (o allOf) InlineJSONSchema() {}
+
+// goTypeString returns type name qualified with full package path, e.g. "map[string]*github.com/acme/models.Order".
+func goTypeString(t reflect.Type) string {
+	if t.Name() != "" {
+		if t.PkgPath() == "" {
+			return t.Name()
+		}
+
+		return t.PkgPath() + "." + t.Name()
+	}
+
+	switch t.Kind() { //nolint:exhaustive // Other kinds are represented with reflect.Type.String.
+	case reflect.Ptr:
+		return "*" + goTypeString(t.Elem())
+	case reflect.Slice:
+		return "[]" + goTypeString(t.Elem())
+	case reflect.Array:
+		return "[" + strconv.Itoa(t.Len()) + "]" + goTypeString(t.Elem())
+	case reflect.Map:
+		return "map[" + goTypeString(t.Key()) + "]" + goTypeString(t.Elem())
+	}
+
+	return t.String()
+}
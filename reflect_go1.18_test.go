@@ -5,9 +5,12 @@ package jsonschema_test
 
 import (
 	"net/netip"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/swaggest/assertjson"
 	"github.com/swaggest/jsonschema-go"
@@ -105,3 +108,193 @@ func TestReflector_Reflect_fieldTags(t *testing.T) {
 	  "type":"object"
 	}`), s)
 }
+
+type genTree[T any] struct {
+	Value    T             `json:"value"`
+	Children []*genTree[T] `json:"children,omitempty"`
+}
+
+type genA[T any] struct {
+	Value T        `json:"value"`
+	B     *genB[T] `json:"b,omitempty"`
+}
+
+type genB[T any] struct {
+	A []genA[T] `json:"a,omitempty"`
+}
+
+func TestReflector_Reflect_recursiveGeneric(t *testing.T) {
+	type item struct {
+		ID int `json:"id"`
+	}
+
+	type root struct {
+		Items  genTree[item]          `json:"items"`
+		Nested genTree[genTree[item]] `json:"nested"`
+		Lists  genTree[[]item]        `json:"lists"`
+		Mutual genA[string]           `json:"mutual"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(genTree[int]{})
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "properties":{
+		"children":{"items":{"$ref":"#"},"type":"array"},
+		"value":{"type":"integer"}
+	  },
+	  "type":"object"
+	}`, s)
+
+	s, err = r.Reflect(root{})
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "definitions":{
+		"JsonschemaGoTestGenA[String]":{
+		  "properties":{
+			"b":{"$ref":"#/definitions/JsonschemaGoTestGenB[String]"},
+			"value":{"type":"string"}
+		  },
+		  "type":"object"
+		},
+		"JsonschemaGoTestGenB[String]":{
+		  "properties":{
+			"a":{"items":{"$ref":"#/definitions/JsonschemaGoTestGenA[String]"},"type":"array"}
+		  },
+		  "type":"object"
+		},
+		"JsonschemaGoTestGenTree[JsonschemaGoTestGenTree[JsonschemaGoTestItem]]":{
+		  "properties":{
+			"children":{
+			  "items":{"$ref":"#/definitions/JsonschemaGoTestGenTree[JsonschemaGoTestGenTree[JsonschemaGoTestItem]]"},
+			  "type":"array"
+			},
+			"value":{"$ref":"#/definitions/JsonschemaGoTestGenTree[JsonschemaGoTestItem]"}
+		  },
+		  "type":"object"
+		},
+		"JsonschemaGoTestGenTree[JsonschemaGoTestItem]":{
+		  "properties":{
+			"children":{
+			  "items":{"$ref":"#/definitions/JsonschemaGoTestGenTree[JsonschemaGoTestItem]"},
+			  "type":"array"
+			},
+			"value":{"$ref":"#/definitions/JsonschemaGoTestItem"}
+		  },
+		  "type":"object"
+		},
+		"JsonschemaGoTestGenTree[[]JsonschemaGoTestItem]":{
+		  "properties":{
+			"children":{
+			  "items":{"$ref":"#/definitions/JsonschemaGoTestGenTree[[]JsonschemaGoTestItem]"},
+			  "type":"array"
+			},
+			"value":{"items":{"$ref":"#/definitions/JsonschemaGoTestItem"},"type":["array","null"]}
+		  },
+		  "type":"object"
+		},
+		"JsonschemaGoTestItem":{"properties":{"id":{"type":"integer"}},"type":"object"}
+	  },
+	  "properties":{
+		"items":{"$ref":"#/definitions/JsonschemaGoTestGenTree[JsonschemaGoTestItem]"},
+		"lists":{"$ref":"#/definitions/JsonschemaGoTestGenTree[[]JsonschemaGoTestItem]"},
+		"mutual":{"$ref":"#/definitions/JsonschemaGoTestGenA[String]"},
+		"nested":{"$ref":"#/definitions/JsonschemaGoTestGenTree[JsonschemaGoTestGenTree[JsonschemaGoTestItem]]"}
+	  },
+	  "type":"object"
+	}`, s)
+}
+
+type opt[T any] struct {
+	Valid bool
+	Value T
+}
+
+func TestReflector_RegisterOptional(t *testing.T) {
+	type address struct {
+		City string `json:"city"`
+	}
+
+	type user struct {
+		Age     opt[int]      `json:"age" minimum:"18"`
+		Name    opt[string]   `json:"name"`
+		Address opt[address]  `json:"address"`
+		Manager *opt[address] `json:"manager"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	require.NoError(t, r.RegisterOptional(opt[int]{}, "Value"))
+	require.EqualError(t, r.RegisterOptional(opt[int]{}, "Val"),
+		"jsonschema_test.opt[int]: field Val not found")
+	require.Error(t, r.RegisterOptional(1, "Value"))
+
+	s, err := r.Reflect(user{Name: opt[string]{Valid: true, Value: "Jane"}}, jsonschema.InlineRefs)
+	require.NoError(t, err)
+	assertjson.EqMarshal(t, `{
+	  "properties":{
+		"address":{"properties":{"city":{"type":"string"}},"type":["object","null"]},
+		"age":{"minimum":18,"type":["null","integer"]},
+		"manager":{"properties":{"city":{"type":"string"}},"type":["object","null"]},
+		"name":{"type":["null","string"]}
+	  },
+	  "type":"object"
+	}`, s)
+}
+
+type genPage[T any] struct {
+	Items []T `json:"items"`
+	Next  int `json:"next"`
+}
+
+type genPair[K comparable, V any] struct {
+	Key   K `json:"key"`
+	Value V `json:"value"`
+}
+
+func TestGenericDefNames(t *testing.T) {
+	type user struct {
+		Name string `json:"name"`
+	}
+
+	type root struct {
+		Users  genPage[user]                          `json:"users"`
+		Lists  genPage[[]*user]                       `json:"lists"`
+		Nested genPage[genPage[user]]                 `json:"nested"`
+		Pairs  genPair[string, map[string]genA[user]] `json:"pairs"`
+		Ints   genPage[int]                           `json:"ints"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(root{}, jsonschema.GenericDefNames(nil),
+		jsonschema.StripDefinitionNamePrefix("JsonschemaGoTest"))
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(s.Definitions))
+	for name := range s.Definitions {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	assert.Equal(t, []string{
+		"GenAOfUser", "GenBOfUser", "GenPageOfGenPageOfUser", "GenPageOfInt", "GenPageOfUser",
+		"GenPageOfUserList", "GenPairOfStringAndMapOfStringAndGenAOfUser", "User",
+	}, names)
+
+	assertjson.EqMarshal(t, `{"$ref":"#/definitions/GenPageOfUser"}`, s.Properties["users"])
+
+	r = jsonschema.Reflector{}
+
+	s, err = r.Reflect(genPair[string, user]{}, jsonschema.RootRef,
+		jsonschema.GenericDefNames(func(base string, args []string) string {
+			return base + "_" + strings.Join(args, "_")
+		}))
+	require.NoError(t, err)
+
+	assert.Equal(t, "#/definitions/JsonschemaGoTestGenPair_String_User", *s.Ref)
+}
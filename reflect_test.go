@@ -14,6 +14,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/swaggest/assertjson"
 	"github.com/swaggest/jsonschema-go"
+	"github.com/swaggest/jsonschema-go/protocheck"
 )
 
 type Role struct {
@@ -2477,3 +2478,1457 @@ func TestReflector_Reflect_ElseExposer(t *testing.T) {
 	require.NoError(t, err)
 	assertjson.EqMarshal(t, `{"type":"string","else":{"title":"test2","type":"string"}}`, s)
 }
+
+func TestReflector_Reflect_referName(t *testing.T) {
+	type User struct {
+		ID       int    `json:"id"`
+		Name     string `json:"name"`
+		Password string `json:"password"`
+	}
+
+	type Order struct {
+		Author    User   `json:"author" refer:"UserSummary"`
+		Reviewers []User `json:"reviewers,omitempty"`
+		Approver  *User  `json:"approver" refer:"UserSummary"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	_, err := r.Reflect(Order{})
+	assert.EqualError(t, err, "referred definition not found: UserSummary")
+
+	us := jsonschema.Schema{}
+	us.AddType(jsonschema.Object)
+	us.WithPropertiesItem("id", jsonschema.Integer.ToSchemaOrBool())
+
+	r.AddDefinition("UserSummary", us)
+	r.AddDefinition("Unused", us)
+
+	s, err := r.Reflect(Order{}, jsonschema.StripDefinitionNamePrefix("JsonschemaGoTest"))
+	require.NoError(t, err)
+	assertjson.EqMarshal(t, `{
+	  "definitions":{
+		"User":{
+		  "properties":{
+			"id":{"type":"integer"},"name":{"type":"string"},
+			"password":{"type":"string"}
+		  },
+		  "type":"object"
+		},
+		"UserSummary":{"properties":{"id":{"type":"integer"}},"type":"object"}
+	  },
+	  "properties":{
+		"approver":{"$ref":"#/definitions/UserSummary"},
+		"author":{"$ref":"#/definitions/UserSummary"},
+		"reviewers":{"items":{"$ref":"#/definitions/User"},"type":"array"}
+	  },
+	  "type":"object"
+	}`, s)
+
+	// Definitions that are collected externally are not checked.
+	_, err = (&jsonschema.Reflector{}).Reflect(Order{}, jsonschema.CollectDefinitions(func(_ string, _ jsonschema.Schema) {}))
+	require.NoError(t, err)
+}
+
+type internalWrapper struct {
+	Value string `json:"value"`
+}
+
+type internalNode struct {
+	Name     string         `json:"name"`
+	Children []internalNode `json:"children"`
+}
+
+func TestHideDefinitions(t *testing.T) {
+	type Public struct {
+		A    internalWrapper  `json:"a"`
+		B    *internalWrapper `json:"b"`
+		Tree internalNode     `json:"tree"`
+		P    Person           `json:"p"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(Public{}, jsonschema.HideDefinitions(func(t reflect.Type) bool {
+		return strings.HasPrefix(t.Name(), "internal")
+	}), jsonschema.StripDefinitionNamePrefix("JsonschemaGoTest"))
+	require.NoError(t, err)
+
+	j, err := json.Marshal(s.Definitions)
+	require.NoError(t, err)
+
+	assertjson.Equal(t, []byte(`{
+	  "InternalNode":{
+		"properties":{
+		  "children":{"items":{"$ref":"#/definitions/InternalNode"},"type":["array","null"]},
+		  "name":{"type":"string"}
+		},
+		"type":"object"
+	  },
+	  "Person":"<ignore-diff>",
+	  "Enumed":"<ignore-diff>"
+	}`), j)
+
+	assertjson.EqMarshal(t, `{
+	  "a":{"properties":{"value":{"type":"string"}},"type":"object"},
+	  "b":{"properties":{"value":{"type":"string"}},"type":["object","null"]},
+	  "p":{"$ref":"#/definitions/Person"},
+	  "tree":{"$ref":"#/definitions/InternalNode"}
+	}`, s.Properties)
+}
+
+func TestNullableRefs(t *testing.T) {
+	type Item struct {
+		Name string `json:"name"`
+	}
+
+	type Items []Item
+
+	type Holder struct {
+		Ptr  *Item `json:"ptr"`
+		List Items `json:"list"`
+	}
+
+	for _, tc := range []struct {
+		strategy jsonschema.NullableRefStrategy
+		expected string
+	}{
+		{
+			strategy: jsonschema.NullableRefAnyOf,
+			expected: `{
+			  "definitions":{
+				"Item":{"properties":{"name":{"type":"string"}},"type":"object"},
+				"Items":{"items":{"$ref":"#/definitions/Item"},"type":"array"}
+			  },
+			  "properties":{
+				"list":{"anyOf":[{"type":"null"},{"$ref":"#/definitions/Items"}]},
+				"ptr":{"anyOf":[{"type":"null"},{"$ref":"#/definitions/Item"}]}
+			  },
+			  "type":"object"
+			}`,
+		},
+		{
+			strategy: jsonschema.NullableRefInline,
+			expected: `{
+			  "definitions":{
+				"Item":{"properties":{"name":{"type":"string"}},"type":"object"},
+				"Items":{"items":{"$ref":"#/definitions/Item"},"type":"array"}
+			  },
+			  "properties":{
+				"list":{"items":{"$ref":"#/definitions/Item"},"type":["array","null"]},
+				"ptr":{"properties":{"name":{"type":"string"}},"type":["object","null"]}
+			  },
+			  "type":"object"
+			}`,
+		},
+		{
+			strategy: jsonschema.NullableRefDrop,
+			expected: `{
+			  "definitions":{
+				"Item":{"properties":{"name":{"type":"string"}},"type":"object"},
+				"Items":{"items":{"$ref":"#/definitions/Item"},"type":"array"}
+			  },
+			  "properties":{
+				"list":{"$ref":"#/definitions/Items"},
+				"ptr":{"$ref":"#/definitions/Item"}
+			  },
+			  "type":"object"
+			}`,
+		},
+	} {
+		r := jsonschema.Reflector{}
+
+		s, err := r.Reflect(Holder{}, jsonschema.NullableRefs(tc.strategy),
+			jsonschema.StripDefinitionNamePrefix("JsonschemaGoTest"))
+		require.NoError(t, err)
+
+		assertjson.EqMarshal(t, tc.expected, s)
+	}
+}
+
+func TestNullableRefs_inlineCopy(t *testing.T) {
+	type Item struct {
+		Name string `json:"name"`
+	}
+
+	type Holder struct {
+		Ptr *Item `json:"ptr"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(Holder{}, jsonschema.NullableRefs(jsonschema.NullableRefInline),
+		jsonschema.StripDefinitionNamePrefix("JsonschemaGoTest"))
+	require.NoError(t, err)
+
+	inlined := s.Properties["ptr"].TypeObject
+	inlined.Properties["name"].TypeObject.WithMinLength(1)
+	inlined.WithExtraPropertiesItem("x-inlined", true)
+
+	assertjson.EqMarshal(t, `{"properties":{"name":{"type":"string"}},"type":"object"}`,
+		s.Definitions["Item"])
+}
+
+func TestOrderKeywords(t *testing.T) {
+	type Leaf struct {
+		Kind string `json:"kind" enum:"zeta,alpha,mid"`
+	}
+
+	type Root struct {
+		Zed   int   `json:"zed" required:"true"`
+		Alpha int   `json:"alpha" required:"true"`
+		Leaf  *Leaf `json:"leaf" required:"true"`
+		Base  Org   `json:"base"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	var names []string
+
+	s, err := r.Reflect(Root{},
+		jsonschema.OrderKeywords(jsonschema.OrderAlphabetical),
+		jsonschema.StripDefinitionNamePrefix("JsonschemaGoTest"),
+		jsonschema.CollectDefinitions(func(name string, _ jsonschema.Schema) {
+			names = append(names, name)
+		}),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"alpha", "leaf", "zed"}, s.Required)
+	assert.Equal(t, []string{"Enumed", "Leaf", "Org", "Person"}, names)
+
+	r = jsonschema.Reflector{}
+	names = nil
+
+	s, err = r.Reflect(Root{},
+		jsonschema.OrderKeywords(jsonschema.OrderDeclaration),
+		jsonschema.StripDefinitionNamePrefix("JsonschemaGoTest"),
+		jsonschema.CollectDefinitions(func(name string, s jsonschema.Schema) {
+			names = append(names, name)
+
+			if name == "Leaf" {
+				assert.Equal(t, []interface{}{"zeta", "alpha", "mid"}, s.Properties["kind"].TypeObject.Enum)
+			}
+		}),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"zed", "alpha", "leaf"}, s.Required)
+	assert.Equal(t, []string{"Leaf", "Enumed", "Person", "Org"}, names)
+
+	r = jsonschema.Reflector{}
+
+	s, err = r.Reflect(Leaf{}, jsonschema.OrderKeywords(jsonschema.OrderAlphabetical))
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "properties":{"kind":{"enum":["alpha","mid","zeta"],"type":"string"}},
+	  "type":"object"
+	}`, s)
+}
+
+type percentage int
+
+func TestReflector_RegisterConstraints(t *testing.T) {
+	type Stats struct {
+		Done    percentage   `json:"done"`
+		Failed  *percentage  `json:"failed"`
+		History []percentage `json:"history"`
+	}
+
+	r := jsonschema.Reflector{}
+	r.RegisterConstraints(percentage(0), jsonschema.Minimum(0), jsonschema.Maximum(100))
+
+	s, err := r.Reflect(Stats{}, jsonschema.StripDefinitionNamePrefix("JsonschemaGoTest"))
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "definitions":{"Percentage":{"maximum":100,"minimum":0,"type":"integer"}},
+	  "properties":{
+		"done":{"$ref":"#/definitions/Percentage"},
+		"failed":{"$ref":"#/definitions/Percentage"},
+		"history":{"items":{"$ref":"#/definitions/Percentage"},"type":["array","null"]}
+	  },
+	  "type":"object"
+	}`, s)
+}
+
+func TestConstraintsByRef(t *testing.T) {
+	type Progress struct {
+		Done percentage `json:"done"`
+	}
+
+	type Stats struct {
+		Progress
+		History []percentage          `json:"history"`
+		ByStage map[string]percentage `json:"byStage"`
+	}
+
+	r := jsonschema.Reflector{}
+	r.RegisterConstraints(percentage(0), jsonschema.Minimum(0), jsonschema.Maximum(100))
+
+	s, err := r.Reflect(Stats{}, jsonschema.InlineRefs)
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "properties":{
+		"byStage":{"additionalProperties":{"maximum":100,"minimum":0,"type":"integer"},"type":["object","null"]},
+		"done":{"maximum":100,"minimum":0,"type":"integer"},
+		"history":{"items":{"maximum":100,"minimum":0,"type":"integer"},"type":["array","null"]}
+	  },
+	  "type":"object"
+	}`, s)
+
+	s, err = r.Reflect(Stats{}, jsonschema.InlineRefs, jsonschema.ConstraintsByRef,
+		jsonschema.StripDefinitionNamePrefix("JsonschemaGoTest"))
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "definitions":{"Percentage":{"maximum":100,"minimum":0,"type":"integer"}},
+	  "properties":{
+		"byStage":{"additionalProperties":{"$ref":"#/definitions/Percentage"},"type":["object","null"]},
+		"done":{"$ref":"#/definitions/Percentage"},
+		"history":{"items":{"$ref":"#/definitions/Percentage"},"type":["array","null"]}
+	  },
+	  "type":"object"
+	}`, s)
+
+	s, err = r.Reflect(Stats{}, jsonschema.InlineScalars(2), jsonschema.ConstraintsByRef,
+		jsonschema.StripDefinitionNamePrefix("JsonschemaGoTest"))
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "definitions":{"Percentage":{"maximum":100,"minimum":0,"type":"integer"}},
+	  "properties":{
+		"byStage":{"additionalProperties":{"$ref":"#/definitions/Percentage"},"type":["object","null"]},
+		"done":{"$ref":"#/definitions/Percentage"},
+		"history":{"items":{"$ref":"#/definitions/Percentage"},"type":["array","null"]}
+	  },
+	  "type":"object"
+	}`, s)
+}
+
+func TestReflector_RegisterPreset(t *testing.T) {
+	r := jsonschema.Reflector{}
+	r.RegisterPreset("slug", jsonschema.Pattern("^[a-z0-9-]+$"), jsonschema.MinLength(1), jsonschema.MaxLength(64),
+		jsonschema.Description("URL-friendly identifier."))
+	r.RegisterPreset("short", jsonschema.MaxLength(8))
+
+	type Article struct {
+		Slug  string `json:"slug" preset:"slug"`
+		Code  string `json:"code" preset:"slug, short"`
+		Title string `json:"title" preset:"slug" maxLength:"200" description:"Title."`
+	}
+
+	s, err := r.Reflect(Article{})
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "properties":{
+		"code":{
+		  "description":"URL-friendly identifier.","maxLength":8,"minLength":1,
+		  "pattern":"^[a-z0-9-]+$","type":"string"
+		},
+		"slug":{
+		  "description":"URL-friendly identifier.","maxLength":64,"minLength":1,
+		  "pattern":"^[a-z0-9-]+$","type":"string"
+		},
+		"title":{"description":"Title.","maxLength":200,"minLength":1,"pattern":"^[a-z0-9-]+$","type":"string"}
+	  },
+	  "type":"object"
+	}`, s)
+
+	_, err = r.Reflect(struct {
+		A string `json:"a" preset:"unknown"`
+	}{})
+	assert.EqualError(t, err, `unknown preset "unknown" in field A`)
+}
+
+func TestPackageOptions(t *testing.T) {
+	type Report struct {
+		Problem  protocheck.Mismatch `json:"problem"`
+		Severity string              `json:"severity"`
+		Note     string
+	}
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(Report{},
+		jsonschema.PackageOptions("github.com/swaggest/jsonschema-go/protocheck",
+			jsonschema.ProcessWithoutTags,
+			jsonschema.InterceptDefName(func(_ reflect.Type, defaultDefName string) string {
+				return "Legacy" + defaultDefName
+			}),
+		),
+	)
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "definitions":{
+		"LegacyProtocheckMismatch":{
+		  "properties":{"Kind":{"type":"string"},"Message":{"type":"string"},"Path":{"type":"string"}},
+		  "type":"object"
+		}
+	  },
+	  "properties":{
+		"problem":{"$ref":"#/definitions/LegacyProtocheckMismatch"},
+		"severity":{"type":"string"}
+	  },
+	  "type":"object"
+	}`, s)
+}
+
+func TestGoTypeAnnotations(t *testing.T) {
+	type Item struct {
+		Name string `json:"name"`
+	}
+
+	type Order struct {
+		ID    int              `json:"id"`
+		Items []*Item          `json:"items"`
+		Index map[string]Item  `json:"index"`
+		Meta  *map[string]bool `json:"meta"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(Order{}, jsonschema.GoTypeAnnotations,
+		jsonschema.StripDefinitionNamePrefix("JsonschemaGoTest"))
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "definitions":{
+		"Item":{
+		  "properties":{"name":{"type":"string","x-go-name":"Name","x-go-type":"string"}},
+		  "type":"object","x-go-type":"github.com/swaggest/jsonschema-go_test.Item"
+		}
+	  },
+	  "properties":{
+		"id":{"type":"integer","x-go-name":"ID","x-go-type":"int"},
+		"index":{
+		  "additionalProperties":{"$ref":"#/definitions/Item"},"type":["object","null"],
+		  "x-go-name":"Index","x-go-type":"map[string]github.com/swaggest/jsonschema-go_test.Item"
+		},
+		"items":{
+		  "items":{"$ref":"#/definitions/Item"},"type":["array","null"],
+		  "x-go-name":"Items","x-go-type":"[]*github.com/swaggest/jsonschema-go_test.Item"
+		},
+		"meta":{
+		  "additionalProperties":{"type":"boolean"},"type":["null","object"],
+		  "x-go-name":"Meta","x-go-type":"*map[string]bool"
+		}
+	  },
+	  "type":"object"
+	}`, s)
+}
+
+func TestReflector_Reflect_typeTag(t *testing.T) {
+	type Event struct {
+		ID      interface{}  `json:"id" types:"string,integer"`
+		Version *json.Number `json:"version" types:"string, number"`
+		Amount  string       `json:"amount" types:"number" minimum:"0"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(Event{})
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "properties":{
+		"amount":{"minimum":0,"type":"number"},
+		"id":{"type":["string","integer"]},
+		"version":{"type":["string","number","null"]}
+	  },
+	  "type":"object"
+	}`, s)
+
+	s, err = r.Reflect(Event{}, jsonschema.UnionTypesAnyOf)
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "properties":{
+		"amount":{"minimum":0,"type":"number"},
+		"id":{"anyOf":[{"type":"string"},{"type":"integer"}]},
+		"version":{"anyOf":[{"type":"string"},{"type":"number"},{"type":"null"}]}
+	  },
+	  "type":"object"
+	}`, s)
+
+	_, err = r.Reflect(struct {
+		A int `json:"a" types:"int"`
+	}{})
+	assert.EqualError(t, err, `unknown type "int" in types tag of field A`)
+}
+
+func TestReflector_Reflect_anyOfTag(t *testing.T) {
+	type Order struct {
+		ID     interface{} `json:"id" anyOf:"string;integer" description:"Order ID."`
+		Ref    *string     `json:"ref" anyOf:"[{\"type\":\"string\",\"format\":\"uuid\"},{\"type\":\"integer\",\"minimum\":1}]"`
+		Parent *int        `json:"parent" anyOf:"integer;null"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(Order{})
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "properties":{
+		"id":{"description":"Order ID.","anyOf":[{"type":"string"},{"type":"integer"}]},
+		"parent":{"anyOf":[{"type":"integer"},{"type":"null"}]},
+		"ref":{
+		  "anyOf":[
+			{"format":"uuid","type":"string"},{"minimum":1,"type":"integer"},{"type":"null"}
+		  ]
+		}
+	  },
+	  "type":"object"
+	}`, s)
+
+	_, err = r.Reflect(struct {
+		A int `json:"a" anyOf:"int;string"`
+	}{})
+	assert.EqualError(t, err, `unknown type "int" in anyOf tag of field A`)
+}
+
+func TestReflector_Reflect_groups(t *testing.T) {
+	type Address struct {
+		Street string `json:"street" group:"billing"`
+		Zip    string `json:"zip" group:"billing"`
+	}
+
+	type Customer struct {
+		Name string `json:"name" section:"general"`
+		Address
+		Email string `json:"email" group:"general"`
+		Notes string `json:"notes"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(Customer{}, jsonschema.GroupsIndex)
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "properties":{
+		"email":{"type":"string","x-group":"general"},
+		"name":{"type":"string","x-group":"general"},
+		"notes":{"type":"string"},
+		"street":{"type":"string","x-group":"billing"},
+		"zip":{"type":"string","x-group":"billing"}
+	  },
+	  "type":"object",
+	  "x-groups":[
+		{"name":"general","properties":["name","email"]},
+		{"name":"billing","properties":["street","zip"]}
+	  ]
+	}`, s)
+
+	s, err = r.Reflect(Address{}, jsonschema.GroupKeyword("x-section"))
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "properties":{
+		"street":{"type":"string","x-section":"billing"},
+		"zip":{"type":"string","x-section":"billing"}
+	  },
+	  "type":"object"
+	}`, s)
+}
+
+func TestFieldEnabled(t *testing.T) {
+	type Line struct {
+		SKU   string `json:"sku"`
+		Promo string `json:"promo" feature:"promo"`
+	}
+
+	type Order struct {
+		ID       string `json:"id" required:"true"`
+		Discount int    `json:"discount" feature:"discounts" required:"true"`
+		Lines    []Line `json:"lines"`
+	}
+
+	flags := map[string]bool{"discounts": false, "promo": true}
+
+	var paths []string
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(Order{},
+		jsonschema.StripDefinitionNamePrefix("JsonschemaGoTest"),
+		jsonschema.FieldEnabled(func(field reflect.StructField, path []string) bool {
+			paths = append(paths, strings.Join(path, "."))
+
+			return true
+		}),
+		jsonschema.FieldEnabled(func(field reflect.StructField, _ []string) bool {
+			f, ok := field.Tag.Lookup("feature")
+
+			return !ok || flags[f]
+		}),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"#.id", "#.discount", "#.lines", "#.lines.[].sku", "#.lines.[].promo"}, paths)
+
+	assertjson.EqMarshal(t, `{
+	  "required":["id"],
+	  "definitions":{
+		"Line":{"properties":{"promo":{"type":"string"},"sku":{"type":"string"}},"type":"object"}
+	  },
+	  "properties":{
+		"id":{"type":"string"},
+		"lines":{"items":{"$ref":"#/definitions/Line"},"type":["array","null"]}
+	  },
+	  "type":"object"
+	}`, s)
+}
+
+func TestSensitiveFields(t *testing.T) {
+	type Credentials struct {
+		Login    string `json:"login" required:"true"`
+		Password string `json:"password" sensitive:"true" required:"true"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(Credentials{})
+	require.NoError(t, err)
+	assertjson.EqMarshal(t, `{
+	  "required":["login","password"],
+	  "properties":{"login":{"type":"string"},"password":{"type":"string"}},"type":"object"
+	}`, s)
+
+	s, err = r.Reflect(Credentials{}, jsonschema.SensitiveFields(jsonschema.SensitiveDrop))
+	require.NoError(t, err)
+	assertjson.EqMarshal(t, `{"required":["login"],"properties":{"login":{"type":"string"}},"type":"object"}`, s)
+
+	s, err = r.Reflect(Credentials{}, jsonschema.SensitiveFields(jsonschema.SensitiveWriteOnly))
+	require.NoError(t, err)
+	assertjson.EqMarshal(t, `{
+	  "required":["login","password"],
+	  "properties":{"login":{"type":"string"},"password":{"type":"string","writeOnly":true}},"type":"object"
+	}`, s)
+
+	s, err = r.Reflect(Credentials{}, jsonschema.SensitiveFields(jsonschema.SensitiveAnnotate))
+	require.NoError(t, err)
+	assertjson.EqMarshal(t, `{
+	  "required":["login","password"],
+	  "properties":{"login":{"type":"string"},"password":{"type":"string","x-sensitive":true}},"type":"object"
+	}`, s)
+
+	v := jsonschema.NewValidator(s)
+	require.NoError(t, v.ValidateJSON([]byte(`{"login":"jdoe","password":"secret"}`)))
+
+	v.SensitiveKeywords = []string{jsonschema.XSensitive}
+	err = v.ValidateJSON([]byte(`{"login":"jdoe","password":"secret"}`))
+	assert.EqualError(t, err, "validation failed: /password: sensitive value must not be present")
+
+	s, err = r.Reflect(Credentials{}, jsonschema.SensitiveFields(jsonschema.SensitiveAnnotate),
+		jsonschema.SensitiveKeyword("x-pii"))
+	require.NoError(t, err)
+	assert.Equal(t, true, s.Properties["password"].TypeObject.ExtraProperties["x-pii"])
+}
+
+func TestInferFormats(t *testing.T) {
+	type User struct {
+		Email      string     `json:"email"`
+		BackupMail string     `json:"backup_email"`
+		Homepage   *string    `json:"homepageURL"`
+		CreatedAt  string     `json:"created_at"`
+		UpdatedAt  time.Time  `json:"updatedAt"`
+		ContactAt  string     `json:"contact_at" format:"date"`
+		Flat       int        `json:"flat"`
+		Tags       []string   `json:"tags_url"`
+		DeletedAt  *time.Time `json:"deleted_at"`
+	}
+
+	var inferred []string
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(User{}, jsonschema.InferFormats(func(f jsonschema.InferredFormat) bool {
+		inferred = append(inferred, strings.Join(f.Path, ".")+" "+f.Format)
+
+		return f.Field.Name != "BackupMail"
+	}))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"#.email email", "#.backup_email email", "#.homepageURL uri", "#.created_at date-time",
+	}, inferred)
+
+	assertjson.EqMarshal(t, `{
+	  "properties":{
+		"backup_email":{"type":"string"},"contact_at":{"type":"string","format":"date"},
+		"created_at":{"type":"string","format":"date-time"},
+		"deleted_at":{"type":["null","string"],"format":"date-time"},
+		"email":{"type":"string","format":"email"},"flat":{"type":"integer"},
+		"homepageURL":{"type":["null","string"],"format":"uri"},
+		"tags_url":{"items":{"type":"string"},"type":["array","null"]},
+		"updatedAt":{"type":"string","format":"date-time"}
+	  },
+	  "type":"object"
+	}`, s)
+
+	s, err = r.Reflect(User{}, jsonschema.InferFormats(nil, jsonschema.FormatRule{Suffix: "email", Format: "idn-email"}))
+	require.NoError(t, err)
+
+	assert.Equal(t, "idn-email", *s.Properties["backup_email"].TypeObject.Format)
+	assert.Nil(t, s.Properties["created_at"].TypeObject.Format)
+}
+
+func TestHoistAnonymousStructs(t *testing.T) {
+	type User struct {
+		Address struct {
+			City string `json:"city"`
+		} `json:"address"`
+		Billing struct {
+			City string `json:"city"`
+		} `json:"billing"`
+		Phones []struct {
+			Number string `json:"number"`
+		} `json:"phones"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(User{}, jsonschema.HoistAnonymousStructs(nil))
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "definitions":{
+		"JsonschemaGoTestUserAddressInline1":{"properties":{"city":{"type":"string"}},"type":"object"},
+		"JsonschemaGoTestUserPhonesItemInline1":{"properties":{"number":{"type":"string"}},"type":"object"}
+	  },
+	  "properties":{
+		"address":{"$ref":"#/definitions/JsonschemaGoTestUserAddressInline1"},
+		"billing":{"$ref":"#/definitions/JsonschemaGoTestUserAddressInline1"},
+		"phones":{
+		  "items":{"$ref":"#/definitions/JsonschemaGoTestUserPhonesItemInline1"},
+		  "type":["array","null"]
+		}
+	  },
+	  "type":"object"
+	}`, s)
+
+	s, err = r.Reflect(User{}, jsonschema.HoistAnonymousStructs(func(t reflect.Type, path []string, defaultDefName string) string {
+		if path[len(path)-1] == "[]" {
+			return ""
+		}
+
+		return "Location"
+	}))
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "definitions":{"Location":{"properties":{"city":{"type":"string"}},"type":"object"}},
+	  "properties":{
+		"address":{"$ref":"#/definitions/Location"},
+		"billing":{"$ref":"#/definitions/Location"},
+		"phones":{"items":{"properties":{"number":{"type":"string"}},"type":"object"},"type":["array","null"]}
+	  },
+	  "type":"object"
+	}`, s)
+}
+
+func TestReflector_Reflect_includeTag(t *testing.T) {
+	type Account struct {
+		ID       int    `json:"id"`
+		Total    int    `json:"-" jsonschema:"total,include,readOnly" minimum:"0"`
+		Password string `json:"-" jsonschema:"include,writeOnly" minLength:"8"`
+		Internal string `json:"-"`
+		Other    string `json:"-" jsonschema:"other"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(Account{})
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "properties":{
+		"Password":{"minLength":8,"type":"string","writeOnly":true},
+		"id":{"type":"integer"},
+		"total":{"minimum":0,"readOnly":true,"type":"integer"}
+	  },
+	  "type":"object"
+	}`, s)
+}
+
+func TestReplaceInterceptProp(t *testing.T) {
+	type Item struct {
+		Name string `json:"name"`
+	}
+
+	var calls []string
+
+	hook := func(name string) jsonschema.InterceptPropFunc {
+		return func(params jsonschema.InterceptPropParams) error {
+			if params.Processed {
+				calls = append(calls, name)
+			}
+
+			return nil
+		}
+	}
+
+	schemaHook := func(name string) jsonschema.InterceptSchemaFunc {
+		return func(params jsonschema.InterceptSchemaParams) (bool, error) {
+			if params.Processed {
+				calls = append(calls, name)
+			}
+
+			return false, nil
+		}
+	}
+
+	preset := []func(*jsonschema.ReflectContext){
+		jsonschema.InterceptProp(hook("preset")),
+		jsonschema.NamedInterceptProp("preset-named", hook("preset-named")),
+		jsonschema.NamedInterceptSchema("preset-schema", schemaHook("preset-schema")),
+	}
+
+	r := jsonschema.Reflector{}
+
+	reflectWith := func(opts ...func(*jsonschema.ReflectContext)) []string {
+		calls = nil
+
+		_, err := r.Reflect(Item{}, append(preset[:len(preset):len(preset)], opts...)...)
+		require.NoError(t, err)
+
+		return calls
+	}
+
+	// Property type schema is processed before property, root schema is processed last.
+	assert.Equal(t, []string{"preset-schema", "preset", "preset-named", "preset-schema"}, reflectWith())
+
+	assert.Equal(t, []string{"first-schema", "preset-schema", "first", "preset", "preset-named", "last",
+		"first-schema", "preset-schema"},
+		reflectWith(
+			jsonschema.InterceptProp(hook("last")),
+			jsonschema.PrependInterceptProp(hook("first")),
+			jsonschema.PrependInterceptSchema(schemaHook("first-schema")),
+		))
+
+	assert.Equal(t, []string{"own-schema", "own", "own-schema"}, reflectWith(
+		jsonschema.ReplaceInterceptProp(hook("own")),
+		jsonschema.ReplaceInterceptSchema(schemaHook("own-schema")),
+	))
+
+	assert.Equal(t, []string{"preset"}, reflectWith(jsonschema.RemoveIntercepts("preset-named", "preset-schema")))
+}
+
+func TestReflector_Reflect_interceptControlsKeepExposer(t *testing.T) {
+	type Address struct {
+		Country ISOCountry   `json:"country"`
+		Raw     PtrRawSchema `json:"raw"`
+	}
+
+	noop := func(params jsonschema.InterceptSchemaParams) (bool, error) {
+		return false, nil
+	}
+
+	r := jsonschema.Reflector{}
+
+	for _, o := range []func(*jsonschema.ReflectContext){
+		jsonschema.ReplaceInterceptSchema(noop),
+		jsonschema.PrependInterceptSchema(noop),
+		jsonschema.RemoveIntercepts("noop"),
+	} {
+		s, err := r.Reflect(Address{}, jsonschema.NamedInterceptSchema("noop", noop), jsonschema.InlineRefs, o)
+		require.NoError(t, err)
+
+		assertjson.EqMarshal(t, `{
+		  "properties":{
+			"country":{
+			  "description":"ISO Country","examples":["US"],"maxLength":2,"minLength":2,
+			  "pattern":"^[a-zA-Z]{2}$","type":"string"
+			},
+			"raw":{"examples":["foo"],"type":"string"}
+		  },
+		  "type":"object"
+		}`, s)
+	}
+}
+
+func TestExcludePaths(t *testing.T) {
+	type Internal struct {
+		Trace string `json:"trace"`
+		Host  string `json:"host"`
+	}
+
+	type Line struct {
+		SKU   string `json:"sku"`
+		Debug string `json:"debug"`
+	}
+
+	type Order struct {
+		ID       int               `json:"id"`
+		Debug    string            `json:"debug"`
+		Internal Internal          `json:"internal"`
+		Lines    []Line            `json:"lines"`
+		Meta     map[string]string `json:"meta"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(Order{}, jsonschema.InlineRefs, jsonschema.ExcludePaths("/internal/*", "**/debug"))
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "properties":{
+		"id":{"type":"integer"},"internal":{"type":["object","null"]},
+		"lines":{"items":{"properties":{"sku":{"type":"string"}},"type":"object"},"type":["array","null"]},
+		"meta":{"additionalProperties":{"type":"string"},"type":["object","null"]}
+	  },
+	  "type":"object"
+	}`, s)
+
+	s, err = r.Reflect(Order{}, jsonschema.InlineRefs, jsonschema.IncludePaths("/id", "/lines/*/sku", "/internal"))
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "properties":{
+		"id":{"type":"integer"},
+		"internal":{"properties":{"host":{"type":"string"},"trace":{"type":"string"}},"type":"object"},
+		"lines":{"items":{"properties":{"sku":{"type":"string"}},"type":"object"},"type":["array","null"]}
+	  },
+	  "type":"object"
+	}`, s)
+}
+
+type globCard struct {
+	Number string `json:"number"`
+	Debug  string `json:"debug"`
+}
+
+type globPayment struct{}
+
+func (globPayment) JSONSchemaOneOf() []interface{} {
+	return []interface{}{globCard{}}
+}
+
+func TestExcludePaths_oneOf(t *testing.T) {
+	type Order struct {
+		Payment globPayment `json:"payment"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(Order{}, jsonschema.InlineRefs, jsonschema.ExcludePaths("/payment/debug"))
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "properties":{
+		"payment":{
+		  "oneOf":[{"properties":{"number":{"type":"string"}},"type":"object"}],
+		  "type":["object","null"]
+		}
+	  },
+	  "type":"object"
+	}`, s)
+}
+
+type stringerLevel int
+
+func (l stringerLevel) String() string { return [...]string{"low", "high"}[l] }
+
+type stringerCode int
+
+func (stringerCode) String() string               { return "code" }
+func (stringerCode) MarshalText() ([]byte, error) { return []byte("code"), nil }
+
+type stringerJSONLevel int
+
+func (stringerJSONLevel) String() string               { return "json" }
+func (stringerJSONLevel) MarshalJSON() ([]byte, error) { return []byte(`"json"`), nil }
+
+type stringerName string
+
+func (n stringerName) String() string { return string(n) }
+
+func TestReflector_RegisterStringer(t *testing.T) {
+	type Item struct {
+		Level *stringerLevel `json:"level"`
+		Name  stringerName   `json:"name"`
+		Code  stringerCode   `json:"code"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	var me jsonschema.StringerMismatchError
+
+	err := r.RegisterStringer(stringerLevel(0))
+	require.ErrorAs(t, err, &me)
+	assert.Equal(t, reflect.Int, me.Kind)
+	assert.Equal(t, "jsonschema_test.stringerLevel implements fmt.Stringer, but encoding/json encodes it as int",
+		err.Error())
+
+	assert.NoError(t, r.RegisterStringer(stringerName("")))
+	assert.ErrorIs(t, r.RegisterStringer(stringerJSONLevel(0)), jsonschema.ErrStringerMarshaler)
+	assert.NoError(t, r.RegisterStringer(stringerCode(0)))
+
+	s, err := r.Reflect(Item{})
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "properties":{
+		"code":{"type":"string"},"level":{"type":["string","null"]},"name":{"type":"string"}
+	  },
+	  "type":"object"
+	}`, s)
+}
+
+func TestInterceptProp_ownerType(t *testing.T) {
+	type Base struct {
+		ID int `json:"id"`
+	}
+
+	type Line struct {
+		Base
+		SKU string `json:"sku"`
+	}
+
+	type Order struct {
+		Base
+		Lines []Line `json:"lines"`
+	}
+
+	var calls []string
+
+	r := jsonschema.Reflector{}
+
+	_, err := r.Reflect(Order{}, jsonschema.InterceptProp(func(params jsonschema.InterceptPropParams) error {
+		if params.Processed {
+			calls = append(calls, params.InstancePath+" "+params.OwnerType.Name())
+		}
+
+		return nil
+	}))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"/id Base", "/lines/*/id Base", "/lines/*/sku Line", "/lines Order"}, calls)
+}
+
+func TestInterceptFinalSchema(t *testing.T) {
+	type Item struct {
+		Count int `json:"count" minimum:"1"`
+	}
+
+	type Order struct {
+		Total int    `json:"total"`
+		Items []Item `json:"items"`
+		Main  Item   `json:"main"`
+	}
+
+	calls := map[string]int{}
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(Order{}, jsonschema.InterceptFinalSchema(func(t reflect.Type, s *jsonschema.Schema) error {
+		calls[t.String()]++
+
+		if t.Kind() == reflect.Int {
+			s.WithMaximum(1000)
+		}
+
+		return nil
+	}))
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]int{
+		"int": 1, "jsonschema_test.Item": 1, "[]jsonschema_test.Item": 1, "jsonschema_test.Order": 1,
+	}, calls)
+
+	assertjson.EqMarshal(t, `{
+	  "definitions":{
+		"JsonschemaGoTestItem":{
+		  "properties":{"count":{"maximum":1000,"minimum":1,"type":"integer"}},"type":"object"
+		}
+	  },
+	  "properties":{
+		"items":{"items":{"$ref":"#/definitions/JsonschemaGoTestItem"},"type":["array","null"]},
+		"main":{"$ref":"#/definitions/JsonschemaGoTestItem"},
+		"total":{"maximum":1000,"type":"integer"}
+	  },
+	  "type":"object"
+	}`, s)
+}
+
+func TestValuesFromSample(t *testing.T) {
+	type Item struct {
+		SKU string `json:"sku"`
+	}
+
+	type Order struct {
+		ID       int       `json:"id" example:"123"`
+		Status   string    `json:"status" default:"new"`
+		Tags     []string  `json:"tags"`
+		Note     string    `json:"note"`
+		Secret   string    `json:"secret" sensitive:"true"`
+		Created  time.Time `json:"created"`
+		Items    []Item    `json:"items"`
+		Shipping *Item     `json:"shipping"`
+	}
+
+	sample := Order{
+		ID:       42,
+		Status:   "paid",
+		Tags:     []string{"a", "b"},
+		Secret:   "hunter2",
+		Created:  time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Items:    []Item{{SKU: "X1"}},
+		Shipping: &Item{SKU: "S1"},
+	}
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(sample, jsonschema.ValuesFromSample(jsonschema.SampleValuesExamples), jsonschema.InlineRefs)
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "properties":{
+		"created":{"type":"string","format":"date-time","examples":["2024-01-02T03:04:05Z"]},
+		"id":{"type":"integer","examples":[123,42]},
+		"items":{
+		  "type":["array","null"],"examples":[[{"sku":"X1"}]],
+		  "items":{"type":"object","properties":{"sku":{"type":"string","examples":["X1"]}}}
+		},
+		"note":{"type":"string"},"secret":{"type":"string"},
+		"shipping":{"type":["object","null"],"properties":{"sku":{"type":"string","examples":["S1"]}}},
+		"status":{"type":"string","default":"new","examples":["paid"]},
+		"tags":{"type":["array","null"],"items":{"type":"string"},"examples":[["a","b"]]}
+	  },
+	  "type":"object"
+	}`, s)
+
+	s, err = r.Reflect(sample, jsonschema.ValuesFromSample(jsonschema.SampleValuesDefault))
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{"default":"new","type":"string"}`, s.Properties["status"])
+	assertjson.EqMarshal(t, `{"default":42,"examples":[123],"type":"integer"}`, s.Properties["id"])
+}
+
+type inlineUserID string
+
+func (inlineUserID) PrepareJSONSchema(s *jsonschema.Schema) error {
+	s.WithPattern("^u[0-9]+$").WithMinLength(2)
+
+	return nil
+}
+
+type inlineCode string
+
+func (inlineCode) PrepareJSONSchema(s *jsonschema.Schema) error {
+	s.WithPattern("^[A-Z]+$")
+
+	return nil
+}
+
+func TestInlineScalars(t *testing.T) {
+	type User struct {
+		ID    inlineUserID `json:"id"`
+		Code  inlineCode   `json:"code"`
+		Codes []inlineCode `json:"codes"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(User{}, jsonschema.InlineScalars(1))
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "definitions":{
+		"JsonschemaGoTestInlineUserID":{"minLength":2,"pattern":"^u[0-9]+$","type":"string"}
+	  },
+	  "properties":{
+		"code":{"pattern":"^[A-Z]+$","type":"string"},
+		"codes":{"items":{"pattern":"^[A-Z]+$","type":"string"},"type":["array","null"]},
+		"id":{"$ref":"#/definitions/JsonschemaGoTestInlineUserID"}
+	  },
+	  "type":"object"
+	}`, s)
+}
+
+type embeddedMeta struct {
+	Version int `json:"version"`
+}
+
+type EmbeddedAudit struct {
+	CreatedBy string `json:"createdBy"`
+}
+
+func TestReflector_Reflect_embeddedTags(t *testing.T) {
+	type Doc struct {
+		embeddedMeta  `json:"meta"`
+		EmbeddedAudit `json:",omitempty" jsonschema:"allOf"`
+		ID            string `json:"id"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(Doc{})
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "definitions":{
+		"JsonschemaGoTestEmbeddedAudit":{"properties":{"createdBy":{"type":"string"}},"type":"object"},
+		"JsonschemaGoTestEmbeddedMeta":{"properties":{"version":{"type":"integer"}},"type":"object"}
+	  },
+	  "properties":{
+		"id":{"type":"string"},
+		"meta":{"$ref":"#/definitions/JsonschemaGoTestEmbeddedMeta"}
+	  },
+	  "type":"object",
+	  "allOf":[{"$ref":"#/definitions/JsonschemaGoTestEmbeddedAudit"}]
+	}`, s)
+
+	type FlatDoc struct {
+		EmbeddedAudit `json:",omitempty"`
+		ID            string `json:"id"`
+	}
+
+	s, err = r.Reflect(FlatDoc{})
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "properties":{"createdBy":{"type":"string"},"id":{"type":"string"}},
+	  "type":"object"
+	}`, s)
+}
+
+func TestCollectFieldErrors(t *testing.T) {
+	type Address struct {
+		City string `json:"city" minLength:"abc"`
+		Zip  string `json:"zip"`
+	}
+
+	type User struct {
+		Name    string    `json:"name" required:"true"`
+		Age     int       `json:"age" default:"old"`
+		Updates chan bool `json:"updates" required:"true"`
+		Address Address   `json:"address"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	_, err := r.Reflect(User{})
+	require.Error(t, err)
+
+	s, err := r.Reflect(User{}, jsonschema.CollectFieldErrors)
+
+	var fe jsonschema.FieldErrors
+
+	require.ErrorAs(t, err, &fe)
+	require.Len(t, fe, 3)
+
+	assert.Equal(t, "Age", fe[0].Field.Name)
+	assert.Equal(t, "Updates", fe[1].Field.Name)
+	assert.Equal(t, "Address.City at #/address: failed to parse int value abc in tag minLength: "+
+		`strconv.ParseInt: parsing "abc": invalid syntax`, fe[2].Error())
+	assert.Contains(t, err.Error(), "3 fields failed reflection: ")
+
+	// Failed required property is not required.
+	assertjson.EqMarshal(t, `{
+	  "definitions":{"JsonschemaGoTestAddress":{"properties":{"zip":{"type":"string"}},"type":"object"}},
+	  "required":["name"],
+	  "properties":{
+		"address":{"$ref":"#/definitions/JsonschemaGoTestAddress"},
+		"name":{"type":"string"}
+	  },
+	  "type":"object"
+	}`, s)
+}
+
+func TestReflector_Reflect_namedExamples(t *testing.T) {
+	type Order struct {
+		Status string `json:"status" namedExamples:"{\"paid\":{\"summary\":\"Paid order\",\"value\":\"paid\"},\"new\":{\"value\":\"new\"}}"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(Order{})
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "properties":{"status":{"examples":["new","paid"],"type":"string"}},"type":"object"
+	}`, s)
+
+	s, err = r.Reflect(Order{}, jsonschema.KeepNamedExamples)
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "examples":["new","paid"],"type":"string",
+	  "x-examples":{"new":{"value":"new"},"paid":{"summary":"Paid order","value":"paid"}}
+	}`, s.Properties["status"])
+
+	j, err := json.Marshal(s)
+	require.NoError(t, err)
+
+	var us jsonschema.Schema
+	require.NoError(t, json.Unmarshal(j, &us))
+
+	assert.Equal(t, map[string]jsonschema.NamedExample{
+		"new":  {Value: "new"},
+		"paid": {Summary: "Paid order", Value: "paid"},
+	}, us.Properties["status"].TypeObject.NamedExamples())
+}
+
+func TestReflector_Reflect_mapExposerValues(t *testing.T) {
+	type Catalog struct {
+		Countries map[string]ISOCountry            `json:"countries"`
+		Names     map[string]*PtrSchema            `json:"names"`
+		Mixed     map[string]jsonschema.Exposer    `json:"mixed"`
+		Same      map[string]jsonschema.Exposer    `json:"same"`
+		Any       map[string]interface{}           `json:"any"`
+		Nested    map[string]map[string]ISOCountry `json:"nested"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(Catalog{
+		Mixed: map[string]jsonschema.Exposer{"b": ISOCountry("FR"), "a": new(PtrSchema), "c": ISOCountry("DE")},
+		Same:  map[string]jsonschema.Exposer{"a": ISOCountry("FR"), "b": ISOCountry("DE")},
+		Any:   map[string]interface{}{"b": "foo", "a": 1},
+	}, jsonschema.StripDefinitionNamePrefix("JsonschemaGoTest"))
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "definitions":{
+		"ISOCountry":{
+		  "description":"ISO Country","examples":["US"],"maxLength":2,"minLength":2,
+		  "pattern":"^[a-zA-Z]{2}$","type":"string"
+		}
+	  },
+	  "properties":{
+		"any":{"additionalProperties":{"type":"integer"},"type":["object","null"]},
+		"countries":{"additionalProperties":{"$ref":"#/definitions/ISOCountry"},"type":["object","null"]},
+		"mixed":{
+		  "additionalProperties":{
+			"anyOf":[{"examples":["bar"],"type":"string"},{"$ref":"#/definitions/ISOCountry"}]
+		  },
+		  "type":["object","null"]
+		},
+		"names":{"additionalProperties":{"examples":["bar"],"type":"string"},"type":["object","null"]},
+		"nested":{
+		  "additionalProperties":{"additionalProperties":{"$ref":"#/definitions/ISOCountry"},"type":"object"},
+		  "type":["object","null"]
+		},
+		"same":{"additionalProperties":{"$ref":"#/definitions/ISOCountry"},"type":["object","null"]}
+	  },
+	  "type":"object"
+	}`, s)
+}
+
+func TestDropZeroDefaults(t *testing.T) {
+	type Query struct {
+		Limit  int      `json:"limit,omitempty" default:"0"`
+		Offset int      `json:"offset" default:"0"`
+		Page   int      `json:"page,omitempty" default:"1"`
+		Sort   string   `json:"sort,omitempty" default:""`
+		Exact  bool     `json:"exact,omitempty" default:"false"`
+		Ratio  *float64 `json:"ratio,omitempty" default:"0"`
+		Tags   []string `json:"tags,omitempty" default:"[]"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(Query{}, jsonschema.DropZeroDefaults)
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "properties":{
+		"exact":{"type":"boolean"},"limit":{"type":"integer"},
+		"offset":{"default":0,"type":"integer"},"page":{"default":1,"type":"integer"},
+		"ratio":{"default":0,"type":["null","number"]},"sort":{"type":"string"},
+		"tags":{"items":{"type":"string"},"type":"array"}
+	  },
+	  "type":"object"
+	}`, s)
+}
+
+func TestTimeFormat(t *testing.T) {
+	type Event struct {
+		Created  time.Time  `json:"created"`
+		Updated  *time.Time `json:"updated"`
+		Deadline time.Time  `json:"deadline" format:"unix-time-millis"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(Event{})
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "properties":{
+		"created":{"format":"date-time","type":"string"},
+		"deadline":{"format":"unix-time-millis","type":"integer"},
+		"updated":{"format":"date-time","type":["null","string"]}
+	  },
+	  "type":"object"
+	}`, s)
+
+	s, err = r.Reflect(Event{}, jsonschema.TimeFormat(jsonschema.TimeUnix))
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "properties":{
+		"created":{"format":"unix-time","type":"integer"},
+		"deadline":{"format":"unix-time-millis","type":"integer"},
+		"updated":{"format":"unix-time","type":["null","integer"]}
+	  },
+	  "type":"object"
+	}`, s)
+}
+
+type pointerVariant struct {
+	Code string `json:"code"`
+}
+
+type pointerChoice struct{}
+
+func (pointerChoice) JSONSchemaOneOf() []interface{} {
+	return []interface{}{struct {
+		Kind string `json:"kind"`
+	}{}, struct {
+		OneOf string `json:"oneOf"`
+	}{}}
+}
+
+func TestReflectContext_SchemaPointer(t *testing.T) {
+	type Order struct {
+		Lines []pointerVariant `json:"lines"`
+		Tags  map[string]struct {
+			Label string `json:"label"`
+		} `json:"a/b~c"`
+		Choice pointerChoice `json:"choice"`
+	}
+
+	var calls []string
+
+	r := jsonschema.Reflector{}
+
+	_, err := r.Reflect(Order{}, jsonschema.InterceptProp(func(params jsonschema.InterceptPropParams) error {
+		if !params.Processed {
+			assert.Equal(t, params.InstancePath, params.Context.InstancePointer())
+			assert.Equal(t, params.SchemaPath, params.Context.SchemaPointer())
+
+			calls = append(calls, params.InstancePath+" "+params.SchemaPath)
+		}
+
+		return nil
+	}))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"/lines #/properties/lines",
+		"/lines/*/code #/properties/lines/items/properties/code",
+		"/a~1b~0c #/properties/a~1b~0c",
+		"/a~1b~0c/*/label #/properties/a~1b~0c/additionalProperties/properties/label",
+		"/choice #/properties/choice",
+		"/choice/kind #/properties/choice/oneOf/0/properties/kind",
+		"/choice/oneOf #/properties/choice/oneOf/1/properties/oneOf",
+	}, calls)
+}
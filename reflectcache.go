@@ -0,0 +1,117 @@
+package jsonschema
+
+import (
+	"reflect"
+	"sync"
+)
+
+// EnableCache enables memoization of Reflect results by type of reflected value.
+//
+// Cached schema is returned for subsequent Reflect calls of the same type without options or with the same
+// CacheKey option. Calls that collect definitions with a callback or a generation report are not cached.
+// Only zero values (or pointers to them) are cached, non-zero samples (e.g. with values of interface fields)
+// are always reflected, because they can produce different schemas for the same type.
+//
+// Cache is safe for concurrent use, configuration of Reflector (e.g. AddTypeMapping) should be complete before
+// Reflect calls, cache has to be invalidated with InvalidateCache if configuration is changed later.
+func (r *Reflector) EnableCache() {
+	if r.cache == nil {
+		r.cache = &reflectCache{}
+	}
+}
+
+// InvalidateCache removes cached schemas of types of given samples, or all cached schemas if no samples are given.
+func (r *Reflector) InvalidateCache(samples ...interface{}) {
+	if r.cache == nil {
+		return
+	}
+
+	r.cache.invalidate(samples...)
+}
+
+// CacheKey enables caching of Reflect results for calls with options, see Reflector.EnableCache.
+//
+// Calls with the same key must have equivalent options.
+func CacheKey(key string) func(*ReflectContext) {
+	return func(rc *ReflectContext) {
+		rc.CacheKey = key
+	}
+}
+
+// isZeroSample checks if sample is a zero value or a pointer to zero value.
+func isZeroSample(i interface{}) bool {
+	v := reflect.ValueOf(i)
+
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	return !v.IsValid() || v.IsZero()
+}
+
+type reflectCacheKey struct {
+	t   reflect.Type
+	key string
+}
+
+// reflectCache stores reflected schemas for Reflector.
+type reflectCache struct {
+	mu      sync.RWMutex
+	schemas map[reflectCacheKey]Schema
+}
+
+func (c *reflectCache) load(k reflectCacheKey) (Schema, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	s, ok := c.schemas[k]
+	if !ok {
+		return s, false
+	}
+
+	return cloneCachedSchema(s), true
+}
+
+func (c *reflectCache) store(k reflectCacheKey, s Schema) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.schemas == nil {
+		c.schemas = map[reflectCacheKey]Schema{}
+	}
+
+	c.schemas[k] = cloneCachedSchema(s)
+}
+
+func (c *reflectCache) invalidate(samples ...interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(samples) == 0 {
+		c.schemas = nil
+
+		return
+	}
+
+	types := make(map[reflect.Type]bool, len(samples))
+	for _, sample := range samples {
+		types[reflect.TypeOf(sample)] = true
+	}
+
+	for k := range c.schemas {
+		if types[k.t] {
+			delete(c.schemas, k)
+		}
+	}
+}
+
+// cloneCachedSchema makes a deep copy of schema including definitions in `$defs`.
+func cloneCachedSchema(s Schema) Schema {
+	c := cloneSchema(s)
+
+	if defs, ok := c.ExtraProperties["$defs"].(map[string]SchemaOrBool); ok {
+		c.ExtraProperties["$defs"] = cloneSchemaMap(defs)
+	}
+
+	return c
+}
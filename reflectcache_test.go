@@ -0,0 +1,128 @@
+package jsonschema_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/jsonschema-go"
+)
+
+type cachedID string
+
+type cachedEntity struct {
+	ID    cachedID    `json:"id"`
+	Owner cachedOwner `json:"owner"`
+}
+
+type cachedOwner struct {
+	Name string `json:"name"`
+}
+
+func TestReflector_EnableCache(t *testing.T) {
+	r := jsonschema.Reflector{}
+	r.EnableCache()
+
+	s, err := r.Reflect(cachedEntity{})
+	require.NoError(t, err)
+
+	expected := `{
+	  "definitions":{
+		"JsonschemaGoTestCachedOwner":{"properties":{"name":{"type":"string"}},"type":"object"}
+	  },
+	  "properties":{
+		"id":{"type":"string"},
+		"owner":{"$ref":"#/definitions/JsonschemaGoTestCachedOwner"}
+	  },
+	  "type":"object"
+	}`
+
+	assertjson.EqMarshal(t, expected, s)
+
+	// Changes of returned schema do not affect cache.
+	s.Properties["id"].TypeObject.WithTitle("changed")
+	s.Definitions["JsonschemaGoTestCachedOwner"].TypeObject.WithTitle("changed")
+
+	s, err = r.Reflect(cachedEntity{})
+	require.NoError(t, err)
+	assertjson.EqMarshal(t, expected, s)
+
+	// Stale schema is served until cache is invalidated.
+	r.AddTypeMapping(cachedID(""), 0)
+
+	s, err = r.Reflect(cachedEntity{})
+	require.NoError(t, err)
+	assertjson.EqMarshal(t, expected, s)
+
+	// Calls with options are not cached without CacheKey.
+	s, err = r.Reflect(cachedEntity{}, jsonschema.RootRef)
+	require.NoError(t, err)
+	assert.Equal(t, "integer", string(*s.Definitions["JsonschemaGoTestCachedEntity"].TypeObject.
+		Properties["id"].TypeObject.Type.SimpleTypes))
+
+	r.InvalidateCache(cachedEntity{})
+
+	s, err = r.Reflect(cachedEntity{})
+	require.NoError(t, err)
+	assert.Equal(t, "integer", string(*s.Properties["id"].TypeObject.Type.SimpleTypes))
+}
+
+func TestReflector_EnableCache_concurrent(t *testing.T) {
+	r := jsonschema.Reflector{}
+	r.EnableCache()
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 20; i++ {
+		i := i
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			var options []func(rc *jsonschema.ReflectContext)
+			if i%2 == 0 {
+				options = append(options, jsonschema.RootRef, jsonschema.CacheKey("root-ref"))
+			}
+
+			for _, sample := range []interface{}{cachedEntity{}, collectRoot{}, collectBranch{}} {
+				s, err := r.Reflect(sample, options...)
+				assert.NoError(t, err)
+				assert.NotEmpty(t, s.Definitions)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	s, err := r.Reflect(cachedEntity{}, jsonschema.RootRef, jsonschema.CacheKey("root-ref"))
+	require.NoError(t, err)
+	assert.Equal(t, "#/definitions/JsonschemaGoTestCachedEntity", *s.Ref)
+}
+
+func TestReflector_EnableCache_nonZeroSample(t *testing.T) {
+	type Holder struct {
+		Any interface{} `json:"any"`
+	}
+
+	r := jsonschema.Reflector{}
+	r.EnableCache()
+
+	s, err := r.Reflect(Holder{Any: cachedOwner{}})
+	require.NoError(t, err)
+	assertjson.EqMarshal(t, `{
+	  "definitions":{"JsonschemaGoTestCachedOwner":{"properties":{"name":{"type":"string"}},"type":"object"}},
+	  "properties":{"any":{"$ref":"#/definitions/JsonschemaGoTestCachedOwner"}},"type":"object"
+	}`, s)
+
+	s, err = r.Reflect(Holder{})
+	require.NoError(t, err)
+	assertjson.EqMarshal(t, `{"properties":{"any":{}},"type":"object"}`, s)
+
+	s, err = r.Reflect(&Holder{Any: cachedOwner{}})
+	require.NoError(t, err)
+	assert.NotEmpty(t, s.Definitions)
+}
@@ -0,0 +1,333 @@
+// Package registry publishes reflected JSON Schemas to a Confluent-compatible schema registry.
+package registry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/swaggest/jsonschema-go"
+)
+
+// ContentType is a media type of schema registry API.
+const ContentType = "application/vnd.schemaregistry.v1+json"
+
+// SchemaTypeJSON is a schema type of JSON Schema in registry.
+const SchemaTypeJSON = "JSON"
+
+// ErrNotFound is returned when subject or version is not registered.
+var ErrNotFound = errors.New("not found")
+
+// Compatibility is a compatibility level.
+type Compatibility string
+
+// Compatibility levels, transitive levels of registry are checked against the latest version only.
+const (
+	None     = Compatibility("NONE")
+	Backward = Compatibility("BACKWARD")
+	Forward  = Compatibility("FORWARD")
+	Full     = Compatibility("FULL")
+)
+
+// Violations returns changes that break compatibility level.
+func (c Compatibility) Violations(changes []jsonschema.Change) []jsonschema.Change {
+	var res []jsonschema.Change
+
+	for _, ch := range changes {
+		if (ch.Narrows && (c == Backward || c == Full)) || (ch.Widens && (c == Forward || c == Full)) {
+			res = append(res, ch)
+		}
+	}
+
+	return res
+}
+
+// SubjectNameStrategy builds subject name for a topic and a record.
+type SubjectNameStrategy func(topic string, isKey bool, recordName string) string
+
+// TopicNameStrategy names subject after topic, e.g. "orders-value".
+func TopicNameStrategy(topic string, isKey bool, _ string) string {
+	if isKey {
+		return topic + "-key"
+	}
+
+	return topic + "-value"
+}
+
+// RecordNameStrategy names subject after record, e.g. "acme.Order".
+func RecordNameStrategy(_ string, _ bool, recordName string) string {
+	return recordName
+}
+
+// TopicRecordNameStrategy names subject after topic and record, e.g. "orders-acme.Order".
+func TopicRecordNameStrategy(topic string, _ bool, recordName string) string {
+	return topic + "-" + recordName
+}
+
+// RecordName returns fully-qualified Go type name of a sample, e.g. "github.com/acme/models.Order".
+func RecordName(sample interface{}) string {
+	t := reflect.TypeOf(sample)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == nil {
+		return ""
+	}
+
+	if t.PkgPath() == "" {
+		return t.String()
+	}
+
+	return t.PkgPath() + "." + t.Name()
+}
+
+// Client is a schema registry API client.
+type Client struct {
+	// BaseURL is a registry URL, e.g. "http://localhost:8081".
+	BaseURL string
+
+	// HTTPClient is used to send requests, http.DefaultClient is used if nil.
+	HTTPClient *http.Client
+
+	// Prepare can alter requests, e.g. to add authentication, can be nil.
+	Prepare func(r *http.Request)
+}
+
+// Version is a registered schema version.
+type Version struct {
+	Subject    string `json:"subject"`
+	ID         int    `json:"id"`
+	Version    int    `json:"version"`
+	Schema     string `json:"schema"`
+	SchemaType string `json:"schemaType,omitempty"`
+}
+
+// APIError is an error response of registry.
+type APIError struct {
+	StatusCode int    `json:"-"`
+	ErrorCode  int    `json:"error_code"`
+	Message    string `json:"message"`
+}
+
+// Error implements error.
+func (e APIError) Error() string {
+	return fmt.Sprintf("schema registry error %d (HTTP %d): %s", e.ErrorCode, e.StatusCode, e.Message)
+}
+
+// Is implements errors.Is for ErrNotFound.
+func (e APIError) Is(target error) bool {
+	return target == ErrNotFound && e.StatusCode == http.StatusNotFound
+}
+
+type schemaRequest struct {
+	Schema     string `json:"schema"`
+	SchemaType string `json:"schemaType"`
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, result interface{}) error {
+	var reqBody io.Reader
+
+	if body != nil {
+		j, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+
+		reqBody = bytes.NewReader(j)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(c.BaseURL, "/")+path, reqBody)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", ContentType)
+
+	if body != nil {
+		req.Header.Set("Content-Type", ContentType)
+	}
+
+	if c.Prepare != nil {
+		c.Prepare(req)
+	}
+
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close() //nolint:errcheck
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		apiErr := APIError{StatusCode: resp.StatusCode}
+		if err := json.Unmarshal(respBody, &apiErr); err != nil {
+			apiErr.Message = string(respBody)
+		}
+
+		return apiErr
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	return json.Unmarshal(respBody, result)
+}
+
+// Register adds schema to subject and returns registered schema ID.
+func (c *Client) Register(ctx context.Context, subject string, schema jsonschema.Schema) (int, error) {
+	j, err := json.Marshal(schema)
+	if err != nil {
+		return 0, err
+	}
+
+	var res struct {
+		ID int `json:"id"`
+	}
+
+	err = c.do(ctx, http.MethodPost, "/subjects/"+url.PathEscape(subject)+"/versions",
+		schemaRequest{Schema: string(j), SchemaType: SchemaTypeJSON}, &res)
+
+	return res.ID, err
+}
+
+// Latest returns latest version of a subject, ErrNotFound is returned for unknown subject.
+func (c *Client) Latest(ctx context.Context, subject string) (Version, error) {
+	var v Version
+
+	err := c.do(ctx, http.MethodGet, "/subjects/"+url.PathEscape(subject)+"/versions/latest", nil, &v)
+
+	return v, err
+}
+
+// IsCompatible checks compatibility of schema with latest version of subject using registry rules.
+func (c *Client) IsCompatible(ctx context.Context, subject string, schema jsonschema.Schema) (bool, error) {
+	j, err := json.Marshal(schema)
+	if err != nil {
+		return false, err
+	}
+
+	var res struct {
+		IsCompatible bool `json:"is_compatible"`
+	}
+
+	err = c.do(ctx, http.MethodPost, "/compatibility/subjects/"+url.PathEscape(subject)+"/versions/latest",
+		schemaRequest{Schema: string(j), SchemaType: SchemaTypeJSON}, &res)
+
+	return res.IsCompatible, err
+}
+
+// IncompatibleError is returned when local compatibility check fails.
+type IncompatibleError struct {
+	Subject    string
+	Level      Compatibility
+	Violations []jsonschema.Change
+}
+
+// Error implements error.
+func (e IncompatibleError) Error() string {
+	msgs := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		msgs = append(msgs, v.String())
+	}
+
+	return fmt.Sprintf("schema is not %s compatible with latest version of %s: %s",
+		e.Level, e.Subject, strings.Join(msgs, ", "))
+}
+
+// Publisher reflects samples and registers their schemas.
+type Publisher struct {
+	Client    *Client
+	Reflector *jsonschema.Reflector
+
+	// SubjectName is a subject naming strategy, TopicNameStrategy is used if nil.
+	SubjectName SubjectNameStrategy
+
+	// Compatibility enables local check against latest version before registration, empty value disables it.
+	Compatibility Compatibility
+
+	// ReflectOptions are applied to Reflect.
+	ReflectOptions []func(rc *jsonschema.ReflectContext)
+}
+
+// Registration describes published schema.
+type Registration struct {
+	Subject string
+	ID      int
+	Schema  jsonschema.Schema
+	Changes []jsonschema.Change
+}
+
+// Publish reflects schema of sample and registers it for a topic.
+//
+// If Compatibility is set, schema is compared with latest version of subject and
+// IncompatibleError is returned in case of violations.
+func (p *Publisher) Publish(ctx context.Context, topic string, isKey bool, sample interface{}) (Registration, error) {
+	reg := Registration{}
+
+	r := p.Reflector
+	if r == nil {
+		r = &jsonschema.Reflector{}
+	}
+
+	s, err := r.Reflect(sample, p.ReflectOptions...)
+	if err != nil {
+		return reg, fmt.Errorf("reflecting %T: %w", sample, err)
+	}
+
+	reg.Schema = s
+
+	sn := p.SubjectName
+	if sn == nil {
+		sn = TopicNameStrategy
+	}
+
+	reg.Subject = sn(topic, isKey, RecordName(sample))
+
+	if p.Compatibility != "" && p.Compatibility != None {
+		latest, err := p.Client.Latest(ctx, reg.Subject)
+
+		switch {
+		case errors.Is(err, ErrNotFound):
+		case err != nil:
+			return reg, fmt.Errorf("fetching latest version of %s: %w", reg.Subject, err)
+		default:
+			var prev jsonschema.Schema
+
+			if err := json.Unmarshal([]byte(latest.Schema), &prev); err != nil {
+				return reg, fmt.Errorf("decoding latest version of %s: %w", reg.Subject, err)
+			}
+
+			reg.Changes = jsonschema.Diff(prev, s)
+
+			if v := p.Compatibility.Violations(reg.Changes); len(v) > 0 {
+				return reg, IncompatibleError{Subject: reg.Subject, Level: p.Compatibility, Violations: v}
+			}
+		}
+	}
+
+	reg.ID, err = p.Client.Register(ctx, reg.Subject, s)
+	if err != nil {
+		return reg, fmt.Errorf("registering %s: %w", reg.Subject, err)
+	}
+
+	return reg, nil
+}
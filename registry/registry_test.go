@@ -0,0 +1,116 @@
+package registry_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/jsonschema-go/registry"
+)
+
+type fakeRegistry struct {
+	mu       sync.Mutex
+	subjects map[string][]string
+}
+
+func (f *fakeRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w.Header().Set("Content-Type", registry.ContentType)
+
+	parts := strings.Split(strings.Trim(r.URL.EscapedPath(), "/"), "/")
+	subject, _ := url.PathUnescape(parts[1])
+	versions := f.subjects[subject]
+
+	switch {
+	case r.Method == http.MethodGet && len(parts) == 4 && parts[3] == "latest":
+		if len(versions) == 0 {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error_code":40401,"message":"Subject not found."}`))
+
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(registry.Version{
+			Subject: subject, ID: len(versions), Version: len(versions), Schema: versions[len(versions)-1],
+		})
+	case r.Method == http.MethodPost && len(parts) == 3:
+		var req struct {
+			Schema     string `json:"schema"`
+			SchemaType string `json:"schemaType"`
+		}
+
+		_ = json.NewDecoder(r.Body).Decode(&req)
+
+		if req.SchemaType != registry.SchemaTypeJSON {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+
+			return
+		}
+
+		f.subjects[subject] = append(versions, req.Schema)
+
+		_, _ = w.Write([]byte(`{"id":` + strconv.Itoa(len(f.subjects[subject])) + `}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+type OrderV1 struct {
+	ID int `json:"id"`
+}
+
+type OrderV2 struct {
+	ID   int    `json:"id"`
+	Note string `json:"note"`
+}
+
+func TestPublisher_Publish(t *testing.T) {
+	srv := httptest.NewServer(&fakeRegistry{subjects: map[string][]string{}})
+	defer srv.Close()
+
+	ctx := context.Background()
+	p := registry.Publisher{
+		Client:        &registry.Client{BaseURL: srv.URL},
+		Compatibility: registry.Forward,
+	}
+
+	reg, err := p.Publish(ctx, "orders", false, OrderV1{})
+	require.NoError(t, err)
+	assert.Equal(t, "orders-value", reg.Subject)
+	assert.Equal(t, 1, reg.ID)
+
+	// Adding a property to an open object narrows the schema, this is forward compatible.
+	reg, err = p.Publish(ctx, "orders", false, OrderV2{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, reg.ID)
+	assert.Len(t, reg.Changes, 1)
+
+	// Removing a property widens the schema, this is not forward compatible.
+	_, err = p.Publish(ctx, "orders", false, OrderV1{})
+	require.Error(t, err)
+
+	var ie registry.IncompatibleError
+
+	require.True(t, errors.As(err, &ie))
+	assert.Equal(t, "schema is not FORWARD compatible with latest version of orders-value: "+
+		`#/properties/note: properties removed: {"type":"string"}`, err.Error())
+
+	p.SubjectName = registry.TopicRecordNameStrategy
+	reg, err = p.Publish(ctx, "orders", false, OrderV1{})
+	require.NoError(t, err)
+	assert.Equal(t, "orders-github.com/swaggest/jsonschema-go/registry_test.OrderV1", reg.Subject)
+
+	_, err = p.Client.Latest(ctx, "unknown")
+	assert.True(t, errors.Is(err, registry.ErrNotFound))
+}
@@ -0,0 +1,139 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// RenameProperty renames property of a definition (or of root schema if defName is empty) in document.
+//
+// Property, `required`, `dependencies` (both keys and names in dependent lists), `examples`, `default`
+// and named examples of the object are updated, as well as of its inline `allOf`, `anyOf`, `oneOf`, `not`,
+// `if`, `then`, `else` and dependency subschemas that describe the same object. References to the definition
+// remain valid as definition name is not changed.
+func RenameProperty(doc *Schema, defName, oldName, newName string) error {
+	target := doc
+
+	if defName != "" {
+		def, ok := doc.Definitions[defName]
+		if !ok || def.TypeObject == nil {
+			return fmt.Errorf("definition not found: %s", defName)
+		}
+
+		target = def.TypeObject
+	}
+
+	if _, ok := target.Properties[oldName]; !ok {
+		return fmt.Errorf("property %q not found", oldName)
+	}
+
+	if _, ok := target.Properties[newName]; ok {
+		return fmt.Errorf("property %q already exists", newName)
+	}
+
+	rn := propertyRenamer{oldName: oldName, newName: newName}
+	rn.schema(target)
+
+	return nil
+}
+
+type propertyRenamer struct {
+	oldName string
+	newName string
+}
+
+func (rn propertyRenamer) schema(s *Schema) {
+	if p, ok := s.Properties[rn.oldName]; ok {
+		delete(s.Properties, rn.oldName)
+		s.Properties[rn.newName] = p
+	}
+
+	for i, r := range s.Required {
+		if r == rn.oldName {
+			s.Required[i] = rn.newName
+		}
+	}
+
+	if d, ok := s.Dependencies[rn.oldName]; ok {
+		delete(s.Dependencies, rn.oldName)
+		s.Dependencies[rn.newName] = d
+	}
+
+	for _, d := range s.Dependencies {
+		for i, name := range d.StringArray {
+			if name == rn.oldName {
+				d.StringArray[i] = rn.newName
+			}
+		}
+
+		if d.SchemaOrBool != nil {
+			rn.subschema(*d.SchemaOrBool)
+		}
+	}
+
+	for i, e := range s.Examples {
+		s.Examples[i] = rn.value(e)
+	}
+
+	if s.Default != nil {
+		s.WithDefault(rn.value(*s.Default))
+	}
+
+	if named := s.NamedExamples(); len(named) > 0 {
+		for k, e := range named {
+			e.Value = rn.value(e.Value)
+			named[k] = e
+		}
+
+		s.WithExtraPropertiesItem(XNamedExamples, named)
+	}
+
+	for _, list := range [][]SchemaOrBool{s.AllOf, s.AnyOf, s.OneOf} {
+		for _, sub := range list {
+			rn.subschema(sub)
+		}
+	}
+
+	for _, sub := range []*SchemaOrBool{s.Not, s.If, s.Then, s.Else} {
+		if sub != nil {
+			rn.subschema(*sub)
+		}
+	}
+}
+
+func (rn propertyRenamer) subschema(s SchemaOrBool) {
+	if s.TypeObject != nil && s.TypeObject.Ref == nil {
+		rn.schema(s.TypeObject)
+	}
+}
+
+// value renames key of an object value, other values are returned as is.
+func (rn propertyRenamer) value(v interface{}) interface{} {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		// Go values (e.g. structs from samples) are converted to JSON values.
+		j, err := json.Marshal(v)
+		if err != nil {
+			return v
+		}
+
+		if err := json.Unmarshal(j, &m); err != nil {
+			return v
+		}
+	}
+
+	val, ok := m[rn.oldName]
+	if !ok {
+		return v
+	}
+
+	res := make(map[string]interface{}, len(m))
+	for k, mv := range m {
+		res[k] = mv
+	}
+
+	delete(res, rn.oldName)
+	res[rn.newName] = val
+
+	return res
+}
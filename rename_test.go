@@ -0,0 +1,56 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/jsonschema-go"
+)
+
+func TestRenameProperty(t *testing.T) {
+	var doc jsonschema.Schema
+
+	require.NoError(t, json.Unmarshal([]byte(`{
+	  "$ref":"#/definitions/User",
+	  "definitions":{
+		"User":{
+		  "type":"object",
+		  "properties":{"name":{"type":"string"},"email":{"type":"string"}},
+		  "required":["name"],
+		  "dependencies":{"name":["email"],"email":{"required":["name"]}},
+		  "examples":[{"name":"Jane","email":"jane@example.com"}],
+		  "default":{"name":"John"},
+		  "x-examples":{"jane":{"summary":"Jane","value":{"name":"Jane"}}},
+		  "if":{"properties":{"name":{"const":"root"}}},
+		  "then":{"required":["email"]}
+		}
+	  }
+	}`), &doc))
+
+	require.NoError(t, jsonschema.RenameProperty(&doc, "User", "name", "full_name"))
+
+	assertjson.EqMarshal(t, `{
+	  "$ref":"#/definitions/User",
+	  "definitions":{
+		"User":{
+		  "type":"object",
+		  "properties":{"full_name":{"type":"string"},"email":{"type":"string"}},
+		  "required":["full_name"],
+		  "dependencies":{"full_name":["email"],"email":{"required":["full_name"]}},
+		  "examples":[{"full_name":"Jane","email":"jane@example.com"}],
+		  "default":{"full_name":"John"},
+		  "x-examples":{"jane":{"summary":"Jane","value":{"full_name":"Jane"}}},
+		  "if":{"properties":{"full_name":{"const":"root"}}},
+		  "then":{"required":["email"]}
+		}
+	  }
+	}`, doc)
+
+	assert.EqualError(t, jsonschema.RenameProperty(&doc, "Order", "id", "order_id"), "definition not found: Order")
+	assert.EqualError(t, jsonschema.RenameProperty(&doc, "User", "name", "nick"), `property "name" not found`)
+	assert.EqualError(t, jsonschema.RenameProperty(&doc, "User", "email", "full_name"),
+		`property "full_name" already exists`)
+}
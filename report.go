@@ -0,0 +1,179 @@
+package jsonschema
+
+import (
+	"reflect"
+
+	"github.com/swaggest/refl"
+)
+
+// GenerationReport is a machine-readable description of what reflector did, see CollectGenerationReport.
+type GenerationReport struct {
+	// Types lists reflected named types (except predeclared ones) in order of first reflection.
+	Types []ReportedType `json:"types"`
+
+	// Interceptors lists schema and property hooks with number of calls.
+	Interceptors []ReportedInterceptor `json:"interceptors,omitempty"`
+
+	// Diagnostics lists reflection errors, including collected field errors.
+	Diagnostics []string `json:"diagnostics,omitempty"`
+
+	types map[string]int
+}
+
+// ReportedType describes reflected Go type.
+type ReportedType struct {
+	// GoType is a type name with package path, e.g. "github.com/acme/app.Order".
+	GoType string `json:"goType"`
+
+	// Package is an import path of type package.
+	Package string `json:"package,omitempty"`
+
+	// Definition is a name of definition, empty if type schema is inlined.
+	Definition string `json:"definition,omitempty"`
+
+	// SkippedFields lists struct fields that are not reflected as properties.
+	SkippedFields []SkippedField `json:"skippedFields,omitempty"`
+}
+
+// SkippedField describes a struct field that is not reflected as property.
+type SkippedField struct {
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
+
+// ReportedInterceptor describes a hook added with InterceptSchema, InterceptProp or their named versions.
+type ReportedInterceptor struct {
+	// Kind is "schema" or "prop".
+	Kind string `json:"kind"`
+
+	// Name is a name of hook, empty for unnamed hooks.
+	Name string `json:"name,omitempty"`
+
+	// Position is an index of hook in its chain.
+	Position int `json:"position"`
+
+	// Calls is a number of hook invocations.
+	Calls int `json:"calls"`
+}
+
+// Reasons of skipped fields.
+const (
+	SkipReasonIgnored     = "ignored with json:\"-\""
+	SkipReasonNoTag       = "no property name tag"
+	SkipReasonUnexported  = "unexported"
+	SkipReasonDisabled    = "disabled with FieldEnabled"
+	SkipReasonSensitive   = "sensitive"
+	SkipReasonIntercepted = "skipped by InterceptProp"
+	SkipReasonUnsupported = "unsupported type"
+)
+
+// CollectGenerationReport enables generation report, report is filled by every Reflect call that has this option.
+func CollectGenerationReport(report *GenerationReport) func(rc *ReflectContext) {
+	return func(rc *ReflectContext) {
+		rc.Report = report
+	}
+}
+
+func (gr *GenerationReport) typeIndex(t reflect.Type) int {
+	name := goTypeString(t)
+
+	if i, ok := gr.types[name]; ok {
+		return i
+	}
+
+	if gr.types == nil {
+		gr.types = map[string]int{}
+	}
+
+	gr.types[name] = len(gr.Types)
+	gr.Types = append(gr.Types, ReportedType{GoType: name, Package: t.PkgPath()})
+
+	return len(gr.Types) - 1
+}
+
+// reportType adds reflected type to report, if enabled.
+func (rc *ReflectContext) reportType(t reflect.Type) {
+	if rc.Report == nil || t.Name() == "" || t.PkgPath() == "" {
+		return
+	}
+
+	rc.Report.typeIndex(t)
+}
+
+// reportSkippedField adds a field that is not reflected to report, if enabled.
+func (rc *ReflectContext) reportSkippedField(owner reflect.Type, field reflect.StructField, reason string) {
+	if rc.Report == nil || owner == nil || owner.Name() == "" {
+		return
+	}
+
+	i := rc.Report.typeIndex(owner)
+
+	for _, f := range rc.Report.Types[i].SkippedFields {
+		if f.Field == field.Name {
+			return
+		}
+	}
+
+	rc.Report.Types[i].SkippedFields = append(rc.Report.Types[i].SkippedFields, SkippedField{
+		Field:  field.Name,
+		Reason: reason,
+	})
+}
+
+// reportHookCall counts call of a hook, if report is enabled.
+func (rc *ReflectContext) reportHookCall(kind, name string, position int) {
+	if rc.Report == nil {
+		return
+	}
+
+	for i, h := range rc.Report.Interceptors {
+		if h.Kind == kind && h.Position == position && h.Name == name {
+			rc.Report.Interceptors[i].Calls++
+
+			return
+		}
+	}
+
+	rc.Report.Interceptors = append(rc.Report.Interceptors, ReportedInterceptor{
+		Kind:     kind,
+		Name:     name,
+		Position: position,
+		Calls:    1,
+	})
+}
+
+// finishReport adds definitions and diagnostics to report, if enabled.
+func (rc *ReflectContext) finishReport(err error) {
+	if rc.Report == nil {
+		return
+	}
+
+	for _, typeString := range rc.definitionsOrder {
+		def := rc.definitions[typeString]
+		if def == nil || def.ReflectType == nil {
+			continue
+		}
+
+		t := refl.DeepIndirect(def.ReflectType)
+		if t.Name() == "" {
+			continue
+		}
+
+		i := rc.Report.typeIndex(t)
+		rc.Report.Types[i].Definition = rc.definitionRefs[typeString].Name
+	}
+
+	if err == nil {
+		return
+	}
+
+	if fe, ok := err.(FieldErrors); ok { //nolint:errorlint // Reflect returns FieldErrors as is.
+		for _, e := range fe {
+			rc.Report.Diagnostics = append(rc.Report.Diagnostics, e.Error())
+		}
+
+		return
+	}
+
+	rc.Report.Diagnostics = append(rc.Report.Diagnostics, err.Error())
+}
@@ -0,0 +1,62 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/jsonschema-go"
+)
+
+type reportItem struct {
+	SKU    string `json:"sku"`
+	secret string
+}
+
+type reportOrder struct {
+	ID       int          `json:"id"`
+	Items    []reportItem `json:"items"`
+	Internal string       `json:"-"`
+	Token    string       `json:"token" sensitive:"true"`
+	Notes    string
+	Callback func() `json:"callback"`
+}
+
+func TestCollectGenerationReport(t *testing.T) {
+	r := jsonschema.Reflector{}
+	report := jsonschema.GenerationReport{}
+
+	_, err := r.Reflect(reportOrder{},
+		jsonschema.CollectGenerationReport(&report),
+		jsonschema.SensitiveFields(jsonschema.SensitiveDrop),
+		jsonschema.CollectFieldErrors,
+		jsonschema.NamedInterceptProp("noop", func(params jsonschema.InterceptPropParams) error {
+			return nil
+		}),
+	)
+	require.Error(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "types":[
+		{
+		  "goType":"github.com/swaggest/jsonschema-go_test.reportOrder",
+		  "package":"github.com/swaggest/jsonschema-go_test",
+		  "skippedFields":[
+			{"field":"Internal","reason":"ignored with json:\"-\""},
+			{"field":"Token","reason":"sensitive"},
+			{"field":"Notes","reason":"no property name tag"}
+		  ]
+		},
+		{
+		  "goType":"github.com/swaggest/jsonschema-go_test.reportItem",
+		  "package":"github.com/swaggest/jsonschema-go_test",
+		  "definition":"JsonschemaGoTestReportItem",
+		  "skippedFields":[{"field":"secret","reason":"no property name tag"}]
+		}
+	  ],
+	  "interceptors":[
+		{"kind":"prop","name":"noop","position":0,"calls":7}
+	  ],
+	  "diagnostics":["reportOrder.Callback at #: callback: type is not supported: func()"]
+	}`, report)
+}
@@ -0,0 +1,414 @@
+package jsonschema
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Loader loads schema document by absolute URI (without fragment).
+type Loader interface {
+	Load(uri string) ([]byte, error)
+}
+
+// LoaderFunc implements Loader with a function.
+type LoaderFunc func(uri string) ([]byte, error)
+
+// Load implements Loader.
+func (f LoaderFunc) Load(uri string) ([]byte, error) {
+	return f(uri)
+}
+
+// FileLoader loads documents from local files by "file://" URIs or by file paths.
+func FileLoader() Loader {
+	return LoaderFunc(func(uri string) ([]byte, error) {
+		u, err := url.Parse(uri)
+		if err != nil {
+			return nil, err
+		}
+
+		return os.ReadFile(filepath.FromSlash(u.Path))
+	})
+}
+
+// MemoryLoader loads documents from a map by URI, e.g. for tests or embedded schemas.
+type MemoryLoader map[string][]byte
+
+// Load implements Loader.
+func (m MemoryLoader) Load(uri string) ([]byte, error) {
+	if data, ok := m[uri]; ok {
+		return data, nil
+	}
+
+	return nil, fmt.Errorf("document not found: %s", uri)
+}
+
+// Resolver resolves references to other documents, e.g. "common.json#/definitions/Money" or
+// "https://example.com/schemas/money.json".
+//
+// References are resolved against BaseURI, documents are loaded by loaders registered for URI scheme
+// and are cached. Local references of loaded documents are made absolute, so that targets can be
+// used outside of their documents.
+//
+// Resolve can be used as a reference resolver of NewValidator or as DereferenceOptions.Resolver.
+type Resolver struct {
+	// BaseURI is the URI of the root document, e.g. "file:///app/schemas/order.json", working directory is used
+	// if empty.
+	BaseURI string
+
+	mu      sync.Mutex
+	loaders map[string]Loader
+	docs    map[string]*Document
+	errs    map[string]error
+}
+
+// NewResolver creates resolver with FileLoader for "file" scheme and for file paths,
+// other loaders (e.g. HTTPLoader) can be added with AddLoader.
+func NewResolver(baseURI string) *Resolver {
+	r := &Resolver{BaseURI: baseURI}
+
+	r.AddLoader("file", FileLoader())
+	r.AddLoader("", FileLoader())
+
+	return r
+}
+
+// AddLoader sets loader of documents with URI scheme, e.g. "https".
+func (r *Resolver) AddLoader(scheme string, l Loader) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.loaders == nil {
+		r.loaders = map[string]Loader{}
+	}
+
+	r.loaders[scheme] = l
+}
+
+// AddDocument adds a document by URI, so that it is not loaded.
+func (r *Resolver) AddDocument(uri string, schema Schema) error {
+	u, err := r.uri(uri)
+	if err != nil {
+		return err
+	}
+
+	u.Fragment = ""
+
+	schema = cloneSchema(schema)
+	rebaseRefs(&schema, u)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.docs == nil {
+		r.docs = map[string]*Document{}
+	}
+
+	r.docs[u.String()] = NewDocument(schema)
+
+	return nil
+}
+
+// Load returns document by URI, document is loaded on first access.
+func (r *Resolver) Load(uri string) (*Document, error) {
+	u, err := r.uri(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	u.Fragment = ""
+	key := u.String()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if d, ok := r.docs[key]; ok {
+		return d, nil
+	}
+
+	if err, ok := r.errs[key]; ok {
+		return nil, err
+	}
+
+	d, err := r.load(u)
+	if err != nil {
+		err = fmt.Errorf("failed to load %s: %w", key, err)
+
+		if r.errs == nil {
+			r.errs = map[string]error{}
+		}
+
+		r.errs[key] = err
+
+		return nil, err
+	}
+
+	if r.docs == nil {
+		r.docs = map[string]*Document{}
+	}
+
+	r.docs[key] = d
+
+	return d, nil
+}
+
+func (r *Resolver) load(u *url.URL) (*Document, error) {
+	l, ok := r.loaders[u.Scheme]
+	if !ok {
+		return nil, fmt.Errorf("no loader for scheme %q", u.Scheme)
+	}
+
+	data, err := l.Load(u.String())
+	if err != nil {
+		return nil, err
+	}
+
+	var s Schema
+	if err := s.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+
+	rebaseRefs(&s, u)
+
+	return NewDocument(s), nil
+}
+
+// uri resolves reference against base URI.
+func (r *Resolver) uri(ref string) (*url.URL, error) {
+	u, err := url.Parse(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	base := r.BaseURI
+	if base == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, err
+		}
+
+		base = "file://" + filepath.ToSlash(wd) + "/"
+	}
+
+	b, err := url.Parse(base)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.ResolveReference(u), nil
+}
+
+// Resolve returns target of reference, e.g. "common.json#/definitions/Money", false is returned
+// if document can not be loaded or does not have target.
+func (r *Resolver) Resolve(ref string) (SchemaOrBool, bool) {
+	u, err := r.uri(ref)
+	if err != nil {
+		return SchemaOrBool{}, false
+	}
+
+	d, err := r.Load(u.String())
+	if err != nil {
+		return SchemaOrBool{}, false
+	}
+
+	return d.Resolve("#" + u.Fragment)
+}
+
+// Err returns errors of documents that failed to load.
+func (r *Resolver) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	keys := make([]string, 0, len(r.errs))
+	for k := range r.errs {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	errs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		errs = append(errs, r.errs[k].Error())
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return errors.New(strings.Join(errs, ", "))
+}
+
+// Dereference returns a copy of schema with local and external references replaced by their targets,
+// see Dereference.
+func (r *Resolver) Dereference(schema Schema, options ...func(o *DereferenceOptions)) (Schema, error) {
+	options = append([]func(o *DereferenceOptions){func(o *DereferenceOptions) {
+		o.Resolver = r.Resolve
+	}}, options...)
+
+	res, err := Dereference(schema, options...)
+	if err != nil {
+		if lerr := r.Err(); lerr != nil {
+			return res, fmt.Errorf("%w: %s", err, lerr.Error())
+		}
+
+		return res, err
+	}
+
+	return res, nil
+}
+
+// Bundle returns a copy of schema with targets of external references added to definitions,
+// so that document is self-contained.
+//
+// Definitions are named after the last token of reference, e.g. "Money" for "common.json#/definitions/Money"
+// or "money" for "https://example.com/money.json", names are made unique with numeric suffix.
+func (r *Resolver) Bundle(schema Schema) (Schema, error) {
+	root := cloneSchema(schema)
+
+	b := bundler{
+		resolver: r,
+		names:    map[string]string{},
+		used:     map[string]bool{},
+		root:     &root,
+	}
+
+	for name := range root.Definitions {
+		b.used[name] = true
+	}
+
+	if err := b.rewrite(&root); err != nil {
+		return schema, err
+	}
+
+	return root, nil
+}
+
+type bundler struct {
+	resolver *Resolver
+	names    map[string]string
+	used     map[string]bool
+	root     *Schema
+}
+
+// rewrite replaces external references of schema and its subschemas with references to definitions.
+func (b *bundler) rewrite(s *Schema) error {
+	var (
+		refs []*Schema
+		err  error
+	)
+
+	walkSchemas(s, func(s *Schema) {
+		if s.Ref != nil && !strings.HasPrefix(*s.Ref, "#") {
+			refs = append(refs, s)
+		}
+	})
+
+	for _, s := range refs {
+		name, ok := b.names[*s.Ref]
+		if !ok {
+			name, err = b.add(*s.Ref)
+			if err != nil {
+				return err
+			}
+		}
+
+		s.WithRef("#/definitions/" + name)
+	}
+
+	return nil
+}
+
+func (b *bundler) add(ref string) (string, error) {
+	target, ok := b.resolver.Resolve(ref)
+	if !ok {
+		if err := b.resolver.Err(); err != nil {
+			return "", fmt.Errorf("unresolved reference %q: %w", ref, err)
+		}
+
+		return "", fmt.Errorf("unresolved reference %q", ref)
+	}
+
+	name := bundledName(ref)
+	for i := 2; b.used[name]; i++ {
+		name = bundledName(ref) + strconv.Itoa(i)
+	}
+
+	b.used[name] = true
+	b.names[ref] = name
+
+	target = cloneSchemaOrBool(target)
+
+	if target.TypeObject != nil {
+		target.TypeObject.Definitions = nil
+		delete(target.TypeObject.ExtraProperties, "$defs")
+
+		if err := b.rewrite(target.TypeObject); err != nil {
+			return "", err
+		}
+	}
+
+	if b.root.Definitions == nil {
+		b.root.Definitions = map[string]SchemaOrBool{}
+	}
+
+	b.root.Definitions[name] = target
+
+	return name, nil
+}
+
+// bundledName returns definition name for external reference.
+func bundledName(ref string) string {
+	name := ref
+
+	if i := strings.Index(ref, "#"); i >= 0 && strings.Trim(ref[i+1:], "/") != "" {
+		name = ref[i+1:]
+	} else if i >= 0 {
+		name = ref[:i]
+	}
+
+	name = strings.TrimRight(name, "/")
+	name = name[strings.LastIndexAny(name, "/#")+1:]
+
+	if ext := filepath.Ext(name); ext != "" {
+		name = strings.TrimSuffix(name, ext)
+	}
+
+	if unescaped, err := url.PathUnescape(name); err == nil {
+		name = unescaped
+	}
+
+	if name == "" {
+		name = "Schema"
+	}
+
+	return name
+}
+
+// rebaseRefs makes references of schema and its subschemas absolute.
+func rebaseRefs(schema *Schema, base *url.URL) {
+	walkSchemas(schema, func(s *Schema) {
+		if s.Ref != nil {
+			if u, err := url.Parse(*s.Ref); err == nil {
+				s.WithRef(base.ResolveReference(u).String())
+			}
+		}
+
+		// Definitions of draft 2020-12 are stored as typed map, so that rebased references are kept in document.
+		if v, ok := s.ExtraProperties["$defs"]; ok {
+			defs := extraDefinitions(v)
+			s.ExtraProperties["$defs"] = defs
+
+			for _, d := range defs {
+				if d.TypeObject != nil {
+					rebaseRefs(d.TypeObject, base)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,37 @@
+//go:build !tinygo && !jsonschema_lite
+// +build !tinygo,!jsonschema_lite
+
+package jsonschema
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// httpLoaderAvailable is true when HTTPLoader is available.
+const httpLoaderAvailable = true
+
+// HTTPLoader loads documents with HTTP GET requests, http.DefaultClient is used if client is nil.
+func HTTPLoader(client *http.Client) Loader {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return LoaderFunc(func(uri string) ([]byte, error) {
+		resp, err := client.Get(uri) //nolint:noctx // Loader does not have context.
+		if err != nil {
+			return nil, err
+		}
+
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unexpected response status: %s", resp.Status)
+		}
+
+		return io.ReadAll(resp.Body)
+	})
+}
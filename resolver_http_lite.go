@@ -0,0 +1,10 @@
+//go:build tinygo || jsonschema_lite
+// +build tinygo jsonschema_lite
+
+package jsonschema
+
+// httpLoaderAvailable is true when HTTPLoader is available.
+//
+// HTTPLoader is not available in this build (with `tinygo` or `jsonschema_lite` tags) to avoid
+// dependency on net/http, a custom Loader can be added with Resolver.AddLoader instead.
+const httpLoaderAvailable = false
@@ -0,0 +1,40 @@
+//go:build !tinygo && !jsonschema_lite
+// +build !tinygo,!jsonschema_lite
+
+package jsonschema_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/jsonschema-go"
+)
+
+func TestHTTPLoader(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/currency.json" {
+			rw.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		_, _ = rw.Write([]byte(`{"type":"string","pattern":"^[A-Z]{3}$"}`))
+	}))
+	defer srv.Close()
+
+	assert.True(t, jsonschema.BuildCapabilities().HTTPLoader)
+
+	r := jsonschema.NewResolver(srv.URL + "/order.json")
+	r.AddLoader("http", jsonschema.HTTPLoader(nil))
+
+	s, ok := r.Resolve("currency.json")
+	require.True(t, ok)
+	assert.Equal(t, "^[A-Z]{3}$", *s.TypeObject.Pattern)
+
+	_, ok = r.Resolve("missing.json")
+	assert.False(t, ok)
+	assert.EqualError(t, r.Err(), "failed to load "+srv.URL+"/missing.json: unexpected response status: 404 Not Found")
+}
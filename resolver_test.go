@@ -0,0 +1,115 @@
+package jsonschema_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/jsonschema-go"
+)
+
+func TestResolver(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "common.json"), []byte(`{
+	  "definitions":{
+		"Money":{
+		  "type":"object",
+		  "properties":{"amount":{"$ref":"#/definitions/Amount"},"currency":{"$ref":"http://example.com/currency.json"}}
+		},
+		"Amount":{"type":"number"}
+	  }
+	}`), 0o600))
+
+	var order jsonschema.Schema
+	require.NoError(t, order.UnmarshalJSON([]byte(`{
+	  "type":"object",
+	  "properties":{
+		"total":{"$ref":"common.json#/definitions/Money"},
+		"tax":{"$ref":"common.json#/definitions/Money"}
+	  }
+	}`)))
+
+	r := jsonschema.NewResolver("file://" + filepath.ToSlash(dir) + "/order.json")
+
+	// Loader of http scheme is not available by default.
+	_, err := r.Dereference(order)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `no loader for scheme "http"`)
+
+	r = jsonschema.NewResolver("file://" + filepath.ToSlash(dir) + "/order.json")
+	r.AddLoader("http", jsonschema.MemoryLoader{
+		"http://example.com/currency.json": []byte(`{"type":"string","pattern":"^[A-Z]{3}$"}`),
+	})
+
+	d, err := r.Dereference(order)
+	require.NoError(t, err)
+	assertjson.EqMarshal(t, `{
+	  "properties":{
+		"tax":{
+		  "properties":{"amount":{"type":"number"},"currency":{"pattern":"^[A-Z]{3}$","type":"string"}},
+		  "type":"object"
+		},
+		"total":{
+		  "properties":{"amount":{"type":"number"},"currency":{"pattern":"^[A-Z]{3}$","type":"string"}},
+		  "type":"object"
+		}
+	  },
+	  "type":"object"
+	}`, d)
+
+	b, err := r.Bundle(order)
+	require.NoError(t, err)
+	assertjson.EqMarshal(t, `{
+	  "definitions":{
+		"Amount":{"type":"number"},
+		"Money":{
+		  "properties":{
+			"amount":{"$ref":"#/definitions/Amount"},"currency":{"$ref":"#/definitions/currency"}
+		  },
+		  "type":"object"
+		},
+		"currency":{"pattern":"^[A-Z]{3}$","type":"string"}
+	  },
+	  "properties":{
+		"tax":{"$ref":"#/definitions/Money"},"total":{"$ref":"#/definitions/Money"}
+	  },
+	  "type":"object"
+	}`, b)
+
+	// Bundled schema is valid without resolver.
+	v := jsonschema.NewValidator(b)
+	assert.NoError(t, v.ValidateJSON([]byte(`{"total":{"amount":1,"currency":"EUR"}}`)))
+	assert.Error(t, v.ValidateJSON([]byte(`{"total":{"amount":1,"currency":"euro"}}`)))
+
+	// Resolver can be used by validator directly.
+	v = jsonschema.NewValidator(order, r.Resolve)
+	assert.Error(t, v.ValidateJSON([]byte(`{"tax":{"amount":"1"}}`)))
+}
+
+func TestResolver_AddDocument(t *testing.T) {
+	r := jsonschema.NewResolver("mem://schemas/order.json")
+	r.AddLoader("mem", jsonschema.MemoryLoader{
+		"mem://schemas/money.json": []byte(`{"$defs":{"Money":{"type":"integer"}},"$ref":"#/$defs/Money"}`),
+	})
+
+	var item jsonschema.Schema
+
+	item.AddType(jsonschema.Object)
+	item.WithPropertiesItem("price", (&jsonschema.Schema{}).WithRef("money.json").ToSchemaOrBool())
+	require.NoError(t, r.AddDocument("item.json", item))
+
+	s, ok := r.Resolve("item.json#/properties/price")
+	require.True(t, ok)
+	assert.Equal(t, "mem://schemas/money.json", *s.TypeObject.Ref)
+
+	s, ok = r.Resolve("mem://schemas/money.json")
+	require.True(t, ok)
+	assert.Equal(t, "mem://schemas/money.json#/$defs/Money", *s.TypeObject.Ref)
+
+	_, ok = r.Resolve("missing.json")
+	assert.False(t, ok)
+	assert.EqualError(t, r.Err(), "failed to load mem://schemas/missing.json: document not found: mem://schemas/missing.json")
+}
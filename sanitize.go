@@ -0,0 +1,215 @@
+package jsonschema
+
+import (
+	"strings"
+)
+
+// SanitizeOptions configures Sanitize.
+type SanitizeOptions struct {
+	// ExtensionPrefixes lists prefixes of extension keywords to remove, default "x-internal".
+	ExtensionPrefixes []string
+
+	// KeepComments disables removal of `$comment`.
+	KeepComments bool
+
+	// KeepGoAnnotations disables removal of `x-go-type`, `x-go-name` and `x-go-source`.
+	KeepGoAnnotations bool
+
+	// InternalDefinition matches definitions (in `definitions` or `$defs`) that must not be published.
+	InternalDefinition func(name string, schema SchemaOrBool) bool
+}
+
+// Sanitize returns a copy of schema that is prepared for public distribution.
+//
+// Internal extensions (see SanitizeOptions.ExtensionPrefixes), comments and Go type annotations are removed.
+// Internal definitions are removed together with properties and anyOf/oneOf alternatives that refer to them,
+// definitions that were only reachable through removed ones are removed too. If an internal definition
+// remains referenced from another location (e.g. from items), RefProblems error is returned with the copy.
+func Sanitize(schema Schema, options ...func(o *SanitizeOptions)) (Schema, error) {
+	o := SanitizeOptions{ExtensionPrefixes: []string{"x-internal"}}
+
+	for _, option := range options {
+		option(&o)
+	}
+
+	s := cloneSchema(schema)
+	sz := sanitizer{o: o, internal: map[string]bool{}}
+
+	defs := cloneSchemaMap(extraDefinitions(s.ExtraProperties["$defs"]))
+	if defs != nil {
+		s.ExtraProperties["$defs"] = defs
+	}
+
+	unreachable := sz.unreachable(s)
+
+	sz.removeInternal(s.Definitions, "#/definitions/")
+	sz.removeInternal(defs, "#/$defs/")
+
+	sz.schemas(&s)
+
+	for _, d := range defs {
+		sz.schemas(d.TypeObject)
+	}
+
+	// Definitions that are no longer reachable were only used by removed schemas.
+	for ptr := range sz.unreachable(s) {
+		if unreachable[ptr] {
+			continue
+		}
+
+		if name := strings.TrimPrefix(ptr, "/definitions/"); name != ptr {
+			delete(s.Definitions, pointerUnescaper.Replace(name))
+		} else if name := strings.TrimPrefix(ptr, "/$defs/"); name != ptr {
+			delete(defs, pointerUnescaper.Replace(name))
+		}
+	}
+
+	var problems RefProblems
+
+	if len(sz.internal) > 0 {
+		for _, p := range NewDocument(s).CheckRefs() {
+			if p.Kind == UnresolvedRef && sz.internal[p.Ref] {
+				problems = append(problems, p)
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return s, problems
+	}
+
+	return s, nil
+}
+
+type sanitizer struct {
+	o SanitizeOptions
+
+	// internal contains references to removed definitions.
+	internal map[string]bool
+}
+
+func (sz *sanitizer) removeInternal(defs map[string]SchemaOrBool, prefix string) {
+	if sz.o.InternalDefinition == nil {
+		return
+	}
+
+	for name, d := range defs {
+		if sz.o.InternalDefinition(name, d) {
+			sz.internal[prefix+escapePointerToken(name)] = true
+
+			delete(defs, name)
+		}
+	}
+}
+
+// unreachable returns pointers of definitions that are not reachable from root schema.
+func (sz *sanitizer) unreachable(s Schema) map[string]bool {
+	res := map[string]bool{}
+
+	for _, p := range NewDocument(s).CheckRefs() {
+		if p.Kind == UnreachableDefinition {
+			res[p.Pointer] = true
+		}
+	}
+
+	return res
+}
+
+func (sz *sanitizer) schemas(s *Schema) {
+	walkSchemas(s, sz.schema)
+}
+
+func (sz *sanitizer) schema(s *Schema) {
+	for k := range s.ExtraProperties {
+		if sz.removedKeyword(k) {
+			delete(s.ExtraProperties, k)
+		}
+	}
+
+	if len(s.ExtraProperties) == 0 {
+		s.ExtraProperties = nil
+	}
+
+	if !sz.o.KeepComments {
+		s.Comment = nil
+	}
+
+	if len(sz.internal) == 0 {
+		return
+	}
+
+	for name, p := range s.Properties {
+		if sz.refersInternal(p.TypeObject) {
+			delete(s.Properties, name)
+
+			s.Required = removeString(s.Required, name)
+			s.PropertiesOrder = removeString(s.PropertiesOrder, name)
+		}
+	}
+
+	s.AnyOf = sz.alternatives(s.AnyOf)
+	s.OneOf = sz.alternatives(s.OneOf)
+}
+
+func (sz *sanitizer) removedKeyword(k string) bool {
+	if !sz.o.KeepGoAnnotations && (k == XGoType || k == XGoName || k == XGoSource) {
+		return true
+	}
+
+	for _, prefix := range sz.o.ExtensionPrefixes {
+		if strings.HasPrefix(k, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (sz *sanitizer) alternatives(l []SchemaOrBool) []SchemaOrBool {
+	if len(l) == 0 {
+		return l
+	}
+
+	res := l[:0]
+
+	for _, a := range l {
+		if !sz.refersInternal(a.TypeObject) {
+			res = append(res, a)
+		}
+	}
+
+	if len(res) == 0 {
+		return nil
+	}
+
+	return res
+}
+
+// refersInternal checks if schema or its subschemas refer to removed definitions.
+func (sz *sanitizer) refersInternal(s *Schema) bool {
+	found := false
+
+	walkSchemas(s, func(s *Schema) {
+		if s.Ref != nil && sz.internal[*s.Ref] {
+			found = true
+		}
+	})
+
+	return found
+}
+
+func removeString(l []string, s string) []string {
+	if l == nil {
+		return nil
+	}
+
+	res := make([]string, 0, len(l))
+
+	for _, v := range l {
+		if v != s {
+			res = append(res, v)
+		}
+	}
+
+	return res
+}
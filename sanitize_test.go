@@ -0,0 +1,90 @@
+package jsonschema_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/jsonschema-go"
+)
+
+func TestSanitize(t *testing.T) {
+	type Audit struct {
+		Actor string `json:"actor"`
+	}
+
+	type InternalState struct {
+		Audit Audit `json:"audit"`
+	}
+
+	type Order struct {
+		ID       string         `json:"id" required:"true"`
+		Note     string         `json:"note,omitempty"`
+		State    InternalState  `json:"state" required:"true"`
+		StatePtr *InternalState `json:"statePtr,omitempty"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(Order{}, jsonschema.GoTypeAnnotations,
+		jsonschema.InterceptSchema(func(params jsonschema.InterceptSchemaParams) (stop bool, err error) {
+			if params.Processed {
+				params.Schema.WithComment("generated")
+			}
+
+			return false, nil
+		}),
+		jsonschema.InterceptProp(func(params jsonschema.InterceptPropParams) error {
+			if params.Processed {
+				params.PropertySchema.WithExtraPropertiesItem("x-internal-owner", "billing")
+				params.PropertySchema.WithExtraPropertiesItem("x-public", params.Name == "note")
+			}
+
+			return nil
+		}),
+	)
+	require.NoError(t, err)
+
+	orig, err := assertjson.MarshalIndentCompact(s, "", " ", 120)
+	require.NoError(t, err)
+
+	ss, err := jsonschema.Sanitize(s, func(o *jsonschema.SanitizeOptions) {
+		o.InternalDefinition = func(name string, _ jsonschema.SchemaOrBool) bool {
+			return strings.HasSuffix(name, "InternalState")
+		}
+	})
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "required":["id"],
+	  "properties":{
+		"id":{"type":"string","x-public":false},
+		"note":{"type":"string","x-public":true}
+	  },
+	  "type":"object"
+	}`, ss)
+
+	// Original schema is not changed.
+	assertjson.EqMarshal(t, string(orig), s)
+}
+
+func TestSanitize_referenced(t *testing.T) {
+	s, err := jsonschema.ParseSchema([]byte(`{
+	  "$comment":"keep",
+	  "items":{"$ref":"#/$defs/Secret"},
+	  "$defs":{"Secret":{"type":"string","x-internal-note":"a"}}
+	}`))
+	require.NoError(t, err)
+
+	ss, err := jsonschema.Sanitize(s, func(o *jsonschema.SanitizeOptions) {
+		o.KeepComments = true
+		o.InternalDefinition = func(name string, _ jsonschema.SchemaOrBool) bool {
+			return name == "Secret"
+		}
+	})
+	assert.EqualError(t, err, "invalid references: /items: unresolved reference #/$defs/Secret")
+
+	assertjson.EqMarshal(t, `{"$comment":"keep","items":{"$ref":"#/$defs/Secret"},"$defs":{}}`, ss)
+}
@@ -0,0 +1,309 @@
+// Package schemagen generates JSON Schema files for many types from a single config file.
+//
+// Go types can not be loaded by name at runtime, so types are registered in a small generator program,
+// that is usually invoked with go:generate:
+//
+//	//go:build ignore
+//
+//	package main
+//
+//	func main() {
+//		schemagen.Main(schemagen.Generator{
+//			Types: map[string]interface{}{
+//				"Order": orders.Order{},
+//				"User":  users.User{},
+//			},
+//		})
+//	}
+//
+// Config lists type to output mappings with optional per entry options:
+//
+//	defaults:
+//	  definitionsPrefix: "#/$defs/"
+//	entries:
+//	  - type: Order
+//	    output: schemas/order.json
+//	  - type: User
+//	    output: schemas/user.json
+//	    preset: public
+package schemagen
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/swaggest/jsonschema-go"
+	"github.com/swaggest/jsonschema-go/schematest"
+	"gopkg.in/yaml.v3"
+)
+
+// Dialects of generated schemas.
+const (
+	// Draft07 is a dialect of generated schemas, it is used by default.
+	Draft07 = "draft-07"
+
+	// Draft2020 is JSON Schema draft 2020-12 dialect, see jsonschema.TargetDraft.
+	Draft2020 = "draft-2020-12"
+)
+
+// ErrOutdated is returned by Check when generated schema differs from output file.
+var ErrOutdated = errors.New("schema files are outdated")
+
+// Options configures generation of an entry.
+type Options struct {
+	// Dialect adds `$schema` keyword of dialect, "draft-07" or "draft-2020-12", omitted if empty.
+	Dialect string `json:"dialect,omitempty" yaml:"dialect,omitempty"`
+
+	// DefinitionsPrefix sets path prefix for definitions, e.g. "#/components/schemas/".
+	DefinitionsPrefix string `json:"definitionsPrefix,omitempty" yaml:"definitionsPrefix,omitempty"`
+
+	// StripDefinitionNamePrefix removes prefixes from definition names, e.g. "Orders".
+	StripDefinitionNamePrefix []string `json:"stripDefinitionNamePrefix,omitempty" yaml:"stripDefinitionNamePrefix,omitempty"`
+
+	// Preset is a name of reflect options registered in Generator.Presets.
+	Preset string `json:"preset,omitempty" yaml:"preset,omitempty"`
+}
+
+// Entry maps registered type to output file.
+type Entry struct {
+	// Type is a name of registered type.
+	Type string `json:"type" yaml:"type"`
+
+	// Output is a path to schema file, relative paths are resolved against config directory.
+	Output string `json:"output" yaml:"output"`
+
+	Options `yaml:",inline"`
+}
+
+// Config lists schemas to generate.
+type Config struct {
+	// Defaults are applied to entries that do not set an option.
+	Defaults Options `json:"defaults,omitempty" yaml:"defaults,omitempty"`
+
+	Entries []Entry `json:"entries" yaml:"entries"`
+
+	// Dir is a base directory of relative outputs, it is set by LoadConfig.
+	Dir string `json:"-" yaml:"-"`
+}
+
+// LoadConfig reads config from YAML (.yaml, .yml) or JSON file.
+func LoadConfig(path string) (Config, error) {
+	var cfg Config
+
+	data, err := os.ReadFile(path) //nolint:gosec // Path is controlled by user.
+	if err != nil {
+		return cfg, err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		err = dec.Decode(&cfg)
+	default:
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&cfg)
+	}
+
+	if err != nil {
+		return cfg, fmt.Errorf("parsing config %s: %w", path, err)
+	}
+
+	cfg.Dir = filepath.Dir(path)
+
+	return cfg, nil
+}
+
+// Generator reflects registered types according to config.
+type Generator struct {
+	// Reflector is used to reflect types, default Reflector is used if nil.
+	Reflector *jsonschema.Reflector
+
+	// Types are samples of types by name that is used in config.
+	Types map[string]interface{}
+
+	// Presets are reflect options by name that is used in config.
+	Presets map[string][]func(rc *jsonschema.ReflectContext)
+}
+
+// Generate returns canonical schema files contents by output paths.
+func (g Generator) Generate(cfg Config) (map[string][]byte, error) {
+	r := g.Reflector
+	if r == nil {
+		r = &jsonschema.Reflector{}
+	}
+
+	res := make(map[string][]byte, len(cfg.Entries))
+
+	for _, e := range cfg.Entries {
+		out := e.Output
+		if !filepath.IsAbs(out) && cfg.Dir != "" {
+			out = filepath.Join(cfg.Dir, out)
+		}
+
+		if _, ok := res[out]; ok {
+			return nil, fmt.Errorf("%s: duplicate output %s", e.Type, e.Output)
+		}
+
+		data, err := g.generate(r, e.Options.withDefaults(cfg.Defaults), e.Type)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", e.Type, err)
+		}
+
+		res[out] = data
+	}
+
+	return res, nil
+}
+
+func (o Options) withDefaults(d Options) Options {
+	if o.Dialect == "" {
+		o.Dialect = d.Dialect
+	}
+
+	if o.DefinitionsPrefix == "" {
+		o.DefinitionsPrefix = d.DefinitionsPrefix
+	}
+
+	if o.StripDefinitionNamePrefix == nil {
+		o.StripDefinitionNamePrefix = d.StripDefinitionNamePrefix
+	}
+
+	if o.Preset == "" {
+		o.Preset = d.Preset
+	}
+
+	return o
+}
+
+func (g Generator) generate(r *jsonschema.Reflector, o Options, typeName string) ([]byte, error) {
+	sample, ok := g.Types[typeName]
+	if !ok {
+		return nil, errors.New("type is not registered")
+	}
+
+	var options []func(rc *jsonschema.ReflectContext)
+
+	if o.Dialect == Draft2020 {
+		options = append(options, jsonschema.TargetDraft(jsonschema.DraftVersion2020))
+	}
+
+	if o.DefinitionsPrefix != "" {
+		options = append(options, jsonschema.DefinitionsPrefix(o.DefinitionsPrefix))
+	}
+
+	if len(o.StripDefinitionNamePrefix) > 0 {
+		options = append(options, jsonschema.StripDefinitionNamePrefix(o.StripDefinitionNamePrefix...))
+	}
+
+	if o.Preset != "" {
+		preset, ok := g.Presets[o.Preset]
+		if !ok {
+			return nil, fmt.Errorf("unknown preset %q", o.Preset)
+		}
+
+		options = append(options, preset...)
+	}
+
+	s, err := r.Reflect(sample, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	switch o.Dialect {
+	case "":
+	case Draft07:
+		s.WithSchema("http://json-schema.org/draft-07/schema#")
+	case Draft2020:
+	default:
+		return nil, fmt.Errorf("unsupported dialect %q", o.Dialect)
+	}
+
+	return schematest.Canonical(s)
+}
+
+// Write generates schema files.
+func (g Generator) Write(cfg Config) error {
+	files, err := g.Generate(cfg)
+	if err != nil {
+		return err
+	}
+
+	for _, path := range sortedPaths(files) {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil { //nolint:gosec // Schemas are not secret.
+			return err
+		}
+
+		if err := os.WriteFile(path, files[path], 0o644); err != nil { //nolint:gosec // Schemas are not secret.
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Check returns ErrOutdated listing files that are missing or differ from generated schemas.
+func (g Generator) Check(cfg Config) error {
+	files, err := g.Generate(cfg)
+	if err != nil {
+		return err
+	}
+
+	var outdated []string
+
+	for _, path := range sortedPaths(files) {
+		existing, err := os.ReadFile(path) //nolint:gosec // Path is controlled by user.
+		if err != nil || !bytes.Equal(existing, files[path]) {
+			outdated = append(outdated, path)
+		}
+	}
+
+	if len(outdated) > 0 {
+		return fmt.Errorf("%w: %s", ErrOutdated, strings.Join(outdated, ", "))
+	}
+
+	return nil
+}
+
+// Main runs generator with command line flags, it is intended to be called from main function.
+//
+//	-config path  config file (default "schemagen.yaml")
+//	-check        fail if schema files are outdated instead of writing them
+func Main(g Generator) {
+	configPath := flag.String("config", "schemagen.yaml", "path to config file")
+	check := flag.Bool("check", false, "fail if schema files are outdated instead of writing them")
+	flag.Parse()
+
+	cfg, err := LoadConfig(*configPath)
+	if err == nil {
+		if *check {
+			err = g.Check(cfg)
+		} else {
+			err = g.Write(cfg)
+		}
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func sortedPaths(files map[string][]byte) []string {
+	paths := make([]string, 0, len(files))
+	for p := range files {
+		paths = append(paths, p)
+	}
+
+	sort.Strings(paths)
+
+	return paths
+}
@@ -0,0 +1,75 @@
+package schemagen_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/jsonschema-go"
+	"github.com/swaggest/jsonschema-go/schemagen"
+)
+
+type Address struct {
+	City string `json:"city"`
+}
+
+type User struct {
+	Name    string  `json:"name"`
+	Secret  string  `json:"secret" sensitive:"true"`
+	Address Address `json:"address"`
+}
+
+func TestGenerator_Write(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "schemagen.yaml")
+
+	require.NoError(t, os.WriteFile(configPath, []byte(`
+defaults:
+  dialect: draft-07
+  stripDefinitionNamePrefix: [Schemagen]
+entries:
+  - type: User
+    output: schemas/user.json
+  - type: User
+    output: schemas/public/user.json
+    preset: public
+    definitionsPrefix: "#/components/schemas/"
+`), 0o600))
+
+	cfg, err := schemagen.LoadConfig(configPath)
+	require.NoError(t, err)
+
+	g := schemagen.Generator{
+		Types: map[string]interface{}{"User": User{}},
+		Presets: map[string][]func(rc *jsonschema.ReflectContext){
+			"public": {jsonschema.SensitiveFields(jsonschema.SensitiveDrop)},
+		},
+	}
+
+	assert.ErrorIs(t, g.Check(cfg), schemagen.ErrOutdated)
+	require.NoError(t, g.Write(cfg))
+	require.NoError(t, g.Check(cfg))
+
+	public, err := os.ReadFile(filepath.Join(dir, "schemas/public/user.json"))
+	require.NoError(t, err)
+
+	assertjson.Equal(t, []byte(`{
+	  "$schema":"http://json-schema.org/draft-07/schema#",
+	  "definitions":{"TestAddress":{"properties":{"city":{"type":"string"}},"type":"object"}},
+	  "properties":{
+		"address":{"$ref":"#/components/schemas/TestAddress"},
+		"name":{"type":"string"}
+	  },
+	  "type":"object"
+	}`), public)
+
+	user, err := os.ReadFile(filepath.Join(dir, "schemas/user.json"))
+	require.NoError(t, err)
+	assert.Contains(t, string(user), `"secret"`)
+
+	cfg.Entries[0].Type = "Unknown"
+	assert.EqualError(t, g.Write(cfg), "Unknown: type is not registered")
+}
@@ -0,0 +1,91 @@
+package schematest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/swaggest/jsonschema-go"
+)
+
+// AssertSchema validates example corpora with schema, all validSamples must pass and all invalidSamples must fail.
+//
+// Samples of []byte or json.RawMessage type are used as JSON documents, other samples are marshaled
+// with encoding/json. Every rejected valid sample is reported with instance and schema locations of errors,
+// every accepted invalid sample is reported with its JSON.
+func AssertSchema(t TestingT, schema jsonschema.Schema, validSamples, invalidSamples []interface{}) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	v := jsonschema.NewValidator(schema)
+	ok := true
+
+	for i, sample := range validSamples {
+		doc, err := sampleJSON(sample)
+		if err != nil {
+			t.Errorf("valid sample #%d: %v", i, err)
+
+			ok = false
+
+			continue
+		}
+
+		err = v.ValidateJSON(doc)
+		if err == nil {
+			continue
+		}
+
+		ok = false
+
+		var ve jsonschema.ValidationErrors
+		if !errors.As(err, &ve) {
+			t.Errorf("valid sample #%d is rejected: %v", i, err)
+
+			continue
+		}
+
+		lines := make([]string, 0, len(ve))
+		for _, e := range ve {
+			lines = append(lines, fmt.Sprintf("  %s (%s)", e.Error(), e.SchemaPath))
+		}
+
+		t.Errorf("valid sample #%d is rejected: %s\n%s", i, doc, strings.Join(lines, "\n"))
+	}
+
+	for i, sample := range invalidSamples {
+		doc, err := sampleJSON(sample)
+		if err != nil {
+			t.Errorf("invalid sample #%d: %v", i, err)
+
+			ok = false
+
+			continue
+		}
+
+		if err := v.ValidateJSON(doc); err == nil {
+			t.Errorf("invalid sample #%d is accepted: %s", i, doc)
+
+			ok = false
+		}
+	}
+
+	return ok
+}
+
+func sampleJSON(sample interface{}) ([]byte, error) {
+	switch s := sample.(type) {
+	case []byte:
+		return s, nil
+	case json.RawMessage:
+		return s, nil
+	}
+
+	doc, err := json.Marshal(sample)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal sample: %w", err)
+	}
+
+	return doc, nil
+}
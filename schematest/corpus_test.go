@@ -0,0 +1,35 @@
+package schematest_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/jsonschema-go"
+	"github.com/swaggest/jsonschema-go/schematest"
+)
+
+func TestAssertSchema(t *testing.T) {
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(Order{})
+	require.NoError(t, err)
+
+	assert.True(t, schematest.AssertSchema(t, s,
+		[]interface{}{Order{ID: 1}, []byte(`{"id":5,"title":"foo"}`)},
+		[]interface{}{Order{ID: 0}, []byte(`{"title":1}`)},
+	))
+
+	rec := &recorder{}
+
+	assert.False(t, schematest.AssertSchema(rec, s,
+		[]interface{}{[]byte(`{"id":0,"title":"foo"}`)},
+		[]interface{}{map[string]interface{}{"id": 3}},
+	))
+
+	assert.Equal(t, []string{
+		"valid sample #0 is rejected: {\"id\":0,\"title\":\"foo\"}\n" +
+			"  /id: value 0 must be greater than or equal to 1 (#/properties/id/minimum)",
+		`invalid sample #0 is accepted: {"id":3}`,
+	}, rec.errs)
+}
@@ -0,0 +1,108 @@
+// Package schematest provides helpers to test JSON Schemas.
+package schematest
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/jsonschema-go"
+)
+
+// UpdateEnv is the name of environment variable that enables update of golden files, e.g. UPDATE_GOLDEN=1.
+const UpdateEnv = "UPDATE_GOLDEN"
+
+// TestingT is a subset of testing.TB.
+type TestingT interface {
+	Errorf(format string, args ...interface{})
+}
+
+type tHelper interface {
+	Helper()
+}
+
+// Golden reflects JSON Schema of a sample and compares it with contents of golden file.
+//
+// Schema is written to file if file does not exist or if UpdateEnv environment variable is not empty.
+// Result is canonicalized with sorted keys and indentation to keep golden files diff-friendly.
+func Golden(t TestingT, sample interface{}, path string, options ...func(rc *jsonschema.ReflectContext)) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(sample, options...)
+	if err != nil {
+		t.Errorf("failed to reflect %T: %v", sample, err)
+
+		return false
+	}
+
+	return GoldenSchema(t, s, path)
+}
+
+// GoldenSchema compares schema with contents of golden file.
+//
+// Schema is written to file if file does not exist or if UpdateEnv environment variable is not empty.
+func GoldenSchema(t TestingT, schema jsonschema.Schema, path string) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	actual, err := Canonical(schema)
+	if err != nil {
+		t.Errorf("failed to marshal schema: %v", err)
+
+		return false
+	}
+
+	expected, err := os.ReadFile(path) //nolint:gosec // Path is controlled by test.
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("failed to read golden file: %v", err)
+
+		return false
+	}
+
+	if err != nil || os.Getenv(UpdateEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Errorf("failed to create directory for golden file: %v", err)
+
+			return false
+		}
+
+		if err := os.WriteFile(path, actual, 0o600); err != nil {
+			t.Errorf("failed to write golden file: %v", err)
+
+			return false
+		}
+
+		return true
+	}
+
+	return assertjson.Equal(t, expected, actual, "schema does not match golden file %s, "+
+		"run tests with %s=1 to update", path, UpdateEnv)
+}
+
+// Canonical marshals schema as indented JSON with sorted keys and trailing new line.
+func Canonical(schema jsonschema.Schema) ([]byte, error) {
+	j, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	var v interface{}
+
+	if err := json.Unmarshal(j, &v); err != nil {
+		return nil, err
+	}
+
+	j, err = json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(j, '\n'), nil
+}
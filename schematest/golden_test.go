@@ -0,0 +1,67 @@
+package schematest_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/jsonschema-go/schematest"
+)
+
+type recorder struct {
+	errs []string
+}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errs = append(r.errs, fmt.Sprintf(format, args...))
+}
+
+type Order struct {
+	ID    int    `json:"id" minimum:"1"`
+	Title string `json:"title"`
+}
+
+type OrderChanged struct {
+	ID    int    `json:"id" minimum:"2"`
+	Title string `json:"title"`
+}
+
+func TestGolden(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "testdata", "order.json")
+
+	// Golden file is created on first run.
+	assert.True(t, schematest.Golden(t, Order{}, path))
+
+	j, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, `{
+  "properties": {
+    "id": {
+      "minimum": 1,
+      "type": "integer"
+    },
+    "title": {
+      "type": "string"
+    }
+  },
+  "type": "object"
+}
+`, string(j))
+
+	assert.True(t, schematest.Golden(t, Order{}, path))
+
+	rec := &recorder{}
+	assert.False(t, schematest.Golden(rec, OrderChanged{}, path))
+	require.Len(t, rec.errs, 1)
+	assert.Contains(t, rec.errs[0], `"minimum": 1`)
+	assert.Contains(t, rec.errs[0], "UPDATE_GOLDEN=1")
+
+	t.Setenv(schematest.UpdateEnv, "1")
+	assert.True(t, schematest.Golden(t, OrderChanged{}, path))
+
+	t.Setenv(schematest.UpdateEnv, "")
+	assert.True(t, schematest.Golden(t, OrderChanged{}, path))
+}
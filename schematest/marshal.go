@@ -0,0 +1,266 @@
+package schematest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/swaggest/jsonschema-go"
+)
+
+// MarshalMismatch describes a difference between schema and JSON produced by encoding/json.
+type MarshalMismatch struct {
+	// InstancePath is a JSON Pointer to the value in marshaled document, e.g. "/items/0/name".
+	InstancePath string
+
+	// Problem is a human-readable description of mismatch.
+	Problem string
+}
+
+// String implements fmt.Stringer.
+func (m MarshalMismatch) String() string {
+	p := m.InstancePath
+	if p == "" {
+		p = "/"
+	}
+
+	return p + ": " + m.Problem
+}
+
+// CheckMarshal marshals sample with encoding/json and checks the result against schema.
+//
+// Validation errors are reported together with properties that are present in marshaled JSON but are not
+// declared in schema (e.g. renamed by custom MarshalJSON or by a tag that reflector does not read),
+// even if schema allows additional properties. Values of schemas without properties are not checked
+// for undeclared properties.
+func CheckMarshal(schema jsonschema.Schema, sample interface{}) ([]MarshalMismatch, error) {
+	doc, err := json.Marshal(sample)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal sample: %w", err)
+	}
+
+	var res []MarshalMismatch
+
+	err = jsonschema.NewValidator(schema).ValidateJSON(doc)
+	if err != nil {
+		var ve jsonschema.ValidationErrors
+		if !errors.As(err, &ve) {
+			return nil, err
+		}
+
+		for _, e := range ve {
+			res = append(res, MarshalMismatch{
+				InstancePath: e.InstancePath,
+				Problem:      e.Message + " (" + e.SchemaPath + ")",
+			})
+		}
+	}
+
+	var value interface{}
+
+	d := json.NewDecoder(bytes.NewReader(doc))
+	d.UseNumber()
+
+	if err := d.Decode(&value); err != nil {
+		return nil, fmt.Errorf("failed to decode marshaled sample: %w", err)
+	}
+
+	c := marshalChecker{doc: jsonschema.NewDocument(schema)}
+	res = append(res, c.undeclared(schema.ToSchemaOrBool(), value, "")...)
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].InstancePath < res[j].InstancePath
+	})
+
+	return res, nil
+}
+
+// AssertMarshal reflects schema of sample and reports mismatches with marshaled sample, see CheckMarshal.
+func AssertMarshal(t TestingT, sample interface{}, options ...func(rc *jsonschema.ReflectContext)) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(sample, options...)
+	if err != nil {
+		t.Errorf("failed to reflect %T: %v", sample, err)
+
+		return false
+	}
+
+	mm, err := CheckMarshal(s, sample)
+	if err != nil {
+		t.Errorf("failed to check %T: %v", sample, err)
+
+		return false
+	}
+
+	if len(mm) == 0 {
+		return true
+	}
+
+	lines := make([]string, 0, len(mm))
+	for _, m := range mm {
+		lines = append(lines, "  "+m.String())
+	}
+
+	t.Errorf("marshaled %T does not match schema:\n%s", sample, strings.Join(lines, "\n"))
+
+	return false
+}
+
+type marshalChecker struct {
+	doc *jsonschema.Document
+}
+
+// schemas returns schema with resolved reference and all its allOf, anyOf and oneOf subschemas.
+func (c marshalChecker) schemas(sb jsonschema.SchemaOrBool, res []*jsonschema.Schema, seen map[*jsonschema.Schema]bool) []*jsonschema.Schema {
+	s := sb.TypeObject
+
+	for i := 0; s != nil && s.Ref != nil && i < 100; i++ {
+		r, ok := c.doc.Resolve(*s.Ref)
+		if !ok {
+			return res
+		}
+
+		s = r.TypeObject
+	}
+
+	if s == nil || seen[s] {
+		return res
+	}
+
+	seen[s] = true
+	res = append(res, s)
+
+	for _, l := range [][]jsonschema.SchemaOrBool{s.AllOf, s.AnyOf, s.OneOf} {
+		for _, sub := range l {
+			res = c.schemas(sub, res, seen)
+		}
+	}
+
+	return res
+}
+
+// undeclared returns properties of value that are not declared in schema.
+//
+// Alternatives (e.g. of anyOf) are merged, the best matching property schema is used for nested values.
+func (c marshalChecker) undeclared(sb jsonschema.SchemaOrBool, value interface{}, path string) []MarshalMismatch {
+	ss := c.schemas(sb, nil, map[*jsonschema.Schema]bool{})
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return c.undeclaredProperties(ss, v, path)
+	case []interface{}:
+		var res []MarshalMismatch
+
+		for i, item := range v {
+			var candidates []jsonschema.SchemaOrBool
+
+			for _, s := range ss {
+				switch {
+				case s.Items == nil:
+				case s.Items.SchemaOrBool != nil:
+					candidates = append(candidates, *s.Items.SchemaOrBool)
+				case i < len(s.Items.SchemaArray):
+					candidates = append(candidates, s.Items.SchemaArray[i])
+				case s.AdditionalItems != nil:
+					candidates = append(candidates, *s.AdditionalItems)
+				}
+			}
+
+			res = append(res, c.bestMatch(candidates, item, path+"/"+strconv.Itoa(i))...)
+		}
+
+		return res
+	}
+
+	return nil
+}
+
+func (c marshalChecker) undeclaredProperties(ss []*jsonschema.Schema, obj map[string]interface{}, path string) []MarshalMismatch {
+	describes := false
+	additional := false
+
+	for _, s := range ss {
+		if len(s.Properties) > 0 || len(s.PatternProperties) > 0 {
+			describes = true
+		}
+
+		if s.AdditionalProperties != nil &&
+			(s.AdditionalProperties.TypeBoolean == nil || *s.AdditionalProperties.TypeBoolean) {
+			additional = true
+		}
+	}
+
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var res []MarshalMismatch
+
+	for _, name := range names {
+		var candidates []jsonschema.SchemaOrBool
+
+		for _, s := range ss {
+			if p, ok := s.Properties[name]; ok {
+				candidates = append(candidates, p)
+			}
+
+			for pattern, p := range s.PatternProperties {
+				if re, err := regexp.Compile(pattern); err == nil && re.MatchString(name) {
+					candidates = append(candidates, p)
+				}
+			}
+		}
+
+		if len(candidates) == 0 {
+			for _, s := range ss {
+				if s.AdditionalProperties != nil && s.AdditionalProperties.TypeObject != nil {
+					candidates = append(candidates, *s.AdditionalProperties)
+				}
+			}
+		}
+
+		p := path + "/" + strings.ReplaceAll(strings.ReplaceAll(name, "~", "~0"), "/", "~1")
+
+		if len(candidates) == 0 && describes && !additional {
+			res = append(res, MarshalMismatch{InstancePath: p, Problem: "property is not declared in schema"})
+
+			continue
+		}
+
+		res = append(res, c.bestMatch(candidates, obj[name], p)...)
+	}
+
+	return res
+}
+
+// bestMatch returns mismatches of the candidate schema that has fewest of them.
+func (c marshalChecker) bestMatch(candidates []jsonschema.SchemaOrBool, value interface{}, path string) []MarshalMismatch {
+	var best []MarshalMismatch
+
+	for i, candidate := range candidates {
+		res := c.undeclared(candidate, value, path)
+
+		if i == 0 || len(res) < len(best) {
+			best = res
+		}
+
+		if len(best) == 0 {
+			break
+		}
+	}
+
+	return best
+}
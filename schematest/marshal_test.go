@@ -0,0 +1,66 @@
+package schematest_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/jsonschema-go"
+	"github.com/swaggest/jsonschema-go/schematest"
+)
+
+type money struct {
+	Cents int `json:"cents"`
+}
+
+// MarshalJSON diverges from reflected schema.
+func (m money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{"amount": float64(m.Cents) / 100})
+}
+
+type invoice struct {
+	Number string            `json:"number,omitempty" required:"true"`
+	Total  money             `json:"total"`
+	Lines  []money           `json:"lines"`
+	Meta   map[string]string `json:"meta"`
+	Note   string            `yaml:"note"`
+}
+
+func TestCheckMarshal(t *testing.T) {
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(invoice{})
+	require.NoError(t, err)
+
+	mm, err := schematest.CheckMarshal(s, invoice{
+		Total: money{Cents: 150},
+		Lines: []money{{Cents: 1}},
+		Meta:  map[string]string{"a": "b"},
+	})
+	require.NoError(t, err)
+
+	res := make([]string, 0, len(mm))
+	for _, m := range mm {
+		res = append(res, m.String())
+	}
+
+	assert.Equal(t, []string{
+		`/: missing required property "number" (#/required)`,
+		"/Note: property is not declared in schema",
+		"/lines/0/amount: property is not declared in schema",
+		"/total/amount: property is not declared in schema",
+	}, res)
+}
+
+func TestAssertMarshal(t *testing.T) {
+	assert.True(t, schematest.AssertMarshal(t, Order{ID: 1}))
+
+	rec := &recorder{}
+
+	assert.False(t, schematest.AssertMarshal(rec, Order{ID: 0}))
+	assert.Equal(t, []string{
+		"marshaled schematest_test.Order does not match schema:\n" +
+			"  /id: value 0 must be greater than or equal to 1 (#/properties/id/minimum)",
+	}, rec.errs)
+}
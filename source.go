@@ -0,0 +1,313 @@
+//go:build !tinygo && !jsonschema_lite
+// +build !tinygo,!jsonschema_lite
+
+package jsonschema
+
+import (
+	"errors"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// sourceIndexAvailable is true when SourceIndex parses Go sources.
+const sourceIndexAvailable = true
+
+// SourceIndex locates declarations of Go types and struct fields in source files.
+//
+// Packages are parsed on first access, package directories are found with go/build,
+// or can be provided explicitly with AddDir. Test files are parsed too, types declared in function
+// bodies are located by name (first declaration wins).
+type SourceIndex struct {
+	// BaseDir makes file names relative to it, if not empty.
+	BaseDir string
+
+	mu   sync.Mutex
+	fset *token.FileSet
+	dirs map[string]string
+	pkgs map[string]*sourcePackage
+}
+
+type sourceDecl struct {
+	pos SourcePosition
+	doc string
+}
+
+type sourcePackage struct {
+	types  map[string]sourceDecl
+	fields map[string]map[string]sourceDecl
+
+	files     []*ast.File
+	constants map[string][]sourceConstant // lazily collected constants by type name
+}
+
+// NewSourceIndex creates source index.
+func NewSourceIndex() *SourceIndex {
+	return &SourceIndex{}
+}
+
+// AddDir sets directory with sources of a package.
+func (si *SourceIndex) AddDir(pkgPath, dir string) {
+	si.mu.Lock()
+	defer si.mu.Unlock()
+
+	if si.dirs == nil {
+		si.dirs = map[string]string{}
+	}
+
+	si.dirs[pkgPath] = dir
+	delete(si.pkgs, pkgPath)
+}
+
+// TypePosition returns source position of a named type declaration.
+func (si *SourceIndex) TypePosition(t reflect.Type) (SourcePosition, bool) {
+	d, ok := si.typeDecl(t)
+
+	return d.pos, ok
+}
+
+// FieldPosition returns source position of a struct field declaration.
+func (si *SourceIndex) FieldPosition(owner reflect.Type, fieldName string) (SourcePosition, bool) {
+	d, ok := si.fieldDecl(owner, fieldName)
+
+	return d.pos, ok
+}
+
+// TypeDoc returns doc comment of a named type declaration.
+func (si *SourceIndex) TypeDoc(t reflect.Type) (string, bool) {
+	d, ok := si.typeDecl(t)
+
+	return d.doc, ok && d.doc != ""
+}
+
+// FieldDoc returns doc comment (or line comment) of a struct field declaration.
+func (si *SourceIndex) FieldDoc(owner reflect.Type, fieldName string) (string, bool) {
+	d, ok := si.fieldDecl(owner, fieldName)
+
+	return d.doc, ok && d.doc != ""
+}
+
+func (si *SourceIndex) typeDecl(t reflect.Type) (sourceDecl, bool) {
+	if si == nil || t == nil {
+		return sourceDecl{}, false
+	}
+
+	p := si.pkg(t.PkgPath())
+	if p == nil {
+		return sourceDecl{}, false
+	}
+
+	d, ok := p.types[sourceTypeName(t)]
+
+	return d, ok
+}
+
+func (si *SourceIndex) fieldDecl(owner reflect.Type, fieldName string) (sourceDecl, bool) {
+	if si == nil || owner == nil {
+		return sourceDecl{}, false
+	}
+
+	p := si.pkg(owner.PkgPath())
+	if p == nil {
+		return sourceDecl{}, false
+	}
+
+	d, ok := p.fields[sourceTypeName(owner)][fieldName]
+
+	return d, ok
+}
+
+func sourceTypeName(t reflect.Type) string {
+	name := t.Name()
+
+	// Generic type instantiation, e.g. "List[int]".
+	if i := strings.Index(name, "["); i > 0 {
+		name = name[:i]
+	}
+
+	return name
+}
+
+func (si *SourceIndex) pkg(pkgPath string) *sourcePackage {
+	if pkgPath == "" {
+		return nil
+	}
+
+	si.mu.Lock()
+	defer si.mu.Unlock()
+
+	if p, ok := si.pkgs[pkgPath]; ok {
+		return p
+	}
+
+	if si.pkgs == nil {
+		si.pkgs = map[string]*sourcePackage{}
+	}
+
+	if si.fset == nil {
+		si.fset = token.NewFileSet()
+	}
+
+	p, err := si.parse(pkgPath)
+	if err != nil {
+		p = nil
+	}
+
+	si.pkgs[pkgPath] = p
+
+	return p
+}
+
+func (si *SourceIndex) parse(pkgPath string) (*sourcePackage, error) {
+	dir, ok := si.dirs[pkgPath]
+	if !ok {
+		// External test package shares directory with package under test.
+		bp, err := build.Import(strings.TrimSuffix(pkgPath, "_test"), "", build.FindOnly)
+		if err != nil {
+			return nil, err
+		}
+
+		dir = bp.Dir
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &sourcePackage{
+		types:  map[string]sourceDecl{},
+		fields: map[string]map[string]sourceDecl{},
+	}
+
+	found := false
+
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".go") {
+			continue
+		}
+
+		f, err := parser.ParseFile(si.fset, filepath.Join(dir, e.Name()), nil, parser.ParseComments)
+		if err != nil {
+			continue
+		}
+
+		// Package name is checked to separate external test package from package under test.
+		isTest := strings.HasSuffix(f.Name.Name, "_test")
+		if isTest != strings.HasSuffix(pkgPath, "_test") {
+			continue
+		}
+
+		found = true
+
+		si.collect(p, f)
+		p.files = append(p.files, f)
+	}
+
+	if !found {
+		return nil, errors.New("no sources found for " + pkgPath)
+	}
+
+	return p, nil
+}
+
+func (si *SourceIndex) collect(p *sourcePackage, f *ast.File) {
+	var doc *ast.CommentGroup
+
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.GenDecl:
+			// Doc of a single spec declaration is attached to GenDecl.
+			doc = nil
+			if n.Tok == token.TYPE && len(n.Specs) == 1 {
+				doc = n.Doc
+			}
+		case *ast.TypeSpec:
+			if _, ok := p.types[n.Name.Name]; ok {
+				return true
+			}
+
+			d := sourceDecl{pos: si.position(n.Name.Pos())}
+
+			switch {
+			case n.Doc != nil:
+				d.doc = n.Doc.Text()
+			case doc != nil:
+				d.doc = doc.Text()
+			}
+
+			p.types[n.Name.Name] = d
+
+			if st, ok := n.Type.(*ast.StructType); ok {
+				p.fields[n.Name.Name] = si.structFields(st)
+			}
+		}
+
+		return true
+	})
+}
+
+func (si *SourceIndex) structFields(st *ast.StructType) map[string]sourceDecl {
+	fields := map[string]sourceDecl{}
+
+	for _, field := range st.Fields.List {
+		d := sourceDecl{}
+
+		if field.Doc != nil {
+			d.doc = field.Doc.Text()
+		} else if field.Comment != nil {
+			d.doc = field.Comment.Text()
+		}
+
+		if len(field.Names) == 0 {
+			// Embedded field is named after its type.
+			d.pos = si.position(field.Type.Pos())
+			fields[embeddedName(field.Type)] = d
+
+			continue
+		}
+
+		for _, name := range field.Names {
+			d.pos = si.position(name.Pos())
+			fields[name.Name] = d
+		}
+	}
+
+	return fields
+}
+
+func embeddedName(e ast.Expr) string {
+	switch e := e.(type) {
+	case *ast.StarExpr:
+		return embeddedName(e.X)
+	case *ast.SelectorExpr:
+		return e.Sel.Name
+	case *ast.IndexExpr:
+		return embeddedName(e.X)
+	case *ast.IndexListExpr:
+		return embeddedName(e.X)
+	case *ast.Ident:
+		return e.Name
+	}
+
+	return ""
+}
+
+func (si *SourceIndex) position(pos token.Pos) SourcePosition {
+	p := si.fset.Position(pos)
+	file := p.Filename
+
+	if si.BaseDir != "" {
+		if rel, err := filepath.Rel(si.BaseDir, file); err == nil {
+			file = rel
+		}
+	}
+
+	return SourcePosition{File: filepath.ToSlash(file), Line: p.Line, Column: p.Column}
+}
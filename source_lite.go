@@ -0,0 +1,51 @@
+//go:build tinygo || jsonschema_lite
+// +build tinygo jsonschema_lite
+
+package jsonschema
+
+import "reflect"
+
+// sourceIndexAvailable is true when SourceIndex parses Go sources.
+const sourceIndexAvailable = false
+
+// SourceIndex locates declarations of Go types and struct fields in source files.
+//
+// Go sources are not parsed in this build (with `tinygo` or `jsonschema_lite` tags),
+// so positions and constants are never found.
+type SourceIndex struct {
+	// BaseDir makes file names relative to it, if not empty.
+	BaseDir string
+}
+
+// NewSourceIndex creates source index.
+func NewSourceIndex() *SourceIndex {
+	return &SourceIndex{}
+}
+
+// AddDir sets directory with sources of a package.
+func (si *SourceIndex) AddDir(_, _ string) {}
+
+// TypePosition returns source position of a named type declaration.
+func (si *SourceIndex) TypePosition(_ reflect.Type) (SourcePosition, bool) {
+	return SourcePosition{}, false
+}
+
+// FieldPosition returns source position of a struct field declaration.
+func (si *SourceIndex) FieldPosition(_ reflect.Type, _ string) (SourcePosition, bool) {
+	return SourcePosition{}, false
+}
+
+// TypeDoc returns doc comment of a named type declaration.
+func (si *SourceIndex) TypeDoc(_ reflect.Type) (string, bool) {
+	return "", false
+}
+
+// FieldDoc returns doc comment (or line comment) of a struct field declaration.
+func (si *SourceIndex) FieldDoc(_ reflect.Type, _ string) (string, bool) {
+	return "", false
+}
+
+// Constants returns values and names of constants declared with named type in order of declaration.
+func (si *SourceIndex) Constants(_ reflect.Type) ([]interface{}, []string, bool) {
+	return nil, nil, false
+}
@@ -0,0 +1,60 @@
+package jsonschema_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/jsonschema-go"
+)
+
+// sourceItem is located in source.
+type sourceItem struct {
+	Name string `json:"name"`
+	Bad  string `json:"bad" preset:"missing"`
+}
+
+type sourceHolder struct {
+	Item sourceItem `json:"item"`
+}
+
+func TestSourcePositions(t *testing.T) {
+	if !jsonschema.BuildCapabilities().SourceIndex {
+		t.Skip("source index is not available in this build")
+	}
+
+	idx := jsonschema.NewSourceIndex()
+	idx.AddDir("github.com/swaggest/jsonschema-go_test", ".")
+
+	pos, ok := idx.TypePosition(reflect.TypeOf(sourceItem{}))
+	require.True(t, ok)
+	assert.Equal(t, "source_test.go", pos.File)
+	assert.Equal(t, 14, pos.Line)
+
+	pos, ok = idx.FieldPosition(reflect.TypeOf(sourceItem{}), "Bad")
+	require.True(t, ok)
+	assert.Equal(t, "source_test.go:16", pos.String())
+
+	r := jsonschema.Reflector{}
+
+	_, err := r.Reflect(sourceHolder{}, jsonschema.SourcePositions(idx))
+	require.Error(t, err)
+
+	var se jsonschema.SourceError
+
+	require.True(t, errors.As(err, &se))
+	assert.Equal(t, "sourceItem.Bad", se.Field)
+	assert.Equal(t, `source_test.go:16: unknown preset "missing" in field Bad`, err.Error())
+
+	r = jsonschema.Reflector{}
+	r.RegisterPreset("missing")
+
+	s, err := r.Reflect(sourceHolder{}, jsonschema.SourcePositions(idx))
+	require.NoError(t, err)
+
+	def := s.Definitions["JsonschemaGoTestSourceItem"].TypeObject
+	require.NotNil(t, def)
+	assert.Equal(t, "source_test.go:14", def.ExtraProperties[jsonschema.XGoSource])
+}
@@ -0,0 +1,136 @@
+//go:build !tinygo && !jsonschema_lite
+// +build !tinygo,!jsonschema_lite
+
+package jsonschema
+
+import (
+	"errors"
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"reflect"
+	"sort"
+)
+
+type sourceConstant struct {
+	name string
+	val  constant.Value
+}
+
+// Constants returns values and names of constants declared with named type in order of declaration,
+// e.g. for `type Level int` with `const (Debug Level = iota; Info)`.
+func (si *SourceIndex) Constants(t reflect.Type) ([]interface{}, []string, bool) {
+	if si == nil || t == nil {
+		return nil, nil, false
+	}
+
+	p := si.pkg(t.PkgPath())
+	if p == nil {
+		return nil, nil, false
+	}
+
+	consts := si.typeConstants(p, sourceTypeName(t))
+	if len(consts) == 0 {
+		return nil, nil, false
+	}
+
+	values := make([]interface{}, 0, len(consts))
+	names := make([]string, 0, len(consts))
+
+	for _, c := range consts {
+		v, ok := constantValue(c.val, t.Kind())
+		if !ok {
+			continue
+		}
+
+		values = append(values, v)
+		names = append(names, c.name)
+	}
+
+	return values, names, len(values) > 0
+}
+
+func (si *SourceIndex) typeConstants(p *sourcePackage, typeName string) []sourceConstant {
+	si.mu.Lock()
+	defer si.mu.Unlock()
+
+	if p.constants == nil {
+		p.constants = si.collectConstants(p.files)
+	}
+
+	return p.constants[typeName]
+}
+
+// collectConstants type checks package files to evaluate constants (including iota expressions),
+// imports are not resolved, so only constants that do not depend on other packages are found.
+func (si *SourceIndex) collectConstants(files []*ast.File) map[string][]sourceConstant {
+	res := map[string][]sourceConstant{}
+
+	if len(files) == 0 {
+		return res
+	}
+
+	conf := types.Config{
+		Importer: importerFunc(func(path string) (*types.Package, error) {
+			return nil, errors.New("imports are not resolved")
+		}),
+		Error: func(err error) {}, // Errors of unresolved imports are ignored.
+	}
+	info := &types.Info{Defs: map[*ast.Ident]types.Object{}}
+
+	_, _ = conf.Check(files[0].Name.Name, si.fset, files, info) //nolint:errcheck // Partial result is used.
+
+	var consts []*types.Const
+
+	for _, obj := range info.Defs {
+		if c, ok := obj.(*types.Const); ok && c.Name() != "_" {
+			consts = append(consts, c)
+		}
+	}
+
+	sort.Slice(consts, func(i, j int) bool {
+		return consts[i].Pos() < consts[j].Pos()
+	})
+
+	for _, c := range consts {
+		named, ok := c.Type().(*types.Named)
+		if !ok || c.Val().Kind() == constant.Unknown {
+			continue
+		}
+
+		name := named.Obj().Name()
+		res[name] = append(res[name], sourceConstant{name: c.Name(), val: c.Val()})
+	}
+
+	return res
+}
+
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) {
+	return f(path)
+}
+
+func constantValue(v constant.Value, kind reflect.Kind) (interface{}, bool) {
+	switch kind { //nolint:exhaustive // Other kinds can not have constants.
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return constant.Int64Val(constant.ToInt(v))
+	case reflect.Float32, reflect.Float64:
+		return constant.Float64Val(constant.ToFloat(v))
+	case reflect.String:
+		if v.Kind() != constant.String {
+			return nil, false
+		}
+
+		return constant.StringVal(v), true
+	case reflect.Bool:
+		if v.Kind() != constant.Bool {
+			return nil, false
+		}
+
+		return constant.BoolVal(v), true
+	}
+
+	return nil, false
+}
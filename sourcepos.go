@@ -0,0 +1,60 @@
+package jsonschema
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+)
+
+const (
+	// XGoSource is the name of JSON property to store source position of originating Go type.
+	XGoSource = "x-go-source"
+)
+
+// SourcePosition is a location of a declaration in Go source file.
+type SourcePosition struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Column int    `json:"column,omitempty"`
+}
+
+// String returns position in "file:line" format that is recognized by editors.
+func (p SourcePosition) String() string {
+	return p.File + ":" + strconv.Itoa(p.Line)
+}
+
+// SourceError is an error caused by a struct field with a known source position.
+type SourceError struct {
+	Position SourcePosition
+	Field    string
+	Err      error
+}
+
+// Error implements error.
+func (e SourceError) Error() string {
+	return e.Position.String() + ": " + e.Err.Error()
+}
+
+// Unwrap returns underlying error.
+func (e SourceError) Unwrap() error {
+	return e.Err
+}
+
+// fieldError adds source position of a struct field to error, if available.
+func (rc *ReflectContext) fieldError(owner reflect.Type, field reflect.StructField, err error) error {
+	if rc.SourceIndex == nil || owner == nil {
+		return err
+	}
+
+	var se SourceError
+	if errors.As(err, &se) {
+		return err
+	}
+
+	pos, ok := rc.SourceIndex.FieldPosition(owner, field.Name)
+	if !ok {
+		return err
+	}
+
+	return SourceError{Position: pos, Field: owner.Name() + "." + field.Name, Err: err}
+}
@@ -0,0 +1,80 @@
+package jsonschema
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/swaggest/refl"
+)
+
+// RegisterSQLNullTypes registers database/sql null types (e.g. sql.NullString, sql.NullInt64, sql.NullTime
+// and generic sql.Null[T] of Go 1.22) as optional wrappers, so that they are reflected as nullable schemas
+// of their values, e.g. `{"type":["null","string"]}`, see RegisterOptional.
+//
+// Note, encoding/json marshals these types as objects with Valid field, registration is only correct
+// if they are marshaled as null or value, e.g. with custom MarshalJSON of wrapping types or a custom JSON codec.
+func (r *Reflector) RegisterSQLNullTypes() {
+	for _, o := range []struct {
+		sample interface{}
+		field  string
+	}{
+		{sql.NullString{}, "String"},
+		{sql.NullInt64{}, "Int64"},
+		{sql.NullInt32{}, "Int32"},
+		{sql.NullInt16{}, "Int16"},
+		{sql.NullByte{}, "Byte"},
+		{sql.NullFloat64{}, "Float64"},
+		{sql.NullBool{}, "Bool"},
+		{sql.NullTime{}, "Time"},
+	} {
+		if err := r.RegisterOptional(o.sample, o.field); err != nil {
+			panic(err) // Fields of standard types are known.
+		}
+	}
+
+	// Generic sql.Null[T] is not available in Go 1.18, it is registered by name.
+	r.optionals["database/sql.Null"] = "V"
+}
+
+// AddNullWrapper registers a custom null wrapper type (e.g. Null[T] with Valid and Value fields)
+// by a sample of its value type, the only field of wrapper that has value type is used as value field,
+// see RegisterOptional.
+//
+//	err := r.AddNullWrapper(Null[int]{}, 0) // Null[string] is reflected as {"type":["null","string"]}.
+func (r *Reflector) AddNullWrapper(wrapperSample, valueSample interface{}) error {
+	t := refl.DeepIndirect(reflect.TypeOf(wrapperSample))
+	if t == nil {
+		return errors.New("nil sample")
+	}
+
+	vt := reflect.TypeOf(valueSample)
+	if vt == nil {
+		return errors.New("nil value sample")
+	}
+
+	if t.Kind() != reflect.Struct {
+		return fmt.Errorf("%s: struct expected", t)
+	}
+
+	var field string
+
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Type != vt {
+			continue
+		}
+
+		if field != "" {
+			return fmt.Errorf("%s: ambiguous fields %s and %s of %s type", t, field, t.Field(i).Name, vt)
+		}
+
+		field = t.Field(i).Name
+	}
+
+	if field == "" {
+		return fmt.Errorf("%s: no field of %s type", t, vt)
+	}
+
+	return r.RegisterOptional(wrapperSample, field)
+}
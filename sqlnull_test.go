@@ -0,0 +1,55 @@
+package jsonschema_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/jsonschema-go"
+)
+
+type nullDecimal struct {
+	Value float64
+	Valid bool
+}
+
+func TestReflector_RegisterSQLNullTypes(t *testing.T) {
+	type Row struct {
+		Name      sql.NullString  `json:"name" minLength:"1"`
+		Count     sql.NullInt64   `json:"count"`
+		Ratio     sql.NullFloat64 `json:"ratio"`
+		Active    sql.NullBool    `json:"active"`
+		UpdatedAt sql.NullTime    `json:"updated_at"`
+		Price     nullDecimal     `json:"price"`
+	}
+
+	r := jsonschema.Reflector{}
+	r.RegisterSQLNullTypes()
+	require.NoError(t, r.AddNullWrapper(nullDecimal{}, float64(0)))
+
+	s, err := r.Reflect(Row{})
+	require.NoError(t, err)
+
+	assertjson.EqMarshal(t, `{
+	  "properties":{
+		"active":{"type":["null","boolean"]},
+		"count":{"type":["null","integer"]},
+		"name":{"minLength":1,"type":["null","string"]},
+		"price":{"type":["null","number"]},
+		"ratio":{"type":["null","number"]},
+		"updated_at":{"format":"date-time","type":["null","string"]}
+	  },
+	  "type":"object"
+	}`, s)
+}
+
+func TestReflector_AddNullWrapper_errors(t *testing.T) {
+	r := jsonschema.Reflector{}
+
+	assert.EqualError(t, r.AddNullWrapper(nullDecimal{}, ""),
+		"jsonschema_test.nullDecimal: no field of string type")
+	assert.EqualError(t, r.AddNullWrapper(struct{ A, B int }{}, 0),
+		"struct { A int; B int }: ambiguous fields A and B of int type")
+}
@@ -0,0 +1,323 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// ValidateStream checks a JSON document read from decoder against schema with bounded memory.
+//
+// Arrays and objects are consumed token by token, items and property values are validated one at a time,
+// so memory usage depends on the size of the largest item rather than the size of the whole document.
+// Subschemas that need a complete value (const, enum, composition keywords, uniqueItems, contains,
+// dependencies or $ref with sibling assertions) are validated against a fully decoded value.
+//
+// Decoder is switched to UseNumber mode, a single top-level value is consumed.
+// Returned error is ValidationErrors if value is invalid.
+func (v *Validator) ValidateStream(d *json.Decoder) error {
+	d.UseNumber()
+
+	errs, err := v.streamValue(d, v.root, "", "#", 0)
+	if err != nil {
+		return fmt.Errorf("failed to decode JSON: %w", err)
+	}
+
+	if len(errs) > 0 {
+		return ValidationErrors(errs)
+	}
+
+	return nil
+}
+
+func (v *Validator) streamValue(d *json.Decoder, s SchemaOrBool, ip, sp string, depth int) ([]ValidationError, error) {
+	schema := s.TypeObject
+
+	if schema == nil || depth > maxValidationDepth || v.needsWholeValue(schema) {
+		var value interface{}
+
+		if err := d.Decode(&value); err != nil {
+			return nil, err
+		}
+
+		return v.validate(s, value, ip, sp, depth), nil
+	}
+
+	if schema.Ref != nil {
+		rs, err := v.resolveRef(*schema.Ref)
+		if err != nil {
+			if err := skipValue(d); err != nil {
+				return nil, err
+			}
+
+			return []ValidationError{{
+				Keyword: "$ref", InstancePath: ip, SchemaPath: sp + "/$ref", Message: err.Error(),
+			}}, nil
+		}
+
+		return v.streamValue(d, rs, ip, *schema.Ref, depth+1)
+	}
+
+	tok, err := d.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return v.validate(s, tok, ip, sp, depth), nil
+	}
+
+	var sample interface{} = map[string]interface{}{}
+	if delim == '[' {
+		sample = []interface{}{}
+	}
+
+	if schema.Type != nil && !typeMatches(schema.Type, sample) {
+		if err := skipRest(d); err != nil {
+			return nil, err
+		}
+
+		return []ValidationError{{
+			Keyword: "type", InstancePath: ip, SchemaPath: sp + "/type",
+			Message: fmt.Sprintf("expected %s, got %s", typeNames(schema.Type), instanceTypeName(sample)),
+		}}, nil
+	}
+
+	if delim == '[' {
+		return v.streamArray(d, schema, ip, sp, depth)
+	}
+
+	return v.streamObject(d, schema, ip, sp, depth)
+}
+
+// needsWholeValue tells if schema has keywords that can not be checked on a stream of tokens.
+func (v *Validator) needsWholeValue(schema *Schema) bool {
+	for _, k := range v.SensitiveKeywords {
+		if _, ok := schema.ExtraProperties[k]; ok {
+			return true
+		}
+	}
+
+	if schema.Const != nil || schema.Enum != nil || schema.AllOf != nil || schema.AnyOf != nil ||
+		schema.OneOf != nil || schema.Not != nil || schema.If != nil || schema.Contains != nil ||
+		schema.Dependencies != nil || (schema.UniqueItems != nil && *schema.UniqueItems) {
+		return true
+	}
+
+	return schema.Ref != nil && hasAssertions(schema)
+}
+
+// hasAssertions tells if schema has validation keywords besides $ref and composition.
+func hasAssertions(schema *Schema) bool {
+	return schema.Type != nil || schema.Properties != nil || schema.PatternProperties != nil ||
+		schema.AdditionalProperties != nil || schema.PropertyNames != nil || schema.Required != nil ||
+		schema.MinProperties != 0 || schema.MaxProperties != nil ||
+		schema.Items != nil || schema.AdditionalItems != nil || schema.MinItems != 0 || schema.MaxItems != nil ||
+		schema.Minimum != nil || schema.Maximum != nil || schema.ExclusiveMinimum != nil ||
+		schema.ExclusiveMaximum != nil || schema.MultipleOf != nil ||
+		schema.MinLength != 0 || schema.MaxLength != nil || schema.Pattern != nil || schema.Format != nil
+}
+
+func (v *Validator) streamArray(d *json.Decoder, schema *Schema, ip, sp string, depth int) ([]ValidationError, error) {
+	var errs []ValidationError
+
+	l := int64(0)
+
+	for d.More() {
+		var (
+			is  *SchemaOrBool
+			isp string
+		)
+
+		if schema.Items != nil {
+			switch {
+			case schema.Items.SchemaOrBool != nil:
+				is, isp = schema.Items.SchemaOrBool, sp+"/items"
+			case l < int64(len(schema.Items.SchemaArray)):
+				is, isp = &schema.Items.SchemaArray[l], sp+"/items/"+strconv.FormatInt(l, 10)
+			case schema.AdditionalItems != nil:
+				is, isp = schema.AdditionalItems, sp+"/additionalItems"
+			}
+		}
+
+		if is == nil {
+			if err := skipValue(d); err != nil {
+				return nil, err
+			}
+		} else {
+			e, err := v.streamValue(d, *is, ip+"/"+strconv.FormatInt(l, 10), isp, depth+1)
+			if err != nil {
+				return nil, err
+			}
+
+			errs = append(errs, e...)
+		}
+
+		l++
+	}
+
+	if _, err := d.Token(); err != nil {
+		return nil, err
+	}
+
+	if schema.MinItems > 0 && l < schema.MinItems {
+		errs = append(errs, ValidationError{
+			Keyword: "minItems", InstancePath: ip, SchemaPath: sp + "/minItems",
+			Message: fmt.Sprintf("array must have at least %d items, got %d", schema.MinItems, l),
+		})
+	}
+
+	if schema.MaxItems != nil && l > *schema.MaxItems {
+		errs = append(errs, ValidationError{
+			Keyword: "maxItems", InstancePath: ip, SchemaPath: sp + "/maxItems",
+			Message: fmt.Sprintf("array must have at most %d items, got %d", *schema.MaxItems, l),
+		})
+	}
+
+	return errs, nil
+}
+
+func (v *Validator) streamObject(d *json.Decoder, schema *Schema, ip, sp string, depth int) ([]ValidationError, error) {
+	var errs []ValidationError
+
+	fail := func(keyword, format string, args ...interface{}) {
+		errs = append(errs, ValidationError{
+			Keyword: keyword, InstancePath: ip, SchemaPath: sp + "/" + keyword,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	l := int64(0)
+	seen := make(map[string]bool, len(schema.Required))
+
+	for _, name := range schema.Required {
+		seen[name] = false
+	}
+
+	for d.More() {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		k, _ := tok.(string)
+		pip := ip + "/" + escapePointerToken(k)
+
+		if _, ok := seen[k]; ok {
+			seen[k] = true
+		}
+
+		var (
+			schemas []SchemaOrBool
+			paths   []string
+		)
+
+		if ps, ok := schema.Properties[k]; ok {
+			schemas = append(schemas, ps)
+			paths = append(paths, sp+"/properties/"+escapePointerToken(k))
+		}
+
+		for pattern, ps := range schema.PatternProperties {
+			re, err := v.pattern(pattern)
+			if err != nil {
+				fail("patternProperties", "invalid pattern %q: %v", pattern, err)
+
+				continue
+			}
+
+			if re.MatchString(k) {
+				schemas = append(schemas, ps)
+				paths = append(paths, sp+"/patternProperties/"+escapePointerToken(pattern))
+			}
+		}
+
+		if len(schemas) == 0 && schema.AdditionalProperties != nil {
+			schemas = append(schemas, *schema.AdditionalProperties)
+			paths = append(paths, sp+"/additionalProperties")
+		}
+
+		switch len(schemas) {
+		case 0:
+			err = skipValue(d)
+		case 1:
+			var e []ValidationError
+
+			e, err = v.streamValue(d, schemas[0], pip, paths[0], depth+1)
+			errs = append(errs, e...)
+		default:
+			// Value is checked against multiple schemas, so it has to be decoded.
+			var val interface{}
+
+			err = d.Decode(&val)
+
+			for i, ps := range schemas {
+				errs = append(errs, v.validate(ps, val, pip, paths[i], depth+1)...)
+			}
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if schema.PropertyNames != nil {
+			errs = append(errs, v.validate(*schema.PropertyNames, k, pip, sp+"/propertyNames", depth+1)...)
+		}
+
+		l++
+	}
+
+	if _, err := d.Token(); err != nil {
+		return nil, err
+	}
+
+	if schema.MinProperties > 0 && l < schema.MinProperties {
+		fail("minProperties", "object must have at least %d properties, got %d", schema.MinProperties, l)
+	}
+
+	if schema.MaxProperties != nil && l > *schema.MaxProperties {
+		fail("maxProperties", "object must have at most %d properties, got %d", *schema.MaxProperties, l)
+	}
+
+	for _, name := range schema.Required {
+		if !seen[name] {
+			fail("required", "missing required property %q", name)
+		}
+	}
+
+	return errs, nil
+}
+
+// skipValue consumes next value from decoder without keeping it in memory.
+func skipValue(d *json.Decoder) error {
+	tok, err := d.Token()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := tok.(json.Delim); ok {
+		return skipRest(d)
+	}
+
+	return nil
+}
+
+// skipRest consumes tokens until the end of current array or object.
+func skipRest(d *json.Decoder) error {
+	for level := 1; level > 0; {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+
+		if delim, ok := tok.(json.Delim); ok {
+			if delim == '[' || delim == '{' {
+				level++
+			} else {
+				level--
+			}
+		}
+	}
+
+	return nil
+}
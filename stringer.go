@@ -0,0 +1,67 @@
+package jsonschema
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/swaggest/refl"
+)
+
+// ErrStringerMarshaler is returned by Reflector.RegisterStringer for types that implement json.Marshaler.
+var ErrStringerMarshaler = errors.New("type implements json.Marshaler")
+
+// StringerMismatchError is returned by Reflector.RegisterStringer when encoding/json would not encode
+// registered type as a string, because it does not implement encoding.TextMarshaler.
+//
+// The type is registered anyway, the error is a diagnostic of a possible mismatch between schema and payloads.
+type StringerMismatchError struct {
+	Type reflect.Type
+	Kind reflect.Kind
+}
+
+// Error implements error.
+func (e StringerMismatchError) Error() string {
+	return fmt.Sprintf("%s implements fmt.Stringer, but encoding/json encodes it as %s", e.Type, e.Kind)
+}
+
+// RegisterStringer makes schema of a type that implements fmt.Stringer a string, e.g. for enum-like
+// third-party types.
+//
+// Types that implement json.Marshaler are not registered and ErrStringerMarshaler is returned.
+// StringerMismatchError is returned if JSON encoding of the type is not a string, registration is done anyway.
+func (r *Reflector) RegisterStringer(sample fmt.Stringer) error {
+	t := reflect.TypeOf(sample)
+	if t == nil {
+		return errors.New("nil sample")
+	}
+
+	if t.Implements(typeOfJSONMarshaler) || reflect.PtrTo(t).Implements(typeOfJSONMarshaler) {
+		return fmt.Errorf("%s: %w", t, ErrStringerMarshaler)
+	}
+
+	dt := refl.DeepIndirect(t)
+
+	if r.stringers == nil {
+		r.stringers = map[reflect.Type]bool{}
+	}
+
+	r.stringers[dt] = true
+
+	if dt.Kind() != reflect.String && !t.Implements(typeOfTextMarshaler) && !reflect.PtrTo(dt).Implements(typeOfTextMarshaler) {
+		return StringerMismatchError{Type: dt, Kind: dt.Kind()}
+	}
+
+	return nil
+}
+
+func (r *Reflector) checkStringer(t reflect.Type, schema *Schema) bool {
+	if !r.stringers[t] {
+		return false
+	}
+
+	schema.TypeEns().WithSimpleTypes(String)
+	schema.Type.SliceOfSimpleTypeValues = nil
+
+	return true
+}
@@ -0,0 +1,262 @@
+package jsonschema
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxSummaryEnum limits number of enum values in summary.
+const maxSummaryEnum = 5
+
+// Summarize returns compact human-readable outline of schema for logs and reviews, e.g.
+//
+//	object
+//	  id*: integer [minimum 1]
+//	  tags: array of string [maxItems 10]
+//	  address: Address
+//	definitions:
+//	  Address: object
+//	    city*: string
+//
+// Required properties are marked with "*", references are shown with definition names,
+// properties are sorted by name.
+func Summarize(schema Schema) string {
+	sm := summarizer{}
+
+	root := schema
+	root.Definitions = nil
+
+	sm.schema("", root.ToSchemaOrBool(), 0)
+
+	if len(schema.Definitions) > 0 {
+		sm.b.WriteString("definitions:\n")
+
+		names := make([]string, 0, len(schema.Definitions))
+		for name := range schema.Definitions {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		for _, name := range names {
+			sm.schema(name+": ", schema.Definitions[name], 1)
+		}
+	}
+
+	return sm.b.String()
+}
+
+type summarizer struct {
+	b strings.Builder
+}
+
+func (sm *summarizer) schema(prefix string, s SchemaOrBool, depth int) {
+	indent := strings.Repeat("  ", depth)
+
+	if s.TypeBoolean != nil {
+		if *s.TypeBoolean {
+			sm.b.WriteString(indent + prefix + "any\n")
+		} else {
+			sm.b.WriteString(indent + prefix + "nothing\n")
+		}
+
+		return
+	}
+
+	if s.TypeObject == nil {
+		sm.b.WriteString(indent + prefix + "any\n")
+
+		return
+	}
+
+	schema := s.TypeObject
+	head := summaryType(schema)
+
+	// Array of scalars or references is shown in one line.
+	var items *Schema
+
+	if schema.Items != nil && schema.Items.SchemaOrBool != nil && schema.Items.SchemaOrBool.TypeObject != nil {
+		items = schema.Items.SchemaOrBool.TypeObject
+		head += " of " + summaryType(items)
+
+		if c := summaryConstraints(items); c != "" {
+			head += " " + c
+		}
+	}
+
+	if c := summaryConstraints(schema); c != "" {
+		head += " " + c
+	}
+
+	sm.b.WriteString(indent + prefix + head + "\n")
+
+	if items != nil {
+		sm.properties(items, depth+1)
+	}
+
+	sm.properties(schema, depth+1)
+
+	for _, list := range [][]SchemaOrBool{schema.AllOf, schema.AnyOf, schema.OneOf} {
+		for _, sub := range list {
+			sm.schema("- ", sub, depth+1)
+		}
+	}
+
+	if schema.AdditionalProperties != nil && schema.AdditionalProperties.TypeObject != nil {
+		sm.schema("{}: ", *schema.AdditionalProperties, depth+1)
+	}
+}
+
+func (sm *summarizer) properties(schema *Schema, depth int) {
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	required := make(map[string]bool, len(schema.Required))
+	for _, name := range schema.Required {
+		required[name] = true
+	}
+
+	for _, name := range names {
+		label := name
+		if required[name] {
+			label += "*"
+		}
+
+		sm.schema(label+": ", schema.Properties[name], depth)
+	}
+}
+
+func summaryType(s *Schema) string {
+	var parts []string
+
+	if s.Ref != nil {
+		ref := *s.Ref
+		if i := strings.LastIndex(ref, "/"); i >= 0 {
+			ref = pointerUnescaper.Replace(ref[i+1:])
+		}
+
+		if ref == "#" {
+			ref = "(root)"
+		}
+
+		parts = append(parts, ref)
+	}
+
+	if s.Type != nil {
+		if s.Type.SimpleTypes != nil {
+			parts = append(parts, string(*s.Type.SimpleTypes))
+		}
+
+		for _, t := range s.Type.SliceOfSimpleTypeValues {
+			parts = append(parts, string(t))
+		}
+	}
+
+	switch {
+	case len(s.AnyOf) > 0:
+		parts = append(parts, "anyOf")
+	case len(s.OneOf) > 0:
+		parts = append(parts, "oneOf")
+	case len(s.AllOf) > 0:
+		parts = append(parts, "allOf")
+	}
+
+	if len(parts) == 0 {
+		return "any"
+	}
+
+	return strings.Join(parts, "|")
+}
+
+func summaryConstraints(s *Schema) string {
+	var c []string
+
+	add := func(format string, args ...interface{}) {
+		c = append(c, fmt.Sprintf(format, args...))
+	}
+
+	if s.Format != nil {
+		add("format %s", *s.Format)
+	}
+
+	if s.Const != nil {
+		add("const %s", jsonString(*s.Const))
+	}
+
+	if len(s.Enum) > 0 {
+		values := make([]string, 0, len(s.Enum))
+
+		for i, e := range s.Enum {
+			if i == maxSummaryEnum {
+				values = append(values, "…+"+strconv.Itoa(len(s.Enum)-maxSummaryEnum))
+
+				break
+			}
+
+			values = append(values, jsonString(e))
+		}
+
+		add("enum %s", strings.Join(values, ","))
+	}
+
+	numbers := []struct {
+		name string
+		val  *float64
+	}{
+		{"minimum", s.Minimum}, {"exclusiveMinimum", s.ExclusiveMinimum},
+		{"maximum", s.Maximum}, {"exclusiveMaximum", s.ExclusiveMaximum}, {"multipleOf", s.MultipleOf},
+	}
+
+	for _, n := range numbers {
+		if n.val != nil {
+			add("%s %s", n.name, strconv.FormatFloat(*n.val, 'g', -1, 64))
+		}
+	}
+
+	lengths := []struct {
+		name string
+		min  int64
+		max  *int64
+	}{
+		{"Length", s.MinLength, s.MaxLength}, {"Items", s.MinItems, s.MaxItems},
+		{"Properties", s.MinProperties, s.MaxProperties},
+	}
+
+	for _, l := range lengths {
+		if l.min > 0 {
+			add("min%s %d", l.name, l.min)
+		}
+
+		if l.max != nil {
+			add("max%s %d", l.name, *l.max)
+		}
+	}
+
+	if s.Pattern != nil {
+		add("pattern %s", *s.Pattern)
+	}
+
+	if s.UniqueItems != nil && *s.UniqueItems {
+		add("unique")
+	}
+
+	if s.ReadOnly != nil && *s.ReadOnly {
+		add("readOnly")
+	}
+
+	if d, ok := s.ExtraProperties["deprecated"].(bool); ok && d {
+		add("deprecated")
+	}
+
+	if len(c) == 0 {
+		return ""
+	}
+
+	return "[" + strings.Join(c, ", ") + "]"
+}
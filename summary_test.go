@@ -0,0 +1,42 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/jsonschema-go"
+)
+
+func TestSummarize(t *testing.T) {
+	type Address struct {
+		City string `json:"city" required:"true" minLength:"1"`
+	}
+
+	type Order struct {
+		ID      int      `json:"id" required:"true" minimum:"1"`
+		Status  string   `json:"status" enum:"new,paid"`
+		Tags    []string `json:"tags" maxItems:"10"`
+		Address Address  `json:"address"`
+		Lines   []struct {
+			SKU string `json:"sku" pattern:"^[A-Z]+$"`
+		} `json:"lines"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(Order{})
+	require.NoError(t, err)
+
+	assert.Equal(t, `object
+  address: JsonschemaGoTestAddress
+  id*: integer [minimum 1]
+  lines: array|null of object
+    sku: string [pattern ^[A-Z]+$]
+  status: string [enum "new","paid"]
+  tags: array|null of string [maxItems 10]
+definitions:
+  JsonschemaGoTestAddress: object
+    city*: string [minLength 1]
+`, jsonschema.Summarize(s))
+}
@@ -0,0 +1,568 @@
+// Package taglint checks JSON Schema related struct field tags before runtime reflection.
+//
+// Findings include malformed tag values (e.g. `minimum:"ten"`), contradictory constraints
+// (e.g. `minLength:"5" maxLength:"3"`), likely misspelled keywords (e.g. `minlength:"1"`) and non-zero defaults
+// of `omitempty` fields (e.g. `json:"limit,omitempty" default:"10"`) that make zero value indistinguishable from default.
+package taglint
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/swaggest/jsonschema-go"
+)
+
+// Finding describes a problem with a struct field tag.
+type Finding struct {
+	// Position is a location of the field in source, empty for runtime checks.
+	Position jsonschema.SourcePosition `json:"position"`
+
+	// Type is a name of struct type, empty for anonymous structs.
+	Type string `json:"type,omitempty"`
+
+	// Field is a name of struct field.
+	Field string `json:"field"`
+
+	// Tag is a name of tag, e.g. "minLength".
+	Tag string `json:"tag"`
+
+	// Message is a human-readable description.
+	Message string `json:"message"`
+}
+
+// String returns finding in "file:line: Type.Field: message" format.
+func (f Finding) String() string {
+	name := f.Field
+	if f.Type != "" {
+		name = f.Type + "." + f.Field
+	}
+
+	if f.Position.File == "" {
+		return name + ": " + f.Message
+	}
+
+	return f.Position.String() + ": " + name + ": " + f.Message
+}
+
+// CheckDir checks struct types declared in Go files of a directory, including test files.
+func CheckDir(dir string) ([]Finding, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+
+	var res []Finding
+
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".go") {
+			continue
+		}
+
+		f, err := parser.ParseFile(fset, filepath.Join(dir, e.Name()), nil, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, CheckFile(fset, f)...)
+	}
+
+	return res, nil
+}
+
+// CheckFile checks struct types of a parsed Go file.
+func CheckFile(fset *token.FileSet, f *ast.File) []Finding {
+	var (
+		res      []Finding
+		typeName string
+	)
+
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.TypeSpec:
+			typeName = n.Name.Name
+		case *ast.StructType:
+			for _, field := range n.Fields.List {
+				if field.Tag == nil {
+					continue
+				}
+
+				tag, err := strconv.Unquote(field.Tag.Value)
+				if err != nil {
+					continue
+				}
+
+				name := ""
+				if len(field.Names) > 0 {
+					name = field.Names[0].Name
+				}
+
+				p := fset.Position(field.Tag.Pos())
+				pos := jsonschema.SourcePosition{File: filepath.ToSlash(p.Filename), Line: p.Line, Column: p.Column}
+
+				findings := CheckTag(reflect.StructTag(tag))
+
+				if ident, ok := field.Type.(*ast.Ident); ok {
+					if kind, ok := basicKinds[ident.Name]; ok {
+						findings = append(findings, checkDefault(reflect.StructTag(tag), kind)...)
+					}
+				}
+
+				for _, pr := range findings {
+					pr.Position = pos
+					pr.Type = typeName
+					pr.Field = name
+					res = append(res, pr)
+				}
+			}
+
+			// Nested anonymous structs are not attributed to a named type.
+			typeName = ""
+		}
+
+		return true
+	})
+
+	return res
+}
+
+// CheckType checks fields of a struct type and nested struct types.
+func CheckType(t reflect.Type) []Finding {
+	var res []Finding
+
+	seen := map[reflect.Type]bool{}
+
+	var walk func(t reflect.Type)
+
+	walk = func(t reflect.Type) {
+		for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+			t = t.Elem()
+		}
+
+		if t.Kind() != reflect.Struct || seen[t] {
+			return
+		}
+
+		seen[t] = true
+
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+
+			findings := append(CheckTag(field.Tag), checkDefault(field.Tag, field.Type.Kind())...)
+
+			for _, f := range findings {
+				f.Type = t.Name()
+				f.Field = field.Name
+				res = append(res, f)
+			}
+
+			walk(field.Type)
+		}
+	}
+
+	walk(t)
+
+	return res
+}
+
+var (
+	floatTags = []string{"minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum", "multipleOf"}
+	intTags   = []string{"minLength", "maxLength", "minItems", "maxItems", "minProperties", "maxProperties"}
+	boolTags  = []string{
+		"required", "nullable", "uniqueItems", "readOnly", "deprecated", "sensitive", "additionalProperties",
+	}
+
+	// otherTags are commonly used by other libraries, they are not checked for misspelling.
+	otherTags = map[string]bool{
+		"json": true, "xml": true, "yaml": true, "db": true, "query": true, "header": true, "path": true,
+		"cookie": true, "form": true, "formData": true, "validate": true, "binding": true, "mapstructure": true,
+		"gorm": true, "bson": true, "protobuf": true, "toml": true, "env": true, "contentType": true,
+	}
+
+	knownTags = map[string]bool{
+		"refer": true, "preset": true, "types": true, "accept": true, "group": true, "section": true,
+		"enum": true, "example": true, "examples": true, "default": true, "const": true, "jsonschema": true,
+		"namedExamples": true, "anyOf": true, "enumFrom": true, "if": true, "then": true, "else": true,
+		"dependentRequired": true, "dependentSchemas": true, "valuesSchemaRef": true,
+	}
+)
+
+func init() {
+	st := reflect.TypeOf(jsonschema.Schema{})
+
+	for i := 0; i < st.NumField(); i++ {
+		name := strings.Split(st.Field(i).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" && !strings.HasPrefix(name, "$") {
+			knownTags[name] = true
+		}
+	}
+
+	for _, l := range [][]string{floatTags, intTags, boolTags} {
+		for _, name := range l {
+			knownTags[name] = true
+		}
+	}
+}
+
+// CheckTag checks a struct field tag, Position, Type and Field of findings are empty.
+func CheckTag(tag reflect.StructTag) []Finding {
+	var res []Finding
+
+	add := func(name, format string, args ...interface{}) {
+		res = append(res, Finding{Tag: name, Message: fmt.Sprintf(format, args...)})
+	}
+
+	floats := map[string]float64{}
+	ints := map[string]int64{}
+
+	// Boolean (draft-04) form of exclusive bounds applies to minimum or maximum.
+	for _, p := range [][2]string{{"exclusiveMinimum", "minimum"}, {"exclusiveMaximum", "maximum"}} {
+		if v, ok := tag.Lookup(p[0]); ok && (v == "true" || v == "false") {
+			if _, ok := tag.Lookup(p[1]); !ok && v == "true" {
+				add(p[0], "%s:\"true\" requires %s", p[0], p[1])
+			}
+		}
+	}
+
+	for _, name := range floatTags {
+		if v, ok := tag.Lookup(name); ok {
+			if strings.HasPrefix(name, "exclusive") && (v == "true" || v == "false") {
+				continue
+			}
+
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				add(name, "%s must be a number, %q given", name, v)
+
+				continue
+			}
+
+			floats[name] = f
+		}
+	}
+
+	for _, name := range intTags {
+		if v, ok := tag.Lookup(name); ok {
+			i, err := strconv.ParseInt(v, 10, 64)
+			if err != nil || i < 0 {
+				add(name, "%s must be a non-negative integer, %q given", name, v)
+
+				continue
+			}
+
+			ints[name] = i
+		}
+	}
+
+	for _, name := range boolTags {
+		if v, ok := tag.Lookup(name); ok {
+			if _, err := strconv.ParseBool(v); err != nil {
+				add(name, "%s must be a boolean, %q given", name, v)
+			}
+		}
+	}
+
+	if v, ok := tag.Lookup("examples"); ok {
+		var e []interface{}
+		if err := json.Unmarshal([]byte(v), &e); err != nil {
+			add("examples", "examples must be a JSON array: %v", err)
+		}
+	}
+
+	if v, ok := tag.Lookup("namedExamples"); ok {
+		var e map[string]jsonschema.NamedExample
+		if err := json.Unmarshal([]byte(v), &e); err != nil {
+			add("namedExamples", "namedExamples must be a JSON object of named examples: %v", err)
+		}
+	}
+
+	if v, ok := tag.Lookup("enum"); ok && strings.HasPrefix(strings.TrimSpace(v), "[") {
+		var e []interface{}
+		if err := json.Unmarshal([]byte(v), &e); err != nil {
+			add("enum", "enum must be a JSON array or comma-separated list: %v", err)
+		}
+	}
+
+	if v, ok := tag.Lookup("pattern"); ok {
+		if _, err := regexp.Compile(v); err != nil {
+			add("pattern", "pattern is not a valid regular expression: %v", err)
+		}
+	}
+
+	if v, ok := tag.Lookup("patternProperties"); ok {
+		if _, err := regexp.Compile(v); err != nil {
+			add("patternProperties", "patternProperties is not a valid regular expression: %v", err)
+		}
+	}
+
+	if v, ok := tag.Lookup("propertyNames"); ok && !strings.HasPrefix(strings.TrimSpace(v), "{") {
+		if _, err := regexp.Compile(v); err != nil {
+			add("propertyNames", "propertyNames is not a valid regular expression: %v", err)
+		}
+	}
+
+	if v, ok := floats["multipleOf"]; ok && v <= 0 {
+		add("multipleOf", "multipleOf must be greater than 0")
+	}
+
+	if v, ok := tag.Lookup("types"); ok {
+		for _, t := range strings.Split(v, ",") {
+			switch jsonschema.SimpleType(strings.TrimSpace(t)) {
+			case jsonschema.Array, jsonschema.Boolean, jsonschema.Integer, jsonschema.Null,
+				jsonschema.Number, jsonschema.Object, jsonschema.String:
+			default:
+				add("types", "unknown type %q", strings.TrimSpace(t))
+			}
+		}
+	}
+
+	if v, ok := tag.Lookup("anyOf"); ok {
+		if strings.HasPrefix(strings.TrimSpace(v), "[") {
+			var alternatives []jsonschema.SchemaOrBool
+			if err := json.Unmarshal([]byte(v), &alternatives); err != nil {
+				add("anyOf", "anyOf must be a JSON array of schemas: %v", err)
+			}
+		} else {
+			for _, t := range strings.Split(v, ";") {
+				switch jsonschema.SimpleType(strings.TrimSpace(t)) {
+				case jsonschema.Array, jsonschema.Boolean, jsonschema.Integer, jsonschema.Null,
+					jsonschema.Number, jsonschema.Object, jsonschema.String:
+				default:
+					add("anyOf", "unknown type %q", strings.TrimSpace(t))
+				}
+			}
+		}
+	}
+
+	if v, ok := tag.Lookup("accept"); ok && v != "null" && v != "any" && v != "nothing" {
+		add("accept", "accept must be one of null, any or nothing, %q given", v)
+	}
+
+	contradiction := func(minName, maxName string, minVal, maxVal float64) {
+		if minVal > maxVal {
+			add(minName, "%s (%v) is greater than %s (%v)", minName, minVal, maxName, maxVal)
+		}
+	}
+
+	if minVal, ok := floats["minimum"]; ok {
+		if maxVal, ok := floats["maximum"]; ok {
+			contradiction("minimum", "maximum", minVal, maxVal)
+		}
+	}
+
+	for _, p := range [][2]string{{"minLength", "maxLength"}, {"minItems", "maxItems"}, {"minProperties", "maxProperties"}} {
+		minVal, okMin := ints[p[0]]
+		maxVal, okMax := ints[p[1]]
+
+		if okMin && okMax {
+			contradiction(p[0], p[1], float64(minVal), float64(maxVal))
+		}
+	}
+
+	// Tags of array items and map values, e.g. itemsMinimum, are checked as tags of nested schemas.
+	for _, prefix := range elemTagPrefixes {
+		if et := prefixedTag(tag, prefix); et != "" {
+			for _, f := range CheckTag(et) {
+				f.Tag = prefixedTagName(prefix, f.Tag)
+				f.Message = prefix + ": " + f.Message
+				res = append(res, f)
+			}
+		}
+	}
+
+	for _, name := range tagNames(tag) {
+		if knownTags[name] || otherTags[name] || isElemTag(name) {
+			continue
+		}
+
+		if suggestion := similarTag(name); suggestion != "" {
+			add(name, "unknown keyword %s, did you mean %s?", name, suggestion)
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Tag < res[j].Tag
+	})
+
+	return res
+}
+
+// basicKinds maps names of predeclared types to their kinds.
+var basicKinds = map[string]reflect.Kind{
+	"bool": reflect.Bool, "string": reflect.String,
+	"int": reflect.Int, "int8": reflect.Int8, "int16": reflect.Int16, "int32": reflect.Int32, "int64": reflect.Int64,
+	"uint": reflect.Uint, "uint8": reflect.Uint8, "uint16": reflect.Uint16, "uint32": reflect.Uint32,
+	"uint64": reflect.Uint64, "float32": reflect.Float32, "float64": reflect.Float64,
+	"byte": reflect.Uint8, "rune": reflect.Int32,
+}
+
+// checkDefault reports non-zero default of a scalar omitempty field, such field can not have zero value
+// in JSON, because zero value is omitted and then read as default.
+func checkDefault(tag reflect.StructTag, kind reflect.Kind) []Finding {
+	d, ok := tag.Lookup("default")
+	if !ok || !strings.Contains(tag.Get("json"), ",omitempty") {
+		return nil
+	}
+
+	nonZero := false
+
+	switch kind { //nolint:exhaustive // Only scalars are checked.
+	case reflect.String:
+		nonZero = d != ""
+	case reflect.Bool:
+		b, err := strconv.ParseBool(d)
+		nonZero = err == nil && b
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(d, 64)
+		nonZero = err == nil && f != 0
+	}
+
+	if !nonZero {
+		return nil
+	}
+
+	return []Finding{{
+		Tag:     "default",
+		Message: fmt.Sprintf("default %q contradicts omitempty, zero value is omitted and would be read as default", d),
+	}}
+}
+
+// tagNames returns names of tags in conventional `key:"value"` format.
+func tagNames(tag reflect.StructTag) []string {
+	var names []string
+
+	s := string(tag)
+
+	for s != "" {
+		s = strings.TrimLeft(s, " ")
+
+		i := strings.Index(s, ":")
+		if i <= 0 || i+1 >= len(s) || s[i+1] != '"' {
+			break
+		}
+
+		names = append(names, s[:i])
+
+		value, err := strconv.QuotedPrefix(s[i+1:])
+		if err != nil {
+			break
+		}
+
+		s = s[i+1+len(value):]
+	}
+
+	return names
+}
+
+// elemTagPrefixes are prefixes of tags of array items and map values.
+var elemTagPrefixes = []string{"items", "additionalProperties"}
+
+func isElemTag(name string) bool {
+	for _, prefix := range elemTagPrefixes {
+		if hasTagPrefix(name, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasTagPrefix(name, prefix string) bool {
+	return len(name) > len(prefix) && strings.HasPrefix(name, prefix) && unicode.IsUpper(rune(name[len(prefix)]))
+}
+
+func prefixedTagName(prefix, name string) string {
+	return prefix + strings.ToUpper(name[:1]) + name[1:]
+}
+
+// prefixedTag returns tag of nested schema from tags with prefix, e.g. `itemsMinimum:"0"` becomes `minimum:"0"`.
+func prefixedTag(tag reflect.StructTag, prefix string) reflect.StructTag {
+	var res []string
+
+	for _, name := range tagNames(tag) {
+		if hasTagPrefix(name, prefix) {
+			v, _ := tag.Lookup(name)
+			name = strings.TrimPrefix(name, prefix)
+			res = append(res, strings.ToLower(name[:1])+name[1:]+":"+strconv.Quote(v))
+		}
+	}
+
+	return reflect.StructTag(strings.Join(res, " "))
+}
+
+// similarTag finds known keyword that differs from name by case or by one or two edits.
+func similarTag(name string) string {
+	best := ""
+	bestDist := 3
+
+	for known := range knownTags {
+		if strings.EqualFold(known, name) {
+			return known
+		}
+
+		if len(name) < 5 {
+			continue
+		}
+
+		if d := distance(strings.ToLower(name), strings.ToLower(known)); d < bestDist || (d == bestDist && known < best) {
+			best, bestDist = known, d
+		}
+	}
+
+	if bestDist > 2 {
+		return ""
+	}
+
+	return best
+}
+
+// distance is a Levenshtein distance between strings.
+func distance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			cur[j] = minInt(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, cur = cur, prev
+	}
+
+	return prev[len(b)]
+}
+
+func minInt(v int, vals ...int) int {
+	for _, x := range vals {
+		if x < v {
+			v = x
+		}
+	}
+
+	return v
+}
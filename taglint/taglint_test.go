@@ -0,0 +1,58 @@
+package taglint_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/jsonschema-go/taglint"
+)
+
+func TestCheckDir(t *testing.T) {
+	findings, err := taglint.CheckDir("testdata")
+	require.NoError(t, err)
+
+	var res []string
+	for _, f := range findings {
+		res = append(res, f.String())
+	}
+
+	assert.Equal(t, []string{
+		"testdata/models.go:4: Order.ID: minLength (5) is greater than maxLength (3)",
+		`testdata/models.go:5: Order.Amount: minimum must be a number, "ten" given`,
+		"testdata/models.go:6: Order.Code: pattern is not a valid regular expression: " +
+			"error parsing regexp: missing closing ]: `[a-z`",
+		"testdata/models.go:7: Order.Comment: unknown keyword minlength, did you mean minLength?",
+		"testdata/models.go:8: Order.Count: unknown keyword mnimum, did you mean minimum?",
+		`testdata/models.go:9: Order.Optional: nullable must be a boolean, "yes" given`,
+		`testdata/models.go:11: Order.Limit: default "10" contradicts omitempty, ` +
+			`zero value is omitted and would be read as default`,
+		"testdata/models.go:14: Order.Tags: items: minLength (3) is greater than maxLength (1)",
+		"testdata/models.go:14: Order.Tags: items: unknown keyword patern, did you mean pattern?",
+		`testdata/models.go:15: Order.Labels: additionalProperties: minimum must be a number, "x" given`,
+	}, res)
+}
+
+func TestCheckType(t *testing.T) {
+	type Item struct {
+		Qty  int    `json:"qty" minimum:"5" maximum:"1"`
+		Unit string `json:"unit,omitempty" default:"pcs"`
+
+		Price float64 `json:"price" minimum:"0" exclusiveMinimum:"true" exclusiveMaximum:"true"`
+		SKU   string  `json:"sku" anyOf:"string;int"`
+	}
+
+	type Cart struct {
+		Items []Item `json:"items" minItems:"-1"`
+	}
+
+	findings := taglint.CheckType(reflect.TypeOf(Cart{}))
+	require.Len(t, findings, 5)
+	assert.Equal(t, `Cart.Items: minItems must be a non-negative integer, "-1" given`, findings[0].String())
+	assert.Equal(t, "Item.Qty: minimum (5) is greater than maximum (1)", findings[1].String())
+	assert.Equal(t, `Item.Unit: default "pcs" contradicts omitempty, zero value is omitted and would be read as default`,
+		findings[2].String())
+	assert.Equal(t, `Item.Price: exclusiveMaximum:"true" requires maximum`, findings[3].String())
+	assert.Equal(t, `Item.SKU: unknown type "int"`, findings[4].String())
+}
@@ -0,0 +1,16 @@
+package testdata
+
+type Order struct {
+	ID       string         `json:"id" minLength:"5" maxLength:"3"`
+	Amount   float64        `json:"amount" minimum:"ten"`
+	Code     string         `json:"code" pattern:"[a-z"`
+	Comment  string         `json:"comment" minlength:"1"`
+	Count    int            `json:"count" mnimum:"1" db:"count"`
+	Optional bool           `json:"optional" nullable:"yes"`
+	Valid    string         `json:"valid" minLength:"1" maxLength:"10" title:"Valid"`
+	Limit    int            `json:"limit,omitempty" default:"10"`
+	Page     int            `json:"page,omitempty" default:"0"`
+	Sort     *string        `json:"sort,omitempty" default:"asc"`
+	Tags     []string       `json:"tags" itemsMinLength:"3" itemsMaxLength:"1" itemsPatern:"^[a-z]+$"`
+	Labels   map[string]int `json:"labels" additionalPropertiesMinimum:"x"`
+}
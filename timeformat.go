@@ -0,0 +1,72 @@
+package jsonschema
+
+import (
+	"reflect"
+
+	"github.com/swaggest/refl"
+)
+
+// Formats of integer timestamps.
+const (
+	// FormatUnixTime is a format of integer number of seconds since Unix epoch.
+	FormatUnixTime = "unix-time"
+
+	// FormatUnixTimeMillis is a format of integer number of milliseconds since Unix epoch.
+	FormatUnixTimeMillis = "unix-time-millis"
+)
+
+// TimeEncoding defines JSON representation of time.Time.
+type TimeEncoding int
+
+// Time encodings.
+const (
+	// TimeRFC3339 is a string with `date-time` format, as marshaled by time.Time.
+	TimeRFC3339 TimeEncoding = iota
+
+	// TimeUnix is an integer with FormatUnixTime format.
+	TimeUnix
+
+	// TimeUnixMillis is an integer with FormatUnixTimeMillis format.
+	TimeUnixMillis
+)
+
+// TimeFormat sets up schema of time.Time values, e.g. for wire formats that transfer epoch timestamps.
+//
+// Individual fields can also be reflected as integer timestamps with `format:"unix-time"`
+// or `format:"unix-time-millis"` field tag.
+func TimeFormat(enc TimeEncoding) func(rc *ReflectContext) {
+	return func(rc *ReflectContext) {
+		rc.TimeEncoding = enc
+	}
+}
+
+// timeSchema sets up type and format of time.Time.
+func timeSchema(schema *Schema, enc TimeEncoding) {
+	switch enc {
+	case TimeUnix:
+		schema.AddType(Integer)
+		schema.WithFormat(FormatUnixTime)
+	case TimeUnixMillis:
+		schema.AddType(Integer)
+		schema.WithFormat(FormatUnixTimeMillis)
+	default:
+		schema.AddType(String)
+		schema.WithFormat("date-time")
+	}
+}
+
+// checkTimeFormat changes type of time.Time property to integer if it has an epoch format in field tag.
+func checkTimeFormat(propertySchema *Schema, ft reflect.Type) {
+	if ft == nil || refl.DeepIndirect(ft) != typeOfTime || propertySchema.Format == nil {
+		return
+	}
+
+	if f := *propertySchema.Format; f != FormatUnixTime && f != FormatUnixTimeMillis {
+		return
+	}
+
+	if propertySchema.HasType(String) {
+		propertySchema.RemoveType(String)
+		propertySchema.AddType(Integer)
+	}
+}
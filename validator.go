@@ -0,0 +1,922 @@
+package jsonschema
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// maxValidationDepth limits nesting of schema evaluation to protect from reference loops.
+const maxValidationDepth = 512
+
+// ValidationError describes a single failed keyword.
+type ValidationError struct {
+	// Keyword is the name of failed JSON Schema keyword, e.g. "minimum".
+	Keyword string `json:"keyword"`
+
+	// InstancePath is a JSON Pointer to the failed value in validated document, e.g. "/items/0/name".
+	InstancePath string `json:"instancePath"`
+
+	// SchemaPath is a JSON Pointer to the failed keyword in schema, e.g. "#/properties/items/minItems".
+	SchemaPath string `json:"schemaPath"`
+
+	// Message is a human-readable description of failure.
+	Message string `json:"message"`
+}
+
+// Error implements error.
+func (e ValidationError) Error() string {
+	p := e.InstancePath
+	if p == "" {
+		p = "/"
+	}
+
+	return p + ": " + e.Message
+}
+
+// ValidationErrors is a list of validation failures.
+type ValidationErrors []ValidationError
+
+// Error implements error.
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+
+	for _, ve := range e {
+		msgs = append(msgs, ve.Error())
+	}
+
+	return "validation failed: " + strings.Join(msgs, ", ")
+}
+
+// Validator checks JSON documents against a schema.
+//
+// Validator is safe for concurrent use.
+type Validator struct {
+	// SensitiveKeywords enables reporting of non-null values with schemas that have any of these
+	// keywords set to true, e.g. XSensitive or "writeOnly", to detect sensitive data in sample payloads.
+	SensitiveKeywords []string
+
+	// MaxLineErrors limits number of reported invalid lines in ValidateLines,
+	// DefaultMaxLineErrors is used if 0, negative value disables limit.
+	MaxLineErrors int
+
+	// Cache enables caching of validation results by hashes of schema and JSON instance, can be nil.
+	Cache *ResultCache
+
+	// Formats enables validation of custom formats, can be nil.
+	Formats *Formats
+
+	hash     [sha256.Size]byte
+	hashOnce sync.Once
+
+	root         SchemaOrBool
+	rootJSON     interface{}
+	rootErr      error
+	rootOnce     sync.Once
+	refResolvers []func(ref string) (SchemaOrBool, bool)
+
+	refs     sync.Map // map[string]SchemaOrBool
+	patterns sync.Map // map[string]*regexp.Regexp
+}
+
+// NewValidator creates validator for a schema.
+//
+// Local references (e.g. "#/definitions/Foo") are resolved against the schema itself,
+// other references can be resolved with refResolvers.
+func NewValidator(schema Schema, refResolvers ...func(ref string) (SchemaOrBool, bool)) *Validator {
+	return &Validator{
+		root:         schema.ToSchemaOrBool(),
+		refResolvers: refResolvers,
+	}
+}
+
+// Validate checks decoded JSON value against schema.
+//
+// Value is expected to be a result of json.Unmarshal into interface{}, with
+// map[string]interface{} objects, []interface{} arrays and float64 or json.Number numbers.
+// Returned error is ValidationErrors if value is invalid.
+func (v *Validator) Validate(value interface{}) error {
+	if v.Cache != nil {
+		if j, err := json.Marshal(value); err == nil {
+			k := v.cacheKey(j)
+			if err, ok := v.Cache.get(k); ok {
+				return err
+			}
+
+			err := v.validateRoot(value)
+			v.Cache.put(k, err)
+
+			return err
+		}
+	}
+
+	return v.validateRoot(value)
+}
+
+func (v *Validator) validateRoot(value interface{}) error {
+	errs := v.validate(v.root, value, "", "#", 0)
+	if len(errs) > 0 {
+		return ValidationErrors(errs)
+	}
+
+	return nil
+}
+
+// ValidateJSON checks JSON document against schema.
+func (v *Validator) ValidateJSON(data []byte) error {
+	var (
+		value interface{}
+		k     resultKey
+	)
+
+	if v.Cache != nil {
+		k = v.cacheKey(data)
+		if err, ok := v.Cache.get(k); ok {
+			return err
+		}
+	}
+
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+
+	if err := d.Decode(&value); err != nil {
+		return fmt.Errorf("failed to decode JSON: %w", err)
+	}
+
+	err := v.validateRoot(value)
+
+	if v.Cache != nil {
+		v.Cache.put(k, err)
+	}
+
+	return err
+}
+
+func (v *Validator) validate(s SchemaOrBool, value interface{}, ip, sp string, depth int) []ValidationError {
+	if s.TypeBoolean != nil {
+		if *s.TypeBoolean {
+			return nil
+		}
+
+		return []ValidationError{{Keyword: "false", InstancePath: ip, SchemaPath: sp, Message: "no value is allowed"}}
+	}
+
+	if s.TypeObject == nil {
+		return nil
+	}
+
+	if depth > maxValidationDepth {
+		return []ValidationError{{
+			Keyword: "$ref", InstancePath: ip, SchemaPath: sp,
+			Message: "maximum validation depth exceeded, possible reference loop",
+		}}
+	}
+
+	schema := s.TypeObject
+
+	var errs []ValidationError
+
+	fail := func(keyword, format string, args ...interface{}) {
+		errs = append(errs, ValidationError{
+			Keyword:      keyword,
+			InstancePath: ip,
+			SchemaPath:   sp + "/" + keyword,
+			Message:      fmt.Sprintf(format, args...),
+		})
+	}
+
+	for _, k := range v.SensitiveKeywords {
+		if b, ok := schema.ExtraProperties[k].(bool); ok && b && value != nil {
+			fail(k, "sensitive value must not be present")
+		}
+	}
+
+	if schema.Ref != nil {
+		rs, err := v.resolveRef(*schema.Ref)
+		if err != nil {
+			fail("$ref", "%s", err.Error())
+		} else {
+			errs = append(errs, v.validate(rs, value, ip, *schema.Ref, depth+1)...)
+		}
+	}
+
+	if schema.Type != nil && !typeMatches(schema.Type, value) {
+		fail("type", "expected %s, got %s", typeNames(schema.Type), instanceTypeName(value))
+
+		// Other keywords are likely to fail with misleading messages on type mismatch.
+		return errs
+	}
+
+	if schema.Const != nil && !jsonEqual(*schema.Const, value) {
+		fail("const", "value must be equal to %s", jsonString(*schema.Const))
+	}
+
+	if schema.Enum != nil {
+		found := false
+
+		for _, e := range schema.Enum {
+			if jsonEqual(e, value) {
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			fail("enum", "value must be one of %s", jsonString(schema.Enum))
+		}
+	}
+
+	switch val := value.(type) {
+	case string:
+		errs = append(errs, v.validateString(schema, val, ip, sp)...)
+	case map[string]interface{}:
+		errs = append(errs, v.validateObject(schema, val, ip, sp, depth)...)
+	case []interface{}:
+		errs = append(errs, v.validateArray(schema, val, ip, sp, depth)...)
+	default:
+		if f, ok := toFloat(value); ok {
+			errs = append(errs, validateNumber(schema, f, ip, sp)...)
+		}
+	}
+
+	errs = append(errs, v.validateComposition(schema, value, ip, sp, depth)...)
+
+	return errs
+}
+
+func (v *Validator) validateComposition(schema *Schema, value interface{}, ip, sp string, depth int) []ValidationError {
+	var errs []ValidationError
+
+	for i, sub := range schema.AllOf {
+		errs = append(errs, v.validate(sub, value, ip, sp+"/allOf/"+strconv.Itoa(i), depth+1)...)
+	}
+
+	if len(schema.AnyOf) > 0 {
+		valid := false
+
+		for i, sub := range schema.AnyOf {
+			if len(v.validate(sub, value, ip, sp+"/anyOf/"+strconv.Itoa(i), depth+1)) == 0 {
+				valid = true
+
+				break
+			}
+		}
+
+		if !valid {
+			errs = append(errs, ValidationError{
+				Keyword: "anyOf", InstancePath: ip, SchemaPath: sp + "/anyOf",
+				Message: "value must match at least one schema in anyOf",
+			})
+		}
+	}
+
+	if len(schema.OneOf) > 0 {
+		matched := 0
+
+		for i, sub := range schema.OneOf {
+			if len(v.validate(sub, value, ip, sp+"/oneOf/"+strconv.Itoa(i), depth+1)) == 0 {
+				matched++
+			}
+		}
+
+		if matched != 1 {
+			errs = append(errs, ValidationError{
+				Keyword: "oneOf", InstancePath: ip, SchemaPath: sp + "/oneOf",
+				Message: fmt.Sprintf("value must match exactly one schema in oneOf, matched %d", matched),
+			})
+		}
+	}
+
+	if schema.Not != nil && len(v.validate(*schema.Not, value, ip, sp+"/not", depth+1)) == 0 {
+		errs = append(errs, ValidationError{
+			Keyword: "not", InstancePath: ip, SchemaPath: sp + "/not",
+			Message: "value must not match schema",
+		})
+	}
+
+	if schema.If != nil {
+		if len(v.validate(*schema.If, value, ip, sp+"/if", depth+1)) == 0 {
+			if schema.Then != nil {
+				errs = append(errs, v.validate(*schema.Then, value, ip, sp+"/then", depth+1)...)
+			}
+		} else if schema.Else != nil {
+			errs = append(errs, v.validate(*schema.Else, value, ip, sp+"/else", depth+1)...)
+		}
+	}
+
+	return errs
+}
+
+func validateNumber(schema *Schema, f float64, ip, sp string) []ValidationError {
+	var errs []ValidationError
+
+	fail := func(keyword, format string, args ...interface{}) {
+		errs = append(errs, ValidationError{
+			Keyword: keyword, InstancePath: ip, SchemaPath: sp + "/" + keyword,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	if schema.MultipleOf != nil && *schema.MultipleOf > 0 {
+		q := f / *schema.MultipleOf
+		if math.Abs(q-math.Round(q)) > 1e-9 {
+			fail("multipleOf", "value %v must be a multiple of %v", f, *schema.MultipleOf)
+		}
+	}
+
+	if schema.Minimum != nil && f < *schema.Minimum {
+		fail("minimum", "value %v must be greater than or equal to %v", f, *schema.Minimum)
+	}
+
+	if schema.Maximum != nil && f > *schema.Maximum {
+		fail("maximum", "value %v must be less than or equal to %v", f, *schema.Maximum)
+	}
+
+	if schema.ExclusiveMinimum != nil && f <= *schema.ExclusiveMinimum {
+		fail("exclusiveMinimum", "value %v must be greater than %v", f, *schema.ExclusiveMinimum)
+	}
+
+	if schema.ExclusiveMaximum != nil && f >= *schema.ExclusiveMaximum {
+		fail("exclusiveMaximum", "value %v must be less than %v", f, *schema.ExclusiveMaximum)
+	}
+
+	// Boolean exclusive bounds of draft-04, see TargetDraft.
+	if schema.Minimum != nil && f == *schema.Minimum && schema.ExtraProperties["exclusiveMinimum"] == true {
+		fail("exclusiveMinimum", "value %v must be greater than %v", f, *schema.Minimum)
+	}
+
+	if schema.Maximum != nil && f == *schema.Maximum && schema.ExtraProperties["exclusiveMaximum"] == true {
+		fail("exclusiveMaximum", "value %v must be less than %v", f, *schema.Maximum)
+	}
+
+	return errs
+}
+
+func (v *Validator) validateString(schema *Schema, s string, ip, sp string) []ValidationError {
+	var errs []ValidationError
+
+	fail := func(keyword, format string, args ...interface{}) {
+		errs = append(errs, ValidationError{
+			Keyword: keyword, InstancePath: ip, SchemaPath: sp + "/" + keyword,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	l := int64(utf8.RuneCountInString(s))
+
+	if schema.MinLength > 0 && l < schema.MinLength {
+		fail("minLength", "length %d must be greater than or equal to %d", l, schema.MinLength)
+	}
+
+	if schema.MaxLength != nil && l > *schema.MaxLength {
+		fail("maxLength", "length %d must be less than or equal to %d", l, *schema.MaxLength)
+	}
+
+	if schema.Pattern != nil {
+		re, err := v.pattern(*schema.Pattern)
+		if err != nil {
+			fail("pattern", "invalid pattern %q: %v", *schema.Pattern, err)
+		} else if !re.MatchString(s) {
+			fail("pattern", "value %q must match pattern %q", s, *schema.Pattern)
+		}
+	}
+
+	if schema.Format != nil {
+		if err := v.Formats.CheckFormat(*schema.Format, s); err != nil {
+			fail("format", "value %q must be of format %q: %v", s, *schema.Format, err)
+		}
+	}
+
+	return errs
+}
+
+func (v *Validator) validateArray(schema *Schema, items []interface{}, ip, sp string, depth int) []ValidationError {
+	var errs []ValidationError
+
+	fail := func(keyword, format string, args ...interface{}) {
+		errs = append(errs, ValidationError{
+			Keyword: keyword, InstancePath: ip, SchemaPath: sp + "/" + keyword,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	l := int64(len(items))
+
+	if schema.MinItems > 0 && l < schema.MinItems {
+		fail("minItems", "array must have at least %d items, got %d", schema.MinItems, l)
+	}
+
+	if schema.MaxItems != nil && l > *schema.MaxItems {
+		fail("maxItems", "array must have at most %d items, got %d", *schema.MaxItems, l)
+	}
+
+	if schema.UniqueItems != nil && *schema.UniqueItems {
+	unique:
+		for i := range items {
+			for j := i + 1; j < len(items); j++ {
+				if jsonEqual(items[i], items[j]) {
+					fail("uniqueItems", "items at %d and %d must be unique", i, j)
+
+					break unique
+				}
+			}
+		}
+	}
+
+	// Draft 2020-12 tuple, `items` applies to items after prefixItems, see TargetDraft.
+	var prefixItems []SchemaOrBool
+
+	if extraKeyword(schema, "prefixItems", &prefixItems) {
+		for i, item := range items {
+			if i >= len(prefixItems) {
+				break
+			}
+
+			errs = append(errs, v.validate(prefixItems[i], item,
+				ip+"/"+strconv.Itoa(i), sp+"/prefixItems/"+strconv.Itoa(i), depth+1)...)
+		}
+	}
+
+	if schema.Items != nil {
+		switch {
+		case schema.Items.SchemaOrBool != nil:
+			for i, item := range items {
+				if i < len(prefixItems) {
+					continue
+				}
+
+				errs = append(errs, v.validate(*schema.Items.SchemaOrBool, item,
+					ip+"/"+strconv.Itoa(i), sp+"/items", depth+1)...)
+			}
+		case schema.Items.SchemaArray != nil:
+			for i, item := range items {
+				if i < len(schema.Items.SchemaArray) {
+					errs = append(errs, v.validate(schema.Items.SchemaArray[i], item,
+						ip+"/"+strconv.Itoa(i), sp+"/items/"+strconv.Itoa(i), depth+1)...)
+				} else if schema.AdditionalItems != nil {
+					errs = append(errs, v.validate(*schema.AdditionalItems, item,
+						ip+"/"+strconv.Itoa(i), sp+"/additionalItems", depth+1)...)
+				}
+			}
+		}
+	}
+
+	if schema.Contains != nil {
+		found := false
+
+		for i, item := range items {
+			if len(v.validate(*schema.Contains, item, ip+"/"+strconv.Itoa(i), sp+"/contains", depth+1)) == 0 {
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			fail("contains", "array must contain at least one matching item")
+		}
+	}
+
+	return errs
+}
+
+func (v *Validator) validateObject(schema *Schema, obj map[string]interface{}, ip, sp string, depth int) []ValidationError {
+	var errs []ValidationError
+
+	fail := func(keyword, format string, args ...interface{}) {
+		errs = append(errs, ValidationError{
+			Keyword: keyword, InstancePath: ip, SchemaPath: sp + "/" + keyword,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	l := int64(len(obj))
+
+	if schema.MinProperties > 0 && l < schema.MinProperties {
+		fail("minProperties", "object must have at least %d properties, got %d", schema.MinProperties, l)
+	}
+
+	if schema.MaxProperties != nil && l > *schema.MaxProperties {
+		fail("maxProperties", "object must have at most %d properties, got %d", *schema.MaxProperties, l)
+	}
+
+	for _, name := range schema.Required {
+		if _, ok := obj[name]; !ok {
+			fail("required", "missing required property %q", name)
+		}
+	}
+
+	// Draft 2020-12 replacements of dependencies, see TargetDraft.
+	var (
+		dependentRequired map[string][]string
+		dependentSchemas  map[string]SchemaOrBool
+	)
+
+	extraKeyword(schema, "dependentRequired", &dependentRequired)
+	extraKeyword(schema, "dependentSchemas", &dependentSchemas)
+
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		val := obj[k]
+		pip := ip + "/" + escapePointerToken(k)
+		matched := false
+
+		if ps, ok := schema.Properties[k]; ok {
+			matched = true
+
+			errs = append(errs, v.validate(ps, val, pip, sp+"/properties/"+escapePointerToken(k), depth+1)...)
+		}
+
+		for pattern, ps := range schema.PatternProperties {
+			re, err := v.pattern(pattern)
+			if err != nil {
+				fail("patternProperties", "invalid pattern %q: %v", pattern, err)
+
+				continue
+			}
+
+			if re.MatchString(k) {
+				matched = true
+
+				errs = append(errs, v.validate(ps, val, pip, sp+"/patternProperties/"+escapePointerToken(pattern), depth+1)...)
+			}
+		}
+
+		if !matched && schema.AdditionalProperties != nil {
+			errs = append(errs, v.validate(*schema.AdditionalProperties, val, pip, sp+"/additionalProperties", depth+1)...)
+		}
+
+		if schema.PropertyNames != nil {
+			errs = append(errs, v.validate(*schema.PropertyNames, k, pip, sp+"/propertyNames", depth+1)...)
+		}
+
+		if dep, ok := schema.Dependencies[k]; ok {
+			dsp := sp + "/dependencies/" + escapePointerToken(k)
+
+			if dep.SchemaOrBool != nil {
+				errs = append(errs, v.validate(*dep.SchemaOrBool, obj, ip, dsp, depth+1)...)
+			}
+
+			for _, name := range dep.StringArray {
+				if _, ok := obj[name]; !ok {
+					errs = append(errs, ValidationError{
+						Keyword: "dependencies", InstancePath: ip, SchemaPath: dsp,
+						Message: fmt.Sprintf("property %q is required by %q", name, k),
+					})
+				}
+			}
+		}
+
+		if ds, ok := dependentSchemas[k]; ok {
+			errs = append(errs, v.validate(ds, obj, ip, sp+"/dependentSchemas/"+escapePointerToken(k), depth+1)...)
+		}
+
+		for _, name := range dependentRequired[k] {
+			if _, ok := obj[name]; !ok {
+				errs = append(errs, ValidationError{
+					Keyword: "dependentRequired", InstancePath: ip, SchemaPath: sp + "/dependentRequired/" + escapePointerToken(k),
+					Message: fmt.Sprintf("property %q is required by %q", name, k),
+				})
+			}
+		}
+	}
+
+	return errs
+}
+
+// extraKeyword decodes value of keyword stored in ExtraProperties, e.g. by TargetDraft conversion.
+func extraKeyword(schema *Schema, keyword string, v interface{}) bool {
+	val, ok := schema.ExtraProperties[keyword]
+	if !ok {
+		return false
+	}
+
+	j, err := jsonMarshal(val)
+	if err != nil {
+		return false
+	}
+
+	return jsonUnmarshal(j, v) == nil
+}
+
+func (v *Validator) pattern(p string) (*regexp.Regexp, error) {
+	if re, ok := v.patterns.Load(p); ok {
+		return re.(*regexp.Regexp), nil //nolint:forcetypeassert // Only *regexp.Regexp is stored.
+	}
+
+	re, err := regexp.Compile(p)
+	if err != nil {
+		return nil, err
+	}
+
+	v.patterns.Store(p, re)
+
+	return re, nil
+}
+
+func (v *Validator) resolveRef(ref string) (SchemaOrBool, error) {
+	if s, ok := v.refs.Load(ref); ok {
+		return s.(SchemaOrBool), nil //nolint:forcetypeassert // Only SchemaOrBool is stored.
+	}
+
+	for _, resolve := range v.refResolvers {
+		if s, found := resolve(ref); found {
+			v.refs.Store(ref, s)
+
+			return s, nil
+		}
+	}
+
+	if !strings.HasPrefix(ref, "#") {
+		return SchemaOrBool{}, fmt.Errorf("unresolved reference %q", ref)
+	}
+
+	v.rootOnce.Do(func() {
+		var j []byte
+
+		j, v.rootErr = json.Marshal(v.root)
+		if v.rootErr == nil {
+			v.rootErr = json.Unmarshal(j, &v.rootJSON)
+		}
+	})
+
+	if v.rootErr != nil {
+		return SchemaOrBool{}, v.rootErr
+	}
+
+	node, err := resolvePointer(v.rootJSON, strings.TrimPrefix(ref, "#"))
+	if err != nil {
+		return SchemaOrBool{}, fmt.Errorf("unresolved reference %q: %w", ref, err)
+	}
+
+	j, err := json.Marshal(node)
+	if err != nil {
+		return SchemaOrBool{}, err
+	}
+
+	var s SchemaOrBool
+	if err := json.Unmarshal(j, &s); err != nil {
+		return SchemaOrBool{}, fmt.Errorf("invalid schema at %q: %w", ref, err)
+	}
+
+	v.refs.Store(ref, s)
+
+	return s, nil
+}
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+var pointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+
+func escapePointerToken(s string) string {
+	return pointerEscaper.Replace(s)
+}
+
+func resolvePointer(doc interface{}, pointer string) (interface{}, error) {
+	if pointer == "" {
+		return doc, nil
+	}
+
+	if p, err := url.PathUnescape(pointer); err == nil {
+		pointer = p
+	}
+
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, errors.New("invalid JSON Pointer: " + pointer)
+	}
+
+	node := doc
+
+	for _, tok := range strings.Split(pointer[1:], "/") {
+		tok = pointerUnescaper.Replace(tok)
+
+		switch n := node.(type) {
+		case map[string]interface{}:
+			c, ok := n[tok]
+			if !ok {
+				return nil, fmt.Errorf("missing %q", tok)
+			}
+
+			node = c
+		case []interface{}:
+			i, err := strconv.Atoi(tok)
+			if err != nil || i < 0 || i >= len(n) {
+				return nil, fmt.Errorf("invalid index %q", tok)
+			}
+
+			node = n[i]
+		default:
+			return nil, fmt.Errorf("can not traverse %q", tok)
+		}
+	}
+
+	return node, nil
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+
+		return f, err == nil
+	case int, int8, int16, int32, int64:
+		return float64(reflect.ValueOf(n).Int()), true
+	case uint, uint8, uint16, uint32, uint64:
+		return float64(reflect.ValueOf(n).Uint()), true
+	}
+
+	return 0, false
+}
+
+func instanceType(v interface{}) SimpleType {
+	switch val := v.(type) {
+	case nil:
+		return Null
+	case bool:
+		return Boolean
+	case string:
+		return String
+	case map[string]interface{}:
+		return Object
+	case []interface{}:
+		return Array
+	case json.Number:
+		if _, err := val.Int64(); err == nil {
+			return Integer
+		}
+	}
+
+	if f, ok := toFloat(v); ok {
+		if f == math.Trunc(f) && !math.IsInf(f, 0) {
+			return Integer
+		}
+
+		return Number
+	}
+
+	return ""
+}
+
+func instanceTypeName(v interface{}) string {
+	t := instanceType(v)
+	if t == "" {
+		return fmt.Sprintf("%T", v)
+	}
+
+	return string(t)
+}
+
+func typeMatches(t *Type, v interface{}) bool {
+	it := instanceType(v)
+
+	match := func(st SimpleType) bool {
+		return st == it || (st == Number && it == Integer)
+	}
+
+	if t.SimpleTypes != nil {
+		return match(*t.SimpleTypes)
+	}
+
+	if len(t.SliceOfSimpleTypeValues) == 0 {
+		return true
+	}
+
+	for _, st := range t.SliceOfSimpleTypeValues {
+		if match(st) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func typeNames(t *Type) string {
+	if t.SimpleTypes != nil {
+		return string(*t.SimpleTypes)
+	}
+
+	names := make([]string, 0, len(t.SliceOfSimpleTypeValues))
+	for _, st := range t.SliceOfSimpleTypeValues {
+		names = append(names, string(st))
+	}
+
+	return strings.Join(names, " or ")
+}
+
+func jsonString(v interface{}) string {
+	j, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+
+	return string(j)
+}
+
+// jsonEqual compares two JSON values, numbers are compared by value.
+func jsonEqual(a, b interface{}) bool {
+	if fa, ok := toFloat(a); ok {
+		fb, ok := toFloat(b)
+
+		return ok && fa == fb
+	}
+
+	switch av := a.(type) {
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+
+		for k, v := range av {
+			if w, ok := bv[k]; !ok || !jsonEqual(v, w) {
+				return false
+			}
+		}
+
+		return true
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+
+		for i := range av {
+			if !jsonEqual(av[i], bv[i]) {
+				return false
+			}
+		}
+
+		return true
+	case nil, bool, string:
+		return a == b
+	}
+
+	// Values of other types (e.g. typed Go slices in schema enum) are compared by JSON representation.
+	return jsonString(a) == jsonString(b)
+}
+
+func checkFormat(format, s string) error {
+	var err error
+
+	switch format {
+	case "date-time":
+		_, err = time.Parse(time.RFC3339, s)
+	case "date":
+		_, err = time.Parse(DateLayout, s)
+	case "time":
+		_, err = time.Parse("15:04:05Z07:00", s)
+	case "email":
+		_, err = mail.ParseAddress(s)
+	case "uri":
+		var u *url.URL
+
+		if u, err = url.Parse(s); err == nil && !u.IsAbs() {
+			err = errors.New("absolute URI expected")
+		}
+	case "uri-reference":
+		_, err = url.Parse(s)
+	case "ipv4":
+		if ip := net.ParseIP(s); ip == nil || ip.To4() == nil {
+			err = errors.New("invalid IPv4 address")
+		}
+	case "ipv6":
+		if ip := net.ParseIP(s); ip == nil || ip.To4() != nil {
+			err = errors.New("invalid IPv6 address")
+		}
+	case "uuid":
+		if !uuidRegex.MatchString(s) {
+			err = errors.New("invalid UUID")
+		}
+	case "regex":
+		_, err = regexp.Compile(s)
+	}
+
+	return err
+}
+
+var uuidRegex = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
@@ -0,0 +1,374 @@
+package jsonschema_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/jsonschema-go"
+)
+
+func TestValidator_ValidateJSON(t *testing.T) {
+	type Item struct {
+		Name  string  `json:"name" minLength:"2" required:"true"`
+		Price float64 `json:"price" minimum:"0" exclusiveMaximum:"1000"`
+	}
+
+	type Order struct {
+		ID     int      `json:"id" required:"true"`
+		Email  string   `json:"email,omitempty" format:"email"`
+		Status string   `json:"status,omitempty" enum:"new,paid"`
+		Items  []Item   `json:"items" minItems:"1"`
+		Tags   []string `json:"tags,omitempty" uniqueItems:"true"`
+		Parent *Order   `json:"parent,omitempty"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(Order{})
+	require.NoError(t, err)
+
+	v := jsonschema.NewValidator(s)
+
+	require.NoError(t, v.ValidateJSON([]byte(`{"id":1,"items":[{"name":"ab","price":10}]}`)))
+	require.NoError(t, v.ValidateJSON([]byte(`{"id":1,"items":[{"name":"ab"}],"parent":{"id":2,"items":null}}`)))
+
+	err = v.ValidateJSON([]byte(`{"id":1.5,"email":"foo","status":"lost","items":[{"price":-1}],"tags":["a","a"],` +
+		`"parent":{"id":"2","items":[]}}`))
+	require.Error(t, err)
+
+	var ve jsonschema.ValidationErrors
+
+	require.True(t, errors.As(err, &ve))
+	assert.Equal(t, []jsonschema.ValidationError{
+		{
+			Keyword: "format", InstancePath: "/email", SchemaPath: "#/properties/email/format",
+			Message: `value "foo" must be of format "email": mail: missing '@' or angle-addr`,
+		},
+		{
+			Keyword: "type", InstancePath: "/id", SchemaPath: "#/properties/id/type",
+			Message: "expected integer, got number",
+		},
+		{
+			Keyword: "required", InstancePath: "/items/0", SchemaPath: "#/definitions/JsonschemaGoTestItem/required",
+			Message: `missing required property "name"`,
+		},
+		{
+			Keyword: "minimum", InstancePath: "/items/0/price",
+			SchemaPath: "#/definitions/JsonschemaGoTestItem/properties/price/minimum",
+			Message:    "value -1 must be greater than or equal to 0",
+		},
+		{
+			Keyword: "type", InstancePath: "/parent/id", SchemaPath: "#/properties/id/type",
+			Message: "expected integer, got string",
+		},
+		{
+			Keyword: "minItems", InstancePath: "/parent/items", SchemaPath: "#/properties/items/minItems",
+			Message: "array must have at least 1 items, got 0",
+		},
+		{
+			Keyword: "enum", InstancePath: "/status", SchemaPath: "#/properties/status/enum",
+			Message: `value must be one of ["new","paid"]`,
+		},
+		{
+			Keyword: "uniqueItems", InstancePath: "/tags", SchemaPath: "#/properties/tags/uniqueItems",
+			Message: "items at 0 and 1 must be unique",
+		},
+	}, []jsonschema.ValidationError(ve))
+}
+
+func TestValidator_Validate_composition(t *testing.T) {
+	var s jsonschema.Schema
+
+	require.NoError(t, s.UnmarshalJSON([]byte(`{
+	  "oneOf":[{"multipleOf":2},{"multipleOf":3}],
+	  "not":{"const":8},
+	  "if":{"minimum":100},"then":{"maximum":200},"else":{"minimum":3}
+	}`)))
+
+	v := jsonschema.NewValidator(s)
+
+	assert.NoError(t, v.Validate(4.0))
+	assert.NoError(t, v.Validate(104.0))
+	assert.EqualError(t, v.Validate(6.0), "validation failed: /: value must match exactly one schema in oneOf, matched 2")
+	assert.EqualError(t, v.Validate(5.0), "validation failed: /: value must match exactly one schema in oneOf, matched 0")
+	assert.EqualError(t, v.Validate(8.0), "validation failed: /: value must not match schema")
+	assert.EqualError(t, v.Validate(202.0), "validation failed: /: value 202 must be less than or equal to 200")
+	assert.EqualError(t, v.Validate(2.0), "validation failed: /: value 2 must be greater than or equal to 3")
+}
+
+func TestValidator_Validate_refResolver(t *testing.T) {
+	var s jsonschema.Schema
+
+	s.WithRef("#/components/schemas/Name")
+
+	name := jsonschema.Schema{}
+	name.AddType(jsonschema.String)
+	name.WithMaxLength(3)
+
+	v := jsonschema.NewValidator(s, func(ref string) (jsonschema.SchemaOrBool, bool) {
+		return name.ToSchemaOrBool(), ref == "#/components/schemas/Name"
+	})
+
+	assert.NoError(t, v.Validate("abc"))
+	assert.EqualError(t, v.Validate("abcd"), "validation failed: /: length 4 must be less than or equal to 3")
+
+	s.WithRef("#/definitions/Missing")
+	assert.EqualError(t, jsonschema.NewValidator(s).Validate("abc"),
+		`validation failed: /: unresolved reference "#/definitions/Missing": missing "definitions"`)
+}
+
+type valueStatus string
+
+func (s valueStatus) MarshalText() ([]byte, error) {
+	return []byte("status-" + string(s)), nil
+}
+
+func TestValidateValue(t *testing.T) {
+	type Base struct {
+		ID   int    `json:"id" minimum:"1"`
+		Note string `json:"note,omitempty" minLength:"3"`
+	}
+
+	type Item struct {
+		*Base
+		Name   string            `json:"name" required:"true" minLength:"2"`
+		Status valueStatus       `json:"status" pattern:"^status-"`
+		Count  uint64            `json:"count"`
+		Labels map[string]string `json:"labels,omitempty" maxProperties:"1"`
+		Skip   string            `json:"-"`
+		secret string
+	}
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(Item{})
+	require.NoError(t, err)
+
+	require.NoError(t, jsonschema.ValidateValue(s, Item{
+		Base: &Base{ID: 1}, Name: "ab", Status: "new", Count: 1, Skip: "x", secret: "y",
+	}))
+	require.NoError(t, jsonschema.ValidateValue(s, &Item{Name: "ab", Status: "new"}))
+
+	err = jsonschema.ValidateValue(s, Item{
+		Base: &Base{ID: 0, Note: "a"}, Name: "a", Labels: map[string]string{"a": "1", "b": "2"},
+	})
+	require.Error(t, err)
+
+	var ve jsonschema.ValidationErrors
+
+	require.True(t, errors.As(err, &ve))
+
+	paths := make([]string, 0, len(ve))
+	for _, e := range ve {
+		paths = append(paths, e.Keyword+" "+e.InstancePath)
+	}
+
+	assert.Equal(t, []string{"minimum /id", "maxProperties /labels", "minLength /name", "minLength /note"}, paths)
+}
+
+func TestSchema_Validate(t *testing.T) {
+	type Item struct {
+		Name string `json:"name" minLength:"2"`
+	}
+
+	type Order struct {
+		Items []Item `json:"items"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(Order{})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	require.NoError(t, s.Validate(ctx, Order{Items: []Item{{Name: "ab"}}}))
+	require.NoError(t, s.Validate(ctx, []byte(`{"items":[{"name":"ab"}]}`)))
+
+	var ve jsonschema.ValidationErrors
+
+	require.True(t, errors.As(s.Validate(ctx, Order{Items: []Item{{Name: "a"}}}), &ve))
+	require.Len(t, ve, 1)
+	assert.Equal(t, "minLength", ve[0].Keyword)
+	assert.Equal(t, "/items/0/name", ve[0].InstancePath)
+	assert.Equal(t, "#/definitions/JsonschemaGoTestItem/properties/name/minLength", ve[0].SchemaPath)
+
+	require.True(t, errors.As(s.Validate(ctx, []byte(`{"items":[{"name":"a"}]}`)), &ve))
+	assert.Equal(t, "/items/0/name", ve[0].InstancePath)
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+
+	assert.ErrorIs(t, s.Validate(cancelled, Order{}), context.Canceled)
+}
+
+func TestValidator_ValidateStream(t *testing.T) {
+	type Item struct {
+		Name  string  `json:"name" minLength:"2" required:"true"`
+		Price float64 `json:"price" minimum:"0"`
+	}
+
+	type Order struct {
+		ID    int               `json:"id" required:"true"`
+		Items []Item            `json:"items" minItems:"1" maxItems:"3"`
+		Tags  []string          `json:"tags,omitempty" uniqueItems:"true"`
+		Meta  map[string]string `json:"meta,omitempty"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect([]Order{})
+	require.NoError(t, err)
+
+	v := jsonschema.NewValidator(s)
+
+	for _, doc := range []string{
+		`[{"id":1,"items":[{"name":"ab","price":10}]}]`,
+		`[{"id":1.5,"items":[{"price":-1},{"name":"a"},{"name":"ab"},{"name":"ab"}],"tags":["a","a"],"meta":{"a":1}},` +
+			`{"items":{}},"foo",null]`,
+		`{"id":1}`,
+	} {
+		expected := v.ValidateJSON([]byte(doc))
+		err := v.ValidateStream(json.NewDecoder(strings.NewReader(doc)))
+
+		if expected == nil {
+			assert.NoError(t, err, doc)
+
+			continue
+		}
+
+		var ve, vs jsonschema.ValidationErrors
+
+		require.True(t, errors.As(expected, &ve))
+		require.True(t, errors.As(err, &vs), err)
+		assert.ElementsMatch(t, ve, vs, doc)
+	}
+
+	err = v.ValidateStream(json.NewDecoder(strings.NewReader(`[{"id":1,"items":[`)))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to decode JSON")
+}
+
+func TestValidateLines(t *testing.T) {
+	type Row struct {
+		ID   int    `json:"id" required:"true" minimum:"1"`
+		Name string `json:"name" minLength:"1"`
+	}
+
+	r := jsonschema.Reflector{}
+
+	s, err := r.Reflect(Row{})
+	require.NoError(t, err)
+
+	require.NoError(t, jsonschema.ValidateLines(strings.NewReader("{\"id\":1}\n\n{\"id\":2,\"name\":\"a\"}"), s))
+
+	input := "{\"id\":1}\n{\"id\":0}\n{\"name\":\"\"}\n{\"id\":\n{\"id\":3}\n"
+
+	err = jsonschema.ValidateLines(strings.NewReader(input), s)
+	require.Error(t, err)
+
+	var le jsonschema.LineErrors
+
+	require.True(t, errors.As(err, &le))
+	assert.Equal(t, 5, le.Lines)
+	assert.Equal(t, 3, le.Invalid)
+	require.Len(t, le.Errors, 3)
+	assert.Equal(t, []int{2, 3, 4}, []int{le.Errors[0].Line, le.Errors[1].Line, le.Errors[2].Line})
+	assert.Equal(t, "line 2: validation failed: /id: value 0 must be greater than or equal to 1", le.Errors[0].Error())
+
+	v := jsonschema.NewValidator(s)
+	v.MaxLineErrors = 1
+
+	err = v.ValidateLines(strings.NewReader(input))
+	require.True(t, errors.As(err, &le))
+	assert.Equal(t, 3, le.Invalid)
+	assert.Len(t, le.Errors, 1)
+	assert.Equal(t, "3 of 5 lines are invalid: line 2: validation failed: /id: "+
+		"value 0 must be greater than or equal to 1; 2 more", err.Error())
+}
+
+func TestValidator_Cache(t *testing.T) {
+	var s jsonschema.Schema
+
+	require.NoError(t, s.UnmarshalJSON([]byte(`{"type":"object","properties":{"id":{"type":"integer","minimum":1}}}`)))
+
+	hits := 0
+	cache := jsonschema.NewResultCache(2)
+	cache.OnHit = func() { hits++ }
+
+	v := jsonschema.NewValidator(s)
+	v.Cache = cache
+
+	assert.NoError(t, v.ValidateJSON([]byte(`{"id":1}`)))
+	assert.NoError(t, v.ValidateJSON([]byte(`{"id":1}`)))
+	invalid := "validation failed: /id: value 0 must be greater than or equal to 1"
+	assert.EqualError(t, v.ValidateJSON([]byte(`{"id":0}`)), invalid)
+	assert.EqualError(t, v.ValidateJSON([]byte(`{"id":0}`)), invalid)
+	assert.Error(t, v.ValidateJSON([]byte(`{"id":`)))
+	assert.Equal(t, 2, hits)
+
+	// Validators of equal schemas share results.
+	v2 := jsonschema.NewValidator(s)
+	v2.Cache = cache
+
+	assert.NoError(t, v2.Validate(map[string]interface{}{"id": 1}))
+	assert.NoError(t, v2.Validate(map[string]interface{}{"id": 2}))
+	assert.Equal(t, 3, hits)
+
+	assert.Equal(t, jsonschema.CacheStats{Hits: 3, Misses: 4, Evictions: 1, Len: 2}, cache.Stats())
+}
+
+func TestSchema_Validate_targetDraft(t *testing.T) {
+	type Order struct {
+		_ struct{} `dependentSchemas:"{\"gift\":{\"required\":[\"message\"]}}"`
+
+		Card    string    `json:"card,omitempty" dependentRequired:"zip"`
+		Zip     string    `json:"zip,omitempty"`
+		Gift    bool      `json:"gift,omitempty"`
+		Message string    `json:"message,omitempty"`
+		Pair    draftPair `json:"pair,omitempty"`
+		Amount  float64   `json:"amount,omitempty" minimum:"5" exclusiveMinimum:"true"`
+	}
+
+	r := jsonschema.Reflector{}
+	ctx := context.Background()
+
+	s, err := r.Reflect(Order{}, jsonschema.TargetDraft(jsonschema.DraftVersion2020))
+	require.NoError(t, err)
+
+	require.NoError(t, s.Validate(ctx, []byte(`{"card":"x","zip":"1","gift":true,"message":"hi","pair":[1,"a"]}`)))
+
+	var ve jsonschema.ValidationErrors
+
+	require.True(t, errors.As(s.Validate(ctx, []byte(`{"card":"x"}`)), &ve))
+	require.Len(t, ve, 1)
+	assert.Equal(t, "dependentRequired", ve[0].Keyword)
+	assert.Equal(t, "#/dependentRequired/card", ve[0].SchemaPath)
+
+	require.True(t, errors.As(s.Validate(ctx, []byte(`{"gift":true}`)), &ve))
+	require.Len(t, ve, 1)
+	assert.Equal(t, "#/dependentSchemas/gift/required", ve[0].SchemaPath)
+
+	require.True(t, errors.As(s.Validate(ctx, []byte(`{"pair":[1,2]}`)), &ve))
+	require.Len(t, ve, 1)
+	assert.Equal(t, "/pair/1", ve[0].InstancePath)
+	assert.Equal(t, "#/$defs/JsonschemaGoTestDraftPair/prefixItems/1/type", ve[0].SchemaPath)
+
+	require.True(t, errors.As(s.Validate(ctx, []byte(`{"pair":[1,"a",3]}`)), &ve))
+	require.Len(t, ve, 1)
+	assert.Equal(t, "/pair/2", ve[0].InstancePath)
+
+	s, err = r.Reflect(Order{}, jsonschema.TargetDraft(jsonschema.DraftVersion04))
+	require.NoError(t, err)
+
+	require.NoError(t, s.Validate(ctx, []byte(`{"amount":5.5}`)))
+	require.True(t, errors.As(s.Validate(ctx, []byte(`{"amount":5}`)), &ve))
+	require.Len(t, ve, 1)
+	assert.Equal(t, "exclusiveMinimum", ve[0].Keyword)
+	assert.Equal(t, "/amount", ve[0].InstancePath)
+}
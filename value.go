@@ -0,0 +1,305 @@
+package jsonschema
+
+import (
+	"bytes"
+	"context"
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ValidateValue checks Go value against schema without JSON marshaling round trip.
+//
+// Value is walked with naming rules of encoding/json (and reflection with default `json` property tag),
+// types that implement json.Marshaler are marshaled, encoding.TextMarshaler values become strings.
+func ValidateValue(schema Schema, v interface{}) error {
+	return NewValidator(schema).ValidateValue(v)
+}
+
+// Validate checks JSON document ([]byte or json.RawMessage) or Go value against schema.
+//
+// References are resolved against the schema itself, returned error is ValidationErrors if value is invalid.
+// Validation is not started if ctx is done, use Validator to check many values against the same schema.
+func (s Schema) Validate(ctx context.Context, value interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	v := NewValidator(s)
+
+	switch data := value.(type) {
+	case []byte:
+		return v.ValidateJSON(data)
+	case json.RawMessage:
+		return v.ValidateJSON(data)
+	default:
+		return v.ValidateValue(value)
+	}
+}
+
+// ValidateValue checks Go value against schema without JSON marshaling round trip, see ValidateValue.
+func (v *Validator) ValidateValue(value interface{}) error {
+	jv, err := jsonValueOf(reflect.ValueOf(value))
+	if err != nil {
+		return err
+	}
+
+	return v.Validate(jv)
+}
+
+// jsonValueOf converts Go value into a value of JSON data model as produced by json.Decoder with UseNumber.
+func jsonValueOf(v reflect.Value) (interface{}, error) {
+	if !v.IsValid() {
+		return nil, nil
+	}
+
+	if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+		return nil, nil
+	}
+
+	if jm, ok := marshalerOf(v, typeOfJSONMarshaler); ok {
+		return decodeMarshaled(jm.(json.Marshaler)) //nolint:forcetypeassert // Type is checked by marshalerOf.
+	}
+
+	if tm, ok := marshalerOf(v, typeOfTextMarshaler); ok {
+		text, err := tm.(encoding.TextMarshaler).MarshalText() //nolint:forcetypeassert // Type is checked by marshalerOf.
+		if err != nil {
+			return nil, err
+		}
+
+		return string(text), nil
+	}
+
+	switch v.Kind() { //nolint:exhaustive // Unsupported kinds fail with error.
+	case reflect.Ptr, reflect.Interface:
+		return jsonValueOf(v.Elem())
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return json.Number(strconv.FormatInt(v.Int(), 10)), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return json.Number(strconv.FormatUint(v.Uint(), 10)), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil, nil
+		}
+
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return base64.StdEncoding.EncodeToString(v.Bytes()), nil
+		}
+
+		return jsonArrayOf(v)
+	case reflect.Array:
+		return jsonArrayOf(v)
+	case reflect.Map:
+		if v.IsNil() {
+			return nil, nil
+		}
+
+		return jsonObjectOfMap(v)
+	case reflect.Struct:
+		res := map[string]interface{}{}
+
+		if err := jsonObjectOfStruct(v, res, map[string]int{}, 0); err != nil {
+			return nil, err
+		}
+
+		return res, nil
+	}
+
+	return nil, fmt.Errorf("unsupported value type %s", v.Type())
+}
+
+func marshalerOf(v reflect.Value, iface reflect.Type) (interface{}, bool) {
+	if v.Type().Implements(iface) && v.CanInterface() {
+		return v.Interface(), true
+	}
+
+	if v.CanAddr() && reflect.PtrTo(v.Type()).Implements(iface) && v.Addr().CanInterface() {
+		return v.Addr().Interface(), true
+	}
+
+	return nil, false
+}
+
+func decodeMarshaled(m json.Marshaler) (interface{}, error) {
+	j, err := m.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	var res interface{}
+
+	d := json.NewDecoder(bytes.NewReader(j))
+	d.UseNumber()
+
+	if err := d.Decode(&res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func jsonArrayOf(v reflect.Value) ([]interface{}, error) {
+	res := make([]interface{}, v.Len())
+
+	for i := 0; i < v.Len(); i++ {
+		item, err := jsonValueOf(v.Index(i))
+		if err != nil {
+			return nil, err
+		}
+
+		res[i] = item
+	}
+
+	return res, nil
+}
+
+func jsonObjectOfMap(v reflect.Value) (map[string]interface{}, error) {
+	res := make(map[string]interface{}, v.Len())
+	iter := v.MapRange()
+
+	for iter.Next() {
+		k := iter.Key()
+
+		var key string
+
+		switch {
+		case k.Kind() == reflect.String:
+			key = k.String()
+		case k.Type().Implements(typeOfTextMarshaler):
+			text, err := k.Interface().(encoding.TextMarshaler).MarshalText() //nolint:forcetypeassert
+			if err != nil {
+				return nil, err
+			}
+
+			key = string(text)
+		case k.CanInt():
+			key = strconv.FormatInt(k.Int(), 10)
+		case k.CanUint():
+			key = strconv.FormatUint(k.Uint(), 10)
+		default:
+			return nil, fmt.Errorf("unsupported map key type %s", k.Type())
+		}
+
+		val, err := jsonValueOf(iter.Value())
+		if err != nil {
+			return nil, err
+		}
+
+		res[key] = val
+	}
+
+	return res, nil
+}
+
+// jsonObjectOfStruct adds struct fields to res, fields of embedded structs are added if not shadowed
+// by fields of lower depth.
+func jsonObjectOfStruct(v reflect.Value, res map[string]interface{}, depths map[string]int, depth int) error {
+	t := v.Type()
+
+	type embedded struct {
+		v reflect.Value
+	}
+
+	var embeds []embedded
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+
+		if tag == "-" {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		fv := v.Field(i)
+
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+
+			if ft.Kind() == reflect.Struct {
+				if fv.Kind() == reflect.Ptr {
+					if fv.IsNil() {
+						continue
+					}
+
+					fv = fv.Elem()
+				}
+
+				embeds = append(embeds, embedded{v: fv})
+
+				continue
+			}
+		}
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		if d, ok := depths[name]; ok && d <= depth {
+			continue
+		}
+
+		if strings.Contains(","+opts+",", ",omitempty,") && isEmptyValue(fv) {
+			continue
+		}
+
+		val, err := jsonValueOf(fv)
+		if err != nil {
+			return fmt.Errorf("%s: %w", field.Name, err)
+		}
+
+		if strings.Contains(","+opts+",", ",string,") && val != nil {
+			switch val.(type) {
+			case bool, json.Number, float64, string:
+				val = fmt.Sprint(val)
+			}
+		}
+
+		depths[name] = depth
+		res[name] = val
+	}
+
+	for _, e := range embeds {
+		if err := jsonObjectOfStruct(e.v, res, depths, depth+1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() { //nolint:exhaustive // Other kinds are never empty.
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+
+	return false
+}
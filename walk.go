@@ -0,0 +1,229 @@
+package jsonschema
+
+// walkSchemas calls visit for schema and all its subschemas, references are not followed.
+func walkSchemas(s *Schema, visit func(s *Schema)) {
+	walkSchema(s, visit, map[*Schema]bool{})
+}
+
+func walkSchema(s *Schema, visit func(s *Schema), seen map[*Schema]bool) {
+	if s == nil || seen[s] {
+		return
+	}
+
+	seen[s] = true
+
+	visit(s)
+
+	sub := func(sb *SchemaOrBool) {
+		if sb != nil {
+			walkSchema(sb.TypeObject, visit, seen)
+		}
+	}
+
+	list := func(l []SchemaOrBool) {
+		for i := range l {
+			sub(&l[i])
+		}
+	}
+
+	dict := func(m map[string]SchemaOrBool) {
+		for _, v := range m {
+			v := v
+			sub(&v)
+		}
+	}
+
+	sub(s.AdditionalItems)
+
+	if s.Items != nil {
+		sub(s.Items.SchemaOrBool)
+		list(s.Items.SchemaArray)
+	}
+
+	sub(s.Contains)
+	sub(s.AdditionalProperties)
+	dict(s.Definitions)
+	dict(s.Properties)
+	dict(s.PatternProperties)
+
+	for _, d := range s.Dependencies {
+		sub(d.SchemaOrBool)
+	}
+
+	sub(s.PropertyNames)
+	sub(s.If)
+	sub(s.Then)
+	sub(s.Else)
+	list(s.AllOf)
+	list(s.AnyOf)
+	list(s.OneOf)
+	sub(s.Not)
+}
+
+// cloneSchema makes a deep copy of schema and its subschemas, so that changes of the copy do not affect original.
+//
+// Scalar values behind pointers are shared, they are replaced rather than changed by Schema methods.
+func cloneSchema(s Schema) Schema {
+	c := s
+	c.Type = copyType(s.Type)
+	c.Examples = cloneSlice(s.Examples)
+	c.Enum = cloneSlice(s.Enum)
+
+	if s.Required != nil {
+		c.Required = append([]string(nil), s.Required...)
+	}
+
+	if s.PropertiesOrder != nil {
+		c.PropertiesOrder = append([]string(nil), s.PropertiesOrder...)
+	}
+
+	if s.ExtraProperties != nil {
+		c.ExtraProperties = make(map[string]interface{}, len(s.ExtraProperties))
+		for k, v := range s.ExtraProperties {
+			c.ExtraProperties[k] = v
+		}
+	}
+
+	c.AdditionalItems = cloneSchemaOrBoolPtr(s.AdditionalItems)
+
+	if s.Items != nil {
+		c.Items = &Items{
+			SchemaOrBool: cloneSchemaOrBoolPtr(s.Items.SchemaOrBool),
+			SchemaArray:  cloneSchemaList(s.Items.SchemaArray),
+		}
+	}
+
+	c.Contains = cloneSchemaOrBoolPtr(s.Contains)
+	c.AdditionalProperties = cloneSchemaOrBoolPtr(s.AdditionalProperties)
+	c.Definitions = cloneSchemaMap(s.Definitions)
+	c.Properties = cloneSchemaMap(s.Properties)
+	c.PatternProperties = cloneSchemaMap(s.PatternProperties)
+
+	if s.Dependencies != nil {
+		c.Dependencies = make(map[string]DependenciesAdditionalProperties, len(s.Dependencies))
+
+		for k, d := range s.Dependencies {
+			c.Dependencies[k] = DependenciesAdditionalProperties{
+				SchemaOrBool: cloneSchemaOrBoolPtr(d.SchemaOrBool),
+				StringArray:  append([]string(nil), d.StringArray...),
+			}
+		}
+	}
+
+	c.PropertyNames = cloneSchemaOrBoolPtr(s.PropertyNames)
+	c.If = cloneSchemaOrBoolPtr(s.If)
+	c.Then = cloneSchemaOrBoolPtr(s.Then)
+	c.Else = cloneSchemaOrBoolPtr(s.Else)
+	c.AllOf = cloneSchemaList(s.AllOf)
+	c.AnyOf = cloneSchemaList(s.AnyOf)
+	c.OneOf = cloneSchemaList(s.OneOf)
+	c.Not = cloneSchemaOrBoolPtr(s.Not)
+
+	return c
+}
+
+func cloneSchemaOrBool(s SchemaOrBool) SchemaOrBool {
+	if s.TypeObject != nil {
+		c := cloneSchema(*s.TypeObject)
+		s.TypeObject = &c
+	}
+
+	return s
+}
+
+func cloneSchemaOrBoolPtr(s *SchemaOrBool) *SchemaOrBool {
+	if s == nil {
+		return nil
+	}
+
+	c := cloneSchemaOrBool(*s)
+
+	return &c
+}
+
+func cloneSchemaList(l []SchemaOrBool) []SchemaOrBool {
+	if l == nil {
+		return nil
+	}
+
+	res := make([]SchemaOrBool, len(l))
+	for i, s := range l {
+		res[i] = cloneSchemaOrBool(s)
+	}
+
+	return res
+}
+
+func cloneSchemaMap(m map[string]SchemaOrBool) map[string]SchemaOrBool {
+	if m == nil {
+		return nil
+	}
+
+	res := make(map[string]SchemaOrBool, len(m))
+	for k, s := range m {
+		res[k] = cloneSchemaOrBool(s)
+	}
+
+	return res
+}
+
+func cloneSlice(l []interface{}) []interface{} {
+	if l == nil {
+		return nil
+	}
+
+	return append([]interface{}(nil), l...)
+}
+
+// mapSubschemas replaces direct subschemas of s with results of f.
+func mapSubschemas(s *Schema, f func(s SchemaOrBool) (SchemaOrBool, error)) error {
+	var err error
+
+	sub := func(sb *SchemaOrBool) {
+		if sb != nil && err == nil {
+			*sb, err = f(*sb)
+		}
+	}
+
+	list := func(l []SchemaOrBool) {
+		for i := range l {
+			sub(&l[i])
+		}
+	}
+
+	dict := func(m map[string]SchemaOrBool) {
+		for k, v := range m {
+			v := v
+			sub(&v)
+			m[k] = v
+		}
+	}
+
+	sub(s.AdditionalItems)
+
+	if s.Items != nil {
+		sub(s.Items.SchemaOrBool)
+		list(s.Items.SchemaArray)
+	}
+
+	sub(s.Contains)
+	sub(s.AdditionalProperties)
+	dict(s.Definitions)
+	dict(s.Properties)
+	dict(s.PatternProperties)
+
+	for _, dep := range s.Dependencies {
+		sub(dep.SchemaOrBool)
+	}
+
+	sub(s.PropertyNames)
+	sub(s.If)
+	sub(s.Then)
+	sub(s.Else)
+	list(s.AllOf)
+	list(s.AnyOf)
+	list(s.OneOf)
+	sub(s.Not)
+
+	return err
+}
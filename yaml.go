@@ -0,0 +1,338 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MarshalYAML implements yaml.Marshaler.
+//
+// Schema is encoded with same keys as in JSON, including ExtraProperties, properties follow PropertiesOrder.
+func (s Schema) MarshalYAML() (interface{}, error) {
+	data, err := s.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	return jsonToYAMLNode(data)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+//
+// Schema is decoded same way as from JSON, order of properties is kept in PropertiesOrder,
+// so that schemas of YAML documents (e.g. OpenAPI or AsyncAPI) can be written back without reordering.
+func (s *Schema) UnmarshalYAML(value *yaml.Node) error {
+	data, err := yamlNodeToJSON(value)
+	if err != nil {
+		return err
+	}
+
+	if err := s.UnmarshalJSON(data); err != nil {
+		return err
+	}
+
+	yamlPropertiesOrder(s, value)
+
+	return nil
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (s SchemaOrBool) MarshalYAML() (interface{}, error) {
+	if s.TypeObject != nil {
+		return s.TypeObject.MarshalYAML()
+	}
+
+	data, err := s.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	return jsonToYAMLNode(data)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (s *SchemaOrBool) UnmarshalYAML(value *yaml.Node) error {
+	var b bool
+
+	if n := yamlResolve(value); n.Kind == yaml.ScalarNode && n.ShortTag() == "!!bool" {
+		if err := n.Decode(&b); err != nil {
+			return err
+		}
+
+		s.TypeObject = nil
+		s.TypeBoolean = &b
+
+		return nil
+	}
+
+	s.TypeBoolean = nil
+	s.TypeObject = &Schema{}
+
+	return s.TypeObject.UnmarshalYAML(value)
+}
+
+// yamlResolve returns content of document and alias nodes.
+func yamlResolve(n *yaml.Node) *yaml.Node {
+	for n != nil {
+		switch {
+		case n.Kind == yaml.DocumentNode && len(n.Content) == 1:
+			n = n.Content[0]
+		case n.Kind == yaml.AliasNode:
+			n = n.Alias
+		default:
+			return n
+		}
+	}
+
+	return n
+}
+
+// yamlNodeToJSON converts YAML node to JSON with same order of mapping keys.
+func yamlNodeToJSON(n *yaml.Node) ([]byte, error) {
+	buf := bytes.NewBuffer(nil)
+
+	if err := writeYAMLNodeJSON(buf, n); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writeYAMLNodeJSON(buf *bytes.Buffer, n *yaml.Node) error {
+	n = yamlResolve(n)
+	if n == nil {
+		buf.WriteString("null")
+
+		return nil
+	}
+
+	switch n.Kind {
+	case yaml.MappingNode:
+		buf.WriteByte('{')
+
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+
+			k, err := json.Marshal(yamlResolve(n.Content[i]).Value)
+			if err != nil {
+				return err
+			}
+
+			buf.Write(k)
+			buf.WriteByte(':')
+
+			if err := writeYAMLNodeJSON(buf, n.Content[i+1]); err != nil {
+				return err
+			}
+		}
+
+		buf.WriteByte('}')
+	case yaml.SequenceNode:
+		buf.WriteByte('[')
+
+		for i, c := range n.Content {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+
+			if err := writeYAMLNodeJSON(buf, c); err != nil {
+				return err
+			}
+		}
+
+		buf.WriteByte(']')
+	case yaml.ScalarNode:
+		var v interface{}
+
+		// Timestamps are kept as strings instead of time.Time values.
+		if tag := n.ShortTag(); tag == "!!str" || tag == "!!timestamp" {
+			v = n.Value
+		} else if err := n.Decode(&v); err != nil {
+			return err
+		}
+
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", n.Line, err)
+		}
+
+		buf.Write(data)
+	default:
+		return fmt.Errorf("line %d: unexpected YAML node kind %d", n.Line, n.Kind)
+	}
+
+	return nil
+}
+
+// jsonToYAMLNode converts JSON to YAML node with same order of object keys.
+func jsonToYAMLNode(data []byte) (*yaml.Node, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	return decodeYAMLNode(dec)
+}
+
+func decodeYAMLNode(dec *json.Decoder) (*yaml.Node, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := tok.(type) {
+	case json.Delim:
+		kind := yaml.SequenceNode
+		tag := "!!seq"
+
+		if t == '{' {
+			kind = yaml.MappingNode
+			tag = "!!map"
+		}
+
+		n := &yaml.Node{Kind: kind, Tag: tag}
+
+		for dec.More() {
+			if kind == yaml.MappingNode {
+				k, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+
+				kn, err := yamlScalar(k)
+				if err != nil {
+					return nil, err
+				}
+
+				n.Content = append(n.Content, kn)
+			}
+
+			c, err := decodeYAMLNode(dec)
+			if err != nil {
+				return nil, err
+			}
+
+			n.Content = append(n.Content, c)
+		}
+
+		// Closing delimiter.
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+
+		return n, nil
+	case json.Number:
+		tag := "!!int"
+		if _, err := t.Int64(); err != nil {
+			tag = "!!float"
+		}
+
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: t.String()}, nil
+	default:
+		return yamlScalar(t)
+	}
+}
+
+// yamlScalar encodes string, bool or nil value in the same way as yaml.Marshal,
+// e.g. strings that are booleans in YAML 1.1 ("yes", "y") are quoted.
+func yamlScalar(v interface{}) (*yaml.Node, error) {
+	n := &yaml.Node{}
+
+	if err := n.Encode(v); err != nil {
+		return nil, err
+	}
+
+	return n, nil
+}
+
+// yamlPropertiesOrder sets PropertiesOrder of schema and its subschemas from order of keys in YAML mapping.
+func yamlPropertiesOrder(s *Schema, n *yaml.Node) {
+	n = yamlResolve(n)
+	if s == nil || n == nil || n.Kind != yaml.MappingNode {
+		return
+	}
+
+	field := func(name string) *yaml.Node {
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			if yamlResolve(n.Content[i]).Value == name {
+				return yamlResolve(n.Content[i+1])
+			}
+		}
+
+		return nil
+	}
+
+	sub := func(sb *SchemaOrBool, n *yaml.Node) {
+		if sb != nil {
+			yamlPropertiesOrder(sb.TypeObject, n)
+		}
+	}
+
+	list := func(l []SchemaOrBool, n *yaml.Node) {
+		if n == nil || n.Kind != yaml.SequenceNode || len(n.Content) != len(l) {
+			return
+		}
+
+		for i := range l {
+			sub(&l[i], n.Content[i])
+		}
+	}
+
+	dict := func(m map[string]SchemaOrBool, n *yaml.Node) []string {
+		if n == nil || n.Kind != yaml.MappingNode {
+			return nil
+		}
+
+		keys := make([]string, 0, len(n.Content)/2)
+
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k := yamlResolve(n.Content[i]).Value
+			keys = append(keys, k)
+
+			if v, ok := m[k]; ok {
+				sub(&v, n.Content[i+1])
+			}
+		}
+
+		return keys
+	}
+
+	if len(s.Properties) > 0 {
+		s.PropertiesOrder = dict(s.Properties, field("properties"))
+	}
+
+	dict(s.Definitions, field("definitions"))
+	dict(s.PatternProperties, field("patternProperties"))
+
+	if len(s.Dependencies) > 0 {
+		deps := make(map[string]SchemaOrBool, len(s.Dependencies))
+
+		for k, d := range s.Dependencies {
+			if d.SchemaOrBool != nil {
+				deps[k] = *d.SchemaOrBool
+			}
+		}
+
+		dict(deps, field("dependencies"))
+	}
+
+	if s.Items != nil {
+		sub(s.Items.SchemaOrBool, field("items"))
+		list(s.Items.SchemaArray, field("items"))
+	}
+
+	sub(s.AdditionalItems, field("additionalItems"))
+	sub(s.Contains, field("contains"))
+	sub(s.AdditionalProperties, field("additionalProperties"))
+	sub(s.PropertyNames, field("propertyNames"))
+	sub(s.If, field("if"))
+	sub(s.Then, field("then"))
+	sub(s.Else, field("else"))
+	list(s.AllOf, field("allOf"))
+	list(s.AnyOf, field("anyOf"))
+	list(s.OneOf, field("oneOf"))
+	sub(s.Not, field("not"))
+}
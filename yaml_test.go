@@ -0,0 +1,115 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/assertjson"
+	"github.com/swaggest/jsonschema-go"
+	"gopkg.in/yaml.v3"
+)
+
+func TestSchema_UnmarshalYAML(t *testing.T) {
+	data := []byte(`type: object
+x-entity: order
+required: [zeta, alpha]
+properties:
+  zeta:
+    type: string
+    format: date
+    default: 2023-01-02
+  alpha:
+    type: integer
+    maximum: 10
+    x-go-name: Alpha
+  middle:
+    type: array
+    items:
+      properties:
+        b: {type: boolean}
+        a: {const: "yes"}
+  any: true
+definitions:
+  Nested:
+    properties:
+      y: {type: number}
+      x: {type: number, multipleOf: 0.5}
+`)
+
+	var s jsonschema.Schema
+
+	require.NoError(t, yaml.Unmarshal(data, &s))
+
+	assert.Equal(t, []string{"zeta", "alpha", "middle", "any"}, s.PropertiesOrder)
+	assert.Equal(t, []string{"b", "a"}, s.Properties["middle"].TypeObject.Items.SchemaOrBool.TypeObject.PropertiesOrder)
+	assert.Equal(t, []string{"y", "x"}, s.Definitions["Nested"].TypeObject.PropertiesOrder)
+	assert.Equal(t, "order", s.ExtraProperties["x-entity"])
+	assert.Equal(t, true, *s.Properties["any"].TypeBoolean)
+
+	assertjson.EqMarshal(t, `{
+	  "required":["zeta","alpha"],"definitions":{"Nested":{"properties":{"y":{"type":"number"},"x":{"multipleOf":0.5,"type":"number"}}}},
+	  "properties":{
+		"zeta":{"default":"2023-01-02","type":"string","format":"date"},
+		"alpha":{"maximum":10,"type":"integer","x-go-name":"Alpha"},
+		"middle":{"items":{"properties":{"b":{"type":"boolean"},"a":{"const":"yes"}}},"type":"array"},
+		"any":true
+	  },
+	  "type":"object","x-entity":"order"
+	}`, s)
+
+	out, err := yaml.Marshal(s)
+	require.NoError(t, err)
+
+	assert.Equal(t, `required:
+    - zeta
+    - alpha
+definitions:
+    Nested:
+        properties:
+            "y":
+                type: number
+            x:
+                multipleOf: 0.5
+                type: number
+type: object
+properties:
+    zeta:
+        default: "2023-01-02"
+        type: string
+        format: date
+    alpha:
+        maximum: 10
+        type: integer
+        x-go-name: Alpha
+    middle:
+        items:
+            properties:
+                b:
+                    type: boolean
+                a:
+                    const: "yes"
+        type: array
+    any: true
+x-entity: order
+`, string(out))
+
+	var s2 jsonschema.Schema
+
+	require.NoError(t, yaml.Unmarshal(out, &s2))
+	assert.Equal(t, s, s2)
+}
+
+func TestSchemaOrBool_MarshalYAML(t *testing.T) {
+	var doc struct {
+		Schemas map[string]jsonschema.SchemaOrBool `yaml:"schemas"`
+	}
+
+	require.NoError(t, yaml.Unmarshal([]byte("schemas:\n  Any: true\n  Name: {type: string, minLength: 1}\n"), &doc))
+	assert.True(t, *doc.Schemas["Any"].TypeBoolean)
+	assert.Equal(t, int64(1), doc.Schemas["Name"].TypeObject.MinLength)
+
+	out, err := yaml.Marshal(doc)
+	require.NoError(t, err)
+	assert.Equal(t, "schemas:\n    Any: true\n    Name:\n        minLength: 1\n        type: string\n", string(out))
+}